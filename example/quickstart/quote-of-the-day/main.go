@@ -2,6 +2,7 @@ package main
 
 import (
     "context"
+    "errors"
     "fmt"
     "log"
     "net/http"
@@ -122,15 +123,12 @@ func (app *WebApp) homeHandler(c *gin.Context) {
         // Create targeting context for the user
         targetingContext = createTargetingContext(username.(string))
 
-        // Get the Greeting variant for the current user
-        if variant, err := app.featureManager.GetVariant("Greeting", targetingContext); err != nil {
+        // Get the greeting message of the Greeting variant assigned to the current user
+        message, err := featuremanagement.GetVariantValue[string](app.featureManager, "Greeting", targetingContext)
+        if err != nil && !errors.Is(err, featuremanagement.ErrNoVariant) {
             log.Printf("Error getting Greeting variant: %v", err)
-        } else if variant != nil && variant.ConfigurationValue != nil {
-            // Extract the greeting message from the variant configuration
-            if configValue, ok := variant.ConfigurationValue.(string); ok {
-                greetingMessage = configValue
-            }
         }
+        greetingMessage = message
     }
 
     c.HTML(http.StatusOK, "index.html", gin.H{
@@ -0,0 +1,348 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package admin provides an HTTP API to inspect the feature flags served by a FeatureManager and
+// temporarily override them on a single instance, so that operators can act during an incident
+// without redeploying or editing the shared configuration store:
+//
+//	overrides := admin.NewOverrides()
+//	manager, _ := featuremanagement.NewFeatureManager(provider, &featuremanagement.Options{
+//		Interceptors: []featuremanagement.Interceptor{overrides.Interceptor()},
+//	})
+//	handler := admin.NewHandler(manager, admin.Options{
+//		Authorize: admin.BearerToken(os.Getenv("FEATURE_ADMIN_TOKEN")),
+//		Overrides: overrides,
+//	})
+//	http.Handle("/admin/features/", http.StripPrefix("/admin/features", handler))
+//
+// The handler serves JSON:
+//
+//	GET    /status                     readiness, flag count, filters and last refresh of the provider
+//	GET    /flags                      the flags served, with their overrides
+//	GET    /flags/{name}               the definition of a flag and its override
+//	GET    /flags/{name}/evaluate      the evaluation of a flag for ?user=, ?group= and ?attribute=key=value
+//	GET    /overrides                  the active overrides
+//	PUT    /overrides/{name}           override a flag: {"enabled": false, "duration": "30m", "reason": "..."}
+//	DELETE /overrides/{name}           remove the override of a flag
+//
+// NewDashboard serves the same information as an HTML page, for diagnosis in development and staging.
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+const (
+	// DefaultOverrideDuration is how long an override applies when the request doesn't set a duration
+	DefaultOverrideDuration = time.Hour
+	// DefaultMaxOverrideDuration is the longest override accepted when Options.MaxOverrideDuration is zero
+	DefaultMaxOverrideDuration = 24 * time.Hour
+)
+
+// Authorizer decides whether a request to the admin API is allowed. It can tell reads from
+// writes by the request method.
+type Authorizer func(r *http.Request) bool
+
+// Options configures the admin API.
+type Options struct {
+	// Authorize is called for every request; requests it rejects get 403 Forbidden.
+	// It is required: when nil, every request is rejected.
+	Authorize Authorizer
+
+	// Overrides holds the overrides set through the API. When nil, the override endpoints are not
+	// served and the API is read-only.
+	//
+	// The overrides only change evaluations when Overrides.Interceptor is registered in
+	// Options.Interceptors of the manager served; otherwise they are accepted and listed but
+	// have no effect. NewHandler logs a warning when the interceptor isn't registered.
+	Overrides *Overrides
+
+	// MaxOverrideDuration is the longest duration accepted for an override, so that a forgotten
+	// override doesn't outlive the incident. Defaults to DefaultMaxOverrideDuration.
+	MaxOverrideDuration time.Duration
+
+	// Logger receives an audit record of every override set or removed. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// BearerToken returns an authorizer allowing requests with an "Authorization: Bearer <token>"
+// header carrying the given token. An empty token rejects every request.
+//
+// Parameters:
+//   - token: The secret token clients must present
+//
+// Returns:
+//   - Authorizer: The authorizer to set in Options.Authorize
+func BearerToken(token string) Authorizer {
+	return func(r *http.Request) bool {
+		presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return ok && token != "" && subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1
+	}
+}
+
+type handler struct {
+	manager     *fm.FeatureManager
+	overrides   *Overrides
+	maxDuration time.Duration
+	logger      *slog.Logger
+}
+
+// NewHandler returns the admin API of a feature manager. See the package documentation for the
+// endpoints; paths are relative to where the handler is mounted, so it is typically wrapped in
+// http.StripPrefix.
+//
+// Parameters:
+//   - manager: The feature manager to inspect
+//   - options: The authorization and overrides of the API
+//
+// Returns:
+//   - http.Handler: The handler serving the API
+func NewHandler(manager *fm.FeatureManager, options Options) http.Handler {
+	h := &handler{
+		manager:     manager,
+		overrides:   options.Overrides,
+		maxDuration: options.MaxOverrideDuration,
+		logger:      options.Logger,
+	}
+	if h.maxDuration <= 0 {
+		h.maxDuration = DefaultMaxOverrideDuration
+	}
+	if h.logger == nil {
+		h.logger = slog.Default()
+	}
+	if h.overrides != nil && !h.overrides.intercepting.Load() {
+		h.logger.Warn("Feature overrides have no effect: register Overrides.Interceptor in the feature manager's Options.Interceptors")
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", h.status)
+	mux.HandleFunc("GET /flags", h.listFlags)
+	mux.HandleFunc("GET /flags/{name}", h.getFlag)
+	mux.HandleFunc("GET /flags/{name}/evaluate", h.evaluate)
+	if h.overrides != nil {
+		mux.HandleFunc("GET /overrides", h.listOverrides)
+		mux.HandleFunc("PUT /overrides/{name}", h.setOverride)
+		mux.HandleFunc("DELETE /overrides/{name}", h.clearOverride)
+	}
+
+	authorize := options.Authorize
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authorize == nil || !authorize(r) {
+			writeError(w, http.StatusForbidden, "forbidden")
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+type statusResponse struct {
+	Ready            bool       `json:"ready"`
+	FeatureFlagCount int        `json:"feature_flag_count"`
+	Filters          []string   `json:"filters"`
+	LastRefreshTime  *time.Time `json:"last_refresh_time,omitempty"`
+	LastRefreshError string     `json:"last_refresh_error,omitempty"`
+}
+
+func (h *handler) status(w http.ResponseWriter, r *http.Request) {
+	stats := h.manager.Stats()
+	response := statusResponse{
+		Ready:            stats.Ready,
+		FeatureFlagCount: stats.FeatureFlagCount,
+		Filters:          stats.Filters,
+	}
+	if !stats.LastRefreshTime.IsZero() {
+		response.LastRefreshTime = &stats.LastRefreshTime
+	}
+	if stats.LastRefreshError != nil {
+		response.LastRefreshError = stats.LastRefreshError.Error()
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+type flagSummary struct {
+	ID          string    `json:"id"`
+	Enabled     bool      `json:"enabled"`
+	Description string    `json:"description,omitempty"`
+	Filters     []string  `json:"filters,omitempty"`
+	Variants    []string  `json:"variants,omitempty"`
+	Override    *Override `json:"override,omitempty"`
+}
+
+func (h *handler) listFlags(w http.ResponseWriter, r *http.Request) {
+	flags := []flagSummary{}
+	for flag := range h.manager.All() {
+		summary := flagSummary{
+			ID:          flag.ID,
+			Enabled:     flag.Enabled,
+			Description: flag.Description,
+			Override:    h.override(flag.ID),
+		}
+		if flag.Conditions != nil {
+			for _, filter := range flag.Conditions.ClientFilters {
+				summary.Filters = append(summary.Filters, filter.Name)
+			}
+		}
+		for _, variant := range flag.Variants {
+			summary.Variants = append(summary.Variants, variant.Name)
+		}
+		flags = append(flags, summary)
+	}
+
+	writeJSON(w, http.StatusOK, flags)
+}
+
+type flagResponse struct {
+	Flag     fm.FeatureFlag `json:"flag"`
+	Override *Override      `json:"override,omitempty"`
+}
+
+func (h *handler) getFlag(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	for flag := range h.manager.All() {
+		if flag.ID == name {
+			writeJSON(w, http.StatusOK, flagResponse{Flag: flag, Override: h.override(name)})
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, fmt.Sprintf("feature flag %s is not defined", name))
+}
+
+type evaluationResponse struct {
+	Feature                 string    `json:"feature"`
+	Enabled                 bool      `json:"enabled"`
+	Variant                 string    `json:"variant,omitempty"`
+	VariantAssignmentReason string    `json:"variant_assignment_reason,omitempty"`
+	TargetingID             string    `json:"targeting_id,omitempty"`
+	Override                *Override `json:"override,omitempty"`
+}
+
+func (h *handler) evaluate(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	query := r.URL.Query()
+	targetingContext := fm.TargetingContext{
+		UserID: query.Get("user"),
+		Groups: query["group"],
+	}
+	for _, attribute := range query["attribute"] {
+		key, value, found := strings.Cut(attribute, "=")
+		if !found || key == "" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid attribute %q: expected key=value", attribute))
+			return
+		}
+		if targetingContext.Attributes == nil {
+			targetingContext.Attributes = make(map[string]string)
+		}
+		targetingContext.Attributes[key] = value
+	}
+
+	// Evaluations made from the API are not experiment exposures
+	res, err := h.manager.EvaluateContext(r.Context(), name, fm.WithTargeting(targetingContext), fm.WithoutTelemetry())
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	response := evaluationResponse{
+		Feature:                 name,
+		Enabled:                 res.Enabled,
+		VariantAssignmentReason: string(res.VariantAssignmentReason),
+		TargetingID:             res.TargetingID,
+		Override:                h.override(name),
+	}
+	if res.Variant != nil {
+		response.Variant = res.Variant.Name
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *handler) listOverrides(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.overrides.List())
+}
+
+type overrideRequest struct {
+	Enabled  *bool  `json:"enabled"`
+	Duration string `json:"duration"`
+	Reason   string `json:"reason"`
+}
+
+func (h *handler) setOverride(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	var request overrideRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid override: %v", err))
+		return
+	}
+	if request.Enabled == nil {
+		writeError(w, http.StatusBadRequest, "invalid override: missing enabled")
+		return
+	}
+
+	duration := DefaultOverrideDuration
+	if request.Duration != "" {
+		var err error
+		if duration, err = time.ParseDuration(request.Duration); err != nil || duration <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid override duration %q", request.Duration))
+			return
+		}
+	}
+	if duration > h.maxDuration {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("override duration %s exceeds the maximum of %s", duration, h.maxDuration))
+		return
+	}
+
+	override := h.overrides.Set(name, *request.Enabled, duration, request.Reason)
+	h.logger.Warn("Feature flag overridden",
+		"feature", name, "enabled", override.Enabled, "expires", override.Expires,
+		"reason", override.Reason, "remote_addr", r.RemoteAddr)
+
+	writeJSON(w, http.StatusOK, override)
+}
+
+func (h *handler) clearOverride(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if !h.overrides.Clear(name) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("feature flag %s is not overridden", name))
+		return
+	}
+	h.logger.Warn("Feature flag override removed", "feature", name, "remote_addr", r.RemoteAddr)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// override returns the active override of a feature, or nil
+func (h *handler) override(feature string) *Override {
+	if h.overrides == nil {
+		return nil
+	}
+	if override, ok := h.overrides.Get(feature); ok {
+		return &override
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	// The status is already sent, so an encoding error can only truncate the body
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,160 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package admin
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+func newTestHandler(t *testing.T) (http.Handler, *fm.FeatureManager) {
+	t.Helper()
+	overrides := NewOverrides()
+	manager, err := fm.NewFeatureManager(fm.NewStaticProvider(map[string]fm.FeatureFlag{
+		"Beta": {
+			ID:      "Beta",
+			Enabled: true,
+			Conditions: &fm.Conditions{ClientFilters: []fm.ClientFilter{{
+				Name:       "Microsoft.Targeting",
+				Parameters: map[string]any{"Audience": map[string]any{"Users": []any{"alice"}, "DefaultRolloutPercentage": 0}},
+			}}},
+		},
+	}), &fm.Options{Interceptors: []fm.Interceptor{overrides.Interceptor()}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	return NewHandler(manager, Options{
+		Authorize: BearerToken("secret"),
+		Overrides: overrides,
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}), manager
+}
+
+func serve(handler http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer secret")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestHandlerAuthorization(t *testing.T) {
+	handler, _ := newTestHandler(t)
+	for _, header := range []string{"", "Bearer wrong", "secret"} {
+		request := httptest.NewRequest(http.MethodGet, "/flags", nil)
+		if header != "" {
+			request.Header.Set("Authorization", header)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		if recorder.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 for Authorization %q, got %d", header, recorder.Code)
+		}
+	}
+
+	manager, _ := fm.NewFeatureManager(fm.NewStaticProvider(nil), nil)
+	recorder := httptest.NewRecorder()
+	NewHandler(manager, Options{}).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/flags", nil))
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected requests to be rejected without an authorizer, got %d", recorder.Code)
+	}
+}
+
+func TestHandlerInspection(t *testing.T) {
+	handler, _ := newTestHandler(t)
+
+	recorder := serve(handler, http.MethodGet, "/flags", "")
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"filters":["Microsoft.Targeting"]`) {
+		t.Errorf("Unexpected flags response %d: %s", recorder.Code, recorder.Body)
+	}
+
+	if recorder := serve(handler, http.MethodGet, "/flags/Beta", ""); recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"id":"Beta"`) {
+		t.Errorf("Unexpected flag response %d: %s", recorder.Code, recorder.Body)
+	}
+	if recorder := serve(handler, http.MethodGet, "/flags/Missing", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an undefined flag, got %d", recorder.Code)
+	}
+
+	for user, expected := range map[string]bool{"alice": true, "bob": false} {
+		recorder := serve(handler, http.MethodGet, "/flags/Beta/evaluate?user="+user, "")
+		var response evaluationResponse
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Enabled != expected {
+			t.Errorf("Expected Beta enabled=%v for %s, got %s", expected, user, recorder.Body)
+		}
+	}
+
+	if recorder := serve(handler, http.MethodGet, "/status", ""); !strings.Contains(recorder.Body.String(), `"feature_flag_count":1`) {
+		t.Errorf("Unexpected status response: %s", recorder.Body)
+	}
+}
+
+func TestHandlerOverrides(t *testing.T) {
+	handler, manager := newTestHandler(t)
+	alice := fm.TargetingContext{UserID: "alice"}
+
+	recorder := serve(handler, http.MethodPut, "/overrides/Beta", `{"enabled": false, "duration": "30m", "reason": "incident"}`)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected the override to be set, got %d: %s", recorder.Code, recorder.Body)
+	}
+	if enabled, _ := manager.IsEnabledWithAppContext("Beta", alice); enabled {
+		t.Error("Expected Beta to be overridden off")
+	}
+	if recorder := serve(handler, http.MethodGet, "/overrides", ""); !strings.Contains(recorder.Body.String(), `"reason":"incident"`) {
+		t.Errorf("Unexpected overrides response: %s", recorder.Body)
+	}
+
+	for _, body := range []string{`{"duration": "30m"}`, `{"enabled": true, "duration": "48h"}`, `{"enabled": true, "duration": "soon"}`, `{"enabled": true, "ttl": "1m"}`} {
+		if recorder := serve(handler, http.MethodPut, "/overrides/Beta", body); recorder.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", body, recorder.Code)
+		}
+	}
+
+	if recorder := serve(handler, http.MethodDelete, "/overrides/Beta", ""); recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected the override to be removed, got %d", recorder.Code)
+	}
+	if enabled, _ := manager.IsEnabledWithAppContext("Beta", alice); !enabled {
+		t.Error("Expected Beta to be evaluated normally once the override is removed")
+	}
+	if recorder := serve(handler, http.MethodDelete, "/overrides/Beta", ""); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 when removing a missing override, got %d", recorder.Code)
+	}
+}
+
+func TestHandlerReadOnly(t *testing.T) {
+	manager, _ := fm.NewFeatureManager(fm.NewStaticProvider(nil), nil)
+	handler := NewHandler(manager, Options{Authorize: func(*http.Request) bool { return true }})
+
+	if recorder := serve(handler, http.MethodPut, "/overrides/Beta", `{"enabled": true}`); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected no override endpoints without Overrides, got %d", recorder.Code)
+	}
+}
+
+func TestHandlerUnregisteredOverrides(t *testing.T) {
+	manager, _ := fm.NewFeatureManager(fm.NewStaticProvider(nil), nil)
+	var log strings.Builder
+	NewHandler(manager, Options{
+		Authorize: BearerToken("secret"),
+		Overrides: NewOverrides(),
+		Logger:    slog.New(slog.NewTextHandler(&log, nil)),
+	})
+	if !strings.Contains(log.String(), "Feature overrides have no effect") {
+		t.Errorf("Expected a warning for overrides without their interceptor, got %q", log.String())
+	}
+
+	log.Reset()
+	overrides := NewOverrides()
+	manager, _ = fm.NewFeatureManager(fm.NewStaticProvider(nil), &fm.Options{Interceptors: []fm.Interceptor{overrides.Interceptor()}})
+	NewHandler(manager, Options{Overrides: overrides, Logger: slog.New(slog.NewTextHandler(&log, nil))})
+	if log.Len() != 0 {
+		t.Errorf("Expected no warning for registered overrides, got %q", log.String())
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package admin
+
+import (
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+//go:embed dashboard.html
+var dashboardHTML string
+
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(dashboardHTML))
+
+type dashboardPage struct {
+	Status    fm.Stats
+	Flags     []dashboardFlag
+	User      string
+	Groups    string
+	Evaluated bool
+}
+
+type dashboardFlag struct {
+	ID              string
+	Description     string
+	Enabled         bool
+	RequirementType fm.RequirementType
+	Filters         []dashboardFilter
+	Variants        []string
+	Allocation      string
+	Override        *Override
+	Evaluation      *dashboardEvaluation
+}
+
+type dashboardFilter struct {
+	Name       string
+	Parameters string
+}
+
+type dashboardEvaluation struct {
+	Enabled bool
+	Variant string
+	Reason  fm.VariantAssignmentReason
+	Error   string
+}
+
+// NewDashboard returns an HTML page showing the feature flags served by a feature manager, with
+// their filters, variants and overrides, the status of the provider's refreshes, and a form to
+// evaluate every flag as a given user. It is meant for diagnosis in development and staging, and
+// is read-only: overrides are set with the API served by NewHandler.
+//
+// The page is rendered on the server, so it can be mounted next to the API:
+//
+//	http.Handle("/admin/dashboard", admin.NewDashboard(manager, options))
+//
+// The page is a plain GET, so Options.Authorize must accept whatever credentials browsers send
+// to the application, such as a session cookie.
+//
+// Parameters:
+//   - manager: The feature manager to show
+//   - options: The authorization of the page, and the overrides to show
+//
+// Returns:
+//   - http.Handler: The handler serving the page
+func NewDashboard(manager *fm.FeatureManager, options Options) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if options.Authorize == nil || !options.Authorize(r) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		page := dashboardPage{
+			Status: manager.Stats(),
+			User:   query.Get("user"),
+			Groups: query.Get("groups"),
+		}
+		page.Evaluated = page.User != "" || page.Groups != ""
+
+		targetingContext := fm.TargetingContext{UserID: page.User}
+		for _, group := range strings.Split(page.Groups, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				targetingContext.Groups = append(targetingContext.Groups, group)
+			}
+		}
+
+		for flag := range manager.All() {
+			row := newDashboardFlag(flag)
+			if options.Overrides != nil {
+				if override, ok := options.Overrides.Get(flag.ID); ok {
+					row.Override = &override
+				}
+			}
+			if page.Evaluated {
+				row.Evaluation = evaluateForDashboard(r, manager, flag.ID, targetingContext)
+			}
+			page.Flags = append(page.Flags, row)
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if err := dashboardTemplate.Execute(w, page); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func newDashboardFlag(flag fm.FeatureFlag) dashboardFlag {
+	row := dashboardFlag{
+		ID:          flag.ID,
+		Description: flag.Description,
+		Enabled:     flag.Enabled,
+	}
+	if flag.Conditions != nil {
+		row.RequirementType = flag.Conditions.RequirementType
+		for _, filter := range flag.Conditions.ClientFilters {
+			row.Filters = append(row.Filters, dashboardFilter{Name: filter.Name, Parameters: compactJSON(filter.Parameters)})
+		}
+	}
+	for _, variant := range flag.Variants {
+		row.Variants = append(row.Variants, variant.Name)
+	}
+	if flag.Allocation != nil {
+		row.Allocation = compactJSON(flag.Allocation)
+	}
+
+	return row
+}
+
+// evaluateForDashboard evaluates a feature for the user entered in the form, without publishing
+// telemetry, since the evaluation is not an exposure of the user
+func evaluateForDashboard(r *http.Request, manager *fm.FeatureManager, featureName string, targetingContext fm.TargetingContext) *dashboardEvaluation {
+	res, err := manager.EvaluateContext(r.Context(), featureName, fm.WithTargeting(targetingContext), fm.WithoutTelemetry())
+	if err != nil {
+		return &dashboardEvaluation{Error: err.Error()}
+	}
+
+	evaluation := &dashboardEvaluation{Enabled: res.Enabled, Reason: res.VariantAssignmentReason}
+	if res.Variant != nil {
+		evaluation.Variant = res.Variant.Name
+	}
+
+	return evaluation
+}
+
+func compactJSON(value any) string {
+	if value == nil {
+		return ""
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+func TestDashboard(t *testing.T) {
+	overrides := NewOverrides()
+	overrides.Set("Legacy", true, time.Hour, "rollback <script>")
+	manager, err := fm.NewFeatureManager(fm.NewStaticProvider(map[string]fm.FeatureFlag{
+		"Beta": {
+			ID:      "Beta",
+			Enabled: true,
+			Conditions: &fm.Conditions{ClientFilters: []fm.ClientFilter{{
+				Name:       "Microsoft.Targeting",
+				Parameters: map[string]any{"Audience": map[string]any{"Groups": []any{map[string]any{"Name": "Ring0", "RolloutPercentage": 100}}}},
+			}}},
+			Variants:   []fm.VariantDefinition{{Name: "Big"}},
+			Allocation: &fm.VariantAllocation{DefaultWhenEnabled: "Big"},
+		},
+		"Legacy": {ID: "Legacy"},
+	}), &fm.Options{Interceptors: []fm.Interceptor{overrides.Interceptor()}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	dashboard := NewDashboard(manager, Options{
+		Authorize: func(r *http.Request) bool { return r.Header.Get("X-Admin") == "yes" },
+		Overrides: overrides,
+	})
+
+	recorder := httptest.NewRecorder()
+	dashboard.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusForbidden {
+		t.Errorf("Expected unauthorized requests to be rejected, got %d", recorder.Code)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/?user=alice&groups=Ring0", nil)
+	request.Header.Set("X-Admin", "yes")
+	recorder = httptest.NewRecorder()
+	dashboard.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected the dashboard to render, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	body := recorder.Body.String()
+	for _, expected := range []string{
+		"2 flags",
+		"Microsoft.Targeting",
+		"Ring0",
+		"<th>As alice</th>",
+		"variant Big",
+		"rollback &lt;script&gt;",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected the dashboard to contain %q, got:\n%s", expected, body)
+		}
+	}
+}
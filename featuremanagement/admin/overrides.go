@@ -0,0 +1,166 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package admin
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// Override is a temporary override of the state of a feature on this instance.
+type Override struct {
+	// Feature is the name of the overridden feature
+	Feature string `json:"feature"`
+	// Enabled is the state the feature is forced to
+	Enabled bool `json:"enabled"`
+	// Expires is when the override stops applying
+	Expires time.Time `json:"expires"`
+	// Reason is the justification given when the override was set, for the audit log
+	Reason string `json:"reason,omitempty"`
+}
+
+// Overrides holds the temporary overrides set through the admin API. Its Interceptor applies them
+// to a feature manager, so it must be registered in Options.Interceptors of the manager served:
+//
+//	overrides := admin.NewOverrides()
+//	manager, _ := featuremanagement.NewFeatureManager(provider, &featuremanagement.Options{
+//		Interceptors: []featuremanagement.Interceptor{overrides.Interceptor()},
+//	})
+//
+// Without the interceptor, overrides set through the API are accepted and listed but change no
+// evaluation; NewHandler logs a warning when the interceptor isn't registered in any manager.
+//
+// Overrides live in memory and only affect this instance; they are lost on restart. It is safe
+// for concurrent use.
+type Overrides struct {
+	mu      sync.RWMutex
+	entries map[string]Override
+	now     func() time.Time
+
+	// intercepting is set once the interceptor wraps the evaluations of a feature manager
+	intercepting atomic.Bool
+}
+
+// NewOverrides creates an empty set of overrides.
+func NewOverrides() *Overrides {
+	return &Overrides{
+		entries: make(map[string]Override),
+		now:     time.Now,
+	}
+}
+
+// Set forces a feature on or off until the duration elapses, replacing any override of the feature.
+//
+// Parameters:
+//   - feature: The name of the feature to override
+//   - enabled: The state to force the feature to
+//   - duration: How long the override applies
+//   - reason: The justification of the override, for the audit log
+//
+// Returns:
+//   - Override: The override set
+func (o *Overrides) Set(feature string, enabled bool, duration time.Duration, reason string) Override {
+	override := Override{
+		Feature: feature,
+		Enabled: enabled,
+		Expires: o.now().Add(duration),
+		Reason:  reason,
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries[feature] = override
+
+	return override
+}
+
+// Clear removes the override of a feature.
+//
+// Parameters:
+//   - feature: The name of the overridden feature
+//
+// Returns:
+//   - bool: true if the feature had an override that hadn't expired
+func (o *Overrides) Clear(feature string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	override, ok := o.entries[feature]
+	delete(o.entries, feature)
+
+	return ok && o.now().Before(override.Expires)
+}
+
+// Get returns the override of a feature, if it hasn't expired.
+//
+// Parameters:
+//   - feature: The name of the feature
+//
+// Returns:
+//   - Override: The override of the feature
+//   - bool: true if the feature is overridden
+func (o *Overrides) Get(feature string) (Override, bool) {
+	o.mu.RLock()
+	override, ok := o.entries[feature]
+	o.mu.RUnlock()
+
+	if !ok || !o.now().Before(override.Expires) {
+		return Override{}, false
+	}
+
+	return override, true
+}
+
+// List returns the overrides that haven't expired, sorted by feature name, and removes the
+// expired ones.
+//
+// Returns:
+//   - []Override: The active overrides
+func (o *Overrides) List() []Override {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := o.now()
+	list := make([]Override, 0, len(o.entries))
+	for name, override := range o.entries {
+		if !now.Before(override.Expires) {
+			delete(o.entries, name)
+			continue
+		}
+		list = append(list, override)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Feature < list[j].Feature })
+
+	return list
+}
+
+// Interceptor returns an interceptor forcing overridden features to their overridden state.
+// The evaluation still runs, so usage is tracked as usual. When the overridden state differs from
+// the evaluated one, the variant is reassigned to the feature's default variant for that state,
+// see EvaluationResult.WithEnabled. An overridden feature that the provider doesn't define
+// evaluates to its overridden state without error.
+//
+// Returns:
+//   - featuremanagement.Interceptor: The interceptor to register in the feature manager's options
+func (o *Overrides) Interceptor() fm.Interceptor {
+	return func(next fm.Evaluator) fm.Evaluator {
+		o.intercepting.Store(true)
+		return func(featureName string, appContext any) (fm.EvaluationResult, error) {
+			res, err := next(featureName, appContext)
+
+			override, ok := o.Get(featureName)
+			if !ok {
+				return res, err
+			}
+			if err != nil {
+				return fm.EvaluationResult{Enabled: override.Enabled, EvaluationID: res.EvaluationID}, nil
+			}
+			return res.WithEnabled(override.Enabled), nil
+		}
+	}
+}
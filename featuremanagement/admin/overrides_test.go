@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package admin
+
+import (
+	"testing"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+func TestOverridesInterceptor(t *testing.T) {
+	overrides := NewOverrides()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	overrides.now = func() time.Time { return now }
+
+	manager, err := fm.NewFeatureManager(fm.NewStaticProvider(map[string]fm.FeatureFlag{
+		"Beta": {ID: "Beta", Enabled: true},
+	}), &fm.Options{Interceptors: []fm.Interceptor{overrides.Interceptor()}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	overrides.Set("Beta", false, time.Minute, "incident")
+	overrides.Set("Missing", true, time.Minute, "")
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || enabled {
+		t.Errorf("Expected Beta to be overridden off, got %v, %v", enabled, err)
+	}
+	if enabled, err := manager.IsEnabled("Missing"); err != nil || !enabled {
+		t.Errorf("Expected an undefined overridden feature to be enabled, got %v, %v", enabled, err)
+	}
+	if list := overrides.List(); len(list) != 2 || list[0].Feature != "Beta" {
+		t.Errorf("Unexpected overrides %+v", list)
+	}
+
+	now = now.Add(time.Minute)
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || !enabled {
+		t.Errorf("Expected the override of Beta to expire, got %v, %v", enabled, err)
+	}
+	if list := overrides.List(); len(list) != 0 {
+		t.Errorf("Expected expired overrides to be removed, got %+v", list)
+	}
+	if overrides.Clear("Beta") {
+		t.Error("Expected clearing an expired override to report no override")
+	}
+}
+
+func TestOverridesInterceptorVariants(t *testing.T) {
+	overrides := NewOverrides()
+	manager, err := fm.NewFeatureManager(fm.NewStaticProvider(map[string]fm.FeatureFlag{
+		"Beta": {
+			ID:         "Beta",
+			Enabled:    true,
+			Variants:   []fm.VariantDefinition{{Name: "New"}, {Name: "Old"}},
+			Allocation: &fm.VariantAllocation{DefaultWhenEnabled: "New", DefaultWhenDisabled: "Old"},
+		},
+	}), &fm.Options{Interceptors: []fm.Interceptor{overrides.Interceptor()}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if variant, err := manager.GetVariant("Beta", nil); err != nil || variant == nil || variant.Name != "New" {
+		t.Fatalf("Expected the default when enabled variant, got %+v, %v", variant, err)
+	}
+
+	// An override turning the feature off also switches it to its default when disabled variant
+	overrides.Set("Beta", false, time.Minute, "incident")
+	res, err := manager.Evaluate("Beta", nil)
+	if err != nil || res.Enabled || res.Variant == nil || res.Variant.Name != "Old" || res.VariantAssignmentReason != fm.VariantAssignmentReasonDefaultWhenDisabled {
+		t.Errorf("Expected the overridden Beta to get the default when disabled variant, got %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+)
+
+// AllocationStrategy assigns variants of enabled features to targeted users. Set one with
+// Options.AllocationStrategy to replace the deterministic allocation defined by feature flags,
+// for example with an adaptive allocator such as a multi-armed bandit. A strategy can delegate
+// features it doesn't handle to DefaultAllocationStrategy.
+//
+// Example:
+//
+//	type banditStrategy struct{ ... }
+//
+//	func (s *banditStrategy) Allocate(featureFlag *featuremanagement.FeatureFlag, targetingContext featuremanagement.TargetingContext) (string, featuremanagement.VariantAssignmentReason) {
+//		if featureFlag.ID != "Checkout" {
+//			return featuremanagement.DefaultAllocationStrategy{}.Allocate(featureFlag, targetingContext)
+//		}
+//		return s.bestArm(), featuremanagement.VariantAssignmentReasonPercentile
+//	}
+type AllocationStrategy interface {
+	// Allocate selects the variant to assign to a user for an enabled feature. It must be safe for
+	// concurrent use and must not modify the feature flag.
+	//
+	// Parameters:
+	//   - featureFlag: The feature flag being evaluated
+	//   - targetingContext: The user being targeted
+	//
+	// Returns:
+	//   - string: The name of the variant to assign, or an empty name to assign the default variant when enabled
+	//   - VariantAssignmentReason: The reason reported for the assignment
+	Allocate(featureFlag *FeatureFlag, targetingContext TargetingContext) (string, VariantAssignmentReason)
+}
+
+// RewardRecorder is implemented by allocation strategies that adapt to feedback on the variants
+// they assign. The FeatureManager forwards rewards reported with RecordReward to it.
+type RewardRecorder interface {
+	// RecordReward records the outcome of assigning a variant, such as 1 for a conversion and 0 otherwise.
+	//
+	// Parameters:
+	//   - featureName: The name of the feature
+	//   - variantName: The name of the assigned variant
+	//   - reward: The observed reward
+	RecordReward(featureName string, variantName string, reward float64)
+}
+
+// DefaultAllocationStrategy allocates variants by the user, group and percentile allocations of
+// the feature flag, in that order. It is used when no AllocationStrategy is configured.
+type DefaultAllocationStrategy struct {
+	// HighResolutionBuckets buckets users with 64-bit context markers for percentile allocations
+	// and exclusion groups; see TargetingFilter.HighResolutionBuckets. Options.HighResolutionBuckets
+	// enables it for the strategy set as Options.AllocationStrategy.
+	HighResolutionBuckets bool
+
+	// UserPatterns matches the users of user allocations containing '*' wildcards as patterns;
+	// see Options.UserPatterns, which enables it for the strategy set as Options.AllocationStrategy.
+	UserPatterns bool
+}
+
+// allocationStrategyFor returns the allocation strategy of the options, with HighResolutionBuckets
+// and UserPatterns enabled on a copy of a DefaultAllocationStrategy when the options enable them
+func allocationStrategyFor(options *Options) AllocationStrategy {
+	if !options.HighResolutionBuckets && !options.UserPatterns {
+		return options.AllocationStrategy
+	}
+
+	var strategy DefaultAllocationStrategy
+	switch s := options.AllocationStrategy.(type) {
+	case DefaultAllocationStrategy:
+		strategy = s
+	case *DefaultAllocationStrategy:
+		if s == nil {
+			return options.AllocationStrategy
+		}
+		strategy = *s
+	default:
+		return options.AllocationStrategy
+	}
+
+	strategy.HighResolutionBuckets = strategy.HighResolutionBuckets || options.HighResolutionBuckets
+	strategy.UserPatterns = strategy.UserPatterns || options.UserPatterns
+	return strategy
+}
+
+func (s DefaultAllocationStrategy) Allocate(featureFlag *FeatureFlag, targetingContext TargetingContext) (string, VariantAssignmentReason) {
+	if featureFlag.Allocation == nil {
+		return "", VariantAssignmentReasonNone
+	}
+
+	assignment := assignVariant(featureFlag, &targetingContext, bucketerFor(s.HighResolutionBuckets), userMatcher{patterns: s.UserPatterns})
+	if assignment.Variant == nil {
+		return "", VariantAssignmentReasonNone
+	}
+
+	return assignment.Variant.Name, assignment.Reason
+}
+
+// RecordReward reports the outcome of a variant assignment to the configured allocation strategy,
+// so that adaptive strategies can shift traffic towards better performing variants.
+//
+// Parameters:
+//   - featureName: The name of the feature
+//   - variantName: The name of the assigned variant
+//   - reward: The observed reward
+//
+// Returns:
+//   - error: An error if the allocation strategy doesn't accept rewards
+func (fm *FeatureManager) RecordReward(featureName string, variantName string, reward float64) error {
+	recorder, ok := fm.allocationStrategy.(RewardRecorder)
+	if !ok {
+		return fmt.Errorf("allocation strategy %T does not accept rewards", fm.allocationStrategy)
+	}
+
+	recorder.RecordReward(featureName, variantName, reward)
+	return nil
+}
+
+// allocate assigns a variant to a targeted user of an enabled feature, honoring the variants
+// forced by the targeting context and then the assignment store before any allocation
+func (fm *FeatureManager) allocate(featureFlag *FeatureFlag, targetingContext *TargetingContext, evaluationID string) variantAssignment {
+	if variantName, ok := targetingContext.ForcedVariants[featureFlag.ID]; ok {
+		if variant := getVariant(featureFlag.Variants, variantName); variant != nil {
+			return variantAssignment{Variant: variant, Reason: VariantAssignmentReasonOverride}
+		}
+		fm.reportError(featureFlag.ID, withEvaluationID(evaluationID, fmt.Errorf("forced variant %s not found in feature %s", variantName, featureFlag.ID)))
+	}
+
+	if fm.assignmentStore == nil {
+		return fm.allocateVariant(featureFlag, targetingContext)
+	}
+
+	targetingID := targetingContext.UserID
+	if featureFlag.Allocation != nil {
+		targetingID = allocationTargetingID(featureFlag.Allocation, targetingContext)
+	}
+	if targetingID == "" {
+		return fm.allocateVariant(featureFlag, targetingContext)
+	}
+
+	stored, found, err := fm.getAssignment(featureFlag.ID, targetingID)
+	if err != nil {
+		fm.reportError(featureFlag.ID, withEvaluationID(evaluationID, fmt.Errorf("failed to get the stored assignment of feature %s: %w", featureFlag.ID, err)))
+	} else if found {
+		if variant := getVariant(featureFlag.Variants, stored.Variant); variant != nil {
+			return variantAssignment{Variant: variant, Reason: stored.Reason}
+		}
+		fm.reportError(featureFlag.ID, withEvaluationID(evaluationID, fmt.Errorf("stored variant %s not found in feature %s", stored.Variant, featureFlag.ID)))
+	}
+
+	assignment := fm.allocateVariant(featureFlag, targetingContext)
+	if assignment.Variant != nil && err == nil {
+		stored := Assignment{Variant: assignment.Variant.Name, Reason: assignment.Reason}
+		if err := fm.putAssignment(featureFlag.ID, targetingID, stored); err != nil {
+			fm.reportError(featureFlag.ID, withEvaluationID(evaluationID, fmt.Errorf("failed to store the assignment of feature %s: %w", featureFlag.ID, err)))
+		}
+	}
+
+	return assignment
+}
+
+// allocateVariant assigns a variant with the allocation strategy, or as defined by the feature flag
+func (fm *FeatureManager) allocateVariant(featureFlag *FeatureFlag, targetingContext *TargetingContext) variantAssignment {
+	var assignment variantAssignment
+	if fm.allocationStrategy == nil {
+		if featureFlag.Allocation == nil {
+			return variantAssignment{Reason: VariantAssignmentReasonNone}
+		}
+		assignment = assignVariant(featureFlag, targetingContext, fm.bucketer, fm.users)
+	} else {
+		variantName, reason, err := fm.allocateWithStrategy(featureFlag, *targetingContext)
+		if err != nil {
+			fm.reportError(featureFlag.ID, err)
+			return variantAssignment{Reason: VariantAssignmentReasonNone}
+		}
+		assignment = getVariantAssignment(featureFlag, variantName, reason)
+	}
+
+	if assignment.MissingVariant != "" {
+		fm.logger.warnf("Variant %s not found in feature %s", assignment.MissingVariant, featureFlag.ID)
+	}
+	return assignment
+}
@@ -0,0 +1,245 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// greedyStrategy assigns the variant with the highest mean reward for Checkout, delegating other features
+type greedyStrategy struct {
+	mu      sync.Mutex
+	rewards map[string][]float64
+}
+
+func (s *greedyStrategy) Allocate(featureFlag *FeatureFlag, targetingContext TargetingContext) (string, VariantAssignmentReason) {
+	if featureFlag.ID != "Checkout" {
+		return DefaultAllocationStrategy{}.Allocate(featureFlag, targetingContext)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	best, bestMean := "", -1.0
+	for _, variant := range featureFlag.Variants {
+		mean := 0.0
+		for _, reward := range s.rewards[variant.Name] {
+			mean += reward / float64(len(s.rewards[variant.Name]))
+		}
+		if mean > bestMean {
+			best, bestMean = variant.Name, mean
+		}
+	}
+	return best, VariantAssignmentReasonPercentile
+}
+
+func (s *greedyStrategy) RecordReward(featureName string, variantName string, reward float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rewards[variantName] = append(s.rewards[variantName], reward)
+}
+
+func TestAllocationStrategy(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Checkout": {
+			Enabled:  true,
+			Variants: []VariantDefinition{{Name: "A"}, {Name: "B"}},
+		},
+		"Greeting": {
+			Enabled:    true,
+			Variants:   []VariantDefinition{{Name: "Casual"}, {Name: "Formal"}},
+			Allocation: &VariantAllocation{User: []UserAllocation{{Variant: "Formal", Users: []string{"Alice"}}}},
+		},
+	})
+
+	strategy := &greedyStrategy{rewards: make(map[string][]float64)}
+	manager, err := NewFeatureManager(provider, &Options{AllocationStrategy: strategy})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	user := TargetingContext{UserID: "Alice"}
+	if variant, _ := manager.GetVariant("Checkout", user); variant == nil || variant.Name != "A" {
+		t.Fatalf("Expected A before any rewards, got %v", variant)
+	}
+
+	if err := manager.RecordReward("Checkout", "B", 1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := manager.RecordReward("Checkout", "A", 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if variant, _ := manager.GetVariant("Checkout", user); variant == nil || variant.Name != "B" {
+		t.Errorf("Expected the strategy to switch to B, got %v", variant)
+	}
+
+	// Delegated features are allocated as defined by the flag
+	if variant, _ := manager.GetVariant("Greeting", user); variant == nil || variant.Name != "Formal" {
+		t.Errorf("Expected Formal from the user allocation, got %v", variant)
+	}
+}
+
+func TestRecordRewardWithoutRecorder(t *testing.T) {
+	manager, err := NewFeatureManager(NewBoolProvider(map[string]bool{"Beta": true}), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if err := manager.RecordReward("Beta", "A", 1); err == nil {
+		t.Error("Expected an error when the allocation strategy doesn't accept rewards")
+	}
+}
+
+func TestForcedVariants(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Greeting": {
+			Enabled:  true,
+			Variants: []VariantDefinition{{Name: "Casual"}, {Name: "Formal"}, {Name: "Off", StatusOverride: StatusOverrideDisabled}},
+			Allocation: &VariantAllocation{
+				DefaultWhenEnabled: "Casual",
+				User:               []UserAllocation{{Variant: "Formal", Users: []string{"Alice"}}},
+			},
+		},
+		"Disabled": {
+			Enabled:  false,
+			Variants: []VariantDefinition{{Name: "Casual"}, {Name: "Formal"}},
+		},
+	})
+
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		feature       string
+		context       TargetingContext
+		expectVariant string
+		expectReason  VariantAssignmentReason
+		expectEnabled bool
+	}{
+		{
+			name:          "forced variant wins over user allocation",
+			feature:       "Greeting",
+			context:       TargetingContext{UserID: "Alice", ForcedVariants: map[string]string{"Greeting": "Casual"}},
+			expectVariant: "Casual",
+			expectReason:  VariantAssignmentReasonOverride,
+			expectEnabled: true,
+		},
+		{
+			name:          "forced variant applies its status override",
+			feature:       "Greeting",
+			context:       TargetingContext{ForcedVariants: map[string]string{"Greeting": "Off"}},
+			expectVariant: "Off",
+			expectReason:  VariantAssignmentReasonOverride,
+			expectEnabled: false,
+		},
+		{
+			name:          "unknown forced variant falls back to allocation",
+			feature:       "Greeting",
+			context:       TargetingContext{UserID: "Alice", ForcedVariants: map[string]string{"Greeting": "Missing"}},
+			expectVariant: "Formal",
+			expectReason:  VariantAssignmentReasonUser,
+			expectEnabled: true,
+		},
+		{
+			name:          "forced variant of another feature is ignored",
+			feature:       "Greeting",
+			context:       TargetingContext{UserID: "Bob", ForcedVariants: map[string]string{"Other": "Formal"}},
+			expectVariant: "Casual",
+			expectReason:  VariantAssignmentReasonDefaultWhenEnabled,
+			expectEnabled: true,
+		},
+		{
+			name:          "disabled feature ignores forced variant",
+			feature:       "Disabled",
+			context:       TargetingContext{ForcedVariants: map[string]string{"Disabled": "Formal"}},
+			expectReason:  VariantAssignmentReasonDefaultWhenDisabled,
+			expectEnabled: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := manager.Evaluate(tt.feature, tt.context)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			variantName := ""
+			if result.Variant != nil {
+				variantName = result.Variant.Name
+			}
+			if variantName != tt.expectVariant {
+				t.Errorf("Expected variant %q, got %q", tt.expectVariant, variantName)
+			}
+			if result.VariantAssignmentReason != tt.expectReason {
+				t.Errorf("Expected reason %s, got %s", tt.expectReason, result.VariantAssignmentReason)
+			}
+			if result.Enabled != tt.expectEnabled {
+				t.Errorf("Expected enabled %v, got %v", tt.expectEnabled, result.Enabled)
+			}
+		})
+	}
+}
+
+func TestAllocateByTargetingAttribute(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Dashboard": {
+			Enabled:  true,
+			Variants: []VariantDefinition{{Name: "Classic"}, {Name: "Modern"}, {Name: "Beta"}},
+			Allocation: &VariantAllocation{
+				DefaultWhenEnabled: "Classic",
+				TargetingAttribute: "tenantId",
+				User:               []UserAllocation{{Variant: "Beta", Users: []string{"contoso"}}},
+				Group:              []GroupAllocation{{Variant: "Beta", Groups: []string{"Insiders"}}},
+				Percentile:         []PercentileAllocation{{Variant: "Modern", From: 0, To: 50}},
+			},
+		},
+	})
+
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	// The user rule matches the tenant rather than the user ID
+	tenant := func(userID, tenantID string) TargetingContext {
+		return TargetingContext{UserID: userID, Attributes: map[string]string{"tenantId": tenantID}}
+	}
+	if result, _ := manager.Evaluate("Dashboard", tenant("Alice", "contoso")); result.Variant.Name != "Beta" || result.VariantAssignmentReason != VariantAssignmentReasonUser {
+		t.Errorf("Expected Beta by tenant, got %s (%s)", result.Variant.Name, result.VariantAssignmentReason)
+	}
+	if result, _ := manager.Evaluate("Dashboard", TargetingContext{UserID: "contoso"}); result.VariantAssignmentReason == VariantAssignmentReasonUser {
+		t.Error("Expected the user ID not to match the tenant user rule")
+	}
+
+	// Every user of a tenant gets the same percentile allocation
+	for i := 0; i < 20; i++ {
+		tenantID := fmt.Sprintf("tenant-%d", i)
+		expected := "Classic"
+		if ComputeBucket(tenantID, "allocation", "Dashboard") < 50 {
+			expected = "Modern"
+		}
+		for _, userID := range []string{"Alice", "Bob", "Carol"} {
+			result, err := manager.Evaluate("Dashboard", tenant(userID, tenantID))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Variant.Name != expected {
+				t.Errorf("Expected %s for %s of %s, got %s", expected, userID, tenantID, result.Variant.Name)
+			}
+		}
+	}
+
+	// Contexts without the attribute are only allocated by group
+	if result, _ := manager.Evaluate("Dashboard", TargetingContext{UserID: "Alice", Groups: []string{"Insiders"}}); result.Variant.Name != "Beta" {
+		t.Errorf("Expected Beta by group, got %s", result.Variant.Name)
+	}
+	if result, _ := manager.Evaluate("Dashboard", TargetingContext{UserID: "Alice"}); result.VariantAssignmentReason != VariantAssignmentReasonDefaultWhenEnabled {
+		t.Errorf("Expected the default variant without the attribute, got %s", result.VariantAssignmentReason)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import "sync"
+
+// Assignment is a variant allocated to a targeted user
+type Assignment struct {
+	// Variant is the name of the allocated variant
+	Variant string
+	// Reason is why the variant was allocated
+	Reason VariantAssignmentReason
+}
+
+// AssignmentStore persists variant assignments per feature and targeting ID. Set one with
+// Options.AssignmentStore to make assignments sticky: the FeatureManager returns the stored
+// variant before allocating, and stores every variant it allocates, so users keep their variant
+// when allocation percentages or seeds are changed mid-experiment. Only allocated variants are
+// stored; users assigned no variant or the default variant are allocated again on each evaluation.
+//
+// The targeting ID is the user ID, or the value of the allocation's targeting attribute.
+// A stored variant that the feature no longer defines is ignored. Errors are logged and the
+// variant is allocated as if no store were set.
+//
+// Implementations must be safe for concurrent use.
+type AssignmentStore interface {
+	// GetAssignment returns the stored assignment of the feature for the targeting ID, and
+	// whether one was found
+	GetAssignment(featureName string, targetingID string) (Assignment, bool, error)
+
+	// PutAssignment stores the assignment of the feature for the targeting ID
+	PutAssignment(featureName string, targetingID string, assignment Assignment) error
+}
+
+// MemoryAssignmentStore is an AssignmentStore that keeps assignments in memory, for tests and
+// single-instance applications. The zero value is ready to use.
+type MemoryAssignmentStore struct {
+	assignments sync.Map // assignmentKey -> Assignment
+}
+
+type assignmentKey struct {
+	featureName string
+	targetingID string
+}
+
+// GetAssignment returns the stored assignment of the feature for the targeting ID.
+//
+// Parameters:
+//   - featureName: The name of the feature
+//   - targetingID: The targeting ID the variant was allocated to
+//
+// Returns:
+//   - Assignment: The stored assignment
+//   - bool: Whether an assignment was found
+//   - error: Always nil
+func (s *MemoryAssignmentStore) GetAssignment(featureName string, targetingID string) (Assignment, bool, error) {
+	value, ok := s.assignments.Load(assignmentKey{featureName: featureName, targetingID: targetingID})
+	if !ok {
+		return Assignment{}, false, nil
+	}
+
+	return value.(Assignment), true, nil
+}
+
+// PutAssignment stores the assignment of the feature for the targeting ID.
+//
+// Parameters:
+//   - featureName: The name of the feature
+//   - targetingID: The targeting ID the variant was allocated to
+//   - assignment: The allocated variant
+//
+// Returns:
+//   - error: Always nil
+func (s *MemoryAssignmentStore) PutAssignment(featureName string, targetingID string, assignment Assignment) error {
+	s.assignments.Store(assignmentKey{featureName: featureName, targetingID: targetingID}, assignment)
+	return nil
+}
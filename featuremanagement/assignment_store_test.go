@@ -0,0 +1,120 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func stickyFlag(treatmentTo float64) FeatureFlag {
+	return FeatureFlag{
+		ID:       "Checkout",
+		Enabled:  true,
+		Variants: []VariantDefinition{{Name: "Control"}, {Name: "Treatment"}},
+		Allocation: &VariantAllocation{
+			DefaultWhenEnabled: "Control",
+			Percentile:         []PercentileAllocation{{Variant: "Treatment", From: 0, To: treatmentTo}},
+		},
+	}
+}
+
+func TestAssignmentStore(t *testing.T) {
+	store := &MemoryAssignmentStore{}
+	before, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{"Checkout": stickyFlag(50)}), &Options{AssignmentStore: store})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	assigned := map[string]string{}
+	for i := 0; i < 100; i++ {
+		userID := fmt.Sprintf("user-%d", i)
+		result, err := before.Evaluate("Checkout", TargetingContext{UserID: userID})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		assigned[userID] = result.Variant.Name
+	}
+
+	// Shrinking the treatment range doesn't move users who were already allocated
+	after, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{"Checkout": stickyFlag(0)}), &Options{AssignmentStore: store})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	for userID, variant := range assigned {
+		result, err := after.Evaluate("Checkout", TargetingContext{UserID: userID})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Variant.Name != variant {
+			t.Errorf("Expected %s to keep %s, got %s", userID, variant, result.Variant.Name)
+		}
+		if variant == "Treatment" && result.VariantAssignmentReason != VariantAssignmentReasonPercentile {
+			t.Errorf("Expected the stored reason Percentile, got %s", result.VariantAssignmentReason)
+		}
+	}
+
+	// Default assignments are not stored
+	for userID, variant := range assigned {
+		_, found, _ := store.GetAssignment("Checkout", userID)
+		if found != (variant == "Treatment") {
+			t.Errorf("Expected stored=%v for %s with %s", variant == "Treatment", userID, variant)
+		}
+	}
+}
+
+func TestAssignmentStoreStaleVariant(t *testing.T) {
+	store := &MemoryAssignmentStore{}
+	_ = store.PutAssignment("Checkout", "Alice", Assignment{Variant: "Removed", Reason: VariantAssignmentReasonUser})
+
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{"Checkout": stickyFlag(100)}), &Options{AssignmentStore: store})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	result, err := manager.Evaluate("Checkout", TargetingContext{UserID: "Alice"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Variant.Name != "Treatment" {
+		t.Errorf("Expected a stale assignment to be reallocated, got %s", result.Variant.Name)
+	}
+	if stored, _, _ := store.GetAssignment("Checkout", "Alice"); stored.Variant != "Treatment" {
+		t.Errorf("Expected the reallocated variant to be stored, got %s", stored.Variant)
+	}
+}
+
+type failingAssignmentStore struct {
+	puts int
+}
+
+func (s *failingAssignmentStore) GetAssignment(string, string) (Assignment, bool, error) {
+	return Assignment{}, false, errors.New("store unavailable")
+}
+
+func (s *failingAssignmentStore) PutAssignment(string, string, Assignment) error {
+	s.puts++
+	return nil
+}
+
+func TestAssignmentStoreError(t *testing.T) {
+	store := &failingAssignmentStore{}
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{"Checkout": stickyFlag(100)}), &Options{AssignmentStore: store})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	result, err := manager.Evaluate("Checkout", TargetingContext{UserID: "Alice"})
+	if err != nil {
+		t.Fatalf("Expected store errors not to fail evaluation, got %v", err)
+	}
+	if result.Variant.Name != "Treatment" {
+		t.Errorf("Expected allocation to proceed, got %s", result.Variant.Name)
+	}
+	if store.puts != 0 {
+		t.Error("Expected no assignment to be stored after a failed lookup")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// BatchOptions configures a batch evaluation
+type BatchOptions struct {
+	// FeatureNames limits the batch to the named features.
+	// When empty, every feature supplied by the provider is evaluated.
+	FeatureNames []string
+
+	// Concurrency is the maximum number of features evaluated in parallel.
+	// Defaults to GOMAXPROCS. Use 1 to evaluate features sequentially.
+	Concurrency int
+}
+
+// BatchResult contains the outcome of evaluating a single feature in a batch
+type BatchResult struct {
+	// FeatureName is the name of the evaluated feature
+	FeatureName string
+	// Result is the evaluation result, valid when Err is nil
+	Result EvaluationResult
+	// Err is the error that occurred while evaluating this feature, if any
+	Err error
+}
+
+// EvaluateAll evaluates a set of features for the given app context, running independent
+// evaluations in parallel. This is useful when custom filters perform I/O, so that one slow
+// filter doesn't serialize the whole batch.
+//
+// Errors are isolated per feature: a failing feature is reported in its BatchResult and doesn't
+// affect the others. When the context is cancelled, features that haven't started are reported
+// with the context's error; evaluations already in progress run to completion.
+//
+// Parameters:
+//   - ctx: A context controlling cancellation of the batch
+//   - appContext: An optional context object for contextual evaluation
+//   - options: Optional batch configuration, such as the features to evaluate and the degree of parallelism
+//
+// Returns:
+//   - []BatchResult: One result per feature, in the order the features were requested or supplied by the provider
+func (fm *FeatureManager) EvaluateAll(ctx context.Context, appContext any, options *BatchOptions) []BatchResult {
+	if options == nil {
+		options = &BatchOptions{}
+	}
+
+	featureNames := options.FeatureNames
+	if len(featureNames) == 0 {
+		names, err := fm.GetFeatureNames()
+		if err != nil {
+			fm.reportError("", err)
+		}
+		featureNames = names
+	}
+
+	concurrency := options.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	results := make([]BatchResult, len(featureNames))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, featureName := range featureNames {
+		results[i].FeatureName = featureName
+
+		// Wait for a free slot unless the batch is cancelled
+		select {
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		case semaphore <- struct{}{}:
+		}
+
+		// Both cases may be ready at once, so check for cancellation again
+		if err := ctx.Err(); err != nil {
+			<-semaphore
+			results[i].Err = err
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i].Result, results[i].Err = fm.evaluate(featureName, appContext)
+			results[i].Result.Feature = cloneFeature(results[i].Result.Feature)
+		}()
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,118 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowFilter blocks each evaluation until released and tracks the peak concurrency
+type slowFilter struct {
+	release chan struct{}
+	active  atomic.Int32
+	peak    atomic.Int32
+}
+
+func (f *slowFilter) Name() string {
+	return "Slow"
+}
+
+func (f *slowFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appCtx any) (bool, error) {
+	active := f.active.Add(1)
+	defer f.active.Add(-1)
+	for {
+		peak := f.peak.Load()
+		if active <= peak || f.peak.CompareAndSwap(peak, active) {
+			break
+		}
+	}
+
+	<-f.release
+	if evalCtx.Parameters["fail"] == true {
+		return false, errors.New("filter failed")
+	}
+	return true, nil
+}
+
+func slowFlag(id string, fail bool) FeatureFlag {
+	return FeatureFlag{
+		ID:      id,
+		Enabled: true,
+		Conditions: &Conditions{
+			ClientFilters: []ClientFilter{{Name: "Slow", Parameters: map[string]any{"fail": fail}}},
+		},
+	}
+}
+
+func TestEvaluateAll(t *testing.T) {
+	filter := &slowFilter{release: make(chan struct{})}
+	provider := &mockFeatureFlagProvider{
+		featureFlags: []FeatureFlag{
+			slowFlag("A", false),
+			slowFlag("B", true),
+			slowFlag("C", false),
+			slowFlag("D", false),
+		},
+	}
+
+	manager, err := NewFeatureManager(provider, &Options{Filters: []FeatureFilter{filter}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	// Release the filters once two evaluations are blocked concurrently
+	go func() {
+		for filter.active.Load() < 2 {
+			time.Sleep(time.Millisecond)
+		}
+		close(filter.release)
+	}()
+
+	results := manager.EvaluateAll(context.Background(), nil, &BatchOptions{Concurrency: 2})
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 results, got %d", len(results))
+	}
+
+	for i, name := range []string{"A", "B", "C", "D"} {
+		result := results[i]
+		if result.FeatureName != name {
+			t.Errorf("Expected result %d to be %s, got %s", i, name, result.FeatureName)
+		}
+		if name == "B" {
+			if result.Err == nil {
+				t.Error("Expected an isolated error for B")
+			}
+			continue
+		}
+		if result.Err != nil || !result.Result.Enabled {
+			t.Errorf("Expected %s to be enabled, got %v, %v", name, result.Result.Enabled, result.Err)
+		}
+	}
+
+	if peak := filter.peak.Load(); peak != 2 {
+		t.Errorf("Expected at most 2 concurrent evaluations, got %d", peak)
+	}
+}
+
+func TestEvaluateAllCancelled(t *testing.T) {
+	provider := &mockFeatureFlagProvider{featureFlags: createTestFeatureFlags()}
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := manager.EvaluateAll(ctx, nil, &BatchOptions{FeatureNames: []string{"BooleanTrue", "Minimal"}})
+	for _, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("Expected %s to be cancelled, got %v", result.FeatureName, result.Err)
+		}
+	}
+}
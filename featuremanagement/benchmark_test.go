@@ -0,0 +1,124 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"testing"
+)
+
+func newBenchmarkFeatureManager(b *testing.B) *FeatureManager {
+	b.Helper()
+
+	provider := &mockFeatureFlagProvider{
+		featureFlags: []FeatureFlag{
+			{ID: "NoFilters", Enabled: true},
+			{
+				ID:      "Targeting",
+				Enabled: true,
+				Conditions: &Conditions{
+					ClientFilters: []ClientFilter{
+						{
+							Name: "Microsoft.Targeting",
+							Parameters: map[string]any{
+								"Audience": map[string]any{
+									"Users": []any{"Alice"},
+									"Groups": []any{
+										map[string]any{"Name": "Stage1", "RolloutPercentage": 50},
+									},
+									"DefaultRolloutPercentage": 25,
+									"Exclusion": map[string]any{
+										"Users": []any{"Dave"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				ID:      "TimeWindow",
+				Enabled: true,
+				Conditions: &Conditions{
+					ClientFilters: []ClientFilter{
+						{
+							Name: "Microsoft.TimeWindow",
+							Parameters: map[string]any{
+								"Start": "Thu, 29 Jun 2023 07:00:00 GMT",
+								"End":   "Sat, 28 Jun 3023 06:05:00 GMT",
+							},
+						},
+					},
+				},
+			},
+			{
+				ID:      "Variants",
+				Enabled: true,
+				Variants: []VariantDefinition{
+					{Name: "Small", ConfigurationValue: "300px"},
+					{Name: "Big", ConfigurationValue: "600px"},
+				},
+				Allocation: &VariantAllocation{
+					DefaultWhenEnabled: "Small",
+					User:               []UserAllocation{{Variant: "Big", Users: []string{"Alice"}}},
+					Percentile: []PercentileAllocation{
+						{Variant: "Small", From: 0, To: 50},
+						{Variant: "Big", From: 50, To: 100},
+					},
+					Seed: "benchmark",
+				},
+			},
+		},
+	}
+
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		b.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	return manager
+}
+
+func BenchmarkIsEnabled(b *testing.B) {
+	manager := newBenchmarkFeatureManager(b)
+	targetingContext := TargetingContext{UserID: "Bob", Groups: []string{"Stage1"}}
+
+	b.Run("NoFilters", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := manager.IsEnabled("NoFilters"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Targeting", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := manager.IsEnabledWithAppContext("Targeting", targetingContext); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("TimeWindow", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := manager.IsEnabled("TimeWindow"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkGetVariant(b *testing.B) {
+	manager := newBenchmarkFeatureManager(b)
+	targetingContext := TargetingContext{UserID: "Bob"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := manager.GetVariant("Variants", targetingContext); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Percentile bucketing is identical in every feature management SDK, so that users keep their
+// cohorts when services move between SDKs. The constants below publish how a bucket is computed;
+// testdata/bucketing/vectors.json holds regression vectors computed with them by this SDK.
+//
+// A user is bucketed by joining the user ID and the parts of a hint with AudienceContextIDSeparator
+// into the audience context ID, hashing its UTF-8 bytes with BucketingHashAlgorithm, reading the
+// first ContextMarkerSize bytes of the hash as a little-endian unsigned integer, the context
+// marker, and scaling it by 100 / MaxContextMarker into a percentile. See ComputeBucket for the
+// hints used by the targeting filter and variant allocation.
+const (
+	// AudienceContextIDSeparator separates the user ID and the parts of the hint in the audience
+	// context ID, for example "Aiden\nComplexTargeting\nStage2"
+	AudienceContextIDSeparator = "\n"
+	// AllocationHint is the first part of the hint of variant percentile allocations without a
+	// seed, followed by the feature name
+	AllocationHint = "allocation"
+	// ExclusionHint is the last part of the hint of the percentile exclusions of the targeting
+	// filter, which follows the feature name and an empty part. The empty part keeps the hint
+	// apart from the hints of group rollouts, including that of a group named ExclusionHint.
+	// Percentile exclusions are specific to this SDK.
+	ExclusionHint = "exclusion"
+	// BucketingHashAlgorithm is the hash of the audience context ID
+	BucketingHashAlgorithm = "SHA-256"
+	// ContextMarkerSize is the number of leading bytes of the hash forming the context marker
+	ContextMarkerSize = 4
+	// MaxContextMarker is the largest context marker, which falls in the percentile 100
+	MaxContextMarker = math.MaxUint32
+
+	// ContextMarkerSize64 is the number of leading bytes of the hash forming the context marker of
+	// high resolution buckets, which are specific to this SDK; see ComputeBucket64
+	ContextMarkerSize64 = 8
+	// MaxContextMarker64 is the largest context marker of high resolution buckets
+	MaxContextMarker64 = math.MaxUint64
+)
+
+// ContextMarker returns the context marker of a user for the given hint, the integer that
+// ComputeBucket scales into a percentile, to compare bucketing with other implementations.
+//
+// Parameters:
+//   - userID: The ID of the user being targeted
+//   - hint: The parts of the hint, as for ComputeBucket
+//
+// Returns:
+//   - uint32: The first ContextMarkerSize bytes of the hash of the audience context ID
+func ContextMarker(userID string, hint ...string) uint32 {
+	return hashAudienceContextID(userID, hint...)
+}
+
+// bucketingVector is a reference bucket computed with the bucketing algorithm
+type bucketingVector struct {
+	userID        string
+	hint          []string
+	contextMarker uint32
+}
+
+// bucketingVectors cover the hint conventions of targeting and allocation, and non-ASCII IDs.
+// The full set is in testdata/bucketing/vectors.json.
+var bucketingVectors = []bucketingVector{
+	{userID: "Alice", hint: []string{"Beta"}, contextMarker: 3931645761},
+	{userID: "Aiden", hint: []string{"ComplexTargeting", "Stage2"}, contextMarker: 673467930},
+	{userID: "Alice", hint: []string{"allocation", "Experiment"}, contextMarker: 2075340189},
+	{userID: "Alice", hint: []string{"experiment-seed"}, contextMarker: 854230692},
+	{userID: "Zoë", hint: []string{"Beta"}, contextMarker: 1327651420},
+}
+
+// VerifyBucketing checks that percentile bucketing assigns reference users to the buckets the
+// published algorithm computes for them, so that a broken hash or build is caught before users
+// change cohorts.
+// Set Options.VerifyBucketing to run it when a FeatureManager is created.
+//
+// Returns:
+//   - error: An error describing the first mismatch, or nil if bucketing is consistent
+func VerifyBucketing() error {
+	for _, vector := range bucketingVectors {
+		if marker := hashAudienceContextID(vector.userID, vector.hint...); marker != vector.contextMarker {
+			return fmt.Errorf("inconsistent bucketing for user %q and hint %q: expected context marker %d, got %d",
+				vector.userID, strings.Join(vector.hint, "\\n"), vector.contextMarker, marker)
+		}
+	}
+
+	return nil
+}
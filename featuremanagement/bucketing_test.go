@@ -0,0 +1,288 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// bucketingTestVector is a regression vector of testdata/bucketing/vectors.json. The vectors were
+// generated by this SDK from the published algorithm rather than imported from the other SDKs, so
+// they catch changes to bucketing here but don't by themselves prove parity with other SDKs.
+type bucketingTestVector struct {
+	Description   string   `json:"description"`
+	UserID        string   `json:"user_id"`
+	Hint          []string `json:"hint"`
+	ContextMarker uint32   `json:"context_marker"`
+	Percentile    float64  `json:"percentile"`
+}
+
+// bucketingAlgorithm describes how the regression vectors were computed
+type bucketingAlgorithm struct {
+	AudienceContextIDSeparator string `json:"audience_context_id_separator"`
+	AllocationHint             string `json:"allocation_hint"`
+	Hash                       string `json:"hash"`
+	ContextMarkerSize          int    `json:"context_marker_size"`
+	ContextMarkerByteOrder     string `json:"context_marker_byte_order"`
+	MaxContextMarker           uint32 `json:"max_context_marker"`
+}
+
+type bucketingTestFile struct {
+	Algorithm bucketingAlgorithm    `json:"algorithm"`
+	Vectors   []bucketingTestVector `json:"vectors"`
+}
+
+func loadBucketingTestFile(t *testing.T) bucketingTestFile {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "bucketing", "vectors.json"))
+	if err != nil {
+		t.Fatalf("Failed to read bucketing vectors: %v", err)
+	}
+
+	var file bucketingTestFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("Failed to parse bucketing vectors: %v", err)
+	}
+	return file
+}
+
+func loadBucketingVectors(t *testing.T) []bucketingTestVector {
+	t.Helper()
+	return loadBucketingTestFile(t).Vectors
+}
+
+func TestBucketingAlgorithm(t *testing.T) {
+	expected := bucketingAlgorithm{
+		AudienceContextIDSeparator: AudienceContextIDSeparator,
+		AllocationHint:             AllocationHint,
+		Hash:                       BucketingHashAlgorithm,
+		ContextMarkerSize:          ContextMarkerSize,
+		ContextMarkerByteOrder:     "little-endian",
+		MaxContextMarker:           MaxContextMarker,
+	}
+	if actual := loadBucketingTestFile(t).Algorithm; actual != expected {
+		t.Errorf("Expected the regression vectors to be computed with %+v, got %+v", expected, actual)
+	}
+}
+
+func TestBucketingVectors(t *testing.T) {
+	for _, vector := range loadBucketingVectors(t) {
+		t.Run(vector.Description+"/"+vector.UserID, func(t *testing.T) {
+			if marker := ContextMarker(vector.UserID, vector.Hint...); marker != vector.ContextMarker {
+				t.Errorf("Expected context marker %d, got %d", vector.ContextMarker, marker)
+			}
+			if bucket := ComputeBucket(vector.UserID, vector.Hint...); math.Abs(bucket-vector.Percentile) > 1e-9 {
+				t.Errorf("Expected percentile %v, got %v", vector.Percentile, bucket)
+			}
+		})
+	}
+}
+
+func TestBucketingHintConventions(t *testing.T) {
+	// A percentile range ending just above the user's bucket must include them, and one ending at it must not
+	assertAllocated := func(t *testing.T, flag FeatureFlag, userID string, bucket float64) {
+		t.Helper()
+		for _, to := range []float64{bucket, math.Min(bucket+1e-6, 100)} {
+			flag.Allocation.Percentile = []PercentileAllocation{{Variant: "In", From: 0, To: to}}
+			manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{flag.ID: flag}), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			variant, err := manager.GetVariant(flag.ID, TargetingContext{UserID: userID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allocated := variant != nil && variant.Name == "In"; allocated != (to > bucket) {
+				t.Errorf("Expected allocated=%v for range [0, %v) and bucket %v", to > bucket, to, bucket)
+			}
+		}
+	}
+
+	variants := []VariantDefinition{{Name: "In"}, {Name: "Out"}}
+	t.Run("Default allocation seed", func(t *testing.T) {
+		flag := FeatureFlag{ID: "Experiment", Enabled: true, Variants: variants, Allocation: &VariantAllocation{DefaultWhenEnabled: "Out"}}
+		assertAllocated(t, flag, "Alice", ComputeBucket("Alice", "allocation", "Experiment"))
+	})
+	t.Run("Explicit allocation seed", func(t *testing.T) {
+		flag := FeatureFlag{ID: "Other", Enabled: true, Variants: variants, Allocation: &VariantAllocation{DefaultWhenEnabled: "Out", Seed: "experiment-seed"}}
+		assertAllocated(t, flag, "Alice", ComputeBucket("Alice", "experiment-seed"))
+	})
+}
+
+// TestBucketingParity runs the regression vectors through the targeting filter and variant
+// allocation, so that they fail if the hints of either diverge from the documented conventions. A vector with a hint of
+// one part is the default rollout of the feature it names, one of two parts starting with
+// AllocationHint is the allocation of the feature it names, and others are group rollouts.
+func TestBucketingParity(t *testing.T) {
+	variants := []VariantDefinition{{Name: "In"}, {Name: "Out"}}
+	for _, vector := range loadBucketingVectors(t) {
+		t.Run(vector.Description+"/"+vector.UserID, func(t *testing.T) {
+			// A range ending just above the bucket of the user must include them, and one ending at it must not
+			for _, to := range []float64{vector.Percentile, math.Min(vector.Percentile+1e-6, 100)} {
+				targeted := to > vector.Percentile
+				var flag FeatureFlag
+				var groups []string
+				switch {
+				case len(vector.Hint) == 1:
+					flag = FeatureFlag{ID: vector.Hint[0], Enabled: true, Conditions: &Conditions{ClientFilters: []ClientFilter{{
+						Name:       "Microsoft.Targeting",
+						Parameters: map[string]any{"Audience": map[string]any{"DefaultRolloutPercentage": to}},
+					}}}}
+				case vector.Hint[0] == AllocationHint:
+					flag = FeatureFlag{ID: vector.Hint[1], Enabled: true, Variants: variants, Allocation: &VariantAllocation{
+						DefaultWhenEnabled: "Out",
+						Percentile:         []PercentileAllocation{{Variant: "In", From: 0, To: to}},
+					}}
+				default:
+					groups = vector.Hint[1:]
+					flag = FeatureFlag{ID: vector.Hint[0], Enabled: true, Conditions: &Conditions{ClientFilters: []ClientFilter{{
+						Name: "Microsoft.Targeting",
+						Parameters: map[string]any{"Audience": map[string]any{
+							"Groups": []any{map[string]any{"Name": vector.Hint[1], "RolloutPercentage": to}},
+						}},
+					}}}}
+				}
+
+				manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{flag.ID: flag}), nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				result, err := manager.Evaluate(flag.ID, TargetingContext{UserID: vector.UserID, Groups: groups})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if flag.Allocation != nil {
+					if allocated := result.Variant != nil && result.Variant.Name == "In"; allocated != targeted {
+						t.Errorf("Expected allocated=%v for range [0, %v) and bucket %v", targeted, to, vector.Percentile)
+					}
+				} else if result.Enabled != targeted {
+					t.Errorf("Expected enabled=%v for rollout %v and bucket %v", targeted, to, vector.Percentile)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyBucketing(t *testing.T) {
+	if err := VerifyBucketing(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	vectors := loadBucketingVectors(t)
+	for _, embedded := range bucketingVectors {
+		found := false
+		for _, vector := range vectors {
+			if vector.UserID == embedded.userID && vector.ContextMarker == embedded.contextMarker {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Embedded vector for %q is missing from the regression vectors", embedded.userID)
+		}
+	}
+
+	if _, err := NewFeatureManager(NewBoolProvider(nil), &Options{VerifyBucketing: true}); err != nil {
+		t.Errorf("Expected verification to pass, got %v", err)
+	}
+}
+
+func TestComputeBucket64(t *testing.T) {
+	if marker := hashAudienceContextID64("Alice", "Beta"); marker != 16886289965647956401 {
+		t.Errorf("Expected context marker 16886289965647956401, got %d", marker)
+	}
+	if bucket := ComputeBucket64("Alice", "Beta"); math.Abs(bucket-91.54076132987844) > 1e-9 {
+		t.Errorf("Expected percentile 91.54076132987844, got %v", bucket)
+	}
+
+	// High resolution buckets refine the 32-bit buckets
+	for _, vector := range loadBucketingVectors(t) {
+		if bucket := ComputeBucket64(vector.UserID, vector.Hint...); math.Abs(bucket-vector.Percentile) > 100.0/MaxContextMarker {
+			t.Errorf("Expected the bucket of %q to be within a 32-bit bucket of %v, got %v", vector.UserID, vector.Percentile, bucket)
+		}
+	}
+}
+
+func TestHighResolutionBuckets(t *testing.T) {
+	// A range ending a fraction of a 32-bit bucket above the user's bucket must include them
+	bucket := ComputeBucket64("Alice", "Beta")
+	for _, to := range []float64{bucket - 1e-12, bucket + 1e-12} {
+		parameters := map[string]any{"Audience": map[string]any{"DefaultRolloutPercentage": to}}
+		evalCtx := FeatureFilterEvaluationContext{FeatureName: "Beta", Parameters: parameters}
+		enabled, err := (&TargetingFilter{HighResolutionBuckets: true}).Evaluate(evalCtx, TargetingContext{UserID: "Alice"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if enabled != (to > bucket) {
+			t.Errorf("Expected enabled=%v for rollout %v and bucket %v", to > bucket, to, bucket)
+		}
+
+		// The option of the feature manager applies to the default and to registered targeting filters
+		flag := FeatureFlag{ID: "Beta", Enabled: true, Conditions: &Conditions{
+			ClientFilters: []ClientFilter{{Name: "Microsoft.Targeting", Parameters: parameters}},
+		}}
+		for _, filters := range [][]FeatureFilter{nil, {&TargetingFilter{AllowAnonymous: true}}} {
+			manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{flag.ID: flag}), &Options{HighResolutionBuckets: true, Filters: filters})
+			if err != nil {
+				t.Fatal(err)
+			}
+			enabled, err := manager.IsEnabledWithAppContext(flag.ID, TargetingContext{UserID: "Alice"})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if enabled != (to > bucket) {
+				t.Errorf("With %d registered filters: expected enabled=%v for rollout %v and bucket %v", len(filters), to > bucket, to, bucket)
+			}
+		}
+	}
+
+	allocationBucket := ComputeBucket64("Alice", AllocationHint, "Experiment")
+	for _, to := range []float64{allocationBucket - 1e-12, allocationBucket + 1e-12} {
+		flag := FeatureFlag{ID: "Experiment", Enabled: true, Variants: []VariantDefinition{{Name: "In"}}, Allocation: &VariantAllocation{
+			Percentile: []PercentileAllocation{{Variant: "In", From: 0, To: to}},
+		}}
+		for _, strategy := range []AllocationStrategy{nil, DefaultAllocationStrategy{}, &DefaultAllocationStrategy{}} {
+			manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{flag.ID: flag}), &Options{HighResolutionBuckets: true, AllocationStrategy: strategy})
+			if err != nil {
+				t.Fatal(err)
+			}
+			variant, err := manager.GetVariant(flag.ID, TargetingContext{UserID: "Alice"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if allocated := variant != nil; allocated != (to > allocationBucket) {
+				t.Errorf("Strategy %T: expected allocated=%v for range [0, %v) and bucket %v", strategy, to > allocationBucket, to, allocationBucket)
+			}
+		}
+
+		name, _ := DefaultAllocationStrategy{HighResolutionBuckets: true}.Allocate(&flag, TargetingContext{UserID: "Alice"})
+		if allocated := name == "In"; allocated != (to > allocationBucket) {
+			t.Errorf("Expected the default strategy to allocate=%v for range [0, %v) and bucket %v", to > allocationBucket, to, allocationBucket)
+		}
+	}
+}
+
+func TestReachesPercentile64(t *testing.T) {
+	// Both markers round to a float64 bucket of exactly 50, but only the second one reaches it
+	below, above := uint64(1<<63-1), uint64(1<<63)
+	if bucket := (float64(below) / MaxContextMarker64) * 100; bucket != 50 {
+		t.Fatalf("Expected the float64 bucket to round to 50, got %v", bucket)
+	}
+	if reachesPercentile64(below, 50) {
+		t.Errorf("Expected marker %d to be below percentile 50", below)
+	}
+	if !reachesPercentile64(above, 50) {
+		t.Errorf("Expected marker %d to reach percentile 50", above)
+	}
+
+	if !reachesPercentile64(0, 0) || !reachesPercentile64(MaxContextMarker64, 100) || reachesPercentile64(MaxContextMarker64-1, 100) {
+		t.Error("Expected the bounds of the percentile range to be compared exactly")
+	}
+	if reachesPercentile64(1<<62, 50) || !reachesPercentile64(3<<62, 50) {
+		t.Error("Expected markers far from the percentile to be compared by their float64 bucket")
+	}
+}
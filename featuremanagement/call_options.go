@@ -0,0 +1,137 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"fmt"
+)
+
+// EvaluationOption customizes a single evaluation made with IsEnabledContext or EvaluateContext.
+type EvaluationOption func(*evaluationOptions)
+
+// evaluationOptions holds the options of a single evaluation
+type evaluationOptions struct {
+	ctx              context.Context
+	appContext       any
+	hasDefault       bool
+	defaultEnabled   bool
+	withoutTelemetry bool
+}
+
+// WithAppContext evaluates the feature for the given app context.
+//
+// Parameters:
+//   - appContext: The context object for contextual evaluation
+//
+// Returns:
+//   - EvaluationOption: The option to pass to the evaluation
+func WithAppContext(appContext any) EvaluationOption {
+	return func(o *evaluationOptions) {
+		o.appContext = appContext
+	}
+}
+
+// WithTargeting evaluates the feature for the given targeting context, such as a TargetingContext
+// or an application type implementing TargetingContexter.
+//
+// Parameters:
+//   - targetingContext: The user or session to evaluate the feature for
+//
+// Returns:
+//   - EvaluationOption: The option to pass to the evaluation
+func WithTargeting(targetingContext TargetingContexter) EvaluationOption {
+	return WithAppContext(targetingContext)
+}
+
+// WithDefault makes an evaluation that fails return the given enabled state instead of an error,
+// for example when the feature isn't defined. The error is passed to Options.OnError, or logged.
+//
+// Parameters:
+//   - enabled: The state of the feature when the evaluation fails
+//
+// Returns:
+//   - EvaluationOption: The option to pass to the evaluation
+func WithDefault(enabled bool) EvaluationOption {
+	return func(o *evaluationOptions) {
+		o.hasDefault = true
+		o.defaultEnabled = enabled
+	}
+}
+
+// WithoutTelemetry skips publishing telemetry events for the evaluation, for example for
+// evaluations made by health checks or background jobs that shouldn't count as exposures.
+//
+// Returns:
+//   - EvaluationOption: The option to pass to the evaluation
+func WithoutTelemetry() EvaluationOption {
+	return func(o *evaluationOptions) {
+		o.withoutTelemetry = true
+	}
+}
+
+// IsEnabledContext determines if a feature flag is enabled, with options for this call only:
+//
+//	enabled, _ := manager.IsEnabledContext(ctx, "Beta",
+//		featuremanagement.WithTargeting(user),
+//		featuremanagement.WithDefault(false),
+//		featuremanagement.WithoutTelemetry())
+//
+// The evaluation ID is taken from the context when it carries one; see WithEvaluationID.
+//
+// Parameters:
+//   - ctx: The context of the call; an evaluation doesn't start once it is done
+//   - featureName: The name of the feature to evaluate
+//   - options: The options of the evaluation
+//
+// Returns:
+//   - bool: true if the feature is enabled, false otherwise
+//   - error: An error if the feature flag cannot be found or evaluated and no default is set
+func (fm *FeatureManager) IsEnabledContext(ctx context.Context, featureName string, options ...EvaluationOption) (bool, error) {
+	res, err := fm.evaluateContext(ctx, featureName, options)
+	return res.Enabled, err
+}
+
+// EvaluateContext evaluates a feature flag and returns the full result, with options for this
+// call only. See IsEnabledContext.
+//
+// Parameters:
+//   - ctx: The context of the call; an evaluation doesn't start once it is done
+//   - featureName: The name of the feature to evaluate
+//   - options: The options of the evaluation
+//
+// Returns:
+//   - EvaluationResult: The state of the feature, its assigned variant and how it was assigned
+//   - error: An error if the feature flag cannot be found or evaluated and no default is set
+func (fm *FeatureManager) EvaluateContext(ctx context.Context, featureName string, options ...EvaluationOption) (EvaluationResult, error) {
+	res, err := fm.evaluateContext(ctx, featureName, options)
+	res.Feature = cloneFeature(res.Feature)
+	return res, err
+}
+
+// evaluateContext evaluates a feature flag with the options of a single call
+func (fm *FeatureManager) evaluateContext(ctx context.Context, featureName string, options []EvaluationOption) (EvaluationResult, error) {
+	callOptions := &evaluationOptions{ctx: ctx}
+	for _, option := range options {
+		option(callOptions)
+	}
+
+	var res EvaluationResult
+	err := ctx.Err()
+	if err != nil {
+		err = fmt.Errorf("failed to evaluate feature %s: %w", featureName, err)
+	} else {
+		evaluator := fm.intercept(func(featureName string, appContext any) (EvaluationResult, error) {
+			return fm.evaluateFlagWithOptions(featureName, appContext, callOptions)
+		})
+		res, err = evaluator(featureName, callOptions.appContext)
+	}
+
+	if err != nil && callOptions.hasDefault {
+		fm.reportError(featureName, err)
+		return EvaluationResult{Enabled: callOptions.defaultEnabled, EvaluationID: res.EvaluationID}, nil
+	}
+
+	return res, err
+}
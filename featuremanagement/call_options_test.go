@@ -0,0 +1,70 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEvaluationOptions(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {
+			Enabled: true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{
+				Name:       "Microsoft.Targeting",
+				Parameters: map[string]any{"Audience": map[string]any{"Users": []any{"Alice"}}},
+			}}},
+			Telemetry: &Telemetry{Enabled: true},
+		},
+	})
+	publisher := &recordingPublisher{}
+	var reported []error
+	manager, err := NewFeatureManager(provider, &Options{
+		TelemetryPublisher: publisher,
+		OnError: func(featureName string, err error) {
+			reported = append(reported, err)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	ctx := context.Background()
+	if enabled, err := manager.IsEnabledContext(ctx, "Beta", WithTargeting(TargetingContext{UserID: "Alice"})); err != nil || !enabled {
+		t.Errorf("Expected Beta to be enabled for Alice, got %v, %v", enabled, err)
+	}
+	if count := publisher.count(EventFeatureEvaluation); count != 1 {
+		t.Errorf("Expected 1 telemetry event, got %d", count)
+	}
+
+	if enabled, err := manager.IsEnabledContext(ctx, "Beta", WithAppContext(TargetingContext{UserID: "Alice"}), WithoutTelemetry()); err != nil || !enabled {
+		t.Errorf("Expected Beta to be enabled for Alice, got %v, %v", enabled, err)
+	}
+	if count := publisher.count(EventFeatureEvaluation); count != 1 {
+		t.Errorf("Expected telemetry to be skipped, got %d events", count)
+	}
+
+	if enabled, err := manager.IsEnabledContext(ctx, "Missing", WithDefault(true)); err != nil || !enabled {
+		t.Errorf("Expected the default for a missing feature, got %v, %v", enabled, err)
+	}
+	if len(reported) != 1 {
+		t.Errorf("Expected the failed evaluation to be reported, got %v", reported)
+	}
+	if _, err := manager.IsEnabledContext(ctx, "Missing"); err == nil {
+		t.Error("Expected an error without a default")
+	}
+
+	result, err := manager.EvaluateContext(WithEvaluationID(ctx, "request-1"), "Beta", WithTargeting(TargetingContext{UserID: "Bob"}))
+	if err != nil || result.Enabled || result.EvaluationID != "request-1" {
+		t.Errorf("Expected Beta disabled for Bob with the context's evaluation ID, got %+v, %v", result, err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := manager.IsEnabledContext(cancelled, "Beta"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+}
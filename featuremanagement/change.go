@@ -0,0 +1,187 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// RefreshListeners holds the callbacks registered with a RefreshNotifier. Its zero value is ready
+// to use and it is safe for concurrent use.
+type RefreshListeners struct {
+	mu        sync.Mutex
+	nextID    int
+	callbacks map[int]func()
+}
+
+// Add registers a callback, returning a function that unregisters it.
+//
+// Parameters:
+//   - callback: The function to call on Notify
+//
+// Returns:
+//   - func(): A function unregistering the callback
+func (l *RefreshListeners) Add(callback func()) func() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.callbacks == nil {
+		l.callbacks = make(map[int]func())
+	}
+	id := l.nextID
+	l.nextID++
+	l.callbacks[id] = callback
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.callbacks, id)
+	}
+}
+
+// Notify calls every registered callback on the calling goroutine.
+func (l *RefreshListeners) Notify() {
+	l.mu.Lock()
+	callbacks := make([]func(), 0, len(l.callbacks))
+	for _, callback := range l.callbacks {
+		callbacks = append(callbacks, callback)
+	}
+	l.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+// FeatureChange describes a change to the definition of a feature
+type FeatureChange struct {
+	// FeatureName is the name of the changed feature
+	FeatureName string
+	// Previous is the definition before the change, or nil if the feature was added
+	Previous *FeatureFlag
+	// Current is the definition after the change, or nil if the feature was removed
+	Current *FeatureFlag
+}
+
+// featureSubscription is a callback registered for the changes of a feature
+type featureSubscription struct {
+	featureName string
+	callback    func(FeatureChange)
+	current     *FeatureFlag
+	fingerprint string
+}
+
+// changeNotifier diffs the subscribed feature flags after each provider refresh
+type changeNotifier struct {
+	mu            sync.Mutex
+	nextID        int
+	subscriptions map[int]*featureSubscription
+	unregister    func()
+	closed        bool
+}
+
+// close unregisters from the provider; later subscriptions never report changes
+func (n *changeNotifier) close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.unregister != nil {
+		n.unregister()
+		n.unregister = nil
+	}
+	n.closed = true
+}
+
+// OnFeatureChanged registers a callback called when the definition of a feature changes after
+// the provider refreshes its feature flags, including when the feature is added or removed.
+// Definitions are compared by content, so a refresh that reloads an identical definition doesn't
+// call the callback. Long-lived components, such as worker pools or caches, can use it to
+// reconfigure as soon as a change is loaded rather than on their next evaluation.
+//
+// The provider must implement RefreshNotifier; other providers never report changes, and neither
+// does a closed feature manager. Callbacks are called on the goroutine of the refresh and must not
+// block for long.
+//
+// Parameters:
+//   - featureName: The name of the feature to watch
+//   - callback: The function called with each change of the feature
+//
+// Returns:
+//   - func(): A function unregistering the callback
+func (fm *FeatureManager) OnFeatureChanged(featureName string, callback func(FeatureChange)) func() {
+	notifier := fm.changes
+	current, fingerprint := fm.currentDefinition(featureName)
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+
+	if notifier.subscriptions == nil {
+		notifier.subscriptions = make(map[int]*featureSubscription)
+	}
+	if refreshNotifier, ok := fm.featureProvider.(RefreshNotifier); ok && notifier.unregister == nil && !notifier.closed {
+		notifier.unregister = refreshNotifier.OnRefresh(fm.notifyFeatureChanges)
+	}
+
+	id := notifier.nextID
+	notifier.nextID++
+	notifier.subscriptions[id] = &featureSubscription{
+		featureName: featureName,
+		callback:    callback,
+		current:     current,
+		fingerprint: fingerprint,
+	}
+
+	return func() {
+		notifier.mu.Lock()
+		defer notifier.mu.Unlock()
+		delete(notifier.subscriptions, id)
+	}
+}
+
+// notifyFeatureChanges calls the callbacks of the subscribed features whose definition changed
+func (fm *FeatureManager) notifyFeatureChanges() {
+	notifier := fm.changes
+	definitions := make(map[string]*FeatureFlag)
+	fingerprints := make(map[string]string)
+
+	var changes []func()
+	notifier.mu.Lock()
+	for _, subscription := range notifier.subscriptions {
+		name := subscription.featureName
+		if _, ok := fingerprints[name]; !ok {
+			definitions[name], fingerprints[name] = fm.currentDefinition(name)
+		}
+		if fingerprints[name] == subscription.fingerprint {
+			continue
+		}
+
+		change := FeatureChange{FeatureName: name, Previous: cloneFeature(subscription.current), Current: cloneFeature(definitions[name])}
+		subscription.current, subscription.fingerprint = definitions[name], fingerprints[name]
+		callback := subscription.callback
+		changes = append(changes, func() { callback(change) })
+	}
+	notifier.mu.Unlock()
+
+	for _, notify := range changes {
+		notify()
+	}
+}
+
+// currentDefinition returns the provider's definition of a feature and a fingerprint of its
+// content, or nil and an empty fingerprint if the provider doesn't define it
+func (fm *FeatureManager) currentDefinition(featureName string) (*FeatureFlag, string) {
+	featureFlag, err := fm.getFeatureFlag(featureName)
+	if err != nil {
+		return nil, ""
+	}
+
+	data, err := json.Marshal(featureFlag)
+	if err != nil {
+		// Definitions that can't be encoded as JSON are compared by their encoding error
+		return &featureFlag, err.Error()
+	}
+
+	return &featureFlag, string(data)
+}
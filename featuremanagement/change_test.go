@@ -0,0 +1,77 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"sync"
+	"testing"
+)
+
+// notifyingProvider is a provider whose feature flags can be replaced, notifying its listeners
+type notifyingProvider struct {
+	mu        sync.Mutex
+	flags     *StaticProvider
+	listeners RefreshListeners
+}
+
+func (p *notifyingProvider) set(flags map[string]FeatureFlag) {
+	p.mu.Lock()
+	p.flags = NewStaticProvider(flags)
+	p.mu.Unlock()
+	p.listeners.Notify()
+}
+
+func (p *notifyingProvider) GetFeatureFlag(name string) (FeatureFlag, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.flags.GetFeatureFlag(name)
+}
+
+func (p *notifyingProvider) GetFeatureFlags() ([]FeatureFlag, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.flags.GetFeatureFlags()
+}
+
+func (p *notifyingProvider) OnRefresh(callback func()) func() {
+	return p.listeners.Add(callback)
+}
+
+func TestOnFeatureChanged(t *testing.T) {
+	provider := &notifyingProvider{flags: NewStaticProvider(map[string]FeatureFlag{
+		"Beta":  {Enabled: false},
+		"Other": {Enabled: false},
+	})}
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	var changes []FeatureChange
+	unsubscribe := manager.OnFeatureChanged("Beta", func(change FeatureChange) {
+		changes = append(changes, change)
+	})
+
+	// Reloading identical definitions and changing other features isn't a change
+	provider.set(map[string]FeatureFlag{"Beta": {Enabled: false}, "Other": {Enabled: true}})
+	if len(changes) != 0 {
+		t.Fatalf("Expected no change, got %+v", changes)
+	}
+
+	provider.set(map[string]FeatureFlag{"Beta": {Enabled: true}})
+	if len(changes) != 1 || changes[0].FeatureName != "Beta" || changes[0].Previous.Enabled || !changes[0].Current.Enabled {
+		t.Fatalf("Expected Beta to change to enabled, got %+v", changes)
+	}
+
+	provider.set(map[string]FeatureFlag{})
+	if len(changes) != 2 || changes[1].Previous == nil || changes[1].Current != nil {
+		t.Fatalf("Expected Beta to be removed, got %+v", changes)
+	}
+
+	unsubscribe()
+	provider.set(map[string]FeatureFlag{"Beta": {Enabled: true}})
+	if len(changes) != 2 {
+		t.Errorf("Expected no change after unsubscribing, got %+v", changes)
+	}
+}
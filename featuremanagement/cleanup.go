@@ -0,0 +1,195 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CleanupReason explains why a feature flag is a candidate for removal
+type CleanupReason string
+
+const (
+	// CleanupReasonFullyEnabled indicates the feature is enabled for everyone, without variants,
+	// and every evaluation during the observation period returned enabled: the flag can be removed
+	// and its code path kept
+	CleanupReasonFullyEnabled CleanupReason = "FullyEnabled"
+	// CleanupReasonDisabled indicates the feature is disabled and has no variants, so it gates
+	// code that never runs
+	CleanupReasonDisabled CleanupReason = "Disabled"
+	// CleanupReasonZeroRollout indicates the feature is enabled but its targeting filters target
+	// no user, group or percentage, so it is disabled for everyone
+	CleanupReasonZeroRollout CleanupReason = "ZeroRollout"
+	// CleanupReasonNoTelemetry indicates the feature has neither telemetry nor variants, so it is
+	// not an experiment and nothing measures its impact
+	CleanupReasonNoTelemetry CleanupReason = "NoTelemetry"
+	// CleanupReasonNeverEvaluated indicates the feature is defined but code didn't evaluate it
+	// during the observation period
+	CleanupReasonNeverEvaluated CleanupReason = "NeverEvaluated"
+)
+
+// DefaultCleanupObservationPeriod is the observation period of CleanupSuggestions when
+// CleanupOptions.ObservationPeriod is zero
+const DefaultCleanupObservationPeriod = 30 * 24 * time.Hour
+
+// CleanupOptions configures CleanupSuggestions.
+type CleanupOptions struct {
+	// ObservationPeriod is how long the feature manager must have tracked evaluations before
+	// suggestions based on them, FullyEnabled and NeverEvaluated, are made. Defaults to
+	// DefaultCleanupObservationPeriod.
+	ObservationPeriod time.Duration
+}
+
+// CleanupSuggestion describes a feature flag that is a candidate for removal.
+type CleanupSuggestion struct {
+	// FeatureName is the name of the feature
+	FeatureName string
+	// Reasons lists why the flag is a candidate, in the order of the CleanupReason constants
+	Reasons []CleanupReason
+	// Details explains each reason, in the same order
+	Details []string
+	// EvaluationCount is the number of evaluations since tracking started
+	EvaluationCount uint64
+	// LastEvaluated is the time of the most recent evaluation, or the zero time if never evaluated
+	LastEvaluated time.Time
+}
+
+// CleanupSuggestions inspects the feature flags served and the evaluations recorded since the
+// feature manager was created, and returns the flags that are likely safe to remove from code
+// and configuration: flags fully enabled for the observation period, disabled flags, flags
+// rolled out to no one, flags with neither telemetry nor variants, and flags never evaluated.
+// The suggestions are sorted by feature name, and can be serialized as JSON for tooling and
+// dashboards. Evaluations are only tracked in memory, so the suggestions reflect a single instance.
+//
+// Parameters:
+//   - options: The observation period of suggestions based on evaluations
+//
+// Returns:
+//   - []CleanupSuggestion: The flags that are candidates for removal
+func (fm *FeatureManager) CleanupSuggestions(options CleanupOptions) []CleanupSuggestion {
+	period := options.ObservationPeriod
+	if period <= 0 {
+		period = DefaultCleanupObservationPeriod
+	}
+	observed := time.Since(fm.tracker.since) >= period
+
+	var suggestions []CleanupSuggestion
+	seen := make(map[string]bool)
+	for flag := range fm.flags() {
+		if seen[flag.ID] {
+			continue
+		}
+		seen[flag.ID] = true
+
+		lifecycle := fm.tracker.lifecycle(flag.ID)
+		suggestion := CleanupSuggestion{
+			FeatureName:     flag.ID,
+			EvaluationCount: lifecycle.EnabledCount + lifecycle.DisabledCount,
+			LastEvaluated:   lifecycle.LastEvaluated,
+		}
+		add := func(reason CleanupReason, detail string) {
+			suggestion.Reasons = append(suggestion.Reasons, reason)
+			suggestion.Details = append(suggestion.Details, detail)
+		}
+
+		hasVariants := len(flag.Variants) > 0
+		switch {
+		case !flag.Enabled && !hasVariants:
+			add(CleanupReasonDisabled, "the feature is disabled and has no variants")
+		case observed && !hasVariants && enabledForEveryone(flag) && lifecycle.DisabledCount == 0:
+			add(CleanupReasonFullyEnabled, fmt.Sprintf("the feature has been enabled for everyone for at least %s", period))
+		case flag.Enabled && targetsNobody(flag):
+			add(CleanupReasonZeroRollout, "the targeting filters of the feature target no users, groups or percentage")
+		}
+		if !hasVariants && (flag.Telemetry == nil || !flag.Telemetry.Enabled) {
+			add(CleanupReasonNoTelemetry, "the feature has neither telemetry nor variants, so it is not an experiment")
+		}
+		if observed && lifecycle.Status == FeatureLifecycleStatusNeverEvaluated {
+			add(CleanupReasonNeverEvaluated, fmt.Sprintf("the feature hasn't been evaluated for %s", period))
+		}
+
+		if len(suggestion.Reasons) > 0 {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].FeatureName < suggestions[j].FeatureName
+	})
+
+	return suggestions
+}
+
+// enabledForEveryone reports whether an enabled feature flag evaluates to enabled for every user:
+// it has no filters, or its filters are targeting filters rolled out to 100% without exclusions
+func enabledForEveryone(flag FeatureFlag) bool {
+	if !flag.Enabled {
+		return false
+	}
+	if flag.Conditions == nil || len(flag.Conditions.ClientFilters) == 0 {
+		return true
+	}
+
+	requireAll := flag.Conditions.RequirementType == RequirementTypeAll
+	for _, filter := range flag.Conditions.ClientFilters {
+		audience, ok := targetingAudience(flag.ID, filter)
+		everyone := ok && audience.DefaultRolloutPercentage >= 100 && audience.Exclusion == nil
+		if everyone && !requireAll {
+			return true
+		}
+		if !everyone && requireAll {
+			return false
+		}
+	}
+
+	return requireAll
+}
+
+// targetsNobody reports whether the filters of a feature flag make it disabled for every user,
+// because the targeting filters they require target no one
+func targetsNobody(flag FeatureFlag) bool {
+	if flag.Conditions == nil || len(flag.Conditions.ClientFilters) == 0 {
+		return false
+	}
+
+	requireAll := flag.Conditions.RequirementType == RequirementTypeAll
+	for _, filter := range flag.Conditions.ClientFilters {
+		audience, ok := targetingAudience(flag.ID, filter)
+		nobody := ok && audience.DefaultRolloutPercentage <= 0 && len(audience.Users) == 0 && !targetsGroups(audience.Groups)
+		if nobody && requireAll {
+			return true
+		}
+		if !nobody && !requireAll {
+			return false
+		}
+	}
+
+	return !requireAll
+}
+
+// targetingAudience returns the audience of a targeting filter
+func targetingAudience(featureName string, filter ClientFilter) (TargetingAudience, bool) {
+	if filter.Name != (&TargetingFilter{}).Name() {
+		return TargetingAudience{}, false
+	}
+
+	params, err := decodeTargetingParams(featureName, filter.Parameters)
+	if err != nil {
+		return TargetingAudience{}, false
+	}
+
+	return params.Audience, true
+}
+
+func targetsGroups(groups []TargetingGroup) bool {
+	for _, group := range groups {
+		if group.RolloutPercentage > 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCleanupSuggestions(t *testing.T) {
+	targeting := func(audience map[string]any) *Conditions {
+		return &Conditions{ClientFilters: []ClientFilter{{Name: "Microsoft.Targeting", Parameters: map[string]any{"Audience": audience}}}}
+	}
+	telemetry := &Telemetry{Enabled: true}
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{
+		"Launched":   {Enabled: true, Telemetry: telemetry},
+		"RolledOut":  {Enabled: true, Telemetry: telemetry, Conditions: targeting(map[string]any{"DefaultRolloutPercentage": 100})},
+		"Flapping":   {Enabled: true, Telemetry: telemetry, Conditions: targeting(map[string]any{"DefaultRolloutPercentage": 50})},
+		"Retired":    {Telemetry: telemetry},
+		"Nobody":     {Enabled: true, Telemetry: telemetry, Conditions: targeting(map[string]any{"Groups": []any{map[string]any{"Name": "Ring0", "RolloutPercentage": 0}}})},
+		"Untracked":  {Enabled: true, Conditions: targeting(map[string]any{"DefaultRolloutPercentage": 50})},
+		"Experiment": {Enabled: true, Variants: []VariantDefinition{{Name: "A"}}, Allocation: &VariantAllocation{DefaultWhenEnabled: "A"}},
+	}), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	for _, name := range []string{"Launched", "RolledOut", "Experiment", "Retired", "Nobody", "Untracked"} {
+		_, _ = manager.IsEnabledWithAppContext(name, TargetingContext{UserID: "alice"})
+	}
+	for _, user := range []string{"alice", "bob", "carol", "dave", "erin", "frank"} {
+		_, _ = manager.IsEnabledWithAppContext("Flapping", TargetingContext{UserID: user})
+	}
+
+	reasons := func(options CleanupOptions) map[string][]CleanupReason {
+		result := make(map[string][]CleanupReason)
+		for _, suggestion := range manager.CleanupSuggestions(options) {
+			result[suggestion.FeatureName] = suggestion.Reasons
+			if len(suggestion.Details) != len(suggestion.Reasons) {
+				t.Errorf("Expected a detail per reason, got %+v", suggestion)
+			}
+		}
+		return result
+	}
+
+	expected := map[string][]CleanupReason{
+		"Retired":   {CleanupReasonDisabled},
+		"Nobody":    {CleanupReasonZeroRollout},
+		"Untracked": {CleanupReasonNoTelemetry},
+	}
+	if actual := reasons(CleanupOptions{}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected suggestions %v before the observation period, got %v", expected, actual)
+	}
+
+	manager.tracker.since = time.Now().Add(-48 * time.Hour)
+	expected["Launched"] = []CleanupReason{CleanupReasonFullyEnabled}
+	expected["RolledOut"] = []CleanupReason{CleanupReasonFullyEnabled}
+	if actual := reasons(CleanupOptions{ObservationPeriod: 24 * time.Hour}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected suggestions %v after the observation period, got %v", expected, actual)
+	}
+}
+
+func TestCleanupSuggestionsNeverEvaluated(t *testing.T) {
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{
+		"Forgotten": {Enabled: true, Telemetry: &Telemetry{Enabled: true}, Conditions: &Conditions{ClientFilters: []ClientFilter{{Name: "Custom"}}}},
+	}), &Options{LogLevel: LogLevelSilent})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	manager.tracker.since = time.Now().Add(-DefaultCleanupObservationPeriod)
+
+	suggestions := manager.CleanupSuggestions(CleanupOptions{})
+	if len(suggestions) != 1 || !reflect.DeepEqual(suggestions[0].Reasons, []CleanupReason{CleanupReasonNeverEvaluated}) {
+		t.Errorf("Expected Forgotten to be suggested as never evaluated, got %+v", suggestions)
+	}
+}
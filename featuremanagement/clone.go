@@ -0,0 +1,133 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of the feature flag, sharing no slices, maps or pointers with it, so
+// that the copy can be modified without affecting the definitions served to other requests.
+// Filter parameters and variant configuration values are copied as decoded from JSON: nested
+// map[string]any and []any values are copied, other values are shared.
+//
+// The feature manager returns copies from All, GetFeaturesByTag, Evaluate and the other methods
+// handing definitions to application code, and so do the providers of this package and of its
+// provider modules. Other providers may return the definitions they hold, which the manager treats
+// as read-only, so they must not be modified.
+//
+// Returns:
+//   - FeatureFlag: The copy of the feature flag
+func (f FeatureFlag) Clone() FeatureFlag {
+	clone := f
+	clone.Tags = slices.Clone(f.Tags)
+
+	if f.Conditions != nil {
+		conditions := *f.Conditions
+		if f.Conditions.ClientFilters != nil {
+			conditions.ClientFilters = make([]ClientFilter, len(f.Conditions.ClientFilters))
+			for i, filter := range f.Conditions.ClientFilters {
+				filter.Parameters = cloneMap(filter.Parameters)
+				conditions.ClientFilters[i] = filter
+			}
+		}
+		clone.Conditions = &conditions
+	}
+
+	if f.Variants != nil {
+		clone.Variants = make([]VariantDefinition, len(f.Variants))
+		for i, variant := range f.Variants {
+			variant.ConfigurationValue = cloneValue(variant.ConfigurationValue)
+			clone.Variants[i] = variant
+		}
+	}
+
+	if f.Allocation != nil {
+		allocation := *f.Allocation
+		if f.Allocation.User != nil {
+			allocation.User = make([]UserAllocation, len(f.Allocation.User))
+			for i, user := range f.Allocation.User {
+				user.Users = slices.Clone(user.Users)
+				allocation.User[i] = user
+			}
+		}
+		if f.Allocation.Group != nil {
+			allocation.Group = make([]GroupAllocation, len(f.Allocation.Group))
+			for i, group := range f.Allocation.Group {
+				group.Groups = slices.Clone(group.Groups)
+				allocation.Group[i] = group
+			}
+		}
+		allocation.Percentile = slices.Clone(f.Allocation.Percentile)
+		if f.Allocation.ExclusionGroup != nil {
+			exclusionGroup := *f.Allocation.ExclusionGroup
+			allocation.ExclusionGroup = &exclusionGroup
+		}
+		clone.Allocation = &allocation
+	}
+
+	if f.Telemetry != nil {
+		telemetry := *f.Telemetry
+		telemetry.Metadata = maps.Clone(f.Telemetry.Metadata)
+		clone.Telemetry = &telemetry
+	}
+
+	return clone
+}
+
+// cloneFeature returns a pointer to a copy of the feature flag, or nil
+func cloneFeature(featureFlag *FeatureFlag) *FeatureFlag {
+	if featureFlag == nil {
+		return nil
+	}
+
+	clone := featureFlag.Clone()
+	return &clone
+}
+
+// cloneFeatureFlags returns an iterator over copies of the feature flags yielded by flags
+func cloneFeatureFlags(flags iter.Seq[FeatureFlag]) iter.Seq[FeatureFlag] {
+	return func(yield func(FeatureFlag) bool) {
+		for flag := range flags {
+			if !yield(flag.Clone()) {
+				return
+			}
+		}
+	}
+}
+
+// cloneMap returns a deep copy of a map decoded from JSON
+func cloneMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+
+	clone := make(map[string]any, len(m))
+	for key, value := range m {
+		clone[key] = cloneValue(value)
+	}
+
+	return clone
+}
+
+// cloneValue returns a deep copy of the maps and slices of a value decoded from JSON
+func cloneValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return cloneMap(v)
+	case []any:
+		if v == nil {
+			return v
+		}
+		clone := make([]any, len(v))
+		for i, item := range v {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	default:
+		return value
+	}
+}
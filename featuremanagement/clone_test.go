@@ -0,0 +1,113 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newCloneTestFlag() FeatureFlag {
+	return FeatureFlag{
+		ID:      "Beta",
+		Enabled: true,
+		Conditions: &Conditions{ClientFilters: []ClientFilter{{
+			Name:       "Microsoft.Targeting",
+			Parameters: map[string]any{"Audience": map[string]any{"Users": []any{"alice"}, "DefaultRolloutPercentage": float64(0)}},
+		}}},
+		Variants:   []VariantDefinition{{Name: "Big", ConfigurationValue: map[string]any{"size": float64(10)}}},
+		Allocation: &VariantAllocation{DefaultWhenEnabled: "Big", User: []UserAllocation{{Variant: "Big", Users: []string{"alice"}}}},
+		Telemetry:  &Telemetry{Enabled: true, Metadata: map[string]string{"owner": "search"}},
+		Tags:       []string{"team:search"},
+	}
+}
+
+func TestFeatureFlagClone(t *testing.T) {
+	flag := newCloneTestFlag()
+	clone := flag.Clone()
+	if !reflect.DeepEqual(clone, flag) {
+		t.Fatalf("Expected the clone to equal the flag, got %+v", clone)
+	}
+
+	clone.Conditions.ClientFilters[0].Parameters["Audience"].(map[string]any)["Users"].([]any)[0] = "mallory"
+	clone.Variants[0].ConfigurationValue.(map[string]any)["size"] = float64(1)
+	clone.Allocation.User[0].Users[0] = "mallory"
+	clone.Telemetry.Metadata["owner"] = "mallory"
+	clone.Tags[0] = "mallory"
+	if !reflect.DeepEqual(flag, newCloneTestFlag()) {
+		t.Errorf("Expected the flag not to change with its clone, got %+v", flag)
+	}
+}
+
+func TestFeatureManagerReturnsCopies(t *testing.T) {
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{"Beta": newCloneTestFlag()}), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	alice := TargetingContext{UserID: "alice"}
+
+	for flag := range manager.All() {
+		flag.Conditions.ClientFilters[0].Parameters["Audience"].(map[string]any)["Users"] = []any{}
+	}
+	res, err := manager.Evaluate("Beta", alice)
+	if err != nil || !res.Enabled {
+		t.Fatalf("Expected Beta to stay enabled for alice, got %v, %v", res.Enabled, err)
+	}
+	res.Feature.Allocation.User[0].Users[0] = "mallory"
+	res.Variant.ConfigurationValue.(map[string]any)["size"] = float64(1)
+
+	res, err = manager.Evaluate("Beta", alice)
+	if err != nil || res.VariantAssignmentReason != VariantAssignmentReasonUser || res.Variant.ConfigurationValue.(map[string]any)["size"] != float64(10) {
+		t.Errorf("Expected the served definition not to change, got %+v, %v", res, err)
+	}
+}
+
+func TestProvidersReturnCopies(t *testing.T) {
+	flags := map[string]FeatureFlag{"Beta": newCloneTestFlag()}
+	snapshotProvider := &SnapshotProvider{}
+	snapshotProvider.StoreSnapshot(NewFeatureFlagSnapshot([]FeatureFlag{newCloneTestFlag()}, nil))
+	providers := map[string]FeatureFlagProvider{
+		"static":   NewStaticProvider(flags),
+		"memory":   NewMemoryProvider(flags),
+		"snapshot": snapshotProvider,
+	}
+
+	for name, provider := range providers {
+		t.Run(name, func(t *testing.T) {
+			manager, err := NewFeatureManager(provider, nil)
+			if err != nil {
+				t.Fatalf("Failed to create feature manager: %v", err)
+			}
+
+			mutate := func(flag FeatureFlag) {
+				flag.Conditions.ClientFilters[0].Parameters["Audience"].(map[string]any)["Users"] = []any{}
+				flag.Allocation.User[0].Users[0] = "mallory"
+				flag.Allocation.DefaultWhenEnabled = ""
+				flag.Tags[0] = "mallory"
+			}
+			flag, err := provider.GetFeatureFlag("Beta")
+			if err != nil {
+				t.Fatalf("Failed to get feature flag: %v", err)
+			}
+			mutate(flag)
+			list, _ := provider.GetFeatureFlags()
+			for _, flag := range list {
+				mutate(flag)
+			}
+			for flag := range provider.(FeatureFlagIterator).All() {
+				mutate(flag)
+			}
+			description, _ := manager.DescribeFeature("Beta")
+			description.Tags[0] = "mallory"
+
+			res, err := manager.Evaluate("Beta", TargetingContext{UserID: "alice"})
+			if err != nil || !res.Enabled || res.VariantAssignmentReason != VariantAssignmentReasonUser {
+				t.Errorf("Expected the served definition not to change, got %+v, %v", res, err)
+			}
+			if tagged := manager.GetFeaturesByTag("team:search"); len(tagged) != 1 {
+				t.Errorf("Expected the tags served not to change, got %+v", tagged)
+			}
+		})
+	}
+}
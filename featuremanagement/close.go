@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// shutdown records the outcome of closing a feature manager, shared with the managers derived from it
+type shutdown struct {
+	once sync.Once
+	err  error
+}
+
+// Close releases the resources of the feature manager so that an application can shut down
+// cleanly: it unregisters the callbacks it registered with the provider, closes the provider if it
+// implements io.Closer, stopping its background refresh, and closes the telemetry publisher if it
+// implements io.Closer, flushing buffered events such as those of a BufferedTelemetryPublisher.
+//
+// The feature manager keeps evaluating the last loaded feature flags after it is closed.
+// Managers derived with WithOverrides share the provider and telemetry publisher, so closing any
+// of them closes them all. Calling Close more than once returns the result of the first call.
+//
+// Returns:
+//   - error: The errors returned by the provider and the telemetry publisher, if any
+func (fm *FeatureManager) Close() error {
+	fm.shutdown.once.Do(func() {
+		fm.changes.close()
+
+		var errs []error
+		if closer, ok := fm.featureProvider.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close feature flag provider: %w", err))
+			}
+		}
+		if fm.telemetry != nil {
+			if closer, ok := fm.telemetry.publisher.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					errs = append(errs, fmt.Errorf("failed to close telemetry publisher: %w", err))
+				}
+			}
+		}
+
+		fm.shutdown.err = errors.Join(errs...)
+	})
+
+	return fm.shutdown.err
+}
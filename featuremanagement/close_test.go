@@ -0,0 +1,58 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// closingProvider records how often it is closed
+type closingProvider struct {
+	notifyingProvider
+	closed int
+}
+
+func (p *closingProvider) Close() error {
+	p.closed++
+	return errors.New("already closed")
+}
+
+func TestClose(t *testing.T) {
+	provider := &closingProvider{notifyingProvider: notifyingProvider{
+		flags: NewStaticProvider(map[string]FeatureFlag{"Beta": {Enabled: true, Telemetry: &Telemetry{Enabled: true}}}),
+	}}
+	var published int
+	publisher := NewBufferedTelemetryPublisher(TelemetryBatchPublisherFunc(func(events []TelemetryEvent) {
+		published += len(events)
+	}), &BufferedTelemetryPublisherOptions{FlushInterval: time.Hour})
+
+	manager, err := NewFeatureManager(provider, &Options{TelemetryPublisher: publisher})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	changed := false
+	manager.OnFeatureChanged("Beta", func(FeatureChange) { changed = true })
+	_, _ = manager.IsEnabled("Beta")
+
+	if err := manager.Close(); err == nil || err.Error() != "failed to close feature flag provider: already closed" {
+		t.Errorf("Expected the provider's close error, got %v", err)
+	}
+	if published != 1 {
+		t.Errorf("Expected the buffered event to be flushed, got %d", published)
+	}
+
+	provider.set(map[string]FeatureFlag{"Beta": {Enabled: false}})
+	if changed {
+		t.Error("Expected no change notification after closing")
+	}
+
+	if err := manager.WithOverrides(nil).Close(); err == nil || provider.closed != 1 {
+		t.Errorf("Expected later calls to return the first result without closing again, got %v after %d closes", err, provider.closed)
+	}
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || enabled {
+		t.Errorf("Expected a closed manager to keep evaluating, got %v, %v", enabled, err)
+	}
+}
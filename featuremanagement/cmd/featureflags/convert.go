@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+	"gopkg.in/yaml.v3"
+)
+
+// runConvert translates a configuration file between the feature_management schema, .NET
+// appsettings and raw App Configuration key-values, writing the result to stdout
+func runConvert(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("convert", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: featureflags convert -to <schema> [flags] <file>")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Converts the feature flags of a JSON or YAML configuration file in any supported schema.")
+		fmt.Fprintln(stderr, "The schemas are v2 (feature_management), v1 (.NET FeatureManagement) and appconfig")
+		fmt.Fprintln(stderr, "(.appconfig.featureflag/ key-values).")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	to := flags.String("to", "", "the schema to convert to: v2, v1 or appconfig")
+	format := flags.String("format", "json", "the output format: json or yaml")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || *to == "" {
+		flags.Usage()
+		return 2
+	}
+	if *format != "json" && *format != "yaml" {
+		fmt.Fprintf(stderr, "featureflags: unknown format %q\n", *format)
+		return 2
+	}
+
+	featureFlags, err := loadFeatureFlags(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "featureflags: %s: %v\n", flags.Arg(0), err)
+		return 1
+	}
+
+	config, err := fm.EncodeFeatureManagement(featureFlags, fm.Schema(*to))
+	if err != nil {
+		fmt.Fprintf(stderr, "featureflags: %v\n", err)
+		return 1
+	}
+
+	if err := writeDocument(stdout, config, *format); err != nil {
+		fmt.Fprintf(stderr, "featureflags: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// writeDocument writes a configuration as indented JSON or YAML
+func writeDocument(w io.Writer, config map[string]any, format string) error {
+	if format == "yaml" {
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(config); err != nil {
+			return err
+		}
+		return encoder.Close()
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(config)
+}
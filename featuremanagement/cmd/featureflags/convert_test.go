@@ -0,0 +1,49 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestConvertRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	file := "testdata/valid.yaml"
+	for _, schema := range []string{"appconfig", "v2"} {
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"convert", "-to", schema, file}, &stdout, &stderr); code != 0 {
+			t.Fatalf("Expected conversion to %s to succeed, got exit code %d: %s", schema, code, stderr.String())
+		}
+		file = writeFile(t, dir, schema+".json", stdout.String())
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", file}, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected the converted file to be valid, got exit code %d:\n%s", code, stdout.String())
+	}
+}
+
+func TestConvertDotnet(t *testing.T) {
+	file := writeFile(t, t.TempDir(), "appsettings.json", `{"feature_management": {"feature_flags": [
+		{"id": "Search", "enabled": true},
+		{"id": "Beta", "enabled": true, "conditions": {"client_filters": [{"name": "Microsoft.Targeting", "parameters": {"Audience": {"DefaultRolloutPercentage": 10}}}]}}
+	]}}`)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"convert", "-to", "v1", "-format", "yaml", file}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+	for _, line := range []string{"FeatureManagement:", "  Search: true", "    EnabledFor:", "      - Name: Microsoft.Targeting"} {
+		if !strings.Contains(stdout.String(), line) {
+			t.Errorf("Expected the output to contain %q, got:\n%s", line, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+	if code := run([]string{"convert", "-to", "v1", "testdata/valid.yaml"}, &stdout, &stderr); code != 1 || !strings.Contains(stderr.String(), "Checkout") {
+		t.Errorf("Expected an error for a flag with variants, got exit code %d: %s", code, stderr.String())
+	}
+}
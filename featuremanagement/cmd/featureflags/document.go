@@ -0,0 +1,160 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+	"gopkg.in/yaml.v3"
+)
+
+// readDocument reads a JSON or YAML configuration file, converting YAML to JSON so both formats
+// are handled alike
+func readDocument(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isYAML(file) {
+		return data, nil
+	}
+
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	return json.Marshal(document)
+}
+
+// loadFeatureFlags reads the feature flags defined by a configuration file in any supported schema
+func loadFeatureFlags(file string) ([]fm.FeatureFlag, error) {
+	data, err := readDocument(file)
+	if err != nil {
+		return nil, err
+	}
+
+	featureManagement, err := fm.ParseFeatureManagement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return featureManagement.FeatureFlags, nil
+}
+
+func isYAML(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// expandFiles replaces the directories among the arguments with the JSON and YAML files they
+// contain, in order of their names
+func expandFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+
+		entries, err := os.ReadDir(arg)
+		if err != nil {
+			return nil, err
+		}
+		var found []string
+		for _, entry := range entries {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			if !entry.IsDir() && (ext == ".json" || isYAML(entry.Name())) {
+				found = append(found, filepath.Join(arg, entry.Name()))
+			}
+		}
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+
+	return files, nil
+}
+
+// flagPath is the JSON path of a feature flag definition within a document
+type flagPath struct {
+	path string
+	// nested is set when the definition has the feature_management schema, so that locations
+	// within the decoded flag are also valid within the document
+	nested bool
+}
+
+// flagPaths maps the ID of each feature flag defined by a document to the JSON path of its
+// definition, so that diagnostics can point into the file
+func flagPaths(data []byte) map[string]flagPath {
+	var document map[string]any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil
+	}
+
+	paths := make(map[string]flagPath)
+	for key, value := range document {
+		switch {
+		case key == "feature_management":
+			section, _ := value.(map[string]any)
+			flags, _ := section["feature_flags"].([]any)
+			for i, flag := range flags {
+				if flag, ok := flag.(map[string]any); ok {
+					if id, ok := flag["id"].(string); ok {
+						paths[id] = flagPath{path: fmt.Sprintf("$.feature_management.feature_flags[%d]", i), nested: true}
+					}
+				}
+			}
+		case strings.HasPrefix(key, ".appconfig.featureflag/"):
+			id := strings.TrimPrefix(key, ".appconfig.featureflag/")
+			flag, nested := value.(map[string]any)
+			if raw, ok := value.(string); ok {
+				_ = json.Unmarshal([]byte(raw), &flag)
+			}
+			if flagID, ok := flag["id"].(string); ok && flagID != "" {
+				id = flagID
+			}
+			if _, ok := paths[id]; !ok {
+				paths[id] = flagPath{path: fmt.Sprintf("$[%q]", key), nested: nested}
+			}
+		case strings.EqualFold(key, "FeatureManagement"):
+			section, _ := value.(map[string]any)
+			for name := range section {
+				if _, ok := paths[name]; !ok {
+					paths[name] = flagPath{path: fmt.Sprintf("$.%s.%s", key, name)}
+				}
+			}
+		}
+	}
+
+	return paths
+}
+
+// diagnosticPath returns the path of a location within the definition of a feature flag, such as
+// "conditions.client_filters[0]", or of the definition itself when the location is empty
+func diagnosticPath(paths map[string]flagPath, featureName string, location string) string {
+	flag, ok := paths[featureName]
+	switch {
+	case !ok && location == "":
+		return "feature " + featureName
+	case !ok:
+		return fmt.Sprintf("feature %s (%s)", featureName, location)
+	case location == "":
+		return flag.path
+	case flag.nested:
+		return flag.path + "." + location
+	default:
+		return fmt.Sprintf("%s (%s)", flag.path, location)
+	}
+}
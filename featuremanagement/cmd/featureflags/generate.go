@@ -0,0 +1,224 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// featureManagementPackage is the import path of the types referenced by generated source
+var featureManagementPackage = reflect.TypeFor[fm.FeatureFlag]().PkgPath()
+
+// runGenerate compiles the feature flags of a configuration file into Go source declaring a
+// provider that serves them, so that binaries can ship with baked-in flags and no configuration
+// to load at runtime
+func runGenerate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: featureflags generate [flags] <file>")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Generates Go source declaring a featuremanagement.StaticProvider serving the feature flags")
+		fmt.Fprintln(stderr, "of a JSON or YAML configuration file in any supported schema. Use it from go:generate:")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "  //go:generate featureflags generate -package flags -o flags_gen.go flags.json")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	packageName := flags.String("package", "main", "the package of the generated source")
+	variable := flags.String("var", "FeatureFlags", "the name of the generated provider variable")
+	output := flags.String("o", "", "the file to write, instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	for _, name := range []string{*packageName, *variable} {
+		if !token.IsIdentifier(name) {
+			fmt.Fprintf(stderr, "featureflags: %q is not a Go identifier\n", name)
+			return 2
+		}
+	}
+
+	file := flags.Arg(0)
+	featureFlags, err := loadFeatureFlags(file)
+	if err != nil {
+		fmt.Fprintf(stderr, "featureflags: %s: %v\n", file, err)
+		return 1
+	}
+
+	source, err := generateProvider(*packageName, *variable, file, featureFlags)
+	if err != nil {
+		fmt.Fprintf(stderr, "featureflags: %s: %v\n", file, err)
+		return 1
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(source)
+	} else {
+		err = os.WriteFile(*output, source, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "featureflags: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// generateProvider returns the formatted source of a file declaring a variable holding a static
+// provider of the feature flags
+func generateProvider(packageName, variable, file string, featureFlags []fm.FeatureFlag) ([]byte, error) {
+	byID := make(map[string]fm.FeatureFlag, len(featureFlags))
+	for _, flag := range featureFlags {
+		if _, ok := byID[flag.ID]; ok {
+			return nil, fmt.Errorf("feature %s is defined more than once", flag.ID)
+		}
+		byID[flag.ID] = flag
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "// Code generated by featureflags generate; DO NOT EDIT.")
+	fmt.Fprintln(&buf)
+	fmt.Fprintf(&buf, "package %s\n\n", packageName)
+	fmt.Fprintf(&buf, "import fm %q\n\n", featureManagementPackage)
+	fmt.Fprintf(&buf, "// %s serves the feature flags defined by %s.\n", variable, file)
+	fmt.Fprintf(&buf, "var %s = fm.NewStaticProvider(", variable)
+	writeGoValue(&buf, reflect.ValueOf(byID), false, false)
+	fmt.Fprintln(&buf, ")")
+
+	return format.Source(buf.Bytes())
+}
+
+// writeGoValue writes a Go expression evaluating to the value. Zero struct fields are omitted, as
+// are the types of struct literals in slice and map elements when elide is set. Values held by an
+// interface are written with their type when an untyped constant would have another default type,
+// so that numbers decoded from JSON stay float64.
+func writeGoValue(buf *bytes.Buffer, v reflect.Value, inInterface, elide bool) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+		writeGoValue(buf, v.Elem(), true, false)
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+		if !elide {
+			buf.WriteString("&")
+		}
+		writeGoValue(buf, v.Elem(), false, elide)
+
+	case reflect.Struct:
+		if !elide {
+			buf.WriteString(goType(v.Type()))
+		}
+		buf.WriteString("{\n")
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || v.Field(i).IsZero() {
+				continue
+			}
+			buf.WriteString(field.Name + ": ")
+			writeGoValue(buf, v.Field(i), false, false)
+			buf.WriteString(",\n")
+		}
+		buf.WriteString("}")
+
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+		buf.WriteString(goType(v.Type()) + "{\n")
+		for i := 0; i < v.Len(); i++ {
+			writeGoValue(buf, v.Index(i), false, true)
+			buf.WriteString(",\n")
+		}
+		buf.WriteString("}")
+
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		buf.WriteString(goType(v.Type()) + "{\n")
+		for _, key := range keys {
+			writeGoValue(buf, key, false, true)
+			buf.WriteString(": ")
+			writeGoValue(buf, v.MapIndex(key), false, true)
+			buf.WriteString(",\n")
+		}
+		buf.WriteString("}")
+
+	case reflect.String:
+		writeConstant(buf, v.Type(), strconv.Quote(v.String()), false)
+
+	case reflect.Bool:
+		writeConstant(buf, v.Type(), strconv.FormatBool(v.Bool()), false)
+
+	case reflect.Float32, reflect.Float64:
+		writeConstant(buf, v.Type(), strconv.FormatFloat(v.Float(), 'g', -1, 64), inInterface)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeConstant(buf, v.Type(), strconv.FormatInt(v.Int(), 10), inInterface && v.Kind() != reflect.Int)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		writeConstant(buf, v.Type(), strconv.FormatUint(v.Uint(), 10), inInterface)
+
+	default:
+		panic(fmt.Sprintf("unsupported type %s in feature flag definition", v.Type()))
+	}
+}
+
+// writeConstant writes a constant, converted to its type when the type is named or the constant
+// would otherwise take another type
+func writeConstant(buf *bytes.Buffer, t reflect.Type, literal string, convert bool) {
+	if convert || t.PkgPath() != "" {
+		fmt.Fprintf(buf, "%s(%s)", goType(t), literal)
+		return
+	}
+	buf.WriteString(literal)
+}
+
+// goType returns the Go syntax of a type, qualifying the types of the feature management package
+func goType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + goType(t.Elem())
+	case reflect.Slice:
+		return "[]" + goType(t.Elem())
+	case reflect.Map:
+		return "map[" + goType(t.Key()) + "]" + goType(t.Elem())
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "any"
+		}
+	}
+
+	if t.PkgPath() == featureManagementPackage {
+		return "fm." + t.Name()
+	}
+
+	return t.String()
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+//go:generate go run . generate -package main -var testFeatureFlags -o generated_flags_test.go testdata/valid.yaml
+
+func TestGenerate(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"generate", "-package", "main", "-var", "testFeatureFlags", "testdata/valid.yaml"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+
+	// generated_flags_test.go is compiled into this test, so the generated source must build
+	expected, err := os.ReadFile("generated_flags_test.go")
+	if err != nil {
+		t.Fatalf("Failed to read generated source: %v", err)
+	}
+	if stdout.String() != string(expected) {
+		t.Errorf("Generated source differs from generated_flags_test.go; run go generate:\n%s", stdout.String())
+	}
+
+	flags, err := loadFeatureFlags("testdata/valid.yaml")
+	if err != nil {
+		t.Fatalf("Failed to load flags: %v", err)
+	}
+	for _, flag := range flags {
+		generated, err := testFeatureFlags.GetFeatureFlag(flag.ID)
+		if err != nil || !reflect.DeepEqual(generated, flag) {
+			t.Errorf("Expected the generated definition of %s to equal the loaded one, got %+v, %v", flag.ID, generated, err)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	if _, err := generateProvider("flags", "FeatureFlags", "flags.json", []fm.FeatureFlag{{ID: "Beta"}, {ID: "Beta"}}); err == nil {
+		t.Error("Expected an error for a feature defined twice")
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"generate", "-var", "feature-flags", "testdata/valid.yaml"}, &stdout, &stderr); code != 2 {
+		t.Errorf("Expected exit code 2 for an invalid identifier, got %d", code)
+	}
+}
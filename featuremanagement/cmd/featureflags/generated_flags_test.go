@@ -0,0 +1,53 @@
+// Code generated by featureflags generate; DO NOT EDIT.
+
+package main
+
+import fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+
+// testFeatureFlags serves the feature flags defined by testdata/valid.yaml.
+var testFeatureFlags = fm.NewStaticProvider(map[string]fm.FeatureFlag{
+	"Beta": {
+		ID:      "Beta",
+		Enabled: true,
+		Conditions: &fm.Conditions{
+			ClientFilters: []fm.ClientFilter{
+				{
+					Name: "Microsoft.Targeting",
+					Parameters: map[string]any{
+						"Audience": map[string]any{
+							"DefaultRolloutPercentage": float64(10),
+							"Users": []any{
+								"*@contoso.com",
+							},
+						},
+					},
+				},
+			},
+		},
+	},
+	"Checkout": {
+		ID:      "Checkout",
+		Enabled: true,
+		Variants: []fm.VariantDefinition{
+			{
+				Name: "Old",
+			},
+			{
+				Name: "New",
+			},
+		},
+		Allocation: &fm.VariantAllocation{
+			Percentile: []fm.PercentileAllocation{
+				{
+					Variant: "Old",
+					To:      50,
+				},
+				{
+					Variant: "New",
+					From:    50,
+					To:      100,
+				},
+			},
+		},
+	},
+})
@@ -0,0 +1,75 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Command featureflags works with feature flag configuration files in the feature_management,
+// .NET FeatureManagement and App Configuration schemas, as JSON or YAML.
+//
+// Usage:
+//
+//	featureflags <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	convert     translate configuration between the v2, .NET and App Configuration schemas
+//	generate    compile configuration into Go source declaring a static provider
+//	simulate    evaluate a feature flag for a population of users
+//	validate    check configuration files against the schema and for likely mistakes
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// command is a subcommand of the CLI, returning the exit code of the process
+type command struct {
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = map[string]command{
+	"convert":  {summary: "translate configuration between the v2, .NET and App Configuration schemas", run: runConvert},
+	"generate": {summary: "compile configuration into Go source declaring a static provider", run: runGenerate},
+	"simulate": {summary: "evaluate a feature flag for a population of users", run: runSimulate},
+	"validate": {summary: "check configuration files against the schema and for likely mistakes", run: runValidate},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "featureflags: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	return cmd.run(args[1:], stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: featureflags <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s  %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'featureflags <command> -h' for the flags of a command.")
+}
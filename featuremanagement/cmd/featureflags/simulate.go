@@ -0,0 +1,317 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// simulatedUser is a user of the simulated population
+type simulatedUser struct {
+	UserID     string            `json:"user_id"`
+	Groups     []string          `json:"groups,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// simulationResult summarizes the evaluation of a feature over a population
+type simulationResult struct {
+	population int
+	enabled    int
+	errors     int
+	variants   map[string]int
+	// groupUsers and groupEnabled count the members of each group and those enabled
+	groupUsers   map[string]int
+	groupEnabled map[string]int
+}
+
+// runSimulate evaluates a feature flag for each user of a population, read from a file or
+// generated, and reports who would be enabled and which variant each user gets.
+func runSimulate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: featureflags simulate [flags] <configuration file>")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Evaluates a feature flag for a population of users and summarizes the results.")
+		fmt.Fprintln(stderr, "The users are read from -users, a JSON array of {\"user_id\", \"groups\", \"attributes\"}")
+		fmt.Fprintln(stderr, "objects or a text file with a user ID and optional comma-separated groups per line,")
+		fmt.Fprintln(stderr, "or generated with -sample.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	featureName := flags.String("feature", "", "the feature to simulate; required when the file defines several")
+	usersFile := flags.String("users", "", "the file listing the users")
+	sample := flags.Int("sample", 0, "the number of users to generate, named user-0 to user-<n-1>")
+	var sampleGroups stringList
+	flags.Var(&sampleGroups, "sample-group", "a group and the percentage of generated users in it, as name=percent; can be repeated")
+	list := flags.Bool("list", false, "print the result for each user")
+	userPatterns := flags.Bool("user-patterns", false, "match listed users containing '*' wildcards as patterns, see Options.UserPatterns")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || (*usersFile == "") == (*sample <= 0) {
+		flags.Usage()
+		return 2
+	}
+
+	featureFlags, err := loadFeatureFlags(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "featureflags: %s: %v\n", flags.Arg(0), err)
+		return 2
+	}
+	featureFlag, err := selectFeatureFlag(featureFlags, *featureName)
+	if err != nil {
+		fmt.Fprintf(stderr, "featureflags: %s: %v\n", flags.Arg(0), err)
+		return 2
+	}
+
+	var users []simulatedUser
+	if *usersFile != "" {
+		users, err = readUsers(*usersFile)
+	} else {
+		users, err = sampleUsers(*sample, sampleGroups)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "featureflags: %v\n", err)
+		return 2
+	}
+
+	manager, err := newSimulationManager(featureFlag, *userPatterns)
+	if err != nil {
+		fmt.Fprintf(stderr, "featureflags: %v\n", err)
+		return 2
+	}
+
+	var listing *tabwriter.Writer
+	if *list {
+		listing = tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(listing, "USER\tENABLED\tVARIANT\tGROUPS")
+	}
+
+	result := simulate(manager, featureFlag.ID, users, func(user simulatedUser, evaluation fm.EvaluationResult, err error) {
+		if listing == nil {
+			return
+		}
+		enabled := strconv.FormatBool(evaluation.Enabled)
+		if err != nil {
+			enabled = "error: " + err.Error()
+		}
+		fmt.Fprintf(listing, "%s\t%s\t%s\t%s\n", user.UserID, enabled, variantName(evaluation), strings.Join(user.Groups, ","))
+	})
+	if listing != nil {
+		listing.Flush()
+		fmt.Fprintln(stdout)
+	}
+
+	printSimulation(stdout, featureFlag.ID, result)
+	if result.errors > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// selectFeatureFlag returns the flag with the given name, or the only flag when no name is given
+func selectFeatureFlag(featureFlags []fm.FeatureFlag, featureName string) (fm.FeatureFlag, error) {
+	if featureName == "" {
+		if len(featureFlags) == 1 {
+			return featureFlags[0], nil
+		}
+		names := make([]string, 0, len(featureFlags))
+		for _, flag := range featureFlags {
+			names = append(names, flag.ID)
+		}
+		return fm.FeatureFlag{}, fmt.Errorf("choose a feature with -feature among: %s", strings.Join(names, ", "))
+	}
+
+	for _, flag := range featureFlags {
+		if flag.ID == featureName {
+			return flag, nil
+		}
+	}
+
+	return fm.FeatureFlag{}, fmt.Errorf("feature %s is not defined", featureName)
+}
+
+// newSimulationManager returns a manager serving only the simulated flag, with every filter
+// shipped with the library registered
+func newSimulationManager(featureFlag fm.FeatureFlag, userPatterns bool) (*fm.FeatureManager, error) {
+	return fm.NewFeatureManager(fm.NewStaticProvider(map[string]fm.FeatureFlag{featureFlag.ID: featureFlag}), &fm.Options{
+		Filters: []fm.FeatureFilter{
+			fm.NewQuotaFilter(&fm.MemoryQuotaCounterStore{}),
+			fm.NewDependencyHealthFilter(fm.DependencyHealthOptions{}),
+			fm.NewJWTClaimsFilter(fm.JWTClaimsOptions{}),
+		},
+		UserPatterns: userPatterns,
+		LogLevel:     fm.LogLevelSilent,
+	})
+}
+
+// simulate evaluates the feature for each user, passing each evaluation to visit
+func simulate(manager *fm.FeatureManager, featureName string, users []simulatedUser, visit func(simulatedUser, fm.EvaluationResult, error)) simulationResult {
+	result := simulationResult{
+		population:   len(users),
+		variants:     make(map[string]int),
+		groupUsers:   make(map[string]int),
+		groupEnabled: make(map[string]int),
+	}
+
+	for _, user := range users {
+		evaluation, err := manager.Evaluate(featureName, fm.TargetingContext{
+			UserID:     user.UserID,
+			Groups:     user.Groups,
+			Attributes: user.Attributes,
+		})
+		visit(user, evaluation, err)
+		if err != nil {
+			result.errors++
+			continue
+		}
+
+		if evaluation.Enabled {
+			result.enabled++
+		}
+		result.variants[variantName(evaluation)]++
+		for _, group := range user.Groups {
+			result.groupUsers[group]++
+			if evaluation.Enabled {
+				result.groupEnabled[group]++
+			}
+		}
+	}
+
+	return result
+}
+
+func variantName(evaluation fm.EvaluationResult) string {
+	if evaluation.Variant == nil {
+		return "-"
+	}
+
+	return evaluation.Variant.Name
+}
+
+func printSimulation(w io.Writer, featureName string, result simulationResult) {
+	percent := func(count, total int) string {
+		if total == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.2f%%", float64(count)/float64(total)*100)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Feature %s, %d users\n", featureName, result.population)
+	fmt.Fprintf(tw, "  enabled\t%d\t%s\n", result.enabled, percent(result.enabled, result.population))
+	disabled := result.population - result.enabled - result.errors
+	fmt.Fprintf(tw, "  disabled\t%d\t%s\n", disabled, percent(disabled, result.population))
+	if result.errors > 0 {
+		fmt.Fprintf(tw, "  errors\t%d\t%s\n", result.errors, percent(result.errors, result.population))
+	}
+
+	// Users without a variant are only listed next to users with one
+	if _, noVariant := result.variants["-"]; len(result.variants) > 1 || (len(result.variants) == 1 && !noVariant) {
+		fmt.Fprintln(tw, "Variants")
+		for _, name := range sortedKeys(result.variants) {
+			fmt.Fprintf(tw, "  %s\t%d\t%s\n", name, result.variants[name], percent(result.variants[name], result.population))
+		}
+	}
+
+	if len(result.groupUsers) > 0 {
+		fmt.Fprintln(tw, "Groups (enabled members)")
+		for _, name := range sortedKeys(result.groupUsers) {
+			fmt.Fprintf(tw, "  %s\t%d/%d\t%s\n", name, result.groupEnabled[name], result.groupUsers[name], percent(result.groupEnabled[name], result.groupUsers[name]))
+		}
+	}
+	tw.Flush()
+}
+
+func sortedKeys(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// readUsers reads a population from a JSON array of users, or from a text file with a user ID and
+// optional comma-separated groups per line. Blank lines and lines starting with # are skipped.
+func readUsers(file string) ([]simulatedUser, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		var users []simulatedUser
+		if err := json.Unmarshal(data, &users); err != nil {
+			return nil, fmt.Errorf("invalid users file %s: %w", file, err)
+		}
+		return users, nil
+	}
+
+	var users []simulatedUser
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		user := simulatedUser{UserID: fields[0]}
+		for _, field := range fields[1:] {
+			for _, group := range strings.Split(field, ",") {
+				if group != "" {
+					user.Groups = append(user.Groups, group)
+				}
+			}
+		}
+		users = append(users, user)
+	}
+
+	return users, scanner.Err()
+}
+
+// sampleUsers generates a population of n users. Each name=percent group holds that share of the
+// users, chosen independently of the other groups and of the rollouts of features.
+func sampleUsers(n int, groups []string) ([]simulatedUser, error) {
+	type sampleGroup struct {
+		name    string
+		percent float64
+	}
+
+	var parsed []sampleGroup
+	for _, group := range groups {
+		name, rawPercent, found := strings.Cut(group, "=")
+		percent, err := strconv.ParseFloat(rawPercent, 64)
+		if !found || name == "" || err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid sample group %q: expected name=percent with a percent between 0 and 100", group)
+		}
+		parsed = append(parsed, sampleGroup{name: name, percent: percent})
+	}
+
+	users := make([]simulatedUser, n)
+	for i := range users {
+		users[i].UserID = fmt.Sprintf("user-%d", i)
+		for _, group := range parsed {
+			if fm.ComputeBucket(users[i].UserID, "sample", group.name) < group.percent {
+				users[i].Groups = append(users[i].Groups, group.name)
+			}
+		}
+	}
+
+	return users, nil
+}
@@ -0,0 +1,72 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSimulateUsersFile(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"simulate", "-feature", "Beta", "-users", "testdata/users.txt", "-list", "-user-patterns", "testdata/valid.yaml"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+
+	output := stdout.String()
+	for _, line := range []string{
+		"alice@contoso.com   true",
+		"Feature Beta, 3 users",
+		"Ring0  0/2",
+	} {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected the output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
+
+func TestSimulateSample(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"simulate", "-feature", "Checkout", "-sample", "1000", "testdata/valid.yaml"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("Expected success, got exit code %d: %s", code, stderr.String())
+	}
+
+	output := stdout.String()
+	for _, line := range []string{"enabled   1000  100.00%", "New  491  49.10%", "Old  509  50.90%"} {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected the output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
+
+func TestSampleUsers(t *testing.T) {
+	users, err := sampleUsers(1000, []string{"Ring0=10"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	members := 0
+	for _, user := range users {
+		if len(user.Groups) > 0 {
+			members++
+		}
+	}
+	if members < 70 || members > 130 {
+		t.Errorf("Expected about 10%% of users in Ring0, got %d of 1000", members)
+	}
+
+	if _, err := sampleUsers(10, []string{"Ring0=150"}); err == nil {
+		t.Error("Expected an error for a percentage above 100")
+	}
+}
+
+func TestSimulateRequiresFeature(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"simulate", "-sample", "10", "testdata/valid.yaml"}, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "Beta, Checkout") {
+		t.Errorf("Expected an error listing the features, got exit code %d: %s", code, stderr.String())
+	}
+}
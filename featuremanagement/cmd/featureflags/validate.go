@@ -0,0 +1,206 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// stringList is a flag that can be given several times
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// runValidate checks configuration files against the feature_management JSON schema, then checks
+// the flags they define for semantic errors, unknown filters and overlapping percentile ranges.
+// Expired time windows and suspicious allocations are reported as warnings. Each diagnostic names the file and the JSON path
+// of the offending definition.
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: featureflags validate [flags] <file or directory>...")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Validates JSON and YAML feature flag configuration files, exiting with status 1 if any is invalid.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	var customFilters stringList
+	flags.Var(&customFilters, "filter", "the name of a custom filter used by the flags; can be repeated")
+	strict := flags.Bool("strict", false, "reject unknown fields and values of the wrong type")
+	strictTimeFormat := flags.Bool("strict-time-format", false, "reject time window times that are not in ISO 8601 or RFC 1123 format in GMT, the formats understood on all platforms")
+	failOnWarnings := flags.Bool("fail-on-warnings", false, "exit with status 1 when there are warnings")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	files, err := expandFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "featureflags: %v\n", err)
+		return 2
+	}
+
+	manager, err := newValidationManager(*strictTimeFormat)
+	if err != nil {
+		fmt.Fprintf(stderr, "featureflags: %v\n", err)
+		return 2
+	}
+	knownFilters := make(map[string]bool)
+	for _, name := range manager.Stats().Filters {
+		knownFilters[name] = true
+	}
+	for _, name := range customFilters {
+		knownFilters[name] = true
+	}
+
+	validator := documentValidator{
+		manager:       manager,
+		knownFilters:  knownFilters,
+		decodeOptions: fm.DecodeOptions{Strict: *strict},
+		now:           time.Now(),
+	}
+
+	var errorCount, warningCount int
+	for _, file := range files {
+		for _, diagnostic := range validator.validate(file) {
+			if diagnostic.warning {
+				warningCount++
+				fmt.Fprintf(stdout, "%s: warning: %s\n", file, diagnostic.message)
+			} else {
+				errorCount++
+				fmt.Fprintf(stdout, "%s: %s\n", file, diagnostic.message)
+			}
+		}
+	}
+
+	fmt.Fprintf(stdout, "%d files, %d errors, %d warnings\n", len(files), errorCount, warningCount)
+	if errorCount > 0 || (*failOnWarnings && warningCount > 0) {
+		return 1
+	}
+
+	return 0
+}
+
+// newValidationManager returns a manager with every filter shipped with the library registered,
+// whether or not it is registered by default, to check the filters and parameters of flags
+func newValidationManager(strictTimeFormat bool) (*fm.FeatureManager, error) {
+	return fm.NewFeatureManager(fm.NewStaticProvider(nil), &fm.Options{
+		Filters: []fm.FeatureFilter{
+			&fm.TimeWindowFilter{StrictTimeFormat: strictTimeFormat},
+			fm.NewQuotaFilter(&fm.MemoryQuotaCounterStore{}),
+			fm.NewDependencyHealthFilter(fm.DependencyHealthOptions{}),
+			fm.NewJWTClaimsFilter(fm.JWTClaimsOptions{}),
+		},
+	})
+}
+
+// diagnostic is a problem found in a configuration file
+type diagnostic struct {
+	message string
+	warning bool
+}
+
+type documentValidator struct {
+	manager       *fm.FeatureManager
+	knownFilters  map[string]bool
+	decodeOptions fm.DecodeOptions
+	now           time.Time
+}
+
+// validate returns the problems found in a configuration file. Semantic checks only run on files
+// that conform to the schema, since their flags may not decode otherwise.
+func (v documentValidator) validate(file string) []diagnostic {
+	data, err := readDocument(file)
+	if err != nil {
+		return []diagnostic{{message: err.Error()}}
+	}
+
+	var diagnostics []diagnostic
+	for _, violation := range fm.ValidateDocument(data) {
+		diagnostics = append(diagnostics, diagnostic{message: violation.Error()})
+	}
+	if len(diagnostics) > 0 {
+		return diagnostics
+	}
+
+	featureManagement, err := fm.ParseFeatureManagementWithOptions(data, &v.decodeOptions)
+	if err != nil {
+		return []diagnostic{{message: err.Error()}}
+	}
+
+	paths := flagPaths(data)
+	report := func(featureName, location, message string, warning bool) {
+		diagnostics = append(diagnostics, diagnostic{
+			message: fmt.Sprintf("%s: %s", diagnosticPath(paths, featureName, location), message),
+			warning: warning,
+		})
+	}
+
+	valid, errs := fm.ValidateFeatureFlags(featureManagement.FeatureFlags)
+	for _, err := range errs {
+		report(err.FeatureName, "", err.Error(), false)
+	}
+
+	defined := make(map[string]bool, len(featureManagement.FeatureFlags))
+	for _, flag := range featureManagement.FeatureFlags {
+		if defined[flag.ID] {
+			report(flag.ID, "", fmt.Sprintf("feature %s is defined more than once", flag.ID), false)
+		}
+		defined[flag.ID] = true
+
+		if flag.Conditions == nil {
+			continue
+		}
+		for i, filter := range flag.Conditions.ClientFilters {
+			if filter.Name != "" && !v.knownFilters[filter.Name] {
+				report(flag.ID, fmt.Sprintf("conditions.client_filters[%d]", i),
+					fmt.Sprintf("unknown filter %s; pass -filter %s if it is a custom filter", filter.Name, filter.Name), false)
+			}
+		}
+	}
+
+	for _, warning := range fm.FindOverlappingPercentiles(valid) {
+		report(warning.FeatureName, warning.Path, warning.Message, false)
+	}
+	for _, warning := range fm.FindExpiredTimeWindows(valid, v.now) {
+		report(warning.FeatureName, warning.Path, warning.Message, true)
+	}
+	for _, warning := range fm.FindSuspiciousAllocations(valid) {
+		report(warning.FeatureName, warning.Path, warning.Message, true)
+	}
+
+	// Filter parameters are checked by the filters themselves, as they are at load time
+	for _, flag := range valid {
+		err := v.manager.CheckFilterParameters(flag)
+		if err == nil {
+			continue
+		}
+		for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+			var paramErr *fm.FilterParameterError
+			if errors.As(err, &paramErr) {
+				report(flag.ID, fmt.Sprintf("conditions.client_filters[%d].parameters", paramErr.Index), paramErr.Err.Error(), false)
+			}
+		}
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,109 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", "testdata/valid.yaml"}, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected a valid file to pass, got exit code %d: %s%s", code, stdout.String(), stderr.String())
+	}
+
+	stdout.Reset()
+	code := run([]string{"validate", "testdata/invalid.json", "testdata/schema.json"}, &stdout, &stderr)
+	if code != 1 {
+		t.Errorf("Expected exit code 1 for invalid files, got %d", code)
+	}
+
+	expected := []string{
+		"testdata/invalid.json: $.feature_management.feature_flags[0].conditions.client_filters[1]: unknown filter Contoso.Region",
+		"testdata/invalid.json: $.feature_management.feature_flags[1].allocation.percentile[1]: percentile allocation at index 1 of feature Checkout overlaps the one at index 0",
+		"testdata/invalid.json: warning: $.feature_management.feature_flags[0].conditions.client_filters[0]: time window of feature Beta ended",
+		"testdata/invalid.json: $.feature_management.feature_flags[0].conditions.client_filters[2].parameters: invalid feature flag: Beta",
+		"testdata/schema.json: $.feature_management.feature_flags[0].enabled: expected boolean, got string",
+		"2 files, 4 errors, 1 warnings",
+	}
+	for _, line := range expected {
+		if !strings.Contains(stdout.String(), line) {
+			t.Errorf("Expected the output to contain %q, got:\n%s", line, stdout.String())
+		}
+	}
+}
+
+func TestValidateCustomFilters(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	run([]string{"validate", "-filter", "Contoso.Region", "testdata/invalid.json"}, &stdout, &stderr)
+	if strings.Contains(stdout.String(), "unknown filter") {
+		t.Errorf("Expected the custom filter to be known, got:\n%s", stdout.String())
+	}
+}
+
+func TestValidateFailOnWarnings(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "expired.json", `{"feature_management": {"feature_flags": [{"id": "Sale", "enabled": true,
+		"conditions": {"client_filters": [{"name": "Microsoft.TimeWindow", "parameters": {"End": "Mon, 01 Jan 2024 00:00:00 GMT"}}]}}]}}`)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", dir}, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected warnings alone to pass, got exit code %d", code)
+	}
+	if code := run([]string{"validate", "-fail-on-warnings", dir}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected warnings to fail with -fail-on-warnings, got exit code %d", code)
+	}
+}
+
+func TestValidateStrictTimeFormat(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "sale.json", `{"feature_management": {"feature_flags": [{"id": "Sale", "enabled": true,
+		"conditions": {"client_filters": [{"name": "Microsoft.TimeWindow", "parameters": {"Start": "Mon Jan  1 00:00:00 UTC 2024"}}]}}]}}`)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", dir}, &stdout, &stderr); code != 0 {
+		t.Errorf("Expected the time to be accepted by default, got exit code %d: %s", code, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"validate", "-strict-time-format", dir}, &stdout, &stderr); code != 1 {
+		t.Errorf("Expected the time to be rejected with -strict-time-format, got exit code %d", code)
+	}
+	if !strings.Contains(stdout.String(), "$.feature_management.feature_flags[0].conditions.client_filters[0].parameters: ") ||
+		!strings.Contains(stdout.String(), "must be in ISO 8601 format") {
+		t.Errorf("Expected the time window parameters to be reported, got:\n%s", stdout.String())
+	}
+}
+
+func TestFlagPaths(t *testing.T) {
+	paths := flagPaths([]byte(`{
+		"feature_management": {"feature_flags": [{"id": "Beta"}]},
+		".appconfig.featureflag/Search": "{\"id\": \"Search\", \"enabled\": true}",
+		"FeatureManagement": {"Legacy": true}
+	}`))
+
+	for _, tc := range []struct {
+		featureName string
+		location    string
+		expected    string
+	}{
+		{"Beta", "allocation.percentile[1]", "$.feature_management.feature_flags[0].allocation.percentile[1]"},
+		{"Search", "conditions.client_filters[0]", `$[".appconfig.featureflag/Search"] (conditions.client_filters[0])`},
+		{"Legacy", "", "$.FeatureManagement.Legacy"},
+		{"Unknown", "", "feature Unknown"},
+	} {
+		if actual := diagnosticPath(paths, tc.featureName, tc.location); actual != tc.expected {
+			t.Errorf("Expected path %s, got %s", tc.expected, actual)
+		}
+	}
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"deploy"}, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "unknown command") {
+		t.Errorf("Expected a usage error, got exit code %d: %s", code, stderr.String())
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// complianceTestCase mirrors an entry of the *.tests.json validation files shared across the SDKs
+type complianceTestCase struct {
+	FeatureFlagName string `json:"FeatureFlagName"`
+	Inputs          *struct {
+		User   string   `json:"user"`
+		Groups []string `json:"groups"`
+	} `json:"Inputs"`
+	IsEnabled struct {
+		Result    string `json:"Result"`
+		Exception string `json:"Exception"`
+	} `json:"IsEnabled"`
+	Variant *struct {
+		Result *struct {
+			Name               string `json:"Name"`
+			ConfigurationValue any    `json:"ConfigurationValue"`
+		} `json:"Result"`
+	} `json:"Variant"`
+	Description string `json:"Description"`
+}
+
+func TestCrossSDKCompliance(t *testing.T) {
+	samples, err := filepath.Glob(filepath.Join("testdata", "compliance", "*.sample.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) == 0 {
+		t.Fatal("No compliance samples found")
+	}
+
+	for _, sample := range samples {
+		name := strings.TrimSuffix(filepath.Base(sample), ".sample.json")
+		t.Run(name, func(t *testing.T) {
+			manager := loadComplianceSample(t, sample)
+			testCases := loadComplianceTests(t, strings.TrimSuffix(sample, ".sample.json")+".tests.json")
+
+			for _, tc := range testCases {
+				t.Run(tc.FeatureFlagName, func(t *testing.T) {
+					runComplianceTestCase(t, manager, tc)
+				})
+			}
+		})
+	}
+}
+
+func runComplianceTestCase(t *testing.T, manager *FeatureManager, tc complianceTestCase) {
+	var appContext any
+	if tc.Inputs != nil {
+		appContext = TargetingContext{UserID: tc.Inputs.User, Groups: tc.Inputs.Groups}
+	}
+
+	enabled, err := manager.IsEnabledWithAppContext(tc.FeatureFlagName, appContext)
+	if tc.IsEnabled.Exception != "" {
+		if err == nil {
+			t.Errorf("Expected error %q: %s", tc.IsEnabled.Exception, tc.Description)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Unexpected error: %v: %s", err, tc.Description)
+	}
+	if expected := tc.IsEnabled.Result == "true"; enabled != expected {
+		t.Errorf("Expected IsEnabled %v, got %v: %s", expected, enabled, tc.Description)
+	}
+
+	if tc.Variant == nil {
+		return
+	}
+
+	variant, err := manager.GetVariant(tc.FeatureFlagName, appContext)
+	if err != nil {
+		t.Fatalf("Unexpected error getting variant: %v: %s", err, tc.Description)
+	}
+
+	expected := tc.Variant.Result
+	switch {
+	case expected == nil && variant != nil:
+		t.Errorf("Expected no variant, got %s: %s", variant.Name, tc.Description)
+	case expected != nil && variant == nil:
+		t.Errorf("Expected variant %s, got none: %s", expected.Name, tc.Description)
+	case expected != nil:
+		if variant.Name != expected.Name {
+			t.Errorf("Expected variant %s, got %s: %s", expected.Name, variant.Name, tc.Description)
+		}
+		if !reflect.DeepEqual(variant.ConfigurationValue, expected.ConfigurationValue) {
+			t.Errorf("Expected configuration value %v, got %v: %s", expected.ConfigurationValue, variant.ConfigurationValue, tc.Description)
+		}
+	}
+}
+
+func loadComplianceSample(t *testing.T, path string) *FeatureManager {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var document struct {
+		FeatureManagement FeatureManagement `json:"feature_management"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatalf("Failed to parse %s: %v", path, err)
+	}
+
+	provider := &mockFeatureFlagProvider{featureFlags: document.FeatureManagement.FeatureFlags}
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	return manager
+}
+
+func loadComplianceTests(t *testing.T, path string) []complianceTestCase {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var testCases []complianceTestCase
+	if err := json.Unmarshal(data, &testCases); err != nil {
+		t.Fatalf("Failed to parse %s: %v", path, err)
+	}
+
+	return testCases
+}
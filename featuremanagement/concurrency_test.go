@@ -0,0 +1,167 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+	"github.com/microsoft/Featuremanagement-Go/featuremanagement/featuretest"
+)
+
+func churnFlagSet(rollout float64, variant string) []fm.FeatureFlag {
+	return []fm.FeatureFlag{
+		{ID: "Static", Enabled: rollout > 50},
+		{
+			ID:      "Targeted",
+			Enabled: true,
+			Conditions: &fm.Conditions{
+				ClientFilters: []fm.ClientFilter{
+					{
+						Name: "Microsoft.Targeting",
+						Parameters: map[string]any{
+							"Audience": map[string]any{
+								"Users":                    []any{"Alice"},
+								"DefaultRolloutPercentage": rollout,
+							},
+						},
+					},
+					{
+						Name: "Microsoft.TimeWindow",
+						Parameters: map[string]any{
+							"Start": "Thu, 29 Jun 2023 07:00:00 GMT",
+						},
+					},
+				},
+				RequirementType: fm.RequirementTypeAll,
+			},
+		},
+		{
+			ID:       "Variants",
+			Enabled:  true,
+			Variants: []fm.VariantDefinition{{Name: "Small"}, {Name: "Big"}},
+			Allocation: &fm.VariantAllocation{
+				DefaultWhenEnabled: variant,
+				Percentile:         []fm.PercentileAllocation{{Variant: "Big", From: 0, To: rollout}},
+			},
+		},
+	}
+}
+
+func TestConcurrentRefreshAndEvaluation(t *testing.T) {
+	provider := featuretest.NewChurnProvider(100*time.Microsecond,
+		churnFlagSet(10, "Small"),
+		churnFlagSet(90, "Big"),
+		churnFlagSet(50, "Small")[:2],
+	)
+	defer provider.Stop()
+
+	manager, err := fm.NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	derived := manager.WithOverrides(map[string]bool{"Static": true})
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			targetingContext := fm.TargetingContext{UserID: string(rune('A' + worker)), Groups: []string{"Stage1"}}
+			for time.Now().Before(deadline) {
+				_, _ = manager.IsEnabled("Static")
+				_, _ = manager.IsEnabledWithAppContext("Targeted", targetingContext)
+				_, _ = manager.GetVariant("Variants", &targetingContext)
+				_, _ = derived.IsEnabledWithAppContext("Static", targetingContext)
+				_, _ = manager.GetFeatureNames()
+				_ = manager.EvaluateAll(context.Background(), targetingContext, &fm.BatchOptions{Concurrency: 2})
+				_ = manager.GetLifecycleReport()
+				for flag := range manager.All() {
+					_ = flag.ID
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	if provider.Updates() == 0 {
+		t.Error("Expected the provider to refresh during the test")
+	}
+}
+
+func versionedFlagSet(version float64, users []any) []fm.FeatureFlag {
+	return []fm.FeatureFlag{{
+		ID:      "Versioned",
+		Enabled: true,
+		Conditions: &fm.Conditions{
+			RequirementType: fm.RequirementTypeAll,
+			ClientFilters: []fm.ClientFilter{
+				{Name: "Version", Parameters: map[string]any{"Version": version}},
+				{Name: "Version", Parameters: map[string]any{"Version": version}},
+				{Name: "Microsoft.Targeting", Parameters: map[string]any{"Audience": map[string]any{"Users": users}}},
+			},
+		},
+	}}
+}
+
+func TestEvaluationUsesOneDefinitionDuringRefresh(t *testing.T) {
+	users := []any{"A", "B", "C", "D"}
+	provider := featuretest.NewChurnProvider(50*time.Microsecond, versionedFlagSet(1, users), versionedFlagSet(2, []any{}))
+	defer provider.Stop()
+
+	recorder := featuretest.NewRecorder("Version").Return(true)
+	manager, err := fm.NewFeatureManager(provider, &fm.Options{Filters: []fm.FeatureFilter{recorder}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	type evaluation struct {
+		appContext *fm.TargetingContext
+		enabled    bool
+	}
+	evaluations := make([][]evaluation, len(users))
+	var wg sync.WaitGroup
+	for worker := range users {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				// Each evaluation gets its own app context, to find the filter calls it made
+				appContext := &fm.TargetingContext{UserID: users[worker].(string)}
+				enabled, err := manager.IsEnabledWithAppContext("Versioned", appContext)
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+					return
+				}
+				evaluations[worker] = append(evaluations[worker], evaluation{appContext, enabled})
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	versions := make(map[any][]any)
+	for _, call := range recorder.Calls() {
+		versions[call.AppContext] = append(versions[call.AppContext], call.Context.Parameters["Version"])
+	}
+	for _, results := range evaluations {
+		for _, evaluation := range results {
+			seen := versions[evaluation.appContext]
+			if len(seen) != 2 || seen[0] != seen[1] {
+				t.Fatalf("Expected both filters of an evaluation to see the same definition, got versions %v", seen)
+			}
+			// The targeting filter must have used the users of the same definition
+			if evaluation.enabled != (seen[0] == float64(1)) {
+				t.Fatalf("Expected the targeting filter to use the users of version %v, got enabled=%v", seen[0], evaluation.enabled)
+			}
+		}
+	}
+
+	if provider.Updates() == 0 {
+		t.Error("Expected the provider to refresh during the test")
+	}
+}
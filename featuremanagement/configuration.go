@@ -0,0 +1,520 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+const (
+	// featureManagementSection is the section holding feature flags in the v2 schema
+	featureManagementSection = "feature_management"
+	// dotnetFeatureManagementSection is the section holding feature flags in .NET appsettings.json
+	dotnetFeatureManagementSection = "FeatureManagement"
+	// appConfigFeatureFlagPrefix is the key prefix of feature flags stored in Azure App Configuration
+	appConfigFeatureFlagPrefix = ".appconfig.featureflag/"
+)
+
+// DecodeOptions configures how feature flag configuration is decoded.
+type DecodeOptions struct {
+	// Strict rejects unknown fields and values of the wrong type instead of ignoring or converting
+	// them, catching typos such as "client_filter" for "client_filters" or "enabled": "yes" at load
+	// time. Field names must match the schema exactly, except in the case-insensitive .NET schema.
+	Strict bool
+}
+
+// Schema identifies a feature flag configuration schema recognized by DecodeFeatureManagement.
+type Schema string
+
+const (
+	// SchemaV2 is the feature management schema: a feature_management section holding a feature_flags array
+	SchemaV2 Schema = "v2"
+	// SchemaV1 is the .NET schema: a FeatureManagement section holding features keyed by name
+	SchemaV1 Schema = "v1"
+	// SchemaAppConfig is raw Azure App Configuration feature flags: .appconfig.featureflag/<name> keys with JSON values
+	SchemaAppConfig Schema = "appconfig"
+)
+
+// dotnetFilterNames maps the short filter aliases accepted by .NET to the filter names used here
+var dotnetFilterNames = map[string]string{
+	"targeting":  "Microsoft.Targeting",
+	"timewindow": "Microsoft.TimeWindow",
+}
+
+// ParseFeatureManagement parses a JSON configuration document into feature flag definitions.
+// See DecodeFeatureManagement for the supported schemas. The document may also be a list of
+// key-values exported from Azure App Configuration, each an object with a "key" and a "value".
+//
+// Parameters:
+//   - data: The JSON configuration document or exported key-values
+//
+// Returns:
+//   - FeatureManagement: The feature flags defined by the document
+//   - error: An error if the document is not valid JSON or a section is malformed
+func ParseFeatureManagement(data []byte) (FeatureManagement, error) {
+	return ParseFeatureManagementWithOptions(data, nil)
+}
+
+// ParseFeatureManagementWithOptions is like ParseFeatureManagement, decoding as configured by the options.
+//
+// Parameters:
+//   - data: The JSON configuration document or exported key-values
+//   - options: The decoding options, or nil for the defaults
+//
+// Returns:
+//   - FeatureManagement: The feature flags defined by the document
+//   - error: An error if the document is not valid JSON or a section is malformed
+func ParseFeatureManagementWithOptions(data []byte, options *DecodeOptions) (FeatureManagement, error) {
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return FeatureManagement{}, fmt.Errorf("failed to parse feature management configuration: %w", err)
+	}
+
+	switch document := document.(type) {
+	case map[string]any:
+		return DecodeFeatureManagementWithOptions(document, options)
+	case []any:
+		config, err := keyValuesToConfig(document)
+		if err != nil {
+			return FeatureManagement{}, err
+		}
+		return DecodeFeatureManagementWithOptions(config, options)
+	default:
+		return FeatureManagement{}, fmt.Errorf("failed to parse feature management configuration: expected an object or an array, got %T", document)
+	}
+}
+
+// DetectSchemas reports the feature flag schemas present in a configuration, in the order of
+// precedence applied by DecodeFeatureManagement.
+//
+// Parameters:
+//   - config: The configuration, with the feature management sections at its root
+//
+// Returns:
+//   - []Schema: The schemas found, or none if the configuration defines no feature flags
+func DetectSchemas(config map[string]any) []Schema {
+	var schemas []Schema
+	if _, ok := config[featureManagementSection]; ok {
+		schemas = append(schemas, SchemaV2)
+	}
+	for key := range config {
+		if strings.HasPrefix(key, appConfigFeatureFlagPrefix) {
+			schemas = append(schemas, SchemaAppConfig)
+			break
+		}
+	}
+	if _, ok := config[findKey(config, dotnetFeatureManagementSection)]; ok {
+		schemas = append(schemas, SchemaV1)
+	}
+
+	return schemas
+}
+
+// DecodeFeatureManagement decodes feature flag definitions from a hierarchical configuration,
+// such as a parsed JSON document or the output of a configuration library. The schemas are
+// detected and normalized into the same model, so older stores, exported files and configuration
+// shared with .NET services load without conversion:
+//
+//	{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": true}]}}
+//	{".appconfig.featureflag/Beta": "{\"id\": \"Beta\", \"enabled\": true}"}
+//	{"FeatureManagement": {"Beta": {"EnabledFor": [{"Name": "TimeWindow", "Parameters": {...}}]}}}
+//
+// Raw App Configuration feature flags hold the same definition as a feature_flags entry, either
+// as a JSON string or an object; a definition without an ID takes the name from its key.
+//
+// In the .NET schema, a feature can also be declared as a boolean, "Beta": true, to enable or disable
+// it unconditionally. Keys are matched case-insensitively, "RequirementType" selects "Any" or "All",
+// the "AlwaysOn" filter enables a feature unconditionally, and a feature with no filters is disabled.
+// When a feature is defined by several schemas, the first definition in the order above wins.
+//
+// Parameters:
+//   - config: The configuration, with the feature management sections at its root
+//
+// Returns:
+//   - FeatureManagement: The feature flags defined by the configuration
+//   - error: An error if a section is malformed
+func DecodeFeatureManagement(config map[string]any) (FeatureManagement, error) {
+	return DecodeFeatureManagementWithOptions(config, nil)
+}
+
+// DecodeFeatureManagementWithOptions is like DecodeFeatureManagement, decoding as configured by the options.
+//
+// Parameters:
+//   - config: The configuration, with the feature management sections at its root
+//   - options: The decoding options, or nil for the defaults
+//
+// Returns:
+//   - FeatureManagement: The feature flags defined by the configuration
+//   - error: An error if a section is malformed, naming the offending feature flag
+func DecodeFeatureManagementWithOptions(config map[string]any, options *DecodeOptions) (FeatureManagement, error) {
+	if options == nil {
+		options = &DecodeOptions{}
+	}
+
+	var result FeatureManagement
+	if section, ok := config[featureManagementSection]; ok {
+		flags, templates, err := decodeFeatureFlags(section, options.Strict)
+		if err != nil {
+			return FeatureManagement{}, fmt.Errorf("invalid %s section: %w", featureManagementSection, err)
+		}
+		flags, err = ResolveFilterTemplates(flags, templates)
+		if err != nil {
+			return FeatureManagement{}, fmt.Errorf("invalid %s section: %w", featureManagementSection, err)
+		}
+		result.FeatureFlags = flags
+		result.FilterTemplates = templates
+	}
+
+	defined := make(map[string]bool, len(result.FeatureFlags))
+	for _, flag := range result.FeatureFlags {
+		defined[flag.ID] = true
+	}
+	merge := func(flags []FeatureFlag) {
+		for _, flag := range flags {
+			if !defined[flag.ID] {
+				defined[flag.ID] = true
+				result.FeatureFlags = append(result.FeatureFlags, flag)
+			}
+		}
+	}
+
+	flags, err := decodeAppConfigFeatureFlags(config, options.Strict)
+	if err != nil {
+		return FeatureManagement{}, err
+	}
+	merge(flags)
+
+	key := findKey(config, dotnetFeatureManagementSection)
+	if section, ok := config[key]; ok {
+		flags, err := decodeDotnetFeatureFlags(section, options.Strict)
+		if err != nil {
+			return FeatureManagement{}, fmt.Errorf("invalid %s section: %w", key, err)
+		}
+		merge(flags)
+	}
+
+	return result, nil
+}
+
+// DecodeFeatureFlag decodes a single feature flag definition from its JSON value, as stored by
+// key-value stores holding one feature flag per key. A definition without an ID takes the ID
+// given, typically the name of its key.
+//
+// Parameters:
+//   - data: The JSON value of the feature flag
+//   - id: The ID of a definition without one, or "" to leave it unset
+//   - options: The decoding options, or nil for the defaults
+//
+// Returns:
+//   - FeatureFlag: The feature flag defined by the value
+//   - error: An error if the value is not valid JSON or is not a feature flag object, such as null
+func DecodeFeatureFlag(data []byte, id string, options *DecodeOptions) (FeatureFlag, error) {
+	var flag map[string]any
+	if err := json.Unmarshal(data, &flag); err != nil {
+		return FeatureFlag{}, fmt.Errorf("failed to parse feature flag: %w", err)
+	}
+	if flag == nil {
+		return FeatureFlag{}, fmt.Errorf("value is not a feature flag")
+	}
+	if _, ok := flag["id"]; !ok && id != "" {
+		flag["id"] = id
+	}
+
+	featureManagement, err := DecodeFeatureManagementWithOptions(map[string]any{
+		featureManagementSection: map[string]any{"feature_flags": []any{flag}},
+	}, options)
+	if err != nil {
+		return FeatureFlag{}, err
+	}
+	if len(featureManagement.FeatureFlags) != 1 {
+		return FeatureFlag{}, fmt.Errorf("value is not a feature flag")
+	}
+
+	return featureManagement.FeatureFlags[0], nil
+}
+
+// decodeFeatureFlags decodes a feature_management section one flag at a time, so that errors can
+// name the flag, and returns the flags along with the filter templates of the section
+func decodeFeatureFlags(section any, strict bool) ([]FeatureFlag, map[string]ClientFilter, error) {
+	var featureManagement struct {
+		FeatureFlags    []any                   `json:"feature_flags"`
+		FilterTemplates map[string]ClientFilter `json:"filter_templates"`
+	}
+	if err := decodeSchema(section, &featureManagement, strict); err != nil {
+		return nil, nil, err
+	}
+
+	flags := make([]FeatureFlag, 0, len(featureManagement.FeatureFlags))
+	for i, entry := range featureManagement.FeatureFlags {
+		var flag FeatureFlag
+		if err := decodeSchema(entry, &flag, strict); err != nil {
+			if entry, ok := entry.(map[string]any); ok && entry["id"] != nil {
+				return nil, nil, fmt.Errorf("invalid feature flag %v: %w", entry["id"], err)
+			}
+			return nil, nil, fmt.Errorf("invalid feature flag at index %d: %w", i, err)
+		}
+		flags = append(flags, flag)
+	}
+
+	return flags, featureManagement.FilterTemplates, nil
+}
+
+// decodeAppConfigFeatureFlags decodes the raw App Configuration feature flags, in order of their keys
+func decodeAppConfigFeatureFlags(config map[string]any, strict bool) ([]FeatureFlag, error) {
+	var keys []string
+	for key := range config {
+		if strings.HasPrefix(key, appConfigFeatureFlagPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	flags := make([]FeatureFlag, 0, len(keys))
+	for _, key := range keys {
+		value := config[key]
+		if raw, ok := value.(string); ok {
+			if err := json.Unmarshal([]byte(raw), &value); err != nil {
+				return nil, fmt.Errorf("invalid feature flag %s: %w", key, err)
+			}
+		}
+
+		var flag FeatureFlag
+		if err := decodeSchema(value, &flag, strict); err != nil {
+			return nil, fmt.Errorf("invalid feature flag %s: %w", key, err)
+		}
+		if flag.ID == "" {
+			flag.ID = strings.TrimPrefix(key, appConfigFeatureFlagPrefix)
+		}
+		flags = append(flags, flag)
+	}
+
+	return flags, nil
+}
+
+// keyValuesToConfig converts an exported list of App Configuration key-values, each with a "key"
+// and a "value", into a configuration keyed by setting key
+func keyValuesToConfig(keyValues []any) (map[string]any, error) {
+	config := make(map[string]any, len(keyValues))
+	for i, entry := range keyValues {
+		keyValue, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid key-value %d: expected an object, got %T", i, entry)
+		}
+
+		key, _ := keyValue["key"].(string)
+		if key == "" {
+			return nil, fmt.Errorf("invalid key-value %d: key is required", i)
+		}
+		config[key] = keyValue["value"]
+	}
+
+	return config, nil
+}
+
+// decodeSchema decodes configuration values into the json-tagged schema types. By default it
+// accepts string representations of numbers and booleans, as hierarchical configuration often
+// holds them, and ignores unknown fields; strict decoding rejects both.
+func decodeSchema(input any, result any, strict bool) error {
+	config := &mapstructure.DecoderConfig{
+		Result:           result,
+		TagName:          "json",
+		WeaklyTypedInput: !strict,
+	}
+	if strict {
+		config.ErrorUnused = true
+		config.MatchName = func(mapKey, fieldName string) bool {
+			return mapKey == fieldName
+		}
+	}
+
+	decoder, err := mapstructure.NewDecoder(config)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(input)
+}
+
+// decodeDotnetFeatureFlags converts the features of a .NET FeatureManagement section, in order of their names
+func decodeDotnetFeatureFlags(section any, strict bool) ([]FeatureFlag, error) {
+	features, ok := section.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object of features, got %T", section)
+	}
+
+	names := make([]string, 0, len(features))
+	for name := range features {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	flags := make([]FeatureFlag, 0, len(names))
+	for _, name := range names {
+		flag, err := decodeDotnetFeatureFlag(name, features[name], strict)
+		if err != nil {
+			return nil, fmt.Errorf("feature %s: %w", name, err)
+		}
+		flags = append(flags, flag)
+	}
+
+	return flags, nil
+}
+
+func decodeDotnetFeatureFlag(name string, definition any, strict bool) (FeatureFlag, error) {
+	flag := FeatureFlag{ID: name}
+
+	// Shorthand for an unconditionally enabled or disabled feature: "Beta": true
+	switch value := definition.(type) {
+	case bool:
+		flag.Enabled = value
+		return flag, nil
+	case string:
+		if strict {
+			return FeatureFlag{}, fmt.Errorf("expected a boolean or an object, got %q", value)
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return FeatureFlag{}, fmt.Errorf("expected a boolean or an object, got %q", value)
+		}
+		flag.Enabled = enabled
+		return flag, nil
+	}
+
+	feature, ok := definition.(map[string]any)
+	if !ok {
+		return FeatureFlag{}, fmt.Errorf("expected a boolean or an object, got %T", definition)
+	}
+	if strict {
+		if err := checkKnownKeys(feature, "EnabledFor", "RequirementType"); err != nil {
+			return FeatureFlag{}, err
+		}
+	}
+
+	var requirementType RequirementType
+	if value, ok := feature[findKey(feature, "RequirementType")]; ok {
+		raw, _ := value.(string)
+		switch {
+		case strings.EqualFold(raw, string(RequirementTypeAny)):
+			requirementType = RequirementTypeAny
+		case strings.EqualFold(raw, string(RequirementTypeAll)):
+			requirementType = RequirementTypeAll
+		default:
+			return FeatureFlag{}, fmt.Errorf("RequirementType must be 'Any' or 'All', got %v", value)
+		}
+	}
+
+	entries, err := dotnetList(feature[findKey(feature, "EnabledFor")])
+	if err != nil {
+		return FeatureFlag{}, fmt.Errorf("invalid EnabledFor: %w", err)
+	}
+
+	alwaysOn := false
+	var filters []ClientFilter
+	for i, entry := range entries {
+		filter, ok := entry.(map[string]any)
+		if !ok {
+			return FeatureFlag{}, fmt.Errorf("invalid EnabledFor[%d]: expected an object, got %T", i, entry)
+		}
+
+		if strict {
+			if err := checkKnownKeys(filter, "Name", "Parameters"); err != nil {
+				return FeatureFlag{}, fmt.Errorf("invalid EnabledFor[%d]: %w", i, err)
+			}
+		}
+
+		filterName, _ := filter[findKey(filter, "Name")].(string)
+		if filterName == "" {
+			return FeatureFlag{}, fmt.Errorf("invalid EnabledFor[%d]: filter name is required", i)
+		}
+		if strings.EqualFold(filterName, "AlwaysOn") || strings.EqualFold(filterName, "On") {
+			alwaysOn = true
+			continue
+		}
+		if canonical, ok := dotnetFilterNames[strings.ToLower(filterName)]; ok {
+			filterName = canonical
+		}
+
+		clientFilter := ClientFilter{Name: filterName}
+		if parameters, ok := filter[findKey(filter, "Parameters")]; ok && parameters != nil {
+			if clientFilter.Parameters, ok = parameters.(map[string]any); !ok {
+				return FeatureFlag{}, fmt.Errorf("invalid EnabledFor[%d]: parameters must be an object, got %T", i, parameters)
+			}
+		}
+		filters = append(filters, clientFilter)
+	}
+
+	// A feature is enabled for the contexts selected by its filters; without filters it is off
+	flag.Enabled = alwaysOn || len(filters) > 0
+
+	// AlwaysOn satisfies an Any requirement by itself; for All the remaining filters decide
+	if len(filters) > 0 && !(alwaysOn && requirementType != RequirementTypeAll) {
+		flag.Conditions = &Conditions{
+			RequirementType: requirementType,
+			ClientFilters:   filters,
+		}
+	}
+
+	return flag, nil
+}
+
+// checkKnownKeys returns an error for the first key, in sorted order, not matching a known key case-insensitively
+func checkKnownKeys(m map[string]any, known ...string) error {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		found := false
+		for _, name := range known {
+			if strings.EqualFold(key, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown field %q", key)
+		}
+	}
+
+	return nil
+}
+
+// dotnetList returns the entries of a configuration array, which hierarchical configuration
+// may also represent as an object keyed by index
+func dotnetList(value any) ([]any, error) {
+	switch list := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		return list, nil
+	case map[string]any:
+		indexes := make([]int, 0, len(list))
+		entries := make(map[int]any, len(list))
+		for key, entry := range list {
+			index, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, fmt.Errorf("expected an array, got key %q", key)
+			}
+			indexes = append(indexes, index)
+			entries[index] = entry
+		}
+		sort.Ints(indexes)
+
+		result := make([]any, 0, len(indexes))
+		for _, index := range indexes {
+			result = append(result, entries[index])
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("expected an array, got %T", value)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseFeatureManagementDotnetSchema(t *testing.T) {
+	config := `{
+		"feature_management": {
+			"feature_flags": [
+				{"id": "Shared", "enabled": false}
+			]
+		},
+		"featureManagement": {
+			"Shared": {"EnabledFor": [{"Name": "AlwaysOn"}]},
+			"AlwaysOn": {"EnabledFor": [{"Name": "AlwaysOn"}]},
+			"Off": {"EnabledFor": []},
+			"On": true,
+			"OffShorthand": false,
+			"FromString": "True",
+			"Windowed": {
+				"requirementType": "all",
+				"EnabledFor": [
+					{"Name": "AlwaysOn"},
+					{"Name": "TimeWindow", "Parameters": {"Start": "Mon, 01 Jan 2024 00:00:00 GMT"}}
+				]
+			},
+			"Targeted": {
+				"EnabledFor": {
+					"0": {"Name": "Microsoft.Targeting", "Parameters": {"Audience": {"Users": ["Alice"]}}}
+				}
+			}
+		}
+	}`
+
+	featureManagement, err := ParseFeatureManagement([]byte(config))
+	if err != nil {
+		t.Fatalf("Failed to parse configuration: %v", err)
+	}
+
+	flags := make(map[string]FeatureFlag)
+	for _, flag := range featureManagement.FeatureFlags {
+		flags[flag.ID] = flag
+	}
+	if len(flags) != 8 {
+		t.Fatalf("Expected 5 feature flags, got %v", featureManagement.FeatureFlags)
+	}
+
+	if flags["Shared"].Enabled {
+		t.Error("Expected the feature_management definition of Shared to win")
+	}
+	if flag := flags["AlwaysOn"]; !flag.Enabled || flag.Conditions != nil {
+		t.Errorf("Expected AlwaysOn to be unconditionally enabled, got %+v", flag)
+	}
+	if flags["Off"].Enabled {
+		t.Error("Expected a feature without filters to be disabled")
+	}
+	for name, expected := range map[string]bool{"On": true, "OffShorthand": false, "FromString": true} {
+		if flag := flags[name]; flag.Enabled != expected || flag.Conditions != nil {
+			t.Errorf("Expected shorthand %s to be unconditionally %v, got %+v", name, expected, flag)
+		}
+	}
+
+	windowed := flags["Windowed"]
+	if !windowed.Enabled || windowed.Conditions == nil || windowed.Conditions.RequirementType != RequirementTypeAll {
+		t.Fatalf("Unexpected Windowed flag %+v", windowed)
+	}
+	if filters := windowed.Conditions.ClientFilters; len(filters) != 1 || filters[0].Name != "Microsoft.TimeWindow" {
+		t.Errorf("Expected only the canonical time window filter, got %+v", filters)
+	}
+
+	manager, err := NewFeatureManager(NewStaticProvider(flags), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	for name, expected := range map[string]bool{"AlwaysOn": true, "Off": false, "Windowed": true} {
+		if enabled, err := manager.IsEnabled(name); err != nil || enabled != expected {
+			t.Errorf("Expected %s to be %v, got %v, %v", name, expected, enabled, err)
+		}
+	}
+	if enabled, _ := manager.IsEnabledWithAppContext("Targeted", TargetingContext{UserID: "Alice"}); !enabled {
+		t.Error("Expected Targeted to be enabled for Alice")
+	}
+}
+
+func TestParseFeatureManagementErrors(t *testing.T) {
+	for _, config := range []string{
+		`not json`,
+		`{"feature_management": {"feature_flags": "Beta"}}`,
+		`{"FeatureManagement": ["Beta"]}`,
+		`{"FeatureManagement": {"Beta": "yes"}}`,
+		`{"FeatureManagement": {"Beta": 1}}`,
+		`{"FeatureManagement": {"Beta": {"RequirementType": "Some"}}}`,
+		`{"FeatureManagement": {"Beta": {"EnabledFor": [{"Parameters": {}}]}}}`,
+	} {
+		if _, err := ParseFeatureManagement([]byte(config)); err == nil {
+			t.Errorf("Expected error for %s", config)
+		}
+	}
+}
+
+func TestParseFeatureManagementAppConfigPayloads(t *testing.T) {
+	config := `{
+		"feature_management": {"feature_flags": [{"id": "Beta", "enabled": true}]},
+		".appconfig.featureflag/Beta": "{\"id\": \"Beta\", \"enabled\": false}",
+		".appconfig.featureflag/Gamma": "{\"id\": \"Gamma\", \"enabled\": true, \"conditions\": {\"client_filters\": []}}",
+		".appconfig.featureflag/Delta": {"enabled": "true"},
+		"FeatureManagement": {"Delta": false, "Legacy": true}
+	}`
+
+	var document map[string]any
+	if err := json.Unmarshal([]byte(config), &document); err != nil {
+		t.Fatal(err)
+	}
+	if schemas := DetectSchemas(document); fmt.Sprint(schemas) != "[v2 appconfig v1]" {
+		t.Errorf("Unexpected detected schemas %v", schemas)
+	}
+
+	featureManagement, err := DecodeFeatureManagement(document)
+	if err != nil {
+		t.Fatalf("Failed to decode configuration: %v", err)
+	}
+
+	expected := map[string]bool{"Beta": true, "Gamma": true, "Delta": true, "Legacy": true}
+	if len(featureManagement.FeatureFlags) != len(expected) {
+		t.Fatalf("Expected %d feature flags, got %+v", len(expected), featureManagement.FeatureFlags)
+	}
+	for _, flag := range featureManagement.FeatureFlags {
+		if enabled, ok := expected[flag.ID]; !ok || flag.Enabled != enabled {
+			t.Errorf("Unexpected feature flag %+v", flag)
+		}
+	}
+}
+
+func TestParseFeatureManagementExportedKeyValues(t *testing.T) {
+	export := `[
+		{"key": "Settings:Color", "value": "blue"},
+		{
+			"key": ".appconfig.featureflag/Beta",
+			"content_type": "application/vnd.microsoft.appconfig.ff+json;charset=utf-8",
+			"value": "{\"id\": \"Beta\", \"enabled\": true}"
+		}
+	]`
+
+	featureManagement, err := ParseFeatureManagement([]byte(export))
+	if err != nil {
+		t.Fatalf("Failed to parse export: %v", err)
+	}
+	if flags := featureManagement.FeatureFlags; len(flags) != 1 || flags[0].ID != "Beta" || !flags[0].Enabled {
+		t.Errorf("Unexpected feature flags %+v", flags)
+	}
+
+	for _, invalid := range []string{`[1]`, `[{"value": "x"}]`, `{".appconfig.featureflag/Beta": "{"}`, `"Beta"`} {
+		if _, err := ParseFeatureManagement([]byte(invalid)); err == nil {
+			t.Errorf("Expected error for %s", invalid)
+		}
+	}
+}
+
+func TestParseFeatureManagementStrict(t *testing.T) {
+	valid := `{
+		"feature_management": {
+			"feature_flags": [
+				{"id": "Beta", "enabled": true, "conditions": {"client_filters": [{"name": "Custom", "parameters": {"Any": "value"}}]}}
+			]
+		},
+		"FeatureManagement": {"Legacy": {"enabledFor": [{"name": "AlwaysOn"}]}}
+	}`
+	if _, err := ParseFeatureManagementWithOptions([]byte(valid), &DecodeOptions{Strict: true}); err != nil {
+		t.Fatalf("Unexpected error in strict mode: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		config   string
+		contains string
+	}{
+		{
+			name:     "Misspelled field",
+			config:   `{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": true, "conditions": {"client_filter": []}}]}}`,
+			contains: "Beta",
+		},
+		{
+			name:     "Wrong type",
+			config:   `{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": "true"}]}}`,
+			contains: "Beta",
+		},
+		{
+			name:     "Wrong casing",
+			config:   `{"feature_management": {"feature_flags": [{"ID": "Beta", "enabled": true}]}}`,
+			contains: "index 0",
+		},
+		{
+			name:     "Unknown .NET field",
+			config:   `{"FeatureManagement": {"Beta": {"EnabledFor": [], "Requirement": "All"}}}`,
+			contains: "Requirement",
+		},
+		{
+			name:     "String .NET shorthand",
+			config:   `{"FeatureManagement": {"Beta": "true"}}`,
+			contains: "Beta",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseFeatureManagement([]byte(tc.config)); err != nil {
+				t.Errorf("Expected lenient decoding to succeed, got %v", err)
+			}
+
+			_, err := ParseFeatureManagementWithOptions([]byte(tc.config), &DecodeOptions{Strict: true})
+			if err == nil {
+				t.Fatal("Expected an error in strict mode")
+			}
+			if !strings.Contains(err.Error(), tc.contains) {
+				t.Errorf("Expected error to mention %q, got %v", tc.contains, err)
+			}
+		})
+	}
+}
+
+func TestDecodeFeatureFlag(t *testing.T) {
+	flag, err := DecodeFeatureFlag([]byte(`{"enabled": true}`), "Beta", nil)
+	if err != nil {
+		t.Fatalf("Failed to decode feature flag: %v", err)
+	}
+	if flag.ID != "Beta" || !flag.Enabled {
+		t.Errorf("Expected Beta enabled, got %+v", flag)
+	}
+
+	flag, err = DecodeFeatureFlag([]byte(`{"id": "Gamma"}`), "Beta", nil)
+	if err != nil || flag.ID != "Gamma" {
+		t.Errorf("Expected the ID of the definition to win, got %+v and %v", flag, err)
+	}
+
+	for _, value := range []string{`null`, `not json`, `[]`, `"Beta"`} {
+		if _, err := DecodeFeatureFlag([]byte(value), "Beta", nil); err == nil {
+			t.Errorf("Expected error for %s", value)
+		}
+	}
+}
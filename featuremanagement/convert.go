@@ -0,0 +1,164 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// EncodeFeatureManagement encodes feature flag definitions into a configuration with the given
+// schema, the inverse of DecodeFeatureManagement, so that configuration can be migrated between
+// the feature_management schema, .NET appsettings and raw App Configuration key-values:
+//
+//	featureManagement, _ := featuremanagement.ParseFeatureManagement(appsettings)
+//	config, _ := featuremanagement.EncodeFeatureManagement(featureManagement.FeatureFlags, featuremanagement.SchemaAppConfig)
+//
+// Raw App Configuration feature flags hold their definition as a JSON string. The .NET schema
+// only expresses whether a feature is enabled and its filters: a feature with variants, an
+// allocation or telemetry can't be encoded into it, and its description, display name and tags
+// are dropped. Filter templates are not kept, since flags hold the filters they resolve to.
+//
+// Parameters:
+//   - flags: The feature flag definitions to encode
+//   - schema: The schema of the configuration
+//
+// Returns:
+//   - map[string]any: The configuration, with the feature management sections at its root
+//   - error: An error if the schema is unknown or a flag can't be expressed in it
+func EncodeFeatureManagement(flags []FeatureFlag, schema Schema) (map[string]any, error) {
+	switch schema {
+	case SchemaV2:
+		encoded := make([]any, 0, len(flags))
+		for _, flag := range flags {
+			value, err := encodeFeatureFlag(flag)
+			if err != nil {
+				return nil, err
+			}
+			encoded = append(encoded, value)
+		}
+		return map[string]any{featureManagementSection: map[string]any{"feature_flags": encoded}}, nil
+
+	case SchemaAppConfig:
+		config := make(map[string]any, len(flags))
+		for _, flag := range flags {
+			data, err := json.Marshal(withoutTemplates(flag))
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode feature flag %s: %w", flag.ID, err)
+			}
+			config[appConfigFeatureFlagPrefix+flag.ID] = string(data)
+		}
+		return config, nil
+
+	case SchemaV1:
+		features := make(map[string]any, len(flags))
+		for _, flag := range flags {
+			feature, err := encodeDotnetFeatureFlag(flag)
+			if err != nil {
+				return nil, err
+			}
+			features[flag.ID] = feature
+		}
+		return map[string]any{dotnetFeatureManagementSection: features}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown feature flag schema %q", schema)
+	}
+}
+
+// ConvertFeatureManagement converts a JSON configuration document in any schema recognized by
+// ParseFeatureManagement into an indented JSON document with the given schema.
+//
+// Parameters:
+//   - data: The JSON configuration document or exported key-values
+//   - schema: The schema of the converted document
+//
+// Returns:
+//   - []byte: The converted document
+//   - error: An error if the document is malformed or a flag can't be expressed in the schema
+func ConvertFeatureManagement(data []byte, schema Schema) ([]byte, error) {
+	featureManagement, err := ParseFeatureManagement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := EncodeFeatureManagement(featureManagement.FeatureFlags, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(config, "", "  ")
+}
+
+// encodeFeatureFlag encodes a feature flag into its generic JSON form
+func encodeFeatureFlag(flag FeatureFlag) (any, error) {
+	data, err := json.Marshal(withoutTemplates(flag))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feature flag %s: %w", flag.ID, err)
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("failed to encode feature flag %s: %w", flag.ID, err)
+	}
+
+	return value, nil
+}
+
+// encodeDotnetFeatureFlag encodes a feature flag into the .NET schema, as a boolean when it
+// doesn't depend on filters
+func encodeDotnetFeatureFlag(flag FeatureFlag) (any, error) {
+	var unsupported []string
+	if len(flag.Variants) > 0 {
+		unsupported = append(unsupported, "variants")
+	}
+	if flag.Allocation != nil {
+		unsupported = append(unsupported, "allocation")
+	}
+	if flag.Telemetry != nil {
+		unsupported = append(unsupported, "telemetry")
+	}
+	if len(unsupported) > 0 {
+		return nil, fmt.Errorf("feature flag %s can't be encoded in the %s schema: it has %s", flag.ID, SchemaV1, strings.Join(unsupported, ", "))
+	}
+
+	if !flag.Enabled || flag.Conditions == nil || len(flag.Conditions.ClientFilters) == 0 {
+		return flag.Enabled, nil
+	}
+
+	enabledFor := make([]any, 0, len(flag.Conditions.ClientFilters))
+	for _, filter := range flag.Conditions.ClientFilters {
+		entry := map[string]any{"Name": filter.Name}
+		if filter.Parameters != nil {
+			entry["Parameters"] = filter.Parameters
+		}
+		enabledFor = append(enabledFor, entry)
+	}
+
+	feature := map[string]any{"EnabledFor": enabledFor}
+	if flag.Conditions.RequirementType != "" {
+		feature["RequirementType"] = string(flag.Conditions.RequirementType)
+	}
+
+	return feature, nil
+}
+
+// withoutTemplates returns the flag with the template references of its filters removed, since
+// the filters hold the definitions of their templates
+func withoutTemplates(flag FeatureFlag) FeatureFlag {
+	if flag.Conditions == nil || !referencesTemplate(flag.Conditions.ClientFilters) {
+		return flag
+	}
+
+	conditions := *flag.Conditions
+	conditions.ClientFilters = make([]ClientFilter, len(flag.Conditions.ClientFilters))
+	for i, filter := range flag.Conditions.ClientFilters {
+		filter.Template = ""
+		conditions.ClientFilters[i] = filter
+	}
+	flag.Conditions = &conditions
+
+	return flag
+}
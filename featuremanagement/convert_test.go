@@ -0,0 +1,87 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeFeatureManagementRoundTrip(t *testing.T) {
+	flags := []FeatureFlag{
+		{ID: "Off"},
+		{ID: "On", Enabled: true},
+		{
+			ID:      "Beta",
+			Enabled: true,
+			Conditions: &Conditions{
+				RequirementType: RequirementTypeAll,
+				ClientFilters: []ClientFilter{{
+					Name:       "Microsoft.Targeting",
+					Parameters: map[string]any{"Audience": map[string]any{"Users": []any{"alice"}, "DefaultRolloutPercentage": float64(10)}},
+				}},
+			},
+		},
+	}
+
+	for _, schema := range []Schema{SchemaV2, SchemaV1, SchemaAppConfig} {
+		config, err := EncodeFeatureManagement(flags, schema)
+		if err != nil {
+			t.Fatalf("Failed to encode to %s: %v", schema, err)
+		}
+		data, err := json.Marshal(config)
+		if err != nil {
+			t.Fatalf("Failed to marshal %s configuration: %v", schema, err)
+		}
+
+		featureManagement, err := ParseFeatureManagement(data)
+		if err != nil {
+			t.Fatalf("Failed to parse %s configuration %s: %v", schema, data, err)
+		}
+		decoded := make(map[string]FeatureFlag)
+		for _, flag := range featureManagement.FeatureFlags {
+			decoded[flag.ID] = flag
+		}
+		for _, flag := range flags {
+			actual := decoded[flag.ID]
+			if actual.Enabled != flag.Enabled {
+				t.Errorf("Expected %s in %s to have enabled=%v", flag.ID, schema, flag.Enabled)
+			}
+			if flag.Conditions != nil && !reflect.DeepEqual(actual.Conditions, flag.Conditions) {
+				t.Errorf("Expected the conditions of %s in %s to round trip, got %+v", flag.ID, schema, actual.Conditions)
+			}
+		}
+	}
+}
+
+func TestEncodeFeatureManagementDotnetUnsupported(t *testing.T) {
+	flags := []FeatureFlag{{ID: "Greeting", Enabled: true, Variants: []VariantDefinition{{Name: "Big"}}}}
+
+	if _, err := EncodeFeatureManagement(flags, SchemaV1); err == nil || !strings.Contains(err.Error(), "variants") {
+		t.Errorf("Expected an error for a flag with variants, got %v", err)
+	}
+	if _, err := EncodeFeatureManagement(flags, Schema("v3")); err == nil {
+		t.Error("Expected an error for an unknown schema")
+	}
+}
+
+func TestConvertFeatureManagementDropsTemplates(t *testing.T) {
+	data, err := ConvertFeatureManagement([]byte(filterTemplateDocument), SchemaV2)
+	if err != nil {
+		t.Fatalf("Failed to convert document: %v", err)
+	}
+	if strings.Contains(string(data), "template") {
+		t.Errorf("Expected template references to be removed, got %s", data)
+	}
+
+	featureManagement, err := ParseFeatureManagement(data)
+	if err != nil {
+		t.Fatalf("Failed to parse converted document: %v", err)
+	}
+	if filter := featureManagement.FeatureFlags[0].Conditions.ClientFilters[0]; filter.Name != "Microsoft.Targeting" || filter.Parameters == nil {
+		t.Errorf("Expected the resolved filter to be kept, got %+v", filter)
+	}
+}
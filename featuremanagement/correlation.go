@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// EvaluationIDCarrier can be implemented by an app context to supply the ID of an evaluation, so
+// that a decision can be traced with the ID of the request that caused it.
+type EvaluationIDCarrier interface {
+	// EvaluationID returns the ID to give the evaluation, or an empty string to let the feature
+	// manager generate one.
+	EvaluationID() string
+}
+
+type evaluationIDKey struct{}
+
+// WithEvaluationID returns a context carrying the given evaluation ID. Passing the context as the
+// app context of an evaluation gives the evaluation that ID.
+//
+// Parameters:
+//   - ctx: The parent context
+//   - id: The evaluation ID, for example the ID of the request being served
+//
+// Returns:
+//   - context.Context: A context carrying the evaluation ID
+func WithEvaluationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, evaluationIDKey{}, id)
+}
+
+// EvaluationIDFromContext returns the evaluation ID carried by a context.
+//
+// Parameters:
+//   - ctx: The context set up by WithEvaluationID
+//
+// Returns:
+//   - string: The evaluation ID
+//   - bool: true if the context carries an evaluation ID
+func EvaluationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(evaluationIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// NewEvaluationID generates a random evaluation ID of 32 hexadecimal characters. It can be set as
+// Options.EvaluationIDGenerator to give every evaluation an ID.
+//
+// Returns:
+//   - string: A new evaluation ID
+func NewEvaluationID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// evaluationID returns the ID supplied by the app context, or generates one when configured
+func (fm *FeatureManager) evaluationID(appContext any) string {
+	if carrier, ok := appContext.(EvaluationIDCarrier); ok {
+		if id := carrier.EvaluationID(); id != "" {
+			return id
+		}
+	}
+	if ctx, ok := appContext.(context.Context); ok {
+		if id, ok := EvaluationIDFromContext(ctx); ok {
+			return id
+		}
+	}
+	if fm.evaluationIDGenerator != nil {
+		return fm.evaluationIDGenerator()
+	}
+
+	return ""
+}
+
+// withEvaluationID prefixes an error with the ID of the evaluation it happened in, if any
+func withEvaluationID(evaluationID string, err error) error {
+	if evaluationID == "" {
+		return err
+	}
+
+	return fmt.Errorf("evaluation %s: %w", evaluationID, err)
+}
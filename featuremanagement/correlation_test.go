@@ -0,0 +1,80 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type requestContext struct {
+	requestID string
+}
+
+func (c requestContext) EvaluationID() string {
+	return c.requestID
+}
+
+func TestEvaluationID(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {Enabled: true, Telemetry: &Telemetry{Enabled: true}},
+		"Broken": {
+			Enabled:    true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{Name: "Missing"}}},
+		},
+	})
+
+	publisher := &recordingPublisher{}
+	var reported []string
+	manager, err := NewFeatureManager(provider, &Options{
+		EvaluationIDGenerator: NewEvaluationID,
+		TelemetryPublisher:    publisher,
+		OnError: func(featureName string, err error) {
+			reported = append(reported, err.Error())
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	first, err := manager.Evaluate("Beta", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := manager.Evaluate("Beta", nil)
+	if len(first.EvaluationID) != 32 || first.EvaluationID == second.EvaluationID {
+		t.Errorf("Expected distinct generated IDs, got %q and %q", first.EvaluationID, second.EvaluationID)
+	}
+	if events := publisher.events; len(events) != 2 || events[0].Result.EvaluationID != first.EvaluationID {
+		t.Errorf("Expected the telemetry event to carry the evaluation ID, got %+v", publisher.events)
+	}
+
+	ctx := WithEvaluationID(context.Background(), "request-1")
+	if result, _ := manager.Evaluate("Beta", ctx); result.EvaluationID != "request-1" {
+		t.Errorf("Expected the ID from the context, got %q", result.EvaluationID)
+	}
+
+	if result, _ := manager.Evaluate("Broken", requestContext{requestID: "request-2"}); result.EvaluationID != "request-2" {
+		t.Errorf("Expected the ID from the app context, got %q", result.EvaluationID)
+	}
+	if len(reported) != 1 || reported[0] != "evaluation request-2: feature filter Missing is not found" {
+		t.Errorf("Expected the reported error to carry the evaluation ID, got %q", reported)
+	}
+
+	if _, err := manager.Evaluate("Missing", ctx); err == nil || !strings.HasPrefix(err.Error(), "evaluation request-1: ") {
+		t.Errorf("Expected the evaluation error to carry the evaluation ID, got %v", err)
+	}
+}
+
+func TestNoEvaluationIDByDefault(t *testing.T) {
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{"Beta": {Enabled: true}}), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if result, _ := manager.Evaluate("Beta", nil); result.EvaluationID != "" {
+		t.Errorf("Expected no evaluation ID, got %q", result.EvaluationID)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// maxProbeDrain is how much of a probe's response body is read so that its connection can be
+// reused; the connection of a larger body is closed instead
+const maxProbeDrain = 64 << 10
+
+// DependencyHealthCheck reports whether a dependency is healthy by returning nil
+type DependencyHealthCheck func(ctx context.Context) error
+
+// DependencyHealthOptions configures a DependencyHealthFilter
+type DependencyHealthOptions struct {
+	// Checks are the health checks of the dependencies, by the names that feature flags list
+	// in their Dependencies parameter
+	Checks map[string]DependencyHealthCheck
+
+	// HTTPClient probes the URLs that feature flags list in their URLs parameter.
+	// Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Timeout bounds each health check and probe. Defaults to 5 seconds.
+	Timeout time.Duration
+
+	// CacheDuration is how long the result of a health check is reused before the dependency
+	// is checked again. Defaults to 10 seconds.
+	CacheDuration time.Duration
+
+	// LogLevel is the minimum severity of the messages logged about unhealthy dependencies.
+	// Defaults to LogLevelWarn, which logs every failed health check.
+	LogLevel LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+}
+
+// DependencyHealthFilter enables a feature only while the dependencies it relies on are healthy,
+// turning the feature flag into an automatic degradation switch. A dependency is either a
+// health check registered by name in the options, or a URL that is healthy when a GET request
+// returns a 2xx status.
+//
+// Results are cached for the configured duration, so evaluations don't call the dependencies
+// each time, and concurrent evaluations share a single check of a stale dependency. The filter
+// isn't registered by default; register it with Options.Filters.
+//
+// Example:
+//
+//	filter := featuremanagement.NewDependencyHealthFilter(featuremanagement.DependencyHealthOptions{
+//		Checks: map[string]featuremanagement.DependencyHealthCheck{
+//			"payments": func(ctx context.Context) error { return paymentsClient.Ping(ctx) },
+//		},
+//	})
+//	manager, _ := featuremanagement.NewFeatureManager(provider, &featuremanagement.Options{
+//		Filters: []featuremanagement.FeatureFilter{filter},
+//	})
+type DependencyHealthFilter struct {
+	checks        map[string]DependencyHealthCheck
+	client        *http.Client
+	timeout       time.Duration
+	cacheDuration time.Duration
+	logger        *logger
+	now           func() time.Time
+
+	paramCache parameterCache[DependencyHealthFilterParameters]
+
+	mu      sync.Mutex
+	results map[string]healthResult
+	// inflight collapses concurrent checks of the same dependency into one
+	inflight singleflight.Group
+}
+
+// DependencyHealthFilterParameters defines the parameters for the dependency health filter.
+// The feature is enabled when every listed dependency is healthy.
+type DependencyHealthFilterParameters struct {
+	// Dependencies are the names of health checks registered in DependencyHealthOptions.Checks
+	Dependencies []string
+	// URLs are health endpoints probed with GET requests
+	URLs []string
+}
+
+type healthResult struct {
+	err       error
+	checkedAt time.Time
+}
+
+// NewDependencyHealthFilter creates a dependency health filter.
+//
+// Parameters:
+//   - options: The health checks and how they are run
+//
+// Returns:
+//   - *DependencyHealthFilter: The dependency health filter
+func NewDependencyHealthFilter(options DependencyHealthOptions) *DependencyHealthFilter {
+	client := options.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	cacheDuration := options.CacheDuration
+	if cacheDuration <= 0 {
+		cacheDuration = 10 * time.Second
+	}
+
+	return &DependencyHealthFilter{
+		checks:        options.Checks,
+		client:        client,
+		timeout:       timeout,
+		cacheDuration: cacheDuration,
+		logger:        newLogger(options.LogLevel, options.Logger),
+		now:           time.Now,
+		results:       make(map[string]healthResult),
+	}
+}
+
+func (d *DependencyHealthFilter) Name() string {
+	return "Microsoft.DependencyHealth"
+}
+
+func (d *DependencyHealthFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appContext any) (bool, error) {
+	params, err := d.paramCache.get(evalCtx.FeatureName, evalCtx.Parameters, d.decodeParams)
+	if err != nil {
+		return false, err
+	}
+
+	for _, name := range params.Dependencies {
+		check := d.checks[name]
+		if err := d.health("dependency "+name, func(ctx context.Context) error { return check(ctx) }); err != nil {
+			d.logger.warnf("Feature %s is disabled because dependency %s is unhealthy: %v", evalCtx.FeatureName, name, err)
+			return false, nil
+		}
+	}
+
+	for _, url := range params.URLs {
+		if err := d.health("url "+url, func(ctx context.Context) error { return d.probe(ctx, url) }); err != nil {
+			d.logger.warnf("Feature %s is disabled because %s is unhealthy: %v", evalCtx.FeatureName, url, err)
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (d *DependencyHealthFilter) preloadParameters(featureName string, parameters map[string]any) error {
+	_, err := d.paramCache.get(featureName, parameters, d.decodeParams)
+	return err
+}
+
+func (d *DependencyHealthFilter) decodeParams(featureName string, parameters map[string]any) (DependencyHealthFilterParameters, error) {
+	var params DependencyHealthFilterParameters
+	if err := BindParameters(parameters, &params); err != nil {
+		return DependencyHealthFilterParameters{}, fmt.Errorf("invalid dependency health parameters format for feature %s: %w", featureName, err)
+	}
+
+	for _, name := range params.Dependencies {
+		if d.checks[name] == nil {
+			return DependencyHealthFilterParameters{}, fmt.Errorf("invalid feature flag: %s. No health check is registered for dependency %s", featureName, name)
+		}
+	}
+
+	return params, nil
+}
+
+// health returns the cached result of a health check, running it again once the result is stale.
+// Concurrent callers finding the result stale wait for a single run of the check.
+func (d *DependencyHealthFilter) health(key string, check DependencyHealthCheck) error {
+	if result, ok := d.cachedResult(key); ok {
+		return result.err
+	}
+
+	res, _, _ := d.inflight.Do(key, func() (any, error) {
+		// The result may have been refreshed by a run that ended since the cache was read
+		if result, ok := d.cachedResult(key); ok {
+			return result.err, nil
+		}
+
+		checkedAt := d.now()
+		ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+		defer cancel()
+		result := healthResult{err: check(ctx), checkedAt: checkedAt}
+
+		d.mu.Lock()
+		d.results[key] = result
+		d.mu.Unlock()
+
+		return result.err, nil
+	})
+
+	err, _ := res.(error)
+	return err
+}
+
+// cachedResult returns the result of a health check unless it is missing or stale
+func (d *DependencyHealthFilter) cachedResult(key string) (healthResult, bool) {
+	d.mu.Lock()
+	result, ok := d.results[key]
+	d.mu.Unlock()
+
+	return result, ok && d.now().Sub(result.checkedAt) < d.cacheDuration
+}
+
+// probe sends a GET request to a health endpoint, failing unless it returns a 2xx status
+func (d *DependencyHealthFilter) probe(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxProbeDrain))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
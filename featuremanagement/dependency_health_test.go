@@ -0,0 +1,135 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDependencyHealthFilter(t *testing.T) {
+	var paymentsErr error
+	calls := 0
+	filter := NewDependencyHealthFilter(DependencyHealthOptions{
+		Checks: map[string]DependencyHealthCheck{
+			"payments": func(ctx context.Context) error {
+				calls++
+				return paymentsErr
+			},
+		},
+		LogLevel: LogLevelSilent,
+	})
+	now := time.Now()
+	filter.now = func() time.Time { return now }
+
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Checkout": {
+			Enabled: true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{
+				Name:       "Microsoft.DependencyHealth",
+				Parameters: map[string]any{"Dependencies": []any{"payments"}},
+			}}},
+		},
+	})
+	manager, err := NewFeatureManager(provider, &Options{Filters: []FeatureFilter{filter}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if enabled, err := manager.IsEnabled("Checkout"); err != nil || !enabled {
+		t.Errorf("Expected Checkout to be enabled while payments is healthy, got %v, %v", enabled, err)
+	}
+
+	// The cached result is used until it is stale
+	paymentsErr = errors.New("connection refused")
+	if enabled, _ := manager.IsEnabled("Checkout"); !enabled || calls != 1 {
+		t.Errorf("Expected the cached result to be used, got enabled=%v after %d calls", enabled, calls)
+	}
+
+	now = now.Add(time.Minute)
+	if enabled, err := manager.IsEnabled("Checkout"); err != nil || enabled {
+		t.Errorf("Expected Checkout to be disabled while payments is unhealthy, got %v, %v", enabled, err)
+	}
+}
+
+func TestDependencyHealthFilterURL(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	filter := NewDependencyHealthFilter(DependencyHealthOptions{CacheDuration: time.Nanosecond, LogLevel: LogLevelSilent})
+	evalCtx := FeatureFilterEvaluationContext{
+		FeatureName: "Search",
+		Parameters:  map[string]any{"URLs": []any{server.URL}},
+	}
+
+	if enabled, err := filter.Evaluate(evalCtx, nil); err != nil || !enabled {
+		t.Errorf("Expected a healthy endpoint to enable the feature, got %v, %v", enabled, err)
+	}
+
+	status.Store(http.StatusServiceUnavailable)
+	time.Sleep(time.Millisecond)
+	if enabled, err := filter.Evaluate(evalCtx, nil); err != nil || enabled {
+		t.Errorf("Expected an unhealthy endpoint to disable the feature, got %v, %v", enabled, err)
+	}
+}
+
+func TestDependencyHealthFilterUnknownDependency(t *testing.T) {
+	filter := NewDependencyHealthFilter(DependencyHealthOptions{})
+	_, err := filter.Evaluate(FeatureFilterEvaluationContext{
+		FeatureName: "Checkout",
+		Parameters:  map[string]any{"Dependencies": []any{"payments"}},
+	}, nil)
+	if err == nil {
+		t.Error("Expected an error for a dependency without a registered health check")
+	}
+}
+
+func TestDependencyHealthFilterConcurrentChecks(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		// A large body is drained up to a limit rather than read to the end
+		_, _ = w.Write(make([]byte, 4*maxProbeDrain))
+	}))
+	defer server.Close()
+
+	filter := NewDependencyHealthFilter(DependencyHealthOptions{LogLevel: LogLevelSilent})
+	evalCtx := FeatureFilterEvaluationContext{
+		FeatureName: "Search",
+		Parameters:  map[string]any{"URLs": []any{server.URL}},
+	}
+
+	results := make(chan bool, 10)
+	for range cap(results) {
+		go func() {
+			enabled, _ := filter.Evaluate(evalCtx, nil)
+			results <- enabled
+		}()
+	}
+	for requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	for range cap(results) {
+		if !<-results {
+			t.Error("Expected every evaluation to see the healthy endpoint")
+		}
+	}
+	if count := requests.Load(); count != 1 {
+		t.Errorf("Expected concurrent evaluations to share one probe, got %d", count)
+	}
+}
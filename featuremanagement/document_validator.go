@@ -0,0 +1,237 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+//go:embed schemas/feature_management.v2.0.0.schema.json
+var featureManagementSchema []byte
+
+// SchemaViolation describes a part of a configuration document that doesn't conform to the
+// feature management JSON schema.
+type SchemaViolation struct {
+	// Path locates the offending value, for example "$.feature_management.feature_flags[0].enabled"
+	Path string
+	// Message describes the violation
+	Message string
+}
+
+func (v SchemaViolation) Error() string {
+	return v.Path + ": " + v.Message
+}
+
+// JSONSchema returns the embedded JSON schema of the feature_management configuration section,
+// for use with editors and external validation tools.
+//
+// Returns:
+//   - []byte: The JSON schema document
+func JSONSchema() []byte {
+	return bytes.Clone(featureManagementSchema)
+}
+
+// ValidateDocument checks a JSON configuration document against the embedded feature management
+// JSON schema. It reports every violation rather than stopping at the first, so that providers,
+// tools and CI pipelines can show all problems in a document at once.
+//
+// Parameters:
+//   - data: The JSON configuration document, with the feature_management section at its root
+//
+// Returns:
+//   - []error: A SchemaViolation for each violation in document order, or a single error if the
+//     document is not valid JSON; nil if the document conforms to the schema
+func ValidateDocument(data []byte) []error {
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return []error{fmt.Errorf("invalid JSON document: %w", err)}
+	}
+
+	root, err := loadDocumentSchema()
+	if err != nil {
+		return []error{err}
+	}
+
+	var violations []error
+	root.validate(root, document, "$", &violations)
+	return violations
+}
+
+// jsonSchema is the subset of JSON Schema draft 7 used by the embedded schema
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []any                  `json:"enum"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinLength            *int                   `json:"minLength"`
+	AnyOf                []*jsonSchema          `json:"anyOf"`
+	Definitions          map[string]*jsonSchema `json:"definitions"`
+
+	// never is set for the boolean schema false, which no value satisfies
+	never bool
+}
+
+// UnmarshalJSON accepts boolean schemas in addition to schema objects
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	var boolean bool
+	if err := json.Unmarshal(data, &boolean); err == nil {
+		*s = jsonSchema{never: !boolean}
+		return nil
+	}
+
+	type plain jsonSchema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+var loadDocumentSchema = sync.OnceValues(func() (*jsonSchema, error) {
+	var schema jsonSchema
+	if err := json.Unmarshal(featureManagementSchema, &schema); err != nil {
+		return nil, fmt.Errorf("invalid embedded feature management schema: %w", err)
+	}
+
+	return &schema, nil
+})
+
+func (s *jsonSchema) validate(root *jsonSchema, value any, path string, violations *[]error) {
+	if s.never {
+		*violations = append(*violations, SchemaViolation{Path: path, Message: "value is not allowed"})
+		return
+	}
+
+	if s.Ref != "" {
+		name, found := strings.CutPrefix(s.Ref, "#/definitions/")
+		definition := root.Definitions[name]
+		if !found || definition == nil {
+			*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf("unresolved schema reference %s", s.Ref)})
+			return
+		}
+		definition.validate(root, value, path, violations)
+		return
+	}
+
+	if s.Type != "" && jsonType(value, s.Type) != s.Type {
+		*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf("expected %s, got %s", s.Type, jsonType(value, s.Type))})
+		return
+	}
+
+	// A value failing every alternative is reported with the violations of the first one
+	if len(s.AnyOf) > 0 {
+		var first []error
+		for i, alternative := range s.AnyOf {
+			var alternativeViolations []error
+			alternative.validate(root, value, path, &alternativeViolations)
+			if len(alternativeViolations) == 0 {
+				first = nil
+				break
+			}
+			if i == 0 {
+				first = alternativeViolations
+			}
+		}
+		*violations = append(*violations, first...)
+	}
+
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf("must be one of %s", formatEnum(s.Enum))})
+	}
+
+	switch value := value.(type) {
+	case string:
+		if s.MinLength != nil && utf8.RuneCountInString(value) < *s.MinLength {
+			*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf("must be at least %d characters long", *s.MinLength)})
+		}
+	case float64:
+		if s.Minimum != nil && value < *s.Minimum {
+			*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf("must be at least %v", *s.Minimum)})
+		}
+		if s.Maximum != nil && value > *s.Maximum {
+			*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf("must be at most %v", *s.Maximum)})
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range value {
+				s.Items.validate(root, item, fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				*violations = append(*violations, SchemaViolation{Path: path, Message: fmt.Sprintf("missing required property %q", name)})
+			}
+		}
+
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			property, ok := s.Properties[key]
+			if !ok {
+				property = s.AdditionalProperties
+			}
+			if property != nil {
+				property.validate(root, value[key], path+"."+key, violations)
+			}
+		}
+	}
+}
+
+// jsonType returns the JSON type of a decoded value, reporting whole numbers as integer
+// only when that is the expected type
+func jsonType(value any, expected string) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if expected == "integer" && value == math.Trunc(value) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func containsValue(values []any, value any) bool {
+	for _, candidate := range values {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatEnum(values []any) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = fmt.Sprintf("%q", fmt.Sprint(value))
+	}
+
+	return strings.Join(formatted, ", ")
+}
@@ -0,0 +1,90 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateDocument(t *testing.T) {
+	document := `{
+		"feature_management": {
+			"feature_flags": [
+				{"id": "Valid", "enabled": true},
+				{
+					"enabled": "yes",
+					"conditions": {"requirement_type": "Some", "client_filters": [{"parameters": {}}]},
+					"variants": [{"name": "Big", "status_override": "Maybe"}],
+					"allocation": {"percentile": [{"variant": "Big", "from": -1, "to": 101}]},
+					"telemetry": {"metadata": {"Team": 7}}
+				}
+			]
+		},
+		"Logging": {"Level": "Info"}
+	}`
+
+	errs := ValidateDocument([]byte(document))
+
+	expected := []string{
+		`$.feature_management.feature_flags[1]: missing required property "id"`,
+		`$.feature_management.feature_flags[1].allocation.percentile[0].from: must be at least 0`,
+		`$.feature_management.feature_flags[1].allocation.percentile[0].to: must be at most 100`,
+		`$.feature_management.feature_flags[1].conditions.client_filters[0]: missing required property "name"`,
+		`$.feature_management.feature_flags[1].conditions.requirement_type: must be one of "Any", "All"`,
+		`$.feature_management.feature_flags[1].enabled: expected boolean, got string`,
+		`$.feature_management.feature_flags[1].telemetry.metadata.Team: expected string, got number`,
+		`$.feature_management.feature_flags[1].variants[0].status_override: must be one of "None", "Enabled", "Disabled"`,
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d violations, got %d: %v", len(expected), len(errs), errs)
+	}
+	for i, err := range errs {
+		if err.Error() != expected[i] {
+			t.Errorf("Violation %d: expected %q, got %q", i, expected[i], err.Error())
+		}
+		var violation SchemaViolation
+		if !errors.As(err, &violation) || !strings.HasPrefix(violation.Path, "$.") {
+			t.Errorf("Expected a SchemaViolation with a JSON path, got %#v", err)
+		}
+	}
+
+	if errs := ValidateDocument([]byte(`{`)); len(errs) != 1 {
+		t.Errorf("Expected a single error for invalid JSON, got %v", errs)
+	}
+	if errs := ValidateDocument([]byte(`[]`)); len(errs) != 1 || errs[0].Error() != "$: expected object, got array" {
+		t.Errorf("Expected a single type violation for the root, got %v", errs)
+	}
+}
+
+func TestComplianceSamplesConformToSchema(t *testing.T) {
+	samples, err := filepath.Glob(filepath.Join("testdata", "compliance", "*.sample.json"))
+	if err != nil || len(samples) == 0 {
+		t.Fatalf("Failed to find compliance samples: %v", err)
+	}
+
+	for _, sample := range samples {
+		data, err := os.ReadFile(sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if errs := ValidateDocument(data); len(errs) != 0 {
+			t.Errorf("Expected %s to conform to the schema, got %v", sample, errs)
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(JSONSchema(), &schema); err != nil {
+		t.Fatalf("Expected the embedded schema to be valid JSON: %v", err)
+	}
+	if _, ok := schema["definitions"].(map[string]any)["FeatureFlag"]; !ok {
+		t.Error("Expected the schema to define FeatureFlag")
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DuplicatePolicy decides which definition of a feature defined more than once is served, for
+// example by several files of a directory or by several providers combined into one.
+type DuplicatePolicy string
+
+const (
+	// DuplicateFirstWins serves the first definition of a feature and ignores the others.
+	// It is the policy of the zero value.
+	DuplicateFirstWins DuplicatePolicy = "FirstWins"
+	// DuplicateLastWins serves the last definition of a feature, so that later definitions
+	// override earlier ones, with a warning for each definition overridden.
+	DuplicateLastWins DuplicatePolicy = "LastWins"
+	// DuplicateError rejects every definition of a feature defined more than once, so that the
+	// feature evaluates as not found until the conflict is resolved.
+	DuplicateError DuplicatePolicy = "Error"
+)
+
+// ErrDuplicateFeature is wrapped by the validation errors of features rejected by DuplicateError
+var ErrDuplicateFeature = errors.New("feature is defined more than once")
+
+// ResolveDuplicateFeatureFlags applies a duplicate policy to feature flags, so that each feature
+// is defined once. Providers apply it when they load or refresh their feature flags, then index
+// the flags it returns by ID.
+//
+// Example:
+//
+//	flags, rejected, warnings := featuremanagement.ResolveDuplicateFeatureFlags(flags, featuremanagement.DuplicateLastWins)
+//	for _, warning := range warnings {
+//		log.Printf("Feature flag %s: %s", warning.FeatureName, warning.Message)
+//	}
+//
+// Parameters:
+//   - flags: The feature flag definitions, in the order the policy applies to
+//   - policy: The duplicate policy; an empty or unknown policy is DuplicateFirstWins
+//
+// Returns:
+//   - []FeatureFlag: The definitions served, one per feature; a definition kept by
+//     DuplicateLastWins takes the position of its last occurrence
+//   - []ValidationError: An error wrapping ErrDuplicateFeature for each feature rejected by
+//     DuplicateError
+//   - []ValidationWarning: A warning for each definition overridden by DuplicateLastWins
+func ResolveDuplicateFeatureFlags(flags []FeatureFlag, policy DuplicatePolicy) ([]FeatureFlag, []ValidationError, []ValidationWarning) {
+	counts := make(map[string]int, len(flags))
+	for _, flag := range flags {
+		counts[flag.ID]++
+	}
+	if len(counts) == len(flags) {
+		return flags, nil, nil
+	}
+
+	resolved := make([]FeatureFlag, 0, len(counts))
+	var errs []ValidationError
+	var warnings []ValidationWarning
+	seen := make(map[string]int, len(counts))
+	for _, flag := range flags {
+		seen[flag.ID]++
+		count := counts[flag.ID]
+		if count == 1 {
+			resolved = append(resolved, flag)
+			continue
+		}
+
+		switch policy {
+		case DuplicateLastWins:
+			if seen[flag.ID] < count {
+				warnings = append(warnings, ValidationWarning{
+					FeatureName: flag.ID,
+					Message:     fmt.Sprintf("feature %s is defined more than once; definition %d of %d is overridden by the last one", flag.ID, seen[flag.ID], count),
+				})
+				continue
+			}
+			resolved = append(resolved, flag)
+		case DuplicateError:
+			if seen[flag.ID] == 1 {
+				errs = append(errs, ValidationError{
+					FeatureName: flag.ID,
+					Err:         fmt.Errorf("%w: %d definitions of feature %s", ErrDuplicateFeature, count, flag.ID),
+				})
+			}
+		default:
+			if seen[flag.ID] == 1 {
+				resolved = append(resolved, flag)
+			}
+		}
+	}
+
+	return resolved, errs, warnings
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestResolveDuplicateFeatureFlags(t *testing.T) {
+	flags := []FeatureFlag{
+		{ID: "Beta", Description: "first"},
+		{ID: "Alpha"},
+		{ID: "Beta", Description: "second"},
+		{ID: "Gamma"},
+		{ID: "Beta", Description: "third"},
+	}
+
+	describe := func(flags []FeatureFlag) []string {
+		var res []string
+		for _, flag := range flags {
+			res = append(res, flag.ID+":"+flag.Description)
+		}
+		return res
+	}
+
+	t.Run("First wins", func(t *testing.T) {
+		for _, policy := range []DuplicatePolicy{"", DuplicateFirstWins, "Unknown"} {
+			resolved, errs, warnings := ResolveDuplicateFeatureFlags(flags, policy)
+			if want := []string{"Beta:first", "Alpha:", "Gamma:"}; !reflect.DeepEqual(describe(resolved), want) {
+				t.Errorf("Policy %q: expected %v, got %v", policy, want, describe(resolved))
+			}
+			if len(errs) != 0 || len(warnings) != 0 {
+				t.Errorf("Policy %q: expected no errors or warnings, got %v and %v", policy, errs, warnings)
+			}
+		}
+	})
+
+	t.Run("Last wins", func(t *testing.T) {
+		resolved, errs, warnings := ResolveDuplicateFeatureFlags(flags, DuplicateLastWins)
+		if want := []string{"Alpha:", "Gamma:", "Beta:third"}; !reflect.DeepEqual(describe(resolved), want) {
+			t.Errorf("Expected %v, got %v", want, describe(resolved))
+		}
+		if len(errs) != 0 {
+			t.Errorf("Expected no errors, got %v", errs)
+		}
+		if len(warnings) != 2 || warnings[0].FeatureName != "Beta" || warnings[1].FeatureName != "Beta" {
+			t.Errorf("Expected a warning for each overridden definition of Beta, got %v", warnings)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		resolved, errs, warnings := ResolveDuplicateFeatureFlags(flags, DuplicateError)
+		if want := []string{"Alpha:", "Gamma:"}; !reflect.DeepEqual(describe(resolved), want) {
+			t.Errorf("Expected %v, got %v", want, describe(resolved))
+		}
+		if len(errs) != 1 || errs[0].FeatureName != "Beta" || !errors.Is(errs[0], ErrDuplicateFeature) {
+			t.Errorf("Expected one duplicate feature error for Beta, got %v", errs)
+		}
+		if len(warnings) != 0 {
+			t.Errorf("Expected no warnings, got %v", warnings)
+		}
+	})
+
+	t.Run("No duplicates", func(t *testing.T) {
+		unique := []FeatureFlag{flags[1], flags[3]}
+		resolved, errs, warnings := ResolveDuplicateFeatureFlags(unique, DuplicateError)
+		if !reflect.DeepEqual(resolved, unique) || errs != nil || warnings != nil {
+			t.Errorf("Expected the flags unchanged, got %v, %v and %v", resolved, errs, warnings)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+)
+
+// inExclusionGroup reports whether the user falls into the slice of the exclusion group.
+// Users are bucketed by group name, so every experiment of a group sees the same buckets.
+func inExclusionGroup(group *ExclusionGroup, userID string, bucket bucketer) bool {
+	if group == nil {
+		return true
+	}
+
+	targeted, err := isTargetedPercentile(bucket, userID, group.From, group.To, "exclusion_group", group.Name)
+	return err == nil && targeted
+}
+
+// checkExclusionGroups returns an error for each pair of features whose slices of the same
+// exclusion group overlap, which would allocate some users into both experiments
+func checkExclusionGroups(flags iter.Seq[FeatureFlag]) []error {
+	type slice struct {
+		featureName string
+		from, to    float64
+	}
+
+	groups := make(map[string][]slice)
+	for flag := range flags {
+		if flag.Allocation == nil || flag.Allocation.ExclusionGroup == nil {
+			continue
+		}
+		group := flag.Allocation.ExclusionGroup
+		groups[group.Name] = append(groups[group.Name], slice{featureName: flag.ID, from: group.From, to: group.To})
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		slices := groups[name]
+		sort.Slice(slices, func(i, j int) bool {
+			return slices[i].from < slices[j].from
+		})
+		for i := 1; i < len(slices); i++ {
+			if slices[i].from < slices[i-1].to {
+				errs = append(errs, fmt.Errorf("exclusion group %s: features %s and %s overlap",
+					name, slices[i-1].featureName, slices[i].featureName))
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,122 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func exclusionGroupFlag(id string, from, to float64) FeatureFlag {
+	return FeatureFlag{
+		ID:       id,
+		Enabled:  true,
+		Variants: []VariantDefinition{{Name: "Control"}, {Name: "Treatment"}},
+		Allocation: &VariantAllocation{
+			DefaultWhenEnabled: "Control",
+			Percentile:         []PercentileAllocation{{Variant: "Treatment", From: 0, To: 100}},
+			ExclusionGroup:     &ExclusionGroup{Name: "Checkout", From: from, To: to},
+		},
+	}
+}
+
+func TestExclusionGroup(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"ButtonColor": exclusionGroupFlag("ButtonColor", 0, 50),
+		"Shipping":    exclusionGroupFlag("Shipping", 50, 100),
+	})
+
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	allocated := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		ctx := TargetingContext{UserID: fmt.Sprintf("user-%d", i)}
+		experiments := 0
+		for _, feature := range []string{"ButtonColor", "Shipping"} {
+			result, err := manager.Evaluate(feature, ctx)
+			if err != nil {
+				t.Fatalf("Unexpected error evaluating %s: %v", feature, err)
+			}
+			switch result.VariantAssignmentReason {
+			case VariantAssignmentReasonPercentile:
+				experiments++
+				allocated[feature]++
+			case VariantAssignmentReasonDefaultWhenEnabled:
+				if result.Variant.Name != "Control" {
+					t.Errorf("Expected default variant Control for %s, got %s", ctx.UserID, result.Variant.Name)
+				}
+			default:
+				t.Errorf("Unexpected assignment reason %s", result.VariantAssignmentReason)
+			}
+		}
+		if experiments != 1 {
+			t.Errorf("Expected %s to be allocated into exactly one experiment, got %d", ctx.UserID, experiments)
+		}
+	}
+
+	for feature, count := range allocated {
+		if count < 400 || count > 600 {
+			t.Errorf("Expected about half of the users in %s, got %d", feature, count)
+		}
+	}
+}
+
+func TestExclusionGroupBucket(t *testing.T) {
+	group := &ExclusionGroup{Name: "Checkout", From: 20, To: 40}
+	for i := 0; i < 100; i++ {
+		userID := fmt.Sprintf("user-%d", i)
+		bucket := ComputeBucket(userID, "exclusion_group", "Checkout")
+		if expected := bucket >= 20 && bucket < 40; inExclusionGroup(group, userID, bucketer{}) != expected {
+			t.Errorf("Expected %s with bucket %v in group to be %v", userID, bucket, expected)
+		}
+	}
+
+	if !inExclusionGroup(nil, "Alice", bucketer{}) {
+		t.Error("Expected every user to be in a missing exclusion group")
+	}
+}
+
+func TestCheckExclusionGroups(t *testing.T) {
+	flags := []FeatureFlag{
+		exclusionGroupFlag("A", 0, 40),
+		exclusionGroupFlag("B", 30, 60),
+		exclusionGroupFlag("C", 60, 100),
+		{ID: "D", Enabled: true},
+	}
+
+	errs := checkExclusionGroups(slices.Values(flags))
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 overlap, got %v", errs)
+	}
+	if expected := "exclusion group Checkout: features A and B overlap"; errs[0].Error() != expected {
+		t.Errorf("Expected %q, got %q", expected, errs[0].Error())
+	}
+}
+
+func TestValidateExclusionGroup(t *testing.T) {
+	tests := []struct {
+		name    string
+		group   ExclusionGroup
+		wantErr bool
+	}{
+		{name: "valid", group: ExclusionGroup{Name: "Checkout", From: 0, To: 50}},
+		{name: "missing name", group: ExclusionGroup{From: 0, To: 50}, wantErr: true},
+		{name: "reversed range", group: ExclusionGroup{Name: "Checkout", From: 60, To: 50}, wantErr: true},
+		{name: "out of range", group: ExclusionGroup{Name: "Checkout", From: 0, To: 150}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := exclusionGroupFlag("Experiment", 0, 0)
+			flag.Allocation.ExclusionGroup = &tt.group
+			if err := ValidateFeatureFlag(flag); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFeatureFlag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
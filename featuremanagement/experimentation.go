@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"strconv"
+)
+
+// Dimension names used by Azure App Configuration experimentation to analyze feature evaluation
+// events. They match the names emitted by the other feature management SDKs, so events from Go
+// services can be analyzed alongside theirs.
+const (
+	DimensionFeatureName                 = "FeatureName"
+	DimensionEnabled                     = "Enabled"
+	DimensionVariant                     = "Variant"
+	DimensionVariantAssignmentReason     = "VariantAssignmentReason"
+	DimensionTargetingID                 = "TargetingId"
+	DimensionVariantAssignmentPercentage = "VariantAssignmentPercentage"
+	DimensionDefaultWhenEnabled          = "DefaultWhenEnabled"
+	DimensionAllocationID                = "AllocationId"
+)
+
+// Telemetry metadata keys set by Azure App Configuration on feature flags with telemetry enabled.
+// They are published with each event as part of the flag's telemetry metadata.
+const (
+	MetadataETag                 = "ETag"
+	MetadataFeatureFlagReference = "FeatureFlagReference"
+	MetadataFeatureFlagID        = "FeatureFlagId"
+	MetadataAllocationID         = "AllocationId"
+)
+
+// Dimensions returns the event as the flat set of dimensions expected by App Configuration
+// experimentation: the evaluation result under the standard dimension names, followed by the
+// telemetry metadata of the feature flag. An allocation ID set in the metadata by the
+// configuration store takes precedence over the one computed by the feature manager.
+//
+// Returns:
+//   - map[string]string: The dimensions of the event
+func (e TelemetryEvent) Dimensions() map[string]string {
+	dimensions := make(map[string]string, 8+len(e.Metadata))
+	dimensions[DimensionFeatureName] = e.FeatureName
+	dimensions[DimensionEnabled] = formatDimensionBool(e.Result.Enabled)
+	dimensions[DimensionVariantAssignmentReason] = string(e.Result.VariantAssignmentReason)
+	if e.Result.Variant != nil {
+		dimensions[DimensionVariant] = e.Result.Variant.Name
+	}
+	if e.Result.TargetingID != "" {
+		dimensions[DimensionTargetingID] = e.Result.TargetingID
+	}
+	if e.Result.VariantAssignmentReason == VariantAssignmentReasonDefaultWhenEnabled ||
+		e.Result.VariantAssignmentReason == VariantAssignmentReasonPercentile {
+		dimensions[DimensionVariantAssignmentPercentage] = strconv.FormatFloat(e.Result.VariantAssignmentPercentage, 'f', -1, 64)
+	}
+	if e.Result.DefaultWhenEnabled != "" {
+		dimensions[DimensionDefaultWhenEnabled] = e.Result.DefaultWhenEnabled
+	}
+	if e.Result.AllocationID != "" {
+		dimensions[DimensionAllocationID] = e.Result.AllocationID
+	}
+
+	for key, value := range e.Metadata {
+		dimensions[key] = value
+	}
+
+	return dimensions
+}
+
+// MetricDimensions returns the dimensions that tie a business metric, such as a purchase or a
+// click, to the variant assignment it follows, so experiment results can attribute the metric to
+// the variant. Attach them to the metric event in the telemetry system of the application.
+//
+// Example:
+//
+//	result, _ := manager.Evaluate("Checkout", targetingContext)
+//	telemetryClient.TrackEvent("Purchase", featuremanagement.MetricDimensions(result))
+//
+// Parameters:
+//   - result: The evaluation result the metric follows
+//
+// Returns:
+//   - map[string]string: The targeting ID, variant and allocation ID of the assignment
+func MetricDimensions(result EvaluationResult) map[string]string {
+	dimensions := make(map[string]string, 4)
+	if result.TargetingID != "" {
+		dimensions[DimensionTargetingID] = result.TargetingID
+	}
+	if result.Feature != nil {
+		dimensions[DimensionFeatureName] = result.Feature.ID
+	}
+	if result.Variant != nil {
+		dimensions[DimensionVariant] = result.Variant.Name
+	}
+	if result.AllocationID != "" {
+		dimensions[DimensionAllocationID] = result.AllocationID
+	}
+
+	return dimensions
+}
+
+// formatDimensionBool formats booleans the way the other SDKs emit them
+func formatDimensionBool(value bool) string {
+	if value {
+		return "True"
+	}
+	return "False"
+}
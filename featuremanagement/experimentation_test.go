@@ -0,0 +1,63 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"testing"
+)
+
+func TestTelemetryEventDimensions(t *testing.T) {
+	flag := telemetryTestFlag()
+	flag.Telemetry.Metadata = map[string]string{
+		MetadataETag:                 "etag-1",
+		MetadataFeatureFlagReference: "https://example.azconfig.io/kv/.appconfig.featureflag/Experiment",
+	}
+
+	publisher := &recordingPublisher{}
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{flag.ID: flag}), &Options{
+		TelemetryPublisher: publisher,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	result, err := manager.Evaluate("Experiment", TargetingContext{UserID: "Alice"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	dimensions := publisher.events[0].Dimensions()
+	expected := map[string]string{
+		DimensionFeatureName:             "Experiment",
+		DimensionEnabled:                 "True",
+		DimensionVariant:                 result.Variant.Name,
+		DimensionVariantAssignmentReason: "Percentile",
+		DimensionTargetingID:             "Alice",
+		DimensionDefaultWhenEnabled:      "Control",
+		DimensionAllocationID:            result.AllocationID,
+		MetadataETag:                     "etag-1",
+		MetadataFeatureFlagReference:     flag.Telemetry.Metadata[MetadataFeatureFlagReference],
+	}
+	for key, value := range expected {
+		if dimensions[key] != value {
+			t.Errorf("Expected dimension %s to be %q, got %q", key, value, dimensions[key])
+		}
+	}
+	if dimensions[DimensionVariantAssignmentPercentage] == "" {
+		t.Error("Expected the variant assignment percentage dimension")
+	}
+
+	// The store's allocation ID takes precedence
+	event := publisher.events[0]
+	event.Metadata = map[string]string{MetadataAllocationID: "from-store"}
+	if id := event.Dimensions()[DimensionAllocationID]; id != "from-store" {
+		t.Errorf("Expected the store allocation ID, got %q", id)
+	}
+
+	metric := MetricDimensions(result)
+	if metric[DimensionTargetingID] != "Alice" || metric[DimensionFeatureName] != "Experiment" ||
+		metric[DimensionVariant] != result.Variant.Name || metric[DimensionAllocationID] != result.AllocationID {
+		t.Errorf("Unexpected metric dimensions %v", metric)
+	}
+}
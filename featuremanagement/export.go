@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// ExportOptions configures ExportFeatureManagement.
+type ExportOptions struct {
+	// Features limits the export to the named features. Features the provider doesn't define are
+	// left out. When empty, every feature flag served is exported.
+	Features []string
+
+	// Targeting pre-evaluates the feature flags for a user. Each flag is exported with the state
+	// it evaluates to for the user and the variant assigned to them, and without its filters,
+	// allocation rules and telemetry, so that the browser neither evaluates targeting again nor
+	// learns the user lists and groups of other audiences. When nil, the definitions are exported
+	// unchanged.
+	Targeting TargetingContexter
+}
+
+// ExportFeatureManagement exports the feature flags served by the manager as a JSON document in
+// the feature_management schema, the shape read by the JavaScript feature management SDK, so
+// that a Go backend can serve the flags of a single-page application:
+//
+//	http.HandleFunc("/feature-flags", func(w http.ResponseWriter, r *http.Request) {
+//		data, err := manager.ExportFeatureManagement(r.Context(), featuremanagement.ExportOptions{
+//			Targeting: currentUser(r),
+//		})
+//		if err != nil {
+//			http.Error(w, err.Error(), http.StatusInternalServerError)
+//			return
+//		}
+//		w.Header().Set("Content-Type", "application/json")
+//		w.Write(data)
+//	})
+//
+// The browser loads the document with ConfigurationObjectFeatureFlagProvider. Pre-evaluated flags
+// report the variants assigned to the user as their default when enabled or disabled, and don't
+// publish telemetry from this evaluation, which is not an exposure of the user.
+//
+// Pre-evaluated flags are exported with telemetry disabled: the browser would report the default
+// variants standing in for the assignment as the reason it was made, and an allocation ID computed
+// from the stand-in allocation, splitting the experiment results of the feature. Report the
+// exposures of pre-evaluated features by evaluating them in the backend when the user is exposed.
+//
+// Parameters:
+//   - ctx: The context of the export
+//   - options: The features to export and the user to pre-evaluate them for
+//
+// Returns:
+//   - []byte: The JSON document
+//   - error: An error if a feature flag can't be evaluated for the user
+func (fm *FeatureManager) ExportFeatureManagement(ctx context.Context, options ExportOptions) ([]byte, error) {
+	var flags []FeatureFlag
+	for flag := range fm.flags() {
+		if len(options.Features) > 0 && !slices.Contains(options.Features, flag.ID) {
+			continue
+		}
+
+		if options.Targeting != nil {
+			evaluated, err := fm.preEvaluate(ctx, flag, options.Targeting)
+			if err != nil {
+				return nil, err
+			}
+			flag = evaluated
+		}
+		flags = append(flags, flag)
+	}
+
+	config, err := EncodeFeatureManagement(flags, SchemaV2)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(config)
+}
+
+// preEvaluate returns a definition of the feature flag that evaluates unconditionally to the
+// state and variant the flag has for the user, without telemetry
+func (fm *FeatureManager) preEvaluate(ctx context.Context, flag FeatureFlag, targeting TargetingContexter) (FeatureFlag, error) {
+	res, err := fm.EvaluateContext(ctx, flag.ID, WithTargeting(targeting), WithoutTelemetry())
+	if err != nil {
+		return FeatureFlag{}, fmt.Errorf("failed to export feature flag %s: %w", flag.ID, err)
+	}
+
+	evaluated := FeatureFlag{
+		ID:          flag.ID,
+		Description: flag.Description,
+		DisplayName: flag.DisplayName,
+		Enabled:     res.Enabled,
+		Tags:        flag.Tags,
+	}
+	if res.Variant != nil {
+		// The state is final, so the variant must not override it again
+		evaluated.Variants = []VariantDefinition{{Name: res.Variant.Name, ConfigurationValue: res.Variant.ConfigurationValue}}
+		evaluated.Allocation = &VariantAllocation{}
+		if res.Enabled {
+			evaluated.Allocation.DefaultWhenEnabled = res.Variant.Name
+		} else {
+			evaluated.Allocation.DefaultWhenDisabled = res.Variant.Name
+		}
+	}
+
+	return evaluated, nil
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExportFeatureManagement(t *testing.T) {
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {
+			ID:      "Beta",
+			Enabled: true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{
+				Name:       "Microsoft.Targeting",
+				Parameters: map[string]any{"Audience": map[string]any{"Users": []any{"alice@contoso.com"}}},
+			}}},
+			Variants: []VariantDefinition{
+				{Name: "Big", ConfigurationValue: "big", StatusOverride: StatusOverrideEnabled},
+				{Name: "Small", ConfigurationValue: "small"},
+			},
+			Allocation: &VariantAllocation{DefaultWhenEnabled: "Big", DefaultWhenDisabled: "Small"},
+			Telemetry:  &Telemetry{Enabled: true},
+		},
+		"Search": {ID: "Search", Enabled: true},
+	}), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	data, err := manager.ExportFeatureManagement(context.Background(), ExportOptions{Features: []string{"Beta"}})
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	exported, err := ParseFeatureManagement(data)
+	if err != nil {
+		t.Fatalf("Failed to parse export %s: %v", data, err)
+	}
+	if len(exported.FeatureFlags) != 1 || exported.FeatureFlags[0].Conditions == nil || exported.FeatureFlags[0].Telemetry == nil {
+		t.Errorf("Expected Beta to be exported unchanged, got %s", data)
+	}
+
+	for user, expected := range map[string]struct {
+		enabled bool
+		variant string
+	}{
+		"alice@contoso.com": {true, "Big"},
+		"bob@fabrikam.com":  {false, "Small"},
+	} {
+		data, err := manager.ExportFeatureManagement(context.Background(), ExportOptions{Targeting: TargetingContext{UserID: user}})
+		if err != nil {
+			t.Fatalf("Failed to export for %s: %v", user, err)
+		}
+		if strings.Contains(string(data), "alice@contoso.com") || strings.Contains(string(data), "status_override") {
+			t.Errorf("Expected the targeting rules to be left out of the export for %s, got %s", user, data)
+		}
+
+		exported, err := ParseFeatureManagement(data)
+		if err != nil {
+			t.Fatalf("Failed to parse export %s: %v", data, err)
+		}
+		browser, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{"Beta": exported.FeatureFlags[0]}), nil)
+		if err != nil {
+			t.Fatalf("Failed to create feature manager: %v", err)
+		}
+		res, err := browser.Evaluate("Beta", nil)
+		if err != nil || res.Enabled != expected.enabled || res.Variant == nil || res.Variant.Name != expected.variant {
+			t.Errorf("Expected the export for %s to evaluate to %+v, got %+v, %v", user, expected, res, err)
+		}
+		if len(exported.FeatureFlags) != 2 {
+			t.Errorf("Expected every flag to be exported, got %s", data)
+		}
+		// The browser would report the stand-in allocation of the export rather than the assignment
+		if exported.FeatureFlags[0].Telemetry != nil {
+			t.Errorf("Expected the export for %s to disable telemetry, got %s", user, data)
+		}
+	}
+}
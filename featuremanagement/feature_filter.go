@@ -3,6 +3,12 @@
 
 package featuremanagement
 
+import (
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
 // FeatureFilterEvaluationContext provides the context information needed
 // to evaluate a feature filter.
 type FeatureFilterEvaluationContext struct {
@@ -11,6 +17,14 @@ type FeatureFilterEvaluationContext struct {
 
 	// Parameters contains the filter-specific configuration parameters
 	Parameters map[string]any
+
+	// highResolutionBuckets is set by a feature manager with Options.HighResolutionBuckets, so
+	// that targeting filters it evaluates bucket users with 64-bit context markers
+	highResolutionBuckets bool
+
+	// userPatterns is set by a feature manager with Options.UserPatterns, so that targeting
+	// filters it evaluates match users listed with wildcards as patterns
+	userPatterns bool
 }
 
 // TargetingContext provides user-specific information for feature flag targeting.
@@ -22,6 +36,62 @@ type TargetingContext struct {
 
 	// Groups are the groups the user belongs to for group targeting
 	Groups []string
+
+	// Attributes are additional properties of the context, such as "tenantId" or "region",
+	// that variant allocations can target instead of the user ID
+	Attributes map[string]string
+
+	// ForcedVariants maps feature names to the variant to assign for them, bypassing allocation.
+	// It lets testers exercise specific variants deterministically, for example from a QA query
+	// parameter or an internal header. Forced variants are assigned with reason "Override", only
+	// to enabled features, and are not reported as experiment exposures.
+	ForcedVariants map[string]string
+}
+
+// TargetingContexter can be implemented by the user or session types of an application, so that
+// they can be passed directly as the app context of targeted evaluations instead of building a
+// TargetingContext. TargetingContext implements it.
+type TargetingContexter interface {
+	// GetUserID returns the identifier of the user
+	GetUserID() string
+	// GetGroups returns the groups the user belongs to
+	GetGroups() []string
+	// GetAttributes returns additional properties of the context, or nil
+	GetAttributes() map[string]string
+}
+
+// GetUserID returns the UserID.
+func (c TargetingContext) GetUserID() string {
+	return c.UserID
+}
+
+// GetGroups returns the Groups.
+func (c TargetingContext) GetGroups() []string {
+	return c.Groups
+}
+
+// GetAttributes returns the Attributes.
+func (c TargetingContext) GetAttributes() map[string]string {
+	return c.Attributes
+}
+
+// targetingContextFrom returns the targeting context of an app context that is a TargetingContext,
+// a non-nil *TargetingContext or a TargetingContexter
+func targetingContextFrom(appContext any) (*TargetingContext, bool) {
+	switch tc := appContext.(type) {
+	case TargetingContext:
+		return &tc, true
+	case *TargetingContext:
+		return tc, tc != nil
+	case TargetingContexter:
+		return &TargetingContext{
+			UserID:     tc.GetUserID(),
+			Groups:     tc.GetGroups(),
+			Attributes: tc.GetAttributes(),
+		}, true
+	default:
+		return nil, false
+	}
 }
 
 // FeatureFilter defines the interface for feature flag filters.
@@ -52,3 +122,48 @@ type FeatureFilter interface {
 	// Evaluate determines whether a feature should be enabled based on the provided contexts
 	Evaluate(evalCtx FeatureFilterEvaluationContext, appCtx any) (bool, error)
 }
+
+// BindParameters decodes filter parameters into a struct, the same way the built-in filters do.
+// Parameter keys match struct fields case-insensitively, by their json tag or else by field name,
+// so "audience", "Audience" and "AUDIENCE" are equivalent. Strings holding numbers or booleans are
+// converted to the field type, as configuration stores often hold values as strings.
+//
+// Example:
+//
+//	type EnvironmentFilterParameters struct {
+//		Environments []string `json:"environments"`
+//	}
+//
+//	func (f EnvironmentFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appCtx any) (bool, error) {
+//		var params EnvironmentFilterParameters
+//		if err := featuremanagement.BindParameters(evalCtx.Parameters, &params); err != nil {
+//			return false, err
+//		}
+//		// ...
+//	}
+//
+// Parameters:
+//   - parameters: The filter parameters from the feature flag definition
+//   - target: A pointer to the struct to decode the parameters into
+//
+// Returns:
+//   - error: An error if a parameter can't be converted to the type of its field
+func BindParameters(parameters map[string]any, target any) error {
+	return decodeValue(parameters, target)
+}
+
+// decodeValue decodes a value parsed from a feature flag definition into target, matching fields
+// by their json tags, case-insensitively, and converting values between compatible types
+func decodeValue(value any, target any) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		Result:           target,
+		TagName:          "json",
+		WeaklyTypedInput: true,
+		MatchName:        strings.EqualFold,
+	})
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(value)
+}
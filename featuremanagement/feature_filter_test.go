@@ -0,0 +1,121 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"testing"
+)
+
+func TestBindParameters(t *testing.T) {
+	type customParameters struct {
+		Environments []string `json:"environments"`
+		Threshold    int
+		Enabled      bool
+	}
+
+	var params customParameters
+	err := BindParameters(map[string]any{
+		"ENVIRONMENTS": []any{"dev", "test"},
+		"threshold":    "42",
+		"Enabled":      true,
+	}, &params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(params.Environments) != 2 || params.Threshold != 42 || !params.Enabled {
+		t.Errorf("Unexpected parameters %+v", params)
+	}
+
+	if err := BindParameters(map[string]any{"threshold": "many"}, &params); err == nil {
+		t.Error("Expected error for a value that can't be converted")
+	}
+}
+
+func TestBuiltInFiltersAcceptAnyParameterCasing(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Targeted": {
+			Enabled: true,
+			Conditions: &Conditions{
+				ClientFilters: []ClientFilter{
+					{
+						Name: "Microsoft.Targeting",
+						Parameters: map[string]any{
+							"audience": map[string]any{
+								"USERS":                    []any{"Alice"},
+								"defaultrolloutpercentage": 0,
+							},
+						},
+					},
+				},
+			},
+		},
+		"Windowed": {
+			Enabled: true,
+			Conditions: &Conditions{
+				ClientFilters: []ClientFilter{
+					{
+						Name:       "Microsoft.TimeWindow",
+						Parameters: map[string]any{"START": "Mon, 01 Jan 2024 00:00:00 GMT", "end": "Fri, 01 Jan 2100 00:00:00 GMT"},
+					},
+				},
+			},
+		},
+	})
+
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if enabled, err := manager.IsEnabledWithAppContext("Targeted", TargetingContext{UserID: "Alice"}); err != nil || !enabled {
+		t.Errorf("Expected Targeted to be enabled for Alice, got %v, %v", enabled, err)
+	}
+	if enabled, err := manager.IsEnabledWithAppContext("Targeted", TargetingContext{UserID: "Bob"}); err != nil || enabled {
+		t.Errorf("Expected Targeted to be disabled for Bob, got %v, %v", enabled, err)
+	}
+	if enabled, err := manager.IsEnabled("Windowed"); err != nil || !enabled {
+		t.Errorf("Expected Windowed to be enabled, got %v, %v", enabled, err)
+	}
+}
+
+func TestOnError(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {
+			Enabled: true,
+			Conditions: &Conditions{
+				ClientFilters: []ClientFilter{{Name: "Missing"}},
+			},
+		},
+		"Gamma": {
+			Enabled: true,
+			Conditions: &Conditions{
+				ClientFilters: []ClientFilter{{
+					Name:       "Microsoft.Targeting",
+					Parameters: map[string]any{"Audience": map[string]any{"DefaultRolloutPercentage": 150}},
+				}},
+			},
+		},
+	})
+
+	reported := map[string]string{}
+	manager, err := NewFeatureManager(provider, &Options{
+		OnError: func(featureName string, err error) {
+			reported[featureName] = err.Error()
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if _, ok := reported["Gamma"]; !ok {
+		t.Error("Expected invalid filter parameters to be reported at load")
+	}
+
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || enabled {
+		t.Errorf("Expected Beta to be disabled, got %v, %v", enabled, err)
+	}
+	if reported["Beta"] != "feature filter Missing is not found" {
+		t.Errorf("Expected the missing filter to be reported, got %q", reported["Beta"])
+	}
+}
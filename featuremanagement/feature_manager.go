@@ -4,15 +4,47 @@
 package featuremanagement
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"iter"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+	"time"
 )
 
 // FeatureManager is responsible for evaluating feature flags and their variants.
 // It is the main entry point for interacting with the feature management library.
+//
+// A FeatureManager is safe for concurrent use. Each evaluation runs against a single definition
+// of its feature, read from the provider when the evaluation starts, even when the provider
+// refreshes during the evaluation; see FeatureFlagProvider.
 type FeatureManager struct {
-	featureProvider FeatureFlagProvider
-	featureFilters  map[string]FeatureFilter
+	featureProvider    FeatureFlagProvider
+	featureFilters     map[string]FeatureFilter
+	overrides          map[string]bool
+	tracker            *evaluationTracker
+	skipValidation     bool
+	skipInvalidFlags   bool
+	onValidationError  func(ValidationError)
+	telemetry          *telemetryPublisher
+	allocationStrategy AllocationStrategy
+	bucketer           bucketer
+	users              userMatcher
+	assignmentStore    AssignmentStore
+	interceptors       []Interceptor
+	evaluator          Evaluator
+	onError            func(featureName string, err error)
+
+	timeFilters          bool
+	filterTimingRecorder FilterTimingRecorder
+
+	evaluationIDGenerator func() string
+
+	logger   *logger
+	changes  *changeNotifier
+	shutdown *shutdown
+	panics   *atomic.Uint64
 }
 
 // Options configures the behavior of the FeatureManager.
@@ -20,6 +52,103 @@ type Options struct {
 	// Filters is a list of custom feature filters that will be used during feature flag evaluation.
 	// Each filter must implement the FeatureFilter interface.
 	Filters []FeatureFilter
+
+	// Overrides forces the listed feature flags on or off regardless of the provider's definition.
+	// Overridden flags skip filter evaluation entirely and are available even if the provider
+	// does not define them. Use LoadLocalOverrides to populate this from the local environment.
+	Overrides map[string]bool
+
+	// OnValidationError is called for each invalid feature flag definition found when the
+	// FeatureManager is created, and each time an invalid flag is evaluated.
+	OnValidationError func(ValidationError)
+
+	// OnValidationWarning is called for each valid feature flag definition that likely needs
+	// attention, such as an expired time window or a suspicious allocation, found when the
+	// FeatureManager is created.
+	// When nil, the warnings are logged.
+	OnValidationWarning func(ValidationWarning)
+
+	// SkipInvalidFlags leaves invalid feature flag definitions out of enumeration and evaluates
+	// them as disabled instead of returning an error, so the remaining flags keep being served.
+	SkipInvalidFlags bool
+
+	// TelemetryPublisher receives an event for every evaluation of a feature with telemetry
+	// enabled, and a separate variant exposure event when a variant is allocated to a user.
+	TelemetryPublisher TelemetryPublisher
+
+	// ExposureDeduplication publishes the exposure of a user to a variant of a feature only once
+	// within the given duration, so repeated evaluations don't inflate exposure counts.
+	// Zero publishes every exposure.
+	ExposureDeduplication time.Duration
+
+	// AllocationStrategy assigns variants to targeted users of enabled features.
+	// When nil, variants are allocated as defined by the feature flags.
+	AllocationStrategy AllocationStrategy
+
+	// AssignmentStore persists the variants allocated to targeted users and is consulted before
+	// allocation, so assignments stay stable when allocation percentages or seeds change.
+	AssignmentStore AssignmentStore
+
+	// OnError is called for internal failures that don't fail an evaluation, such as a missing
+	// feature filter, invalid filter parameters, a provider failing to enumerate its feature flags
+	// or an assignment store error, with the name of the feature involved, if any. When set, these
+	// failures are no longer logged, so applications can route them to their own alerting.
+	OnError func(featureName string, err error)
+
+	// RecordFilterTimings measures how long each feature filter takes to evaluate, and reports
+	// the durations in EvaluationResult.FilterTimings.
+	RecordFilterTimings bool
+
+	// FilterTimingRecorder receives the duration of every feature filter evaluation, for example
+	// to feed a latency histogram per filter. Setting it implies RecordFilterTimings.
+	FilterTimingRecorder FilterTimingRecorder
+
+	// Interceptors wrap every evaluation, in order: the first interceptor is the outermost.
+	// See Interceptor.
+	Interceptors []Interceptor
+
+	// EvaluationIDGenerator generates the ID of evaluations whose app context doesn't supply one,
+	// for example NewEvaluationID. The ID is set in EvaluationResult.EvaluationID, and therefore in
+	// telemetry events, and prefixes the errors of the evaluation, so that a decision can be traced
+	// across application logs and experiment pipelines. When nil, evaluations only get an ID from
+	// an app context implementing EvaluationIDCarrier or set up by WithEvaluationID.
+	EvaluationIDGenerator func() string
+
+	// ExpectedFeatures declares the features the application evaluates. NewFeatureManager checks
+	// that the provider defines each of them, or that they are overridden, and logs a warning
+	// listing those that are missing, catching misspelled feature names at startup.
+	ExpectedFeatures []string
+
+	// RequireExpectedFeatures makes NewFeatureManager fail instead of warning when an expected
+	// feature is missing.
+	RequireExpectedFeatures bool
+
+	// LogLevel is the minimum severity of the messages logged by the feature manager and its
+	// built-in filters. Defaults to LogLevelWarn; LogLevelSilent disables logging. Providers log
+	// through a ProviderLogger configured by their own options.
+	LogLevel LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+
+	// VerifyBucketing makes NewFeatureManager fail unless percentile bucketing assigns reference
+	// users to the buckets of the published algorithm. See VerifyBucketing.
+	VerifyBucketing bool
+
+	// HighResolutionBuckets buckets users with 64-bit context markers, see ComputeBucket64, in
+	// variant percentile allocations, exclusion groups and targeting filters, for rollouts finer
+	// than 32-bit buckets can split evenly. Only users within a 32-bit bucket of a percentile
+	// boundary are bucketed differently than by the other SDKs. It also applies to a
+	// TargetingFilter registered with Filters and to a DefaultAllocationStrategy set as
+	// AllocationStrategy; other allocation strategies bucket users their own way.
+	HighResolutionBuckets bool
+
+	// UserPatterns lets the users of targeting audiences, of their exclusions and of variant user
+	// allocations contain '*' wildcards matching any sequence of characters, so that
+	// "*@contoso.com" targets a whole email domain. Without it, '*' is an ordinary character, so
+	// user IDs containing one keep matching only themselves. It also applies to a TargetingFilter
+	// registered with Filters and to a DefaultAllocationStrategy set as AllocationStrategy.
+	UserPatterns bool
 }
 
 // EvaluationResult contains information about a feature flag evaluation
@@ -34,6 +163,21 @@ type EvaluationResult struct {
 	Variant *Variant
 	// VariantAssignmentReason explains why the variant was assigned
 	VariantAssignmentReason VariantAssignmentReason
+	// VariantAssignmentPercentage is the percentage of users assigned the variant for the same reason,
+	// when it was assigned by percentile or as the default when enabled
+	VariantAssignmentPercentage float64
+	// DefaultWhenEnabled is the variant assigned to enabled users outside of any allocation
+	DefaultWhenEnabled string
+	// AllocationID identifies the allocation the variant was assigned from, so that experiment
+	// results can be split when the allocation changes. It is set only when telemetry is enabled
+	// for the feature and the feature allocates variants.
+	AllocationID string
+	// EvaluationID identifies the evaluation, when supplied by the app context or generated by
+	// Options.EvaluationIDGenerator
+	EvaluationID string
+	// FilterTimings holds the duration of each feature filter evaluated, in evaluation order,
+	// when Options.RecordFilterTimings or Options.FilterTimingRecorder is set.
+	FilterTimings []FilterTiming
 }
 
 // NewFeatureManager creates and initializes a new instance of the FeatureManager.
@@ -57,9 +201,18 @@ func NewFeatureManager(provider FeatureFlagProvider, options *Options) (*Feature
 		options = &Options{}
 	}
 
+	if options.VerifyBucketing {
+		if err := VerifyBucketing(); err != nil {
+			return nil, err
+		}
+	}
+
+	logger := newLogger(options.LogLevel, options.Logger)
 	filters := []FeatureFilter{
 		&TargetingFilter{},
-		&TimeWindowFilter{},
+		&TimeWindowFilter{logger: logger},
+		&LocaleFilter{},
+		&NumericFilter{},
 	}
 
 	filters = append(filters, options.Filters...)
@@ -70,10 +223,76 @@ func NewFeatureManager(provider FeatureFlagProvider, options *Options) (*Feature
 		}
 	}
 
-	return &FeatureManager{
-		featureProvider: provider,
-		featureFilters:  featureFilters,
-	}, nil
+	overrides := make(map[string]bool, len(options.Overrides))
+	for name, enabled := range options.Overrides {
+		logger.infof("Feature flag %s is overridden to enabled=%t", name, enabled)
+		overrides[name] = enabled
+	}
+
+	manager := &FeatureManager{
+		featureProvider:    provider,
+		featureFilters:     featureFilters,
+		overrides:          overrides,
+		skipInvalidFlags:   options.SkipInvalidFlags,
+		onValidationError:  options.OnValidationError,
+		allocationStrategy: allocationStrategyFor(options),
+		bucketer:           bucketerFor(options.HighResolutionBuckets),
+		users:              userMatcher{patterns: options.UserPatterns},
+		assignmentStore:    options.AssignmentStore,
+		interceptors:       options.Interceptors,
+		onError:            options.OnError,
+
+		evaluationIDGenerator: options.EvaluationIDGenerator,
+		logger:                logger,
+		changes:               &changeNotifier{},
+		shutdown:              &shutdown{},
+		panics:                &atomic.Uint64{},
+
+		timeFilters:          options.RecordFilterTimings || options.FilterTimingRecorder != nil,
+		filterTimingRecorder: options.FilterTimingRecorder,
+	}
+	manager.evaluator = manager.intercept(manager.evaluateFlag)
+	manager.tracker = newEvaluationTracker(manager.definedFeatures)
+	if options.TelemetryPublisher != nil {
+		manager.telemetry = &telemetryPublisher{
+			publisher:   options.TelemetryPublisher,
+			dedupWindow: options.ExposureDeduplication,
+		}
+	}
+	if validating, ok := provider.(ValidatingFeatureFlagProvider); ok {
+		manager.skipValidation = validating.ValidatesFeatureFlags()
+	}
+
+	if manager.onValidationError != nil {
+		for _, validationErr := range manager.ValidationErrors() {
+			manager.onValidationError(validationErr)
+		}
+	}
+
+	if err := manager.checkExpectedFeatures(options.ExpectedFeatures); err != nil {
+		if options.RequireExpectedFeatures {
+			return nil, err
+		}
+		logger.warnf("%s", err)
+	}
+
+	for _, warning := range manager.ValidationWarnings() {
+		if options.OnValidationWarning != nil {
+			options.OnValidationWarning(warning)
+		} else {
+			logger.warnf("Feature flag %s: %s", warning.FeatureName, warning.Message)
+		}
+	}
+
+	for _, err := range checkExclusionGroups(manager.flags()) {
+		manager.reportError("", fmt.Errorf("invalid exclusion group: %w", err))
+	}
+
+	// Decode built-in filter parameters up front so invalid configuration is reported at load
+	// and the first evaluations don't pay the parsing cost
+	preloadFilterParameters(featureFilters, manager.flags(), manager.reportError)
+
+	return manager, nil
 }
 
 // IsEnabled determines if a feature flag is enabled.
@@ -86,15 +305,9 @@ func NewFeatureManager(provider FeatureFlagProvider, options *Options) (*Feature
 //   - bool: true if the feature is enabled, false otherwise
 //   - error: An error if the feature flag cannot be found or evaluated
 func (fm *FeatureManager) IsEnabled(featureName string) (bool, error) {
-	// Get the feature flag
-	featureFlag, err := fm.featureProvider.GetFeatureFlag(featureName)
+	res, err := fm.evaluate(featureName, nil)
 	if err != nil {
-		return false, fmt.Errorf("failed to get feature flag %s: %w", featureName, err)
-	}
-
-	res, err := fm.evaluateFeature(featureFlag, nil)
-	if err != nil {
-		return false, fmt.Errorf("failed to evaluate feature %s: %w", featureName, err)
+		return false, err
 	}
 
 	return res.Enabled, nil
@@ -111,15 +324,9 @@ func (fm *FeatureManager) IsEnabled(featureName string) (bool, error) {
 //   - bool: true if the feature is enabled, false otherwise
 //   - error: An error if the feature flag cannot be found or evaluated
 func (fm *FeatureManager) IsEnabledWithAppContext(featureName string, appContext any) (bool, error) {
-	// Get the feature flag
-	featureFlag, err := fm.featureProvider.GetFeatureFlag(featureName)
+	res, err := fm.evaluate(featureName, appContext)
 	if err != nil {
-		return false, fmt.Errorf("failed to get feature flag %s: %w", featureName, err)
-	}
-
-	res, err := fm.evaluateFeature(featureFlag, appContext)
-	if err != nil {
-		return false, fmt.Errorf("failed to evaluate feature %s: %w", featureName, err)
+		return false, err
 	}
 
 	return res.Enabled, nil
@@ -137,40 +344,262 @@ func (fm *FeatureManager) IsEnabledWithAppContext(featureName string, appContext
 //   - Variant: The assigned variant with its name and configuration value. If no variant is assigned, this will be nil.
 //   - error: An error if the feature flag cannot be found or evaluated
 func (fm *FeatureManager) GetVariant(featureName string, appContext any) (*Variant, error) {
-	// Get the feature flag
-	featureFlag, err := fm.featureProvider.GetFeatureFlag(featureName)
+	res, err := fm.evaluate(featureName, appContext)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get feature flag %s: %w", featureName, err)
+		return nil, err
 	}
 
-	res, err := fm.evaluateFeature(featureFlag, appContext)
-	if err != nil {
-		return nil, fmt.Errorf("failed to evaluate feature %s: %w", featureName, err)
+	return res.Variant, nil
+}
+
+// Evaluate evaluates a feature flag for the given context and returns the full result, including
+// the assigned variant and the details needed to attribute metrics to it.
+//
+// Parameters:
+//   - featureName: The name of the feature to evaluate
+//   - appContext: An optional context object for contextual evaluation
+//
+// Returns:
+//   - EvaluationResult: The state of the feature, its assigned variant and how it was assigned
+//   - error: An error if the feature flag cannot be found or evaluated
+func (fm *FeatureManager) Evaluate(featureName string, appContext any) (EvaluationResult, error) {
+	res, err := fm.evaluate(featureName, appContext)
+	res.Feature = cloneFeature(res.Feature)
+	return res, err
+}
+
+// GetFeatureNames returns the names of all available features, in provider order. A feature
+// defined more than once is listed once, at its first position, since its first definition is the
+// one evaluated. Invalid flags are left out when Options.SkipInvalidFlags is set.
+//
+// Returns:
+//   - []string: The names of the available features; empty, not nil, when the provider defines none
+//   - error: An error if the provider fails to enumerate its feature flags. Providers implementing
+//     FeatureFlagIterator enumerate their flags without failing.
+func (fm *FeatureManager) GetFeatureNames() ([]string, error) {
+	flags := fm.flags()
+	if _, ok := fm.featureProvider.(FeatureFlagIterator); !ok {
+		list, err := fm.getFeatureFlags()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get feature flags: %w", err)
+		}
+		flags = fm.withoutInvalidFlags(slices.Values(list))
 	}
 
-	return res.Variant, nil
+	names := []string{}
+	seen := make(map[string]bool)
+	for flag := range flags {
+		if !seen[flag.ID] {
+			seen[flag.ID] = true
+			names = append(names, flag.ID)
+		}
+	}
+
+	return names, nil
+}
+
+// GetFeaturesByTag returns the feature flags carrying the given tag, in provider order.
+//
+// Parameters:
+//   - tag: The tag to look for, matched exactly
+//
+// Returns:
+//   - []FeatureFlag: The feature flags carrying the tag
+func (fm *FeatureManager) GetFeaturesByTag(tag string) []FeatureFlag {
+	var res []FeatureFlag
+	for flag := range fm.flags() {
+		if slices.Contains(flag.Tags, tag) {
+			res = append(res, flag.Clone())
+		}
+	}
+
+	return res
 }
 
-// GetFeatureNames returns the names of all available features.
+// All returns an iterator over copies of all feature flags supplied by the provider.
+// Providers implementing FeatureFlagIterator are enumerated directly; otherwise the
+// flags are retrieved with GetFeatureFlags. Invalid flags are left out when
+// Options.SkipInvalidFlags is set.
 //
 // Returns:
-//   - []string: A slice containing the names of all available features
-func (fm *FeatureManager) GetFeatureNames() []string {
-	flags, err := fm.featureProvider.GetFeatureFlags()
+//   - iter.Seq[FeatureFlag]: An iterator yielding each feature flag in provider order
+func (fm *FeatureManager) All() iter.Seq[FeatureFlag] {
+	return cloneFeatureFlags(fm.flags())
+}
+
+// flags enumerates the feature flags served, as returned by the provider. Unlike All, it doesn't
+// copy them, so they must not be modified or handed to application code.
+func (fm *FeatureManager) flags() iter.Seq[FeatureFlag] {
+	return fm.withoutInvalidFlags(fm.providerFlags())
+}
+
+// withoutInvalidFlags leaves the invalid flags out of an enumeration when Options.SkipInvalidFlags is set
+func (fm *FeatureManager) withoutInvalidFlags(flags iter.Seq[FeatureFlag]) iter.Seq[FeatureFlag] {
+	if !fm.skipInvalidFlags || fm.skipValidation {
+		return flags
+	}
+
+	return func(yield func(FeatureFlag) bool) {
+		for flag := range flags {
+			if validateFeatureFlag(flag) != nil {
+				continue
+			}
+			if !yield(flag) {
+				return
+			}
+		}
+	}
+}
+
+// ValidationErrors returns an error for each invalid feature flag definition currently supplied
+// by the provider. Providers implementing ValidationReporter report the flags they rejected;
+// otherwise every flag is validated.
+//
+// Returns:
+//   - []ValidationError: An error for each invalid feature flag, in provider order
+func (fm *FeatureManager) ValidationErrors() []ValidationError {
+	if reporter, ok := fm.featureProvider.(ValidationReporter); ok {
+		return reporter.ValidationErrors()
+	}
+
+	var errs []ValidationError
+	for flag := range fm.providerFlags() {
+		if err := validateFeatureFlag(flag); err != nil {
+			errs = append(errs, ValidationError{FeatureName: flag.ID, Err: err})
+		}
+	}
+
+	return errs
+}
+
+// ValidationWarnings returns a warning for each feature flag currently supplied by the provider
+// whose definition is valid but likely needs attention, such as an enabled flag whose time window
+// has already ended or an allocation of an undefined variant. See FindExpiredTimeWindows and
+// FindSuspiciousAllocations.
+//
+// Returns:
+//   - []ValidationWarning: A warning for each feature flag needing attention, in provider order
+func (fm *FeatureManager) ValidationWarnings() []ValidationWarning {
+	now := time.Now()
+	var warnings []ValidationWarning
+	for flag := range fm.flags() {
+		warnings = append(warnings, expiredTimeWindows(flag, now)...)
+		warnings = append(warnings, suspiciousAllocations(flag)...)
+	}
+
+	return warnings
+}
+
+// providerFlags enumerates the feature flags supplied by the provider, valid or not. A panic of the
+// provider ends the enumeration and is reported, while panics of the loop body are propagated.
+func (fm *FeatureManager) providerFlags() iter.Seq[FeatureFlag] {
+	return func(yield func(FeatureFlag) bool) {
+		yielding := false
+		defer func() {
+			if yielding {
+				return
+			}
+			if value := recover(); value != nil {
+				fm.reportError("", fmt.Errorf("failed to get feature flags: %w", fm.panicError("feature flag provider", "", fm.featureProvider, value)))
+			}
+		}()
+
+		var flags iter.Seq[FeatureFlag]
+		if iterator, ok := fm.featureProvider.(FeatureFlagIterator); ok {
+			flags = iterator.All()
+		} else {
+			list, err := fm.featureProvider.GetFeatureFlags()
+			if err != nil {
+				fm.reportError("", fmt.Errorf("failed to get feature flags: %w", err))
+				return
+			}
+			flags = slices.Values(list)
+		}
+
+		for flag := range flags {
+			yielding = true
+			if !yield(flag) {
+				return
+			}
+			yielding = false
+		}
+	}
+}
+
+// reportError passes an internal failure that doesn't fail an evaluation to the OnError
+// callback, or logs it when no callback is set
+func (fm *FeatureManager) reportError(featureName string, err error) {
+	if fm.onError != nil {
+		fm.onError(featureName, err)
+		return
+	}
+
+	fm.logger.errorf("%s", err)
+}
+
+// evaluate evaluates the named feature flag through the interceptor chain
+func (fm *FeatureManager) evaluate(featureName string, appContext any) (EvaluationResult, error) {
+	return fm.evaluator(featureName, appContext)
+}
+
+// evaluateFlag retrieves the named feature flag from the provider, applies any configured
+// override and evaluates it against the given app context.
+func (fm *FeatureManager) evaluateFlag(featureName string, appContext any) (EvaluationResult, error) {
+	return fm.evaluateFlagWithOptions(featureName, appContext, nil)
+}
+
+// evaluateFlagWithOptions evaluates the named feature flag, applying the options of a single call
+// when they are not nil
+func (fm *FeatureManager) evaluateFlagWithOptions(featureName string, appContext any, options *evaluationOptions) (EvaluationResult, error) {
+	evaluationID := fm.evaluationID(appContext)
+	if evaluationID == "" && options != nil && options.ctx != nil {
+		evaluationID, _ = EvaluationIDFromContext(options.ctx)
+	}
+
+	// Get the feature flag
+	featureFlag, err := fm.getFeatureFlag(featureName)
+	enabled, overridden := fm.overrides[featureName]
 	if err != nil {
-		log.Printf("failed to get feature flag names: %v", err)
-		return nil
+		if !overridden {
+			var panicErr *PanicError
+			if !errors.As(err, &panicErr) {
+				fm.tracker.recordMissing(featureName)
+			}
+			return EvaluationResult{EvaluationID: evaluationID}, withEvaluationID(evaluationID, fmt.Errorf("failed to get feature flag %s: %w", featureName, err))
+		}
+		// Overridden flags don't need to exist in the provider
+		featureFlag = FeatureFlag{ID: featureName}
 	}
 
-	res := make([]string, 0, len(flags))
-	for i, flag := range flags {
-		res[i] = flag.ID
+	if overridden {
+		featureFlag.Enabled = enabled
+		featureFlag.Conditions = nil
 	}
 
-	return res
+	res, err := fm.evaluateFeature(featureFlag, appContext, evaluationID)
+	if err != nil {
+		return res, withEvaluationID(evaluationID, fmt.Errorf("failed to evaluate feature %s: %w", featureName, err))
+	}
+
+	// Variant status overrides can't change the state of an overridden feature
+	if overridden {
+		res.Enabled = enabled
+	}
+
+	fm.tracker.record(featureName, res.Enabled)
+	if fm.logger.enabled(LogLevelDebug) {
+		fm.logger.debugf("Feature flag %s evaluated to enabled=%t", featureName, res.Enabled)
+	}
+	if fm.telemetry != nil && (options == nil || !options.withoutTelemetry) {
+		fm.publishTelemetry(featureName, res)
+	}
+
+	return res, nil
 }
 
-func (fm *FeatureManager) isEnabled(featureFlag FeatureFlag, appContext any) (bool, error) {
+// isEnabled evaluates the state of a feature flag, appending the duration of each evaluated
+// filter to timings when it is not nil
+func (fm *FeatureManager) isEnabled(featureFlag *FeatureFlag, appContext any, evaluationID string, timings *[]FilterTiming) (bool, error) {
 	// If the feature is not explicitly enabled, then it is disabled by default
 	if !featureFlag.Enabled {
 		return false, nil
@@ -196,18 +625,33 @@ func (fm *FeatureManager) isEnabled(featureFlag FeatureFlag, appContext any) (bo
 	for _, clientFilter := range featureFlag.Conditions.ClientFilters {
 		matchedFeatureFilter, exists := fm.featureFilters[clientFilter.Name]
 		if !exists {
-			log.Printf("Feature filter %s is not found", clientFilter.Name)
+			fm.reportError(featureFlag.ID, withEvaluationID(evaluationID, fmt.Errorf("feature filter %s is not found", clientFilter.Name)))
 			return false, nil
 		}
 
 		// Create context with feature name and parameters
 		filterContext := FeatureFilterEvaluationContext{
-			FeatureName: featureFlag.ID,
-			Parameters:  clientFilter.Parameters,
+			FeatureName:           featureFlag.ID,
+			Parameters:            clientFilter.Parameters,
+			highResolutionBuckets: fm.bucketer.highResolution,
+			userPatterns:          fm.users.patterns,
 		}
 
 		// Evaluate the filter
-		filterResult, err := matchedFeatureFilter.Evaluate(filterContext, appContext)
+		var start time.Time
+		if timings != nil {
+			start = time.Now()
+		}
+		filterResult, err := fm.evaluateFilter(matchedFeatureFilter, clientFilter.Name, filterContext, appContext)
+		if timings != nil {
+			*timings = append(*timings, FilterTiming{
+				FeatureName: featureFlag.ID,
+				FilterName:  clientFilter.Name,
+				Duration:    time.Since(start),
+				Result:      filterResult,
+				Err:         err,
+			})
+		}
 		if err != nil {
 			return false, fmt.Errorf("error evaluating filter %s: %w", clientFilter.Name, err)
 		}
@@ -222,32 +666,44 @@ func (fm *FeatureManager) isEnabled(featureFlag FeatureFlag, appContext any) (bo
 	return !shortCircuitEvalResult, nil
 }
 
-func (fm *FeatureManager) evaluateFeature(featureFlag FeatureFlag, appContext any) (EvaluationResult, error) {
+func (fm *FeatureManager) evaluateFeature(featureFlag FeatureFlag, appContext any, evaluationID string) (EvaluationResult, error) {
 	result := EvaluationResult{
-		Feature: &featureFlag,
+		Feature:      &featureFlag,
+		EvaluationID: evaluationID,
 	}
 
-	// Validate feature flag format
-	if err := validateFeatureFlag(featureFlag); err != nil {
-		return result, fmt.Errorf("invalid feature flag: %w", err)
+	// Validate feature flag format, unless the provider already did when loading it
+	if !fm.skipValidation {
+		if err := validateFeatureFlag(featureFlag); err != nil {
+			if fm.onValidationError != nil {
+				fm.onValidationError(ValidationError{FeatureName: featureFlag.ID, Err: err})
+			}
+			if fm.skipInvalidFlags {
+				return result, nil
+			}
+			return result, fmt.Errorf("invalid feature flag: %w", err)
+		}
 	}
 
 	// Evaluate if feature is enabled
-	enabled, err := fm.isEnabled(featureFlag, appContext)
+	var timings *[]FilterTiming
+	if fm.timeFilters {
+		timings = &result.FilterTimings
+	}
+	enabled, err := fm.isEnabled(&featureFlag, appContext, evaluationID, timings)
+	if fm.filterTimingRecorder != nil {
+		for _, timing := range result.FilterTimings {
+			fm.filterTimingRecorder.RecordFilterTiming(timing)
+		}
+	}
 	if err != nil {
 		return result, err
 	}
 	result.Enabled = enabled
 
-	var targetingContext *TargetingContext
-	if appContext != nil {
-		if tc, ok := appContext.(TargetingContext); ok {
-			result.TargetingID = tc.UserID
-			targetingContext = &tc
-		} else if tc, ok := appContext.(*TargetingContext); ok {
-			result.TargetingID = tc.UserID
-			targetingContext = tc
-		}
+	targetingContext, ok := targetingContextFrom(appContext)
+	if ok {
+		result.TargetingID = targetingContext.UserID
 	}
 
 	// Determine variant
@@ -262,11 +718,10 @@ func (fm *FeatureManager) evaluateFeature(featureFlag FeatureFlag, appContext an
 			}
 		} else {
 			// Enabled, assign based on allocation
-			if targetingContext != nil && featureFlag.Allocation != nil {
-				if variantAssignment, err := assignVariant(featureFlag, *targetingContext); variantAssignment != nil && err == nil {
-					variantDef = variantAssignment.Variant
-					reason = variantAssignment.Reason
-				}
+			if targetingContext != nil {
+				assignment := fm.allocate(&featureFlag, targetingContext, evaluationID)
+				variantDef = assignment.Variant
+				reason = assignment.Reason
 			}
 
 			// Allocation failed, assign default if specified
@@ -279,14 +734,21 @@ func (fm *FeatureManager) evaluateFeature(featureFlag FeatureFlag, appContext an
 		}
 	}
 
-	// Set variant in result
+	// Set variant in result, with its own copy of the configuration value for the application
 	if variantDef != nil {
 		result.Variant = &Variant{
 			Name:               variantDef.Name,
-			ConfigurationValue: variantDef.ConfigurationValue,
+			ConfigurationValue: cloneValue(variantDef.ConfigurationValue),
 		}
 	}
 	result.VariantAssignmentReason = reason
+	if featureFlag.Allocation != nil {
+		result.DefaultWhenEnabled = featureFlag.Allocation.DefaultWhenEnabled
+		result.VariantAssignmentPercentage = variantAssignmentPercentage(featureFlag.Allocation, result.Variant, reason)
+		if featureFlag.Telemetry != nil && featureFlag.Telemetry.Enabled {
+			result.AllocationID = allocationID(&featureFlag)
+		}
+	}
 
 	// Apply status override from variant
 	if variantDef != nil && featureFlag.Enabled {
@@ -301,9 +763,10 @@ func (fm *FeatureManager) evaluateFeature(featureFlag FeatureFlag, appContext an
 }
 
 func getVariant(variants []VariantDefinition, name string) *VariantDefinition {
-	for _, v := range variants {
-		if v.Name == name {
-			return &v
+	// Index rather than range so the result points into the slice instead of a heap-allocated copy
+	for i := range variants {
+		if variants[i].Name == name {
+			return &variants[i]
 		}
 	}
 
@@ -313,57 +776,85 @@ func getVariant(variants []VariantDefinition, name string) *VariantDefinition {
 type variantAssignment struct {
 	Variant *VariantDefinition
 	Reason  VariantAssignmentReason
+	// MissingVariant is the name of the allocated variant when the feature doesn't define it
+	MissingVariant string
 }
 
-func getVariantAssignment(featureFlag FeatureFlag, variantName string, reason VariantAssignmentReason) *variantAssignment {
+func getVariantAssignment(featureFlag *FeatureFlag, variantName string, reason VariantAssignmentReason) variantAssignment {
 	if variantName == "" {
-		return nil
+		return variantAssignment{Reason: VariantAssignmentReasonNone}
 	}
 
 	variant := getVariant(featureFlag.Variants, variantName)
 	if variant == nil {
-		log.Printf("Variant %s not found in feature %s", variantName, featureFlag.ID)
-		return nil
+		return variantAssignment{Reason: VariantAssignmentReasonNone, MissingVariant: variantName}
 	}
 
-	return &variantAssignment{
+	return variantAssignment{
 		Variant: variant,
 		Reason:  reason,
 	}
 }
 
-func assignVariant(featureFlag FeatureFlag, targetingContext TargetingContext) (*variantAssignment, error) {
+// allocationHint returns the hint used to bucket users for percentile allocation.
+// The default seed is "allocation\n<feature id>".
+func allocationHint(featureFlag *FeatureFlag) []string {
+	if featureFlag.Allocation.Seed != "" {
+		return []string{featureFlag.Allocation.Seed}
+	}
+
+	return []string{AllocationHint, featureFlag.ID}
+}
+
+// allocationTargetingID returns the ID targeted by the user, percentile and exclusion group rules
+// of an allocation: the user ID, or the value of the allocation's targeting attribute
+func allocationTargetingID(allocation *VariantAllocation, targetingContext *TargetingContext) string {
+	if allocation.TargetingAttribute == "" {
+		return targetingContext.UserID
+	}
+
+	return targetingContext.Attributes[allocation.TargetingAttribute]
+}
+
+func assignVariant(featureFlag *FeatureFlag, targetingContext *TargetingContext, bucket bucketer, users userMatcher) variantAssignment {
+	targetingID := allocationTargetingID(featureFlag.Allocation, targetingContext)
+	// Contexts without the targeted attribute can only be allocated by group, and not at all
+	// into an exclusion group
+	attributeMissing := featureFlag.Allocation.TargetingAttribute != "" && targetingID == ""
+
+	if group := featureFlag.Allocation.ExclusionGroup; group != nil {
+		if attributeMissing || !inExclusionGroup(group, targetingID, bucket) {
+			return variantAssignment{Reason: VariantAssignmentReasonNone}
+		}
+	}
+
 	if len(featureFlag.Allocation.User) > 0 {
 		for _, userAlloc := range featureFlag.Allocation.User {
-			if isTargetedUser(targetingContext.UserID, userAlloc.Users) {
-				return getVariantAssignment(featureFlag, userAlloc.Variant, VariantAssignmentReasonUser), nil
+			if isTargetedUser(targetingID, userAlloc.Users, users.match) {
+				return getVariantAssignment(featureFlag, userAlloc.Variant, VariantAssignmentReasonUser)
 			}
 		}
 	}
 
 	if len(featureFlag.Allocation.Group) > 0 {
 		for _, groupAlloc := range featureFlag.Allocation.Group {
-			if isTargetedGroup(targetingContext.Groups, groupAlloc.Groups) {
-				return getVariantAssignment(featureFlag, groupAlloc.Variant, VariantAssignmentReasonGroup), nil
+			if isTargetedGroup(targetingContext.Groups, groupAlloc.Groups, userMatcher{}.match) {
+				return getVariantAssignment(featureFlag, groupAlloc.Variant, VariantAssignmentReasonGroup)
 			}
 		}
 	}
 
-	if len(featureFlag.Allocation.Percentile) > 0 {
+	if len(featureFlag.Allocation.Percentile) > 0 && !attributeMissing {
+		hint := allocationHint(featureFlag)
 		for _, percentAlloc := range featureFlag.Allocation.Percentile {
-			hint := featureFlag.Allocation.Seed
-			if hint == "" {
-				hint = fmt.Sprintf("allocation\n%s", featureFlag.ID)
-			}
-
-			if ok, _ := isTargetedPercentile(targetingContext.UserID, hint, percentAlloc.From, percentAlloc.To); ok {
-				return getVariantAssignment(featureFlag, percentAlloc.Variant, VariantAssignmentReasonPercentile), nil
+			if ok, _ := isTargetedPercentile(bucket, targetingID, percentAlloc.From, percentAlloc.To, hint...); ok {
+				return getVariantAssignment(featureFlag, percentAlloc.Variant, VariantAssignmentReasonPercentile)
 			}
 		}
 	}
 
-	return &variantAssignment{
+	return variantAssignment{
 		Variant: nil,
 		Reason:  VariantAssignmentReasonNone,
-	}, nil
+	}
 }
@@ -3,9 +3,25 @@
 
 package featuremanagement
 
+import (
+	"iter"
+	"time"
+)
+
 // FeatureFlagProvider defines the interface for retrieving feature flags from a source.
 // Implementations of this interface can fetch feature flags from various configuration
 // stores such as Azure App Configuration, local JSON files, or other sources.
+//
+// Providers may return the definitions they hold without copying them: the FeatureManager only
+// reads them, and its filters cache their decoded parameters by the identity of the parameter
+// maps, falling back to comparing their content. The manager hands copies to application code;
+// see FeatureFlag.Clone. The providers of this package and of its provider modules return copies
+// as well, so callers can't change the definitions they serve.
+//
+// Refreshes must be copy-on-write: a provider publishes new definitions, with new parameter maps,
+// instead of modifying the definitions it already returned. An evaluation reads the definition of
+// its feature once, so it then runs entirely against that definition, and a refresh completing
+// during the evaluation can't mix old and new filter parameters.
 type FeatureFlagProvider interface {
 	// GetFeatureFlag retrieves a specific feature flag by its name.
 	//
@@ -24,3 +40,112 @@ type FeatureFlagProvider interface {
 	//   - error: An error if the feature flags cannot be retrieved
 	GetFeatureFlags() ([]FeatureFlag, error)
 }
+
+// FeatureFlagIterator can be implemented by a FeatureFlagProvider to enumerate feature flags
+// without materializing them into a new slice. The FeatureManager uses it when available,
+// which avoids copying very large flag sets on every enumeration.
+type FeatureFlagIterator interface {
+	// All returns an iterator over all available feature flags.
+	//
+	// Returns:
+	//   - iter.Seq[FeatureFlag]: An iterator yielding each feature flag in provider order
+	All() iter.Seq[FeatureFlag]
+}
+
+// ValidatingFeatureFlagProvider is implemented by providers that validate feature flags with
+// ValidateFeatureFlag when they load or refresh them, and never return invalid flags.
+// The FeatureManager skips per-evaluation validation for such providers.
+type ValidatingFeatureFlagProvider interface {
+	FeatureFlagProvider
+
+	// ValidatesFeatureFlags reports whether every feature flag returned by the provider
+	// has already passed ValidateFeatureFlag.
+	ValidatesFeatureFlags() bool
+}
+
+// ValidationReporter can be implemented by a ValidatingFeatureFlagProvider to report the feature
+// flags it rejected. The FeatureManager surfaces these through ValidationErrors.
+type ValidationReporter interface {
+	// ValidationErrors returns the feature flags rejected by the most recent load or refresh.
+	//
+	// Returns:
+	//   - []ValidationError: An error for each rejected feature flag
+	ValidationErrors() []ValidationError
+}
+
+// FeatureFlagWriter defines the interface for updating feature flags in a source.
+// Providers backed by a writable store, such as an in-memory set or a file, can implement it
+// to allow feature flags to be changed at runtime, for example by a RolloutController.
+type FeatureFlagWriter interface {
+	// SetFeatureFlag creates or replaces the feature flag with the same ID.
+	//
+	// Parameters:
+	//   - flag: The feature flag definition to store
+	//
+	// Returns:
+	//   - error: An error if the feature flag cannot be stored
+	SetFeatureFlag(flag FeatureFlag) error
+}
+
+// WritableFeatureFlagProvider is a FeatureFlagProvider whose feature flags can be updated.
+type WritableFeatureFlagProvider interface {
+	FeatureFlagProvider
+	FeatureFlagWriter
+}
+
+// RefreshIntervalController can be implemented by a FeatureFlagProvider that refreshes its feature
+// flags in the background, so that the refresh cadence can be changed at runtime, for example to
+// refresh more often during an incident. The FeatureManager exposes it through SetRefreshInterval.
+type RefreshIntervalController interface {
+	// RefreshInterval returns the current interval between background refreshes.
+	//
+	// Returns:
+	//   - time.Duration: The refresh interval; a negative interval means background refresh is paused
+	RefreshInterval() time.Duration
+
+	// SetRefreshInterval changes the interval between background refreshes. The next refresh is
+	// scheduled one interval after the change.
+	//
+	// Parameters:
+	//   - interval: The new refresh interval; a negative interval pauses background refresh
+	//
+	// Returns:
+	//   - error: An error if the interval is not supported by the provider
+	SetRefreshInterval(interval time.Duration) error
+}
+
+// ProviderStatus describes whether a provider has loaded its feature flags and the outcome of its
+// most recent refresh.
+type ProviderStatus struct {
+	// Loaded reports that the provider completed its first load of feature flags
+	Loaded bool
+	// LastRefreshTime is the time of the last successful load or refresh
+	LastRefreshTime time.Time
+	// LastRefreshError is the error of the most recent refresh, or nil if it succeeded
+	LastRefreshError error
+}
+
+// ProviderStatusReporter can be implemented by a FeatureFlagProvider that loads or refreshes its
+// feature flags from a remote source. The FeatureManager surfaces the status through Ready and Stats.
+type ProviderStatusReporter interface {
+	// ProviderStatus returns whether the first load completed and the outcome of the last refresh.
+	//
+	// Returns:
+	//   - ProviderStatus: The current status of the provider
+	ProviderStatus() ProviderStatus
+}
+
+// RefreshNotifier can be implemented by a FeatureFlagProvider that refreshes its feature flags, so
+// that the FeatureManager can react to changes as soon as they are loaded, for example to call the
+// callbacks registered with OnFeatureChanged. Providers can use RefreshListeners to implement it.
+type RefreshNotifier interface {
+	// OnRefresh registers a callback called after each refresh that loaded new feature flags.
+	// The callback may be called concurrently with evaluations and must not block for long.
+	//
+	// Parameters:
+	//   - callback: The function to call after a refresh
+	//
+	// Returns:
+	//   - func(): A function unregistering the callback
+	OnRefresh(callback func()) func()
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"iter"
+	"time"
+)
+
+// FeatureSet evaluates features against a frozen copy of the feature flags of a FeatureManager.
+// Refreshes of the provider after the FeatureSet is taken don't affect it, so a batch job can make
+// consistent decisions across many items while the flags keep refreshing. It uses the filters,
+// overrides, interceptors and telemetry of the manager it was taken from, and is safe for
+// concurrent use.
+type FeatureSet struct {
+	manager   *FeatureManager
+	flags     *StaticProvider
+	createdAt time.Time
+}
+
+// Snapshot freezes the feature flags currently supplied by the provider into a FeatureSet.
+// Flags are copied by value; providers replace their definitions on refresh rather than modify
+// them, so later refreshes don't change the snapshot. When IDs are duplicated, the first
+// definition wins.
+//
+// Example:
+//
+//	features := manager.Snapshot()
+//	for _, order := range orders {
+//		enabled, _ := features.IsEnabled("NewPricing", order.Customer)
+//		// ...
+//	}
+//
+// Returns:
+//   - *FeatureSet: The frozen feature flags
+func (fm *FeatureManager) Snapshot() *FeatureSet {
+	frozen := &StaticProvider{featureFlagsByID: make(map[string]FeatureFlag)}
+	for flag := range fm.flags() {
+		if _, exists := frozen.featureFlagsByID[flag.ID]; exists {
+			continue
+		}
+		frozen.featureFlags = append(frozen.featureFlags, flag)
+		frozen.featureFlagsByID[flag.ID] = flag
+	}
+
+	derived := *fm
+	derived.featureProvider = frozen
+	derived.evaluator = derived.intercept(derived.evaluateFlag)
+
+	return &FeatureSet{manager: &derived, flags: frozen, createdAt: time.Now()}
+}
+
+// CreatedAt returns the time the feature flags were frozen.
+func (s *FeatureSet) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+// IsEnabled determines if a feature flag of the snapshot is enabled for the given context.
+//
+// Parameters:
+//   - featureName: The name of the feature to evaluate
+//   - appContext: An optional context object for contextual evaluation
+//
+// Returns:
+//   - bool: true if the feature is enabled, false otherwise
+//   - error: An error if the feature flag is not in the snapshot or cannot be evaluated
+func (s *FeatureSet) IsEnabled(featureName string, appContext any) (bool, error) {
+	return s.manager.IsEnabledWithAppContext(featureName, appContext)
+}
+
+// GetVariant returns the variant of a feature flag of the snapshot assigned for the given context.
+//
+// Parameters:
+//   - featureName: The name of the feature to evaluate
+//   - appContext: An optional context object for contextual evaluation
+//
+// Returns:
+//   - *Variant: The assigned variant, or nil if no variant is assigned
+//   - error: An error if the feature flag is not in the snapshot or cannot be evaluated
+func (s *FeatureSet) GetVariant(featureName string, appContext any) (*Variant, error) {
+	return s.manager.GetVariant(featureName, appContext)
+}
+
+// Evaluate evaluates a feature flag of the snapshot and returns the full result.
+//
+// Parameters:
+//   - featureName: The name of the feature to evaluate
+//   - appContext: An optional context object for contextual evaluation
+//
+// Returns:
+//   - EvaluationResult: The state of the feature, its assigned variant and how it was assigned
+//   - error: An error if the feature flag is not in the snapshot or cannot be evaluated
+func (s *FeatureSet) Evaluate(featureName string, appContext any) (EvaluationResult, error) {
+	return s.manager.Evaluate(featureName, appContext)
+}
+
+// GetFeatureNames returns the names of the feature flags of the snapshot, in provider order.
+// See FeatureManager.GetFeatureNames.
+func (s *FeatureSet) GetFeatureNames() ([]string, error) {
+	return s.manager.GetFeatureNames()
+}
+
+// All returns an iterator over the feature flags of the snapshot, in provider order.
+func (s *FeatureSet) All() iter.Seq[FeatureFlag] {
+	return s.manager.All()
+}
@@ -0,0 +1,47 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	provider := &notifyingProvider{flags: NewStaticProvider(map[string]FeatureFlag{
+		"Alpha": {Enabled: true},
+		"Beta":  {Enabled: false},
+	})}
+	manager, err := NewFeatureManager(provider, &Options{Overrides: map[string]bool{"Local": true}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	features := manager.Snapshot()
+	provider.set(map[string]FeatureFlag{"Beta": {Enabled: true}})
+
+	if enabled, err := features.IsEnabled("Alpha", nil); err != nil || !enabled {
+		t.Errorf("Expected Alpha to stay enabled in the snapshot, got %v, %v", enabled, err)
+	}
+	if enabled, err := features.IsEnabled("Beta", nil); err != nil || enabled {
+		t.Errorf("Expected Beta to stay disabled in the snapshot, got %v, %v", enabled, err)
+	}
+	if enabled, err := features.IsEnabled("Local", nil); err != nil || !enabled {
+		t.Errorf("Expected overrides to apply to the snapshot, got %v, %v", enabled, err)
+	}
+	if names, err := features.GetFeatureNames(); err != nil || fmt.Sprint(names) != "[Alpha Beta]" {
+		t.Errorf("Unexpected feature names %v, %v", names, err)
+	}
+	if flags := slices.Collect(features.All()); len(flags) != 2 {
+		t.Errorf("Expected 2 frozen flags, got %+v", flags)
+	}
+
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || !enabled {
+		t.Errorf("Expected the manager to see the refreshed flags, got %v, %v", enabled, err)
+	}
+	if _, err := manager.IsEnabled("Alpha"); err == nil {
+		t.Error("Expected Alpha to be removed from the manager")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuretest
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// ChurnProvider is a FeatureFlagProvider that replaces its feature flags from a background
+// goroutine on a fixed schedule, cycling through a list of flag sets. It simulates a provider
+// refreshing under load, so that tests run with the race detector can prove that concurrent
+// refresh and evaluation is safe.
+//
+// Example:
+//
+//	provider := featuretest.NewChurnProvider(time.Millisecond, setA, setB)
+//	defer provider.Stop()
+//	manager, _ := featuremanagement.NewFeatureManager(provider, nil)
+type ChurnProvider struct {
+	sets    [][]fm.FeatureFlag
+	mu      sync.RWMutex
+	current []fm.FeatureFlag
+	updates atomic.Int64
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewChurnProvider creates a provider serving the first flag set and starts a goroutine
+// switching to the next set every interval, wrapping around after the last one.
+// Call Stop to end the goroutine.
+func NewChurnProvider(interval time.Duration, sets ...[]fm.FeatureFlag) *ChurnProvider {
+	if len(sets) == 0 {
+		sets = [][]fm.FeatureFlag{nil}
+	}
+
+	p := &ChurnProvider{
+		sets:    sets,
+		current: sets[0],
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go p.churn(interval)
+	return p
+}
+
+// Stop ends the background updates and waits for the goroutine to exit. It is safe to call more than once.
+func (p *ChurnProvider) Stop() {
+	p.once.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+}
+
+// Updates returns the number of times the flag set has been replaced.
+func (p *ChurnProvider) Updates() int64 {
+	return p.updates.Load()
+}
+
+func (p *ChurnProvider) GetFeatureFlag(name string) (fm.FeatureFlag, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, flag := range p.current {
+		if flag.ID == name {
+			return flag, nil
+		}
+	}
+
+	return fm.FeatureFlag{}, fmt.Errorf("feature flag with ID %s not found", name)
+}
+
+func (p *ChurnProvider) GetFeatureFlags() ([]fm.FeatureFlag, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.current, nil
+}
+
+func (p *ChurnProvider) churn(interval time.Duration) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for next := 1; ; next++ {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+
+		// Replace the whole set, as real providers do on refresh
+		set := make([]fm.FeatureFlag, len(p.sets[next%len(p.sets)]))
+		copy(set, p.sets[next%len(p.sets)])
+
+		p.mu.Lock()
+		p.current = set
+		p.mu.Unlock()
+		p.updates.Add(1)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package featuretest provides helpers for unit testing applications that use feature management.
+//
+// Instead of hand-rolling mock providers and JSON fixtures, tests describe the feature state
+// they need with a fluent builder and get a ready-to-use FeatureManager:
+//
+//	manager := featuretest.New().
+//		Enabled("Beta").
+//		Disabled("Legacy").
+//		Variant("Greeting", "Casual").
+//		Manager()
+package featuretest
+
+import (
+	"fmt"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// Builder assembles a set of feature flags for tests
+type Builder struct {
+	flags   []fm.FeatureFlag
+	index   map[string]int
+	filters []fm.FeatureFilter
+}
+
+// New creates an empty Builder.
+func New() *Builder {
+	return &Builder{
+		index: make(map[string]int),
+	}
+}
+
+// Enabled declares features that are unconditionally enabled.
+func (b *Builder) Enabled(featureNames ...string) *Builder {
+	for _, name := range featureNames {
+		flag := b.flag(name)
+		flag.Enabled = true
+		flag.Conditions = nil
+	}
+
+	return b
+}
+
+// Disabled declares features that are unconditionally disabled.
+func (b *Builder) Disabled(featureNames ...string) *Builder {
+	for _, name := range featureNames {
+		flag := b.flag(name)
+		flag.Enabled = false
+		flag.Conditions = nil
+	}
+
+	return b
+}
+
+// Variant assigns the named variant to every evaluation of the feature, whether it is
+// enabled or disabled. Features declared for the first time by Variant are enabled.
+func (b *Builder) Variant(featureName, variantName string) *Builder {
+	return b.VariantWithValue(featureName, variantName, nil)
+}
+
+// VariantWithValue is like Variant, with the given configuration value for the variant.
+func (b *Builder) VariantWithValue(featureName, variantName string, configurationValue any) *Builder {
+	if _, exists := b.index[featureName]; !exists {
+		b.flag(featureName).Enabled = true
+	}
+
+	flag := b.flag(featureName)
+	flag.Variants = []fm.VariantDefinition{
+		{
+			Name:               variantName,
+			ConfigurationValue: configurationValue,
+		},
+	}
+	flag.Allocation = &fm.VariantAllocation{
+		DefaultWhenEnabled:  variantName,
+		DefaultWhenDisabled: variantName,
+	}
+
+	return b
+}
+
+// Flag adds a complete feature flag definition, replacing any flag with the same ID.
+// Use it for scenarios the shorthand methods don't cover, such as filters and allocation rules.
+func (b *Builder) Flag(flag fm.FeatureFlag) *Builder {
+	*b.flag(flag.ID) = flag
+	return b
+}
+
+// Filter registers a custom feature filter with the manager built by Manager.
+func (b *Builder) Filter(filters ...fm.FeatureFilter) *Builder {
+	b.filters = append(b.filters, filters...)
+	return b
+}
+
+// Provider returns a FeatureFlagProvider supplying the declared feature flags.
+func (b *Builder) Provider() fm.FeatureFlagProvider {
+	flags := make(map[string]fm.FeatureFlag, len(b.flags))
+	for _, flag := range b.flags {
+		flags[flag.ID] = flag
+	}
+
+	return fm.NewStaticProvider(flags)
+}
+
+// Manager returns a FeatureManager evaluating the declared feature flags with the registered filters.
+func (b *Builder) Manager() *fm.FeatureManager {
+	manager, err := fm.NewFeatureManager(b.Provider(), &fm.Options{Filters: b.filters})
+	if err != nil {
+		// NewFeatureManager only fails for a nil provider
+		panic(fmt.Sprintf("featuretest: failed to create feature manager: %v", err))
+	}
+
+	return manager
+}
+
+// flag returns the declared flag with the given ID, declaring a disabled flag if it doesn't exist yet
+func (b *Builder) flag(id string) *fm.FeatureFlag {
+	i, exists := b.index[id]
+	if !exists {
+		i = len(b.flags)
+		b.index[id] = i
+		b.flags = append(b.flags, fm.FeatureFlag{ID: id})
+	}
+
+	return &b.flags[i]
+}
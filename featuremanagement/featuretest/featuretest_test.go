@@ -0,0 +1,68 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuretest
+
+import (
+	"testing"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+type alwaysFilter struct{}
+
+func (f *alwaysFilter) Name() string {
+	return "Always"
+}
+
+func (f *alwaysFilter) Evaluate(evalCtx fm.FeatureFilterEvaluationContext, appCtx any) (bool, error) {
+	return true, nil
+}
+
+func TestBuilder(t *testing.T) {
+	manager := New().
+		Enabled("Beta").
+		Disabled("Legacy").
+		Variant("Greeting", "Casual").
+		Disabled("DisabledWithVariant").
+		VariantWithValue("DisabledWithVariant", "Small", "300px").
+		Flag(fm.FeatureFlag{
+			ID:      "Filtered",
+			Enabled: true,
+			Conditions: &fm.Conditions{
+				ClientFilters: []fm.ClientFilter{{Name: "Always"}},
+			},
+		}).
+		Filter(&alwaysFilter{}).
+		Manager()
+
+	for name, expected := range map[string]bool{
+		"Beta":                true,
+		"Legacy":              false,
+		"Greeting":            true,
+		"DisabledWithVariant": false,
+		"Filtered":            true,
+	} {
+		enabled, err := manager.IsEnabled(name)
+		if err != nil {
+			t.Fatalf("Unexpected error evaluating %s: %v", name, err)
+		}
+		if enabled != expected {
+			t.Errorf("Expected %s to be %v, got %v", name, expected, enabled)
+		}
+	}
+
+	variant, err := manager.GetVariant("Greeting", nil)
+	if err != nil || variant == nil || variant.Name != "Casual" {
+		t.Errorf("Expected Casual variant, got %v, %v", variant, err)
+	}
+
+	variant, err = manager.GetVariant("DisabledWithVariant", nil)
+	if err != nil || variant == nil || variant.Name != "Small" || variant.ConfigurationValue != "300px" {
+		t.Errorf("Expected Small variant, got %v, %v", variant, err)
+	}
+
+	if _, err := manager.IsEnabled("Unknown"); err == nil {
+		t.Error("Expected error for an undeclared feature")
+	}
+}
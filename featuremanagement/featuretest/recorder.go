@@ -0,0 +1,124 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuretest
+
+import (
+	"sync"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// Call is an evaluation received by a Recorder.
+type Call struct {
+	Context    fm.FeatureFilterEvaluationContext
+	AppContext any
+}
+
+type scriptedResult struct {
+	enabled bool
+	err     error
+}
+
+// Recorder is a FeatureFilter that records every evaluation it receives and returns scripted results.
+// It lets tests assert that their feature flags invoke custom filters with the expected parameters,
+// and that requirement types short-circuit as expected.
+//
+// Example:
+//
+//	recorder := featuretest.NewRecorder("Custom").ReturnFor("Beta", true, false)
+//	manager := featuretest.New().Flag(betaFlag).Filter(recorder).Manager()
+//	manager.IsEnabled("Beta")
+//	calls := recorder.CallsFor("Beta")
+type Recorder struct {
+	name    string
+	mu      sync.Mutex
+	calls   []Call
+	result  scriptedResult
+	scripts map[string][]scriptedResult
+}
+
+// NewRecorder creates a Recorder registered under the given filter name. It returns false
+// for every evaluation until configured otherwise.
+func NewRecorder(name string) *Recorder {
+	return &Recorder{
+		name:    name,
+		scripts: make(map[string][]scriptedResult),
+	}
+}
+
+// Return sets the result of evaluations that have no scripted result.
+func (r *Recorder) Return(enabled bool) *Recorder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.result = scriptedResult{enabled: enabled}
+	return r
+}
+
+// ReturnFor scripts the results of successive evaluations for a feature. Once the script is
+// exhausted, its last result is repeated.
+func (r *Recorder) ReturnFor(featureName string, results ...bool) *Recorder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, enabled := range results {
+		r.scripts[featureName] = append(r.scripts[featureName], scriptedResult{enabled: enabled})
+	}
+	return r
+}
+
+// ReturnErrorFor scripts an evaluation error for a feature, after any results already scripted for it.
+func (r *Recorder) ReturnErrorFor(featureName string, err error) *Recorder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scripts[featureName] = append(r.scripts[featureName], scriptedResult{err: err})
+	return r
+}
+
+// Calls returns the evaluations received so far, in order.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := make([]Call, len(r.calls))
+	copy(calls, r.calls)
+	return calls
+}
+
+// CallsFor returns the evaluations received so far for a feature, in order.
+func (r *Recorder) CallsFor(featureName string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, call := range r.calls {
+		if call.Context.FeatureName == featureName {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded evaluations. Scripted results are kept.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+func (r *Recorder) Name() string {
+	return r.name
+}
+
+func (r *Recorder) Evaluate(evalCtx fm.FeatureFilterEvaluationContext, appContext any) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Context: evalCtx, AppContext: appContext})
+
+	result := r.result
+	if script := r.scripts[evalCtx.FeatureName]; len(script) > 0 {
+		result = script[0]
+		if len(script) > 1 {
+			r.scripts[evalCtx.FeatureName] = script[1:]
+		}
+	}
+
+	return result.enabled, result.err
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuretest
+
+import (
+	"errors"
+	"testing"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+func TestRecorder(t *testing.T) {
+	first := NewRecorder("First").ReturnFor("Beta", false, true)
+	second := NewRecorder("Second").Return(true).ReturnErrorFor("Broken", errors.New("boom"))
+
+	flag := func(id string, requirementType fm.RequirementType) fm.FeatureFlag {
+		return fm.FeatureFlag{
+			ID:      id,
+			Enabled: true,
+			Conditions: &fm.Conditions{
+				RequirementType: requirementType,
+				ClientFilters: []fm.ClientFilter{
+					{Name: "First", Parameters: map[string]any{"Region": "EU"}},
+					{Name: "Second"},
+				},
+			},
+		}
+	}
+
+	manager := New().
+		Flag(flag("Beta", fm.RequirementTypeAll)).
+		Flag(flag("Broken", fm.RequirementTypeAny)).
+		Filter(first, second).
+		Manager()
+
+	// First evaluation: First returns false, so All short-circuits before Second
+	if enabled, _ := manager.IsEnabled("Beta"); enabled {
+		t.Error("Expected Beta to be disabled on the first evaluation")
+	}
+	if len(second.CallsFor("Beta")) != 0 {
+		t.Error("Expected the All requirement to short-circuit before the second filter")
+	}
+
+	// Second evaluation: both filters pass
+	if enabled, _ := manager.IsEnabledWithAppContext("Beta", "ctx"); !enabled {
+		t.Error("Expected Beta to be enabled on the second evaluation")
+	}
+	calls := first.CallsFor("Beta")
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 calls to the first filter, got %d", len(calls))
+	}
+	if calls[1].Context.Parameters["Region"] != "EU" || calls[1].AppContext != "ctx" {
+		t.Errorf("Unexpected recorded call %+v", calls[1])
+	}
+
+	if _, err := manager.IsEnabled("Broken"); err == nil {
+		t.Error("Expected the scripted error to surface")
+	}
+
+	first.Reset()
+	if len(first.Calls()) != 0 {
+		t.Error("Expected no calls after Reset")
+	}
+}
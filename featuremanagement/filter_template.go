@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import "fmt"
+
+// ResolveFilterTemplates replaces the client filters of feature flags that reference a filter
+// template with the name and parameters of the template, so that audiences shared by many flags,
+// such as the internal users of a company, are defined once and can't drift apart:
+//
+//	{"feature_management": {
+//		"filter_templates": {"InternalUsers": {"name": "Microsoft.Targeting", "parameters": {...}}},
+//		"feature_flags": [{"id": "Beta", "enabled": true, "conditions": {
+//			"client_filters": [{"template": "InternalUsers"}]}}]}}
+//
+// DecodeFeatureManagement resolves the templates of the feature_management section; providers
+// building flags by other means can call it directly. The flags given are not modified, and the
+// resolved filters keep their Template so that tools can tell where they come from.
+//
+// Parameters:
+//   - flags: The feature flag definitions
+//   - templates: The filter templates, by name
+//
+// Returns:
+//   - []FeatureFlag: The feature flags with their templates resolved
+//   - error: An error naming the feature flag that references an undefined template, or the
+//     template that is itself invalid
+func ResolveFilterTemplates(flags []FeatureFlag, templates map[string]ClientFilter) ([]FeatureFlag, error) {
+	for name, template := range templates {
+		if template.Name == "" {
+			return nil, fmt.Errorf("invalid filter template %s: missing name", name)
+		}
+		if template.Template != "" {
+			return nil, fmt.Errorf("invalid filter template %s: templates can't reference other templates", name)
+		}
+	}
+
+	resolved := make([]FeatureFlag, len(flags))
+	for i, flag := range flags {
+		resolved[i] = flag
+		if flag.Conditions == nil || !referencesTemplate(flag.Conditions.ClientFilters) {
+			continue
+		}
+
+		conditions := *flag.Conditions
+		conditions.ClientFilters = make([]ClientFilter, len(flag.Conditions.ClientFilters))
+		for j, filter := range flag.Conditions.ClientFilters {
+			if filter.Template != "" {
+				template, ok := templates[filter.Template]
+				if !ok {
+					return nil, fmt.Errorf("invalid feature flag %s: filter template %s is not defined", flag.ID, filter.Template)
+				}
+				filter.Name = template.Name
+				filter.Parameters = template.Parameters
+			}
+			conditions.ClientFilters[j] = filter
+		}
+		resolved[i].Conditions = &conditions
+	}
+
+	return resolved, nil
+}
+
+func referencesTemplate(filters []ClientFilter) bool {
+	for _, filter := range filters {
+		if filter.Template != "" {
+			return true
+		}
+	}
+
+	return false
+}
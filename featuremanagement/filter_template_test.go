@@ -0,0 +1,88 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"strings"
+	"testing"
+)
+
+const filterTemplateDocument = `{
+	"feature_management": {
+		"filter_templates": {
+			"InternalUsers": {
+				"name": "Microsoft.Targeting",
+				"parameters": {"Audience": {"Users": ["*@contoso.com"]}}
+			}
+		},
+		"feature_flags": [
+			{"id": "Beta", "enabled": true, "conditions": {"client_filters": [{"template": "InternalUsers"}]}},
+			{"id": "Search", "enabled": true, "conditions": {"client_filters": [{"template": "InternalUsers"}]}}
+		]
+	}
+}`
+
+func TestFilterTemplates(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		featureManagement, err := ParseFeatureManagementWithOptions([]byte(filterTemplateDocument), &DecodeOptions{Strict: strict})
+		if err != nil {
+			t.Fatalf("Failed to parse document with strict=%v: %v", strict, err)
+		}
+		if len(featureManagement.FilterTemplates) != 1 {
+			t.Errorf("Expected the filter templates to be returned, got %v", featureManagement.FilterTemplates)
+		}
+
+		flags := make(map[string]FeatureFlag)
+		for _, flag := range featureManagement.FeatureFlags {
+			filter := flag.Conditions.ClientFilters[0]
+			if filter.Name != "Microsoft.Targeting" || filter.Template != "InternalUsers" {
+				t.Errorf("Expected the filter of %s to be resolved, got %+v", flag.ID, filter)
+			}
+			flags[flag.ID] = flag
+		}
+
+		manager, err := NewFeatureManager(NewStaticProvider(flags), &Options{UserPatterns: true})
+		if err != nil {
+			t.Fatalf("Failed to create feature manager: %v", err)
+		}
+		for _, name := range []string{"Beta", "Search"} {
+			if enabled, err := manager.IsEnabledWithAppContext(name, TargetingContext{UserID: "alice@contoso.com"}); err != nil || !enabled {
+				t.Errorf("Expected %s to be enabled for internal users, got %v, %v", name, enabled, err)
+			}
+			if enabled, _ := manager.IsEnabledWithAppContext(name, TargetingContext{UserID: "bob@fabrikam.com"}); enabled {
+				t.Errorf("Expected %s to be disabled for external users", name)
+			}
+		}
+	}
+
+	if errs := ValidateDocument([]byte(filterTemplateDocument)); len(errs) != 0 {
+		t.Errorf("Expected the document to conform to the schema, got %v", errs)
+	}
+}
+
+func TestResolveFilterTemplates(t *testing.T) {
+	flags := []FeatureFlag{{
+		ID:         "Beta",
+		Conditions: &Conditions{ClientFilters: []ClientFilter{{Template: "InternalUsers"}, {Name: "Microsoft.TimeWindow"}}},
+	}}
+	templates := map[string]ClientFilter{"InternalUsers": {Name: "Microsoft.Targeting"}}
+
+	resolved, err := ResolveFilterTemplates(flags, templates)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolved[0].Conditions.ClientFilters[0].Name != "Microsoft.Targeting" || resolved[0].Conditions.ClientFilters[1].Name != "Microsoft.TimeWindow" {
+		t.Errorf("Unexpected resolved filters %+v", resolved[0].Conditions.ClientFilters)
+	}
+	if flags[0].Conditions.ClientFilters[0].Name != "" {
+		t.Error("Expected the given flags not to be modified")
+	}
+
+	if _, err := ResolveFilterTemplates(flags, nil); err == nil || !strings.Contains(err.Error(), "InternalUsers is not defined") {
+		t.Errorf("Expected an error for an undefined template, got %v", err)
+	}
+	if _, err := ResolveFilterTemplates(nil, map[string]ClientFilter{"Nested": {Name: "Microsoft.Targeting", Template: "InternalUsers"}}); err == nil {
+		t.Error("Expected an error for a template referencing another template")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import "time"
+
+// FilterTiming records how long a feature filter took to evaluate, so that a slow filter
+// dragging down request latency can be spotted.
+type FilterTiming struct {
+	// FeatureName is the name of the evaluated feature
+	FeatureName string
+	// FilterName is the name of the feature filter
+	FilterName string
+	// Duration is how long the filter took to evaluate
+	Duration time.Duration
+	// Result is the result of the filter
+	Result bool
+	// Err is the error returned by the filter, if any
+	Err error
+}
+
+// FilterTimingRecorder receives the duration of feature filter evaluations, for example to
+// export them as metrics. RecordFilterTiming is called synchronously on the evaluating goroutine
+// and must be safe for concurrent use.
+type FilterTimingRecorder interface {
+	// RecordFilterTiming handles the duration of a feature filter evaluation.
+	//
+	// Parameters:
+	//   - timing: The feature, filter and duration of the evaluation
+	RecordFilterTiming(timing FilterTiming)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type sleepingFilter struct {
+	delay time.Duration
+}
+
+func (f sleepingFilter) Name() string {
+	return "Sleeping"
+}
+
+func (f sleepingFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appCtx any) (bool, error) {
+	time.Sleep(f.delay)
+	return true, nil
+}
+
+type timingRecorder struct {
+	mu      sync.Mutex
+	timings []FilterTiming
+}
+
+func (r *timingRecorder) RecordFilterTiming(timing FilterTiming) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timings = append(r.timings, timing)
+}
+
+func TestFilterTimings(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {
+			Enabled: true,
+			Conditions: &Conditions{
+				RequirementType: RequirementTypeAll,
+				ClientFilters:   []ClientFilter{{Name: "Sleeping"}, {Name: "Microsoft.TimeWindow", Parameters: map[string]any{"Start": "Mon, 01 Jan 2024 00:00:00 GMT"}}},
+			},
+		},
+	})
+
+	recorder := &timingRecorder{}
+	manager, err := NewFeatureManager(provider, &Options{
+		Filters:              []FeatureFilter{sleepingFilter{delay: 5 * time.Millisecond}},
+		FilterTimingRecorder: recorder,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	result, err := manager.Evaluate("Beta", nil)
+	if err != nil || !result.Enabled {
+		t.Fatalf("Expected Beta to be enabled, got %v, %v", result.Enabled, err)
+	}
+
+	if len(result.FilterTimings) != 2 {
+		t.Fatalf("Expected a timing for each filter, got %+v", result.FilterTimings)
+	}
+	if timing := result.FilterTimings[0]; timing.FilterName != "Sleeping" || timing.FeatureName != "Beta" || !timing.Result || timing.Duration < 5*time.Millisecond {
+		t.Errorf("Unexpected timing for the sleeping filter: %+v", timing)
+	}
+	if len(recorder.timings) != 2 || recorder.timings[1].FilterName != "Microsoft.TimeWindow" {
+		t.Errorf("Expected the recorder to receive both timings, got %+v", recorder.timings)
+	}
+}
+
+func TestFilterTimingsDisabled(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {Enabled: true, Conditions: &Conditions{ClientFilters: []ClientFilter{{Name: "Sleeping"}}}},
+	})
+
+	manager, err := NewFeatureManager(provider, &Options{Filters: []FeatureFilter{sleepingFilter{}}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if result, err := manager.Evaluate("Beta", nil); err != nil || result.FilterTimings != nil {
+		t.Errorf("Expected no timings unless enabled, got %+v, %v", result.FilterTimings, err)
+	}
+}
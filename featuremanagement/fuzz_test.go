@@ -0,0 +1,88 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var fuzzFeatureFlagSeeds = []string{
+	`{"id": "Minimal", "enabled": true}`,
+	`{"id": "Invalid", "enabled": "invalid"}`,
+	`{"id": "Empty", "conditions": {}}`,
+	`{"id": "Targeting", "enabled": true, "conditions": {"requirement_type": "All", "client_filters": [
+		{"name": "Microsoft.Targeting", "parameters": {"Audience": {"Users": ["Alice"], "Groups": [{"Name": "Stage1", "RolloutPercentage": 50}],
+		"DefaultRolloutPercentage": 25, "Exclusion": {"Users": ["Dave"], "Groups": ["Stage3"]}}}},
+		{"name": "Microsoft.TimeWindow", "parameters": {"Start": "Thu, 29 Jun 2023 07:00:00 GMT", "End": "Sat, 28 Jun 3023 06:05:00 GMT"}}]}}`,
+	`{"id": "Variants", "enabled": true, "variants": [{"name": "Big", "configuration_value": {"Size": 1}, "status_override": "Disabled"}, {"name": "Small"}],
+		"allocation": {"default_when_enabled": "Small", "default_when_disabled": "Missing", "user": [{"variant": "Big", "users": ["Alice"]}],
+		"group": [{"variant": "Big", "groups": ["Stage1"]}], "percentile": [{"variant": "Big", "from": 50, "to": 10}], "seed": "1234"}}`,
+	`{"id": "BadParameters", "enabled": true, "conditions": {"client_filters": [
+		{"name": "Microsoft.Targeting", "parameters": {"Audience": "everyone"}},
+		{"name": "Microsoft.TimeWindow", "parameters": {"Start": 42}}]}}`,
+}
+
+func FuzzFeatureFlagUnmarshal(f *testing.F) {
+	for _, seed := range fuzzFeatureFlagSeeds {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var flag FeatureFlag
+		if err := json.Unmarshal(data, &flag); err != nil {
+			return
+		}
+
+		// Validation must never panic, whatever the shape of the definition
+		_ = ValidateFeatureFlag(flag)
+	})
+}
+
+func FuzzFilterParameters(f *testing.F) {
+	f.Add([]byte(`{"Audience": {"Users": ["Alice"], "DefaultRolloutPercentage": 50}}`), "Alice", "Stage1")
+	f.Add([]byte(`{"Audience": {"Groups": [{"Name": "Stage1", "RolloutPercentage": "50"}]}}`), "", "Stage1")
+	f.Add([]byte(`{"Audience": {"Exclusion": {"Users": "Dave"}}}`), "Dave", "")
+	f.Add([]byte(`{"Start": "Thu, 29 Jun 2023 07:00:00 GMT", "End": "not a time"}`), "Bob", "")
+	f.Add([]byte(`{"start": ["Thu, 29 Jun 2023 07:00:00 GMT"]}`), "Bob", "")
+
+	f.Fuzz(func(t *testing.T, data []byte, userID string, group string) {
+		var parameters map[string]any
+		if err := json.Unmarshal(data, &parameters); err != nil {
+			return
+		}
+
+		evalCtx := FeatureFilterEvaluationContext{FeatureName: "Fuzz", Parameters: parameters}
+		targetingContext := TargetingContext{UserID: userID, Groups: []string{group}}
+		for _, filter := range []FeatureFilter{&TargetingFilter{}, &TimeWindowFilter{}} {
+			// Errors are expected for malformed parameters, panics are not
+			_, _ = filter.Evaluate(evalCtx, targetingContext)
+			_, _ = filter.Evaluate(evalCtx, nil)
+		}
+	})
+}
+
+func FuzzIsEnabled(f *testing.F) {
+	for _, seed := range fuzzFeatureFlagSeeds {
+		f.Add([]byte(seed), "Alice", "Stage1")
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, userID string, group string) {
+		var flag FeatureFlag
+		if err := json.Unmarshal(data, &flag); err != nil {
+			return
+		}
+
+		manager, err := NewFeatureManager(&mockFeatureFlagProvider{featureFlags: []FeatureFlag{flag}}, nil)
+		if err != nil {
+			t.Fatalf("Failed to create feature manager: %v", err)
+		}
+
+		targetingContext := TargetingContext{UserID: userID, Groups: []string{group}}
+		_, _ = manager.IsEnabled(flag.ID)
+		_, _ = manager.IsEnabledWithAppContext(flag.ID, targetingContext)
+		_, _ = manager.GetVariant(flag.ID, &targetingContext)
+		_, _ = manager.GetFeatureNames()
+	})
+}
@@ -2,4 +2,8 @@ module github.com/microsoft/Featuremanagement-Go/featuremanagement
 
 go 1.23.0
 
-require github.com/go-viper/mapstructure/v2 v2.4.0
+require (
+	github.com/go-viper/mapstructure/v2 v2.4.0
+	golang.org/x/sync v0.16.0
+	golang.org/x/text v0.27.0
+)
@@ -0,0 +1,71 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+// Evaluator evaluates a feature flag for an app context, as FeatureManager.Evaluate does.
+type Evaluator func(featureName string, appContext any) (EvaluationResult, error)
+
+// Interceptor wraps the evaluation of every feature flag, to apply cross-cutting policies such
+// as a global kill switch, auditing or per-tenant restrictions without changing the FeatureManager.
+// It receives the next Evaluator in the chain and returns an Evaluator that may inspect or change
+// the arguments, call next, inspect or change the result, or return a result without calling next.
+//
+// Evaluations that don't call next skip usage tracking and telemetry.
+//
+// Example of a kill switch that disables every feature:
+//
+//	killSwitch := func(next featuremanagement.Evaluator) featuremanagement.Evaluator {
+//		return func(featureName string, appContext any) (featuremanagement.EvaluationResult, error) {
+//			if killed.Load() {
+//				return featuremanagement.EvaluationResult{}, nil
+//			}
+//			return next(featureName, appContext)
+//		}
+//	}
+type Interceptor func(next Evaluator) Evaluator
+
+// WithEnabled returns a copy of the result with the feature forced to a state, for interceptors
+// overriding the evaluated state. When the state changes, the variant is reassigned as an
+// evaluation in that state assigns it: the default_when_enabled or default_when_disabled variant
+// of the feature's allocation, if any.
+//
+// Parameters:
+//   - enabled: The state to force
+//
+// Returns:
+//   - EvaluationResult: The result in the forced state
+func (r EvaluationResult) WithEnabled(enabled bool) EvaluationResult {
+	if r.Enabled == enabled {
+		return r
+	}
+	r.Enabled = enabled
+	if r.Feature == nil || len(r.Feature.Variants) == 0 {
+		return r
+	}
+
+	allocation := r.Feature.Allocation
+	var name string
+	if enabled {
+		r.VariantAssignmentReason = VariantAssignmentReasonDefaultWhenEnabled
+		if allocation != nil {
+			name = allocation.DefaultWhenEnabled
+		}
+	} else {
+		r.VariantAssignmentReason = VariantAssignmentReasonDefaultWhenDisabled
+		if allocation != nil {
+			name = allocation.DefaultWhenDisabled
+		}
+	}
+
+	r.Variant = nil
+	if variantDef := getVariant(r.Feature.Variants, name); name != "" && variantDef != nil {
+		r.Variant = &Variant{
+			Name:               variantDef.Name,
+			ConfigurationValue: cloneValue(variantDef.ConfigurationValue),
+		}
+	}
+	r.VariantAssignmentPercentage = variantAssignmentPercentage(allocation, r.Variant, r.VariantAssignmentReason)
+
+	return r
+}
@@ -0,0 +1,140 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestInterceptors(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta":  {Enabled: true},
+		"Gamma": {Enabled: true},
+	})
+
+	var calls []string
+	tracing := func(name string) Interceptor {
+		return func(next Evaluator) Evaluator {
+			return func(featureName string, appContext any) (EvaluationResult, error) {
+				calls = append(calls, name+">"+featureName)
+				res, err := next(featureName, appContext)
+				calls = append(calls, name+"<"+featureName)
+				return res, err
+			}
+		}
+	}
+	killSwitch := func(next Evaluator) Evaluator {
+		return func(featureName string, appContext any) (EvaluationResult, error) {
+			if featureName == "Gamma" {
+				return EvaluationResult{Enabled: false}, nil
+			}
+			return next(featureName, appContext)
+		}
+	}
+
+	manager, err := NewFeatureManager(provider, &Options{
+		Interceptors: []Interceptor{tracing("outer"), killSwitch, tracing("inner")},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || !enabled {
+		t.Errorf("Expected Beta to be enabled, got %v, %v", enabled, err)
+	}
+	if got := strings.Join(calls, " "); got != "outer>Beta inner>Beta inner<Beta outer<Beta" {
+		t.Errorf("Expected interceptors to run in order, got %s", got)
+	}
+
+	calls = nil
+	if enabled, err := manager.IsEnabled("Gamma"); err != nil || enabled {
+		t.Errorf("Expected the kill switch to disable Gamma, got %v, %v", enabled, err)
+	}
+	if got := strings.Join(calls, " "); got != "outer>Gamma outer<Gamma" {
+		t.Errorf("Expected the kill switch to stop the chain, got %s", got)
+	}
+}
+
+func TestInterceptorError(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{"Beta": {Enabled: true}})
+	restricted := func(next Evaluator) Evaluator {
+		return func(featureName string, appContext any) (EvaluationResult, error) {
+			if tenant, _ := appContext.(string); tenant == "blocked" {
+				return EvaluationResult{}, fmt.Errorf("tenant %s may not evaluate %s", tenant, featureName)
+			}
+			return next(featureName, appContext)
+		}
+	}
+
+	manager, err := NewFeatureManager(provider, &Options{Interceptors: []Interceptor{restricted}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if _, err := manager.IsEnabledWithAppContext("Beta", "blocked"); err == nil {
+		t.Error("Expected the interceptor error to be returned")
+	}
+	if enabled, err := manager.IsEnabledWithAppContext("Beta", "allowed"); err != nil || !enabled {
+		t.Errorf("Expected Beta to be enabled, got %v, %v", enabled, err)
+	}
+}
+
+func TestEvaluationResultWithEnabled(t *testing.T) {
+	variants := []VariantDefinition{
+		{Name: "On", ConfigurationValue: map[string]any{"color": "green"}},
+		{Name: "Off", ConfigurationValue: "grey"},
+	}
+	allocation := &VariantAllocation{DefaultWhenEnabled: "On", DefaultWhenDisabled: "Off"}
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta":  {Enabled: true, Variants: variants, Allocation: allocation},
+		"Gamma": {Enabled: false, Variants: variants, Allocation: allocation},
+		"Plain": {Enabled: true},
+	})
+	forced := map[string]bool{"Beta": false, "Gamma": true, "Plain": false}
+	forcing := func(next Evaluator) Evaluator {
+		return func(featureName string, appContext any) (EvaluationResult, error) {
+			res, err := next(featureName, appContext)
+			if err != nil {
+				return res, err
+			}
+			return res.WithEnabled(forced[featureName]), nil
+		}
+	}
+
+	manager, err := NewFeatureManager(provider, &Options{Interceptors: []Interceptor{forcing}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	res, err := manager.Evaluate("Beta", nil)
+	if err != nil || res.Enabled || res.Variant == nil || res.Variant.Name != "Off" || res.VariantAssignmentReason != VariantAssignmentReasonDefaultWhenDisabled {
+		t.Errorf("Expected Beta forced off to get the default when disabled variant, got %+v, %v", res, err)
+	}
+
+	res, err = manager.Evaluate("Gamma", nil)
+	if err != nil || !res.Enabled || res.Variant == nil || res.Variant.Name != "On" || res.VariantAssignmentReason != VariantAssignmentReasonDefaultWhenEnabled {
+		t.Errorf("Expected Gamma forced on to get the default when enabled variant, got %+v, %v", res, err)
+	}
+	if res.VariantAssignmentPercentage != 100 {
+		t.Errorf("Expected the default when enabled variant to cover every user, got %v", res.VariantAssignmentPercentage)
+	}
+	// The variant holds its own copy of the configuration value
+	res.Variant.ConfigurationValue.(map[string]any)["color"] = "red"
+	if variants[0].ConfigurationValue.(map[string]any)["color"] != "green" {
+		t.Error("Expected the feature's configuration value to be unaffected")
+	}
+
+	res, err = manager.Evaluate("Plain", nil)
+	if err != nil || res.Enabled || res.Variant != nil || res.VariantAssignmentReason != VariantAssignmentReasonNone {
+		t.Errorf("Expected Plain forced off without a variant, got %+v, %v", res, err)
+	}
+
+	// Forcing the evaluated state leaves the result unchanged
+	res, _ = manager.Evaluate("Beta", nil)
+	if again := res.WithEnabled(false); again.Variant != res.Variant || again.VariantAssignmentReason != res.VariantAssignmentReason {
+		t.Errorf("Expected the result to be unchanged, got %+v", again)
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ClaimsCarrier can be implemented by app contexts holding the claims of a verified JWT, so that
+// the JWT claims filter can evaluate them
+type ClaimsCarrier interface {
+	// GetClaims returns the claims of the token
+	GetClaims() map[string]any
+}
+
+// TokenCarrier can be implemented by app contexts holding a raw JWT. The JWT claims filter only
+// reads the claims of a raw token through JWTClaimsOptions.VerifyToken.
+type TokenCarrier interface {
+	// GetToken returns the raw token, without the "Bearer " prefix
+	GetToken() string
+}
+
+// JWTClaims is an app context holding the claims of a verified JWT
+type JWTClaims map[string]any
+
+// GetClaims returns the claims.
+func (c JWTClaims) GetClaims() map[string]any {
+	return c
+}
+
+// JWTClaimsOptions configures a JWTClaimsFilter
+type JWTClaimsOptions struct {
+	// VerifyToken verifies the signature and lifetime of the raw token of a TokenCarrier app
+	// context and returns its claims, typically with a JWT library and the keys of the identity
+	// provider. When nil, app contexts must carry verified claims.
+	VerifyToken func(token string) (map[string]any, error)
+
+	// RolesClaim is the claim holding the roles of the user. Defaults to "roles".
+	RolesClaim string
+}
+
+// JWTClaimsFilter enables a feature for users whose JWT claims meet the configured requirements,
+// so that features can be gated on attributes of the identity provider. The app context must
+// implement ClaimsCarrier, or TokenCarrier when a VerifyToken function is configured.
+//
+// The filter isn't registered by default; register it with Options.Filters.
+type JWTClaimsFilter struct {
+	verifyToken func(token string) (map[string]any, error)
+	rolesClaim  string
+	paramCache  parameterCache[JWTClaimsFilterParameters]
+}
+
+// JWTClaimsFilterParameters defines the parameters for the JWT claims filter. The feature is
+// enabled when every configured requirement is met; a requirement listing several values is
+// met by any of them.
+type JWTClaimsFilterParameters struct {
+	// Issuers are the accepted values of the "iss" claim
+	Issuers []string
+	// Audiences are the accepted values of the "aud" claim, which may hold several audiences
+	Audiences []string
+	// Roles are the accepted roles, of which the user must have at least one
+	Roles []string
+	// Claims are requirements on other claims
+	Claims []JWTClaimRequirement
+}
+
+// JWTClaimRequirement requires a claim to hold one of the given values
+type JWTClaimRequirement struct {
+	// Name is the name of the claim. Dots select nested claims, as in "realm_access.roles".
+	Name string
+	// Values are the accepted values. A claim holding a list is accepted when any of its
+	// elements is; numbers and booleans are compared by their text.
+	Values []string
+}
+
+// NewJWTClaimsFilter creates a JWT claims filter.
+//
+// Parameters:
+//   - options: How tokens are verified and where roles are read from
+//
+// Returns:
+//   - *JWTClaimsFilter: The JWT claims filter
+func NewJWTClaimsFilter(options JWTClaimsOptions) *JWTClaimsFilter {
+	rolesClaim := options.RolesClaim
+	if rolesClaim == "" {
+		rolesClaim = "roles"
+	}
+
+	return &JWTClaimsFilter{verifyToken: options.VerifyToken, rolesClaim: rolesClaim}
+}
+
+func (j *JWTClaimsFilter) Name() string {
+	return "Microsoft.JwtClaims"
+}
+
+func (j *JWTClaimsFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appContext any) (bool, error) {
+	params, err := j.paramCache.get(evalCtx.FeatureName, evalCtx.Parameters, decodeJWTClaimsParams)
+	if err != nil {
+		return false, err
+	}
+
+	claims, err := j.claimsFrom(appContext)
+	if err != nil {
+		return false, err
+	}
+
+	requirements := []JWTClaimRequirement{
+		{Name: "iss", Values: params.Issuers},
+		{Name: "aud", Values: params.Audiences},
+		{Name: j.rolesClaim, Values: params.Roles},
+	}
+	for _, requirement := range append(requirements, params.Claims...) {
+		if len(requirement.Values) > 0 && !claimMatches(lookupClaim(claims, requirement.Name), requirement.Values) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (j *JWTClaimsFilter) preloadParameters(featureName string, parameters map[string]any) error {
+	_, err := j.paramCache.get(featureName, parameters, decodeJWTClaimsParams)
+	return err
+}
+
+// claimsFrom returns the claims of an app context, verifying its raw token when it carries one
+func (j *JWTClaimsFilter) claimsFrom(appContext any) (map[string]any, error) {
+	if carrier, ok := appContext.(ClaimsCarrier); ok {
+		return carrier.GetClaims(), nil
+	}
+
+	carrier, ok := appContext.(TokenCarrier)
+	if !ok || j.verifyToken == nil {
+		return nil, fmt.Errorf("the app context is required for JWT claims filter and must implement ClaimsCarrier, or TokenCarrier when VerifyToken is set")
+	}
+
+	claims, err := j.verifyToken(carrier.GetToken())
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify the token of the app context: %w", err)
+	}
+
+	return claims, nil
+}
+
+func decodeJWTClaimsParams(featureName string, parameters map[string]any) (JWTClaimsFilterParameters, error) {
+	var params JWTClaimsFilterParameters
+	if err := BindParameters(parameters, &params); err != nil {
+		return JWTClaimsFilterParameters{}, fmt.Errorf("invalid JWT claims parameters format for feature %s: %w", featureName, err)
+	}
+
+	for i, requirement := range params.Claims {
+		if requirement.Name == "" {
+			return JWTClaimsFilterParameters{}, fmt.Errorf("invalid feature flag: %s. Claim requirement at index %d missing name", featureName, i)
+		}
+	}
+
+	return params, nil
+}
+
+// lookupClaim returns the claim with the given name, following dots into nested claims
+func lookupClaim(claims map[string]any, name string) any {
+	if value, ok := claims[name]; ok {
+		return value
+	}
+
+	first, rest, found := strings.Cut(name, ".")
+	if !found {
+		return nil
+	}
+	nested, ok := claims[first].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	return lookupClaim(nested, rest)
+}
+
+// claimMatches reports whether a claim, or any element of a claim holding a list, is one of the
+// accepted values
+func claimMatches(claim any, values []string) bool {
+	switch claim := claim.(type) {
+	case nil:
+		return false
+	case []any:
+		for _, element := range claim {
+			if claimMatches(element, values) {
+				return true
+			}
+		}
+		return false
+	case []string:
+		for _, element := range claim {
+			if claimMatches(element, values) {
+				return true
+			}
+		}
+		return false
+	}
+
+	text := claimText(claim)
+	for _, value := range values {
+		if text == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// claimText formats a claim for comparison with the accepted values. Numbers decoded from JSON
+// are float64, which fmt formats with an exponent from seven digits on, so they are formatted
+// in full: 1234567 rather than 1.234567e+06.
+func claimText(claim any) string {
+	switch claim := claim.(type) {
+	case string:
+		return claim
+	case float64:
+		return strconv.FormatFloat(claim, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(claim), 'f', -1, 32)
+	default:
+		return fmt.Sprint(claim)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type bearerToken string
+
+func (t bearerToken) GetToken() string {
+	return string(t)
+}
+
+func TestJWTClaimsFilter(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Admin": {
+			Enabled: true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{
+				Name: "Microsoft.JwtClaims",
+				Parameters: map[string]any{
+					"Issuers":   []any{"https://login.contoso.com"},
+					"Audiences": []any{"api://checkout"},
+					"Roles":     []any{"Admin", "Support"},
+					"Claims": []any{
+						map[string]any{"Name": "email_verified", "Values": []any{"true"}},
+						map[string]any{"Name": "org.tier", "Values": []any{"gold", "platinum"}},
+						map[string]any{"Name": "tenant", "Values": []any{"1234567", "10000000"}},
+					},
+				},
+			}}},
+		},
+	})
+	manager, err := NewFeatureManager(provider, &Options{
+		Filters: []FeatureFilter{NewJWTClaimsFilter(JWTClaimsOptions{})},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	claims := func(overrides map[string]any) JWTClaims {
+		c := JWTClaims{
+			"iss":            "https://login.contoso.com",
+			"aud":            []any{"api://checkout", "api://profile"},
+			"roles":          []any{"Reader", "Support"},
+			"email_verified": true,
+			"org":            map[string]any{"tier": "gold"},
+			"tenant":         float64(1234567),
+		}
+		for name, value := range overrides {
+			c[name] = value
+		}
+		return c
+	}
+
+	for _, tc := range []struct {
+		name       string
+		appContext any
+		expected   bool
+	}{
+		{"all requirements met", claims(nil), true},
+		{"single audience", claims(map[string]any{"aud": "api://checkout"}), true},
+		{"other issuer", claims(map[string]any{"iss": "https://login.fabrikam.com"}), false},
+		{"other audience", claims(map[string]any{"aud": "api://profile"}), false},
+		{"no accepted role", claims(map[string]any{"roles": []any{"Reader"}}), false},
+		{"unverified email", claims(map[string]any{"email_verified": false}), false},
+		{"other tier", claims(map[string]any{"org": map[string]any{"tier": "silver"}}), false},
+		{"missing claim", claims(map[string]any{"org": nil}), false},
+		{"eight digit tenant", claims(map[string]any{"tenant": float64(10000000)}), true},
+		{"tenant as a JSON number", claims(map[string]any{"tenant": json.Number("10000000")}), true},
+		{"other tenant", claims(map[string]any{"tenant": float64(7654321)}), false},
+	} {
+		if enabled, err := manager.IsEnabledWithAppContext("Admin", tc.appContext); err != nil || enabled != tc.expected {
+			t.Errorf("%s: expected enabled=%v, got %v, %v", tc.name, tc.expected, enabled, err)
+		}
+	}
+
+	if _, err := manager.IsEnabledWithAppContext("Admin", bearerToken("token")); err == nil {
+		t.Error("Expected an error for a raw token without VerifyToken")
+	}
+}
+
+func TestJWTClaimsFilterVerifyToken(t *testing.T) {
+	filter := NewJWTClaimsFilter(JWTClaimsOptions{
+		VerifyToken: func(token string) (map[string]any, error) {
+			if token != "valid" {
+				return nil, errors.New("invalid signature")
+			}
+			return map[string]any{"groups": []any{"Beta"}}, nil
+		},
+		RolesClaim: "groups",
+	})
+	evalCtx := FeatureFilterEvaluationContext{
+		FeatureName: "Beta",
+		Parameters:  map[string]any{"Roles": []any{"Beta"}},
+	}
+
+	if enabled, err := filter.Evaluate(evalCtx, bearerToken("valid")); err != nil || !enabled {
+		t.Errorf("Expected a verified token with the role to enable the feature, got %v, %v", enabled, err)
+	}
+	if _, err := filter.Evaluate(evalCtx, bearerToken("forged")); err == nil {
+		t.Error("Expected an error for a token failing verification")
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FeatureLifecycleStatus classifies a feature flag by the evaluation results observed since tracking started
+type FeatureLifecycleStatus string
+
+const (
+	// FeatureLifecycleStatusNeverEvaluated indicates the feature has not been evaluated
+	FeatureLifecycleStatusNeverEvaluated FeatureLifecycleStatus = "NeverEvaluated"
+	// FeatureLifecycleStatusAlwaysEnabled indicates every evaluation of the feature returned enabled
+	FeatureLifecycleStatusAlwaysEnabled FeatureLifecycleStatus = "AlwaysEnabled"
+	// FeatureLifecycleStatusAlwaysDisabled indicates every evaluation of the feature returned disabled
+	FeatureLifecycleStatusAlwaysDisabled FeatureLifecycleStatus = "AlwaysDisabled"
+	// FeatureLifecycleStatusActive indicates the feature has been evaluated as both enabled and disabled
+	FeatureLifecycleStatusActive FeatureLifecycleStatus = "Active"
+)
+
+// FeatureLifecycle summarizes the evaluations of a single feature flag
+type FeatureLifecycle struct {
+	// FeatureName is the name of the feature
+	FeatureName string
+	// Status classifies the feature based on its evaluation results
+	Status FeatureLifecycleStatus
+	// LastEvaluated is the time of the most recent evaluation, or the zero time if never evaluated
+	LastEvaluated time.Time
+	// EnabledCount is the number of evaluations that returned enabled
+	EnabledCount uint64
+	// DisabledCount is the number of evaluations that returned disabled
+	DisabledCount uint64
+	// Warnings describes problems with the feature's definition, such as an expired time window
+	Warnings []string
+}
+
+// LifecycleReport describes how the feature flags known to a FeatureManager have been used.
+// It supports flag cleanup by identifying flags that are permanently on, permanently off or unused.
+type LifecycleReport struct {
+	// TrackingSince is the time the feature manager started recording evaluations
+	TrackingSince time.Time
+	// GeneratedAt is the time the report was created
+	GeneratedAt time.Time
+	// Features contains one entry per feature, sorted by name
+	Features []FeatureLifecycle
+}
+
+// Stale returns the features whose status is AlwaysEnabled, AlwaysDisabled or NeverEvaluated.
+func (r LifecycleReport) Stale() []FeatureLifecycle {
+	var stale []FeatureLifecycle
+	for _, feature := range r.Features {
+		if feature.Status != FeatureLifecycleStatusActive {
+			stale = append(stale, feature)
+		}
+	}
+
+	return stale
+}
+
+// GetLifecycleReport returns the evaluation statistics of every feature defined by the provider,
+// as well as features that were evaluated but are no longer defined. The statistics of features
+// no longer defined are discarded once the feature manager tracks more than 1000 features, so
+// that the tracked features stay bounded by the features the provider defines.
+//
+// Returns:
+//   - LifecycleReport: The evaluation statistics recorded since the feature manager was created
+func (fm *FeatureManager) GetLifecycleReport() LifecycleReport {
+	report := LifecycleReport{
+		TrackingSince: fm.tracker.since,
+		GeneratedAt:   time.Now(),
+	}
+
+	seen := make(map[string]bool)
+	for flag := range fm.flags() {
+		if !seen[flag.ID] {
+			seen[flag.ID] = true
+			lifecycle := fm.tracker.lifecycle(flag.ID)
+			for _, warning := range expiredTimeWindows(flag, report.GeneratedAt) {
+				lifecycle.Warnings = append(lifecycle.Warnings, warning.Message)
+			}
+			report.Features = append(report.Features, lifecycle)
+		}
+	}
+
+	fm.tracker.stats.Range(func(key, _ any) bool {
+		if name := key.(string); !seen[name] {
+			seen[name] = true
+			report.Features = append(report.Features, fm.tracker.lifecycle(name))
+		}
+		return true
+	})
+
+	sort.Slice(report.Features, func(i, j int) bool {
+		return report.Features[i].FeatureName < report.Features[j].FeatureName
+	})
+
+	return report
+}
+
+// maxMissingFeatures caps the number of undefined features whose evaluations are tracked, so that
+// evaluating arbitrary names, such as names taken from requests, can't grow the tracker without bound
+const maxMissingFeatures = 1000
+
+// minPruneThreshold is the number of tracked features from which the statistics of the features
+// the provider no longer defines are discarded
+const minPruneThreshold = 1000
+
+// definedFeatures returns the names of the features the provider defines and of the overridden
+// features, which can all be evaluated
+func (fm *FeatureManager) definedFeatures() (map[string]bool, error) {
+	flags := fm.providerFlags()
+	if _, ok := fm.featureProvider.(FeatureFlagIterator); !ok {
+		list, err := fm.getFeatureFlags()
+		if err != nil {
+			return nil, err
+		}
+		flags = slices.Values(list)
+	}
+
+	defined := make(map[string]bool, len(fm.overrides))
+	for name := range fm.overrides {
+		defined[name] = true
+	}
+	for flag := range flags {
+		defined[flag.ID] = true
+	}
+
+	return defined, nil
+}
+
+// evaluationTracker records per-feature evaluation results without locking on the evaluation path
+type evaluationTracker struct {
+	since time.Time
+	// definedFeatures returns the names of the features that can currently be evaluated
+	definedFeatures func() (map[string]bool, error)
+
+	stats      sync.Map // feature name -> *evaluationStats
+	statsCount atomic.Int64
+	// pruneThreshold is the number of tracked features from which the features no longer
+	// defined are pruned; it doubles with the features remaining, so pruning stays amortized
+	pruneThreshold atomic.Int64
+	pruneMu        sync.Mutex
+
+	missing      sync.Map // feature name -> *evaluationStats of evaluations that didn't find the feature
+	missingCount atomic.Int64
+	// untracked counts the evaluations of undefined features left out once maxMissingFeatures
+	// features are tracked
+	untracked atomic.Uint64
+}
+
+type evaluationStats struct {
+	enabled        atomic.Uint64
+	disabled       atomic.Uint64
+	firstEvaluated atomic.Int64 // Unix nanoseconds
+	lastEvaluated  atomic.Int64 // Unix nanoseconds
+}
+
+func newEvaluationTracker(definedFeatures func() (map[string]bool, error)) *evaluationTracker {
+	tracker := &evaluationTracker{since: time.Now(), definedFeatures: definedFeatures}
+	tracker.pruneThreshold.Store(minPruneThreshold)
+	return tracker
+}
+
+func (t *evaluationTracker) record(featureName string, enabled bool) {
+	value, ok := t.stats.Load(featureName)
+	if !ok {
+		var loaded bool
+		value, loaded = t.stats.LoadOrStore(featureName, &evaluationStats{})
+		if !loaded && t.statsCount.Add(1) >= t.pruneThreshold.Load() {
+			t.prune()
+		}
+	}
+
+	stats := value.(*evaluationStats)
+	if enabled {
+		stats.enabled.Add(1)
+	} else {
+		stats.disabled.Add(1)
+	}
+	stats.touch()
+}
+
+// prune discards the statistics of the features the provider no longer defines
+func (t *evaluationTracker) prune() {
+	t.pruneMu.Lock()
+	defer t.pruneMu.Unlock()
+
+	// Another evaluation may have pruned the statistics while this one waited
+	if t.statsCount.Load() < t.pruneThreshold.Load() {
+		return
+	}
+
+	// Statistics are kept when the provider fails to enumerate its features, rather than discarded
+	if defined, err := t.definedFeatures(); err == nil {
+		t.stats.Range(func(key, _ any) bool {
+			if !defined[key.(string)] {
+				if _, loaded := t.stats.LoadAndDelete(key); loaded {
+					t.statsCount.Add(-1)
+				}
+			}
+			return true
+		})
+	}
+	t.pruneThreshold.Store(max(2*t.statsCount.Load(), minPruneThreshold))
+}
+
+// recordMissing records an evaluation of a feature the provider doesn't define, unless
+// maxMissingFeatures other undefined features are already tracked
+func (t *evaluationTracker) recordMissing(featureName string) {
+	value, ok := t.missing.Load(featureName)
+	if !ok {
+		if t.missingCount.Add(1) > maxMissingFeatures {
+			t.missingCount.Add(-1)
+			t.untracked.Add(1)
+			return
+		}
+
+		var loaded bool
+		value, loaded = t.missing.LoadOrStore(featureName, &evaluationStats{})
+		if loaded {
+			t.missingCount.Add(-1)
+		}
+	}
+
+	stats := value.(*evaluationStats)
+	stats.disabled.Add(1)
+	stats.touch()
+}
+
+// touch updates the first and last evaluation times
+func (s *evaluationStats) touch() {
+	now := time.Now().UnixNano()
+	s.firstEvaluated.CompareAndSwap(0, now)
+	s.lastEvaluated.Store(now)
+}
+
+func (t *evaluationTracker) lifecycle(featureName string) FeatureLifecycle {
+	lifecycle := FeatureLifecycle{
+		FeatureName: featureName,
+		Status:      FeatureLifecycleStatusNeverEvaluated,
+	}
+
+	value, ok := t.stats.Load(featureName)
+	if !ok {
+		return lifecycle
+	}
+
+	stats := value.(*evaluationStats)
+	lifecycle.EnabledCount = stats.enabled.Load()
+	lifecycle.DisabledCount = stats.disabled.Load()
+	if last := stats.lastEvaluated.Load(); last != 0 {
+		lifecycle.LastEvaluated = time.Unix(0, last)
+	}
+
+	switch {
+	case lifecycle.EnabledCount > 0 && lifecycle.DisabledCount > 0:
+		lifecycle.Status = FeatureLifecycleStatusActive
+	case lifecycle.EnabledCount > 0:
+		lifecycle.Status = FeatureLifecycleStatusAlwaysEnabled
+	case lifecycle.DisabledCount > 0:
+		lifecycle.Status = FeatureLifecycleStatusAlwaysDisabled
+	}
+
+	return lifecycle
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestGetLifecycleReport(t *testing.T) {
+	provider := &mockFeatureFlagProvider{
+		featureFlags: []FeatureFlag{
+			{ID: "AlwaysOn", Enabled: true},
+			{ID: "AlwaysOff", Enabled: false},
+			{ID: "Unused", Enabled: true},
+			{
+				ID:      "Mixed",
+				Enabled: true,
+				Conditions: &Conditions{
+					ClientFilters: []ClientFilter{
+						{
+							Name: "Microsoft.Targeting",
+							Parameters: map[string]any{
+								"Audience": map[string]any{
+									"Users": []any{"Alice"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	manager, err := NewFeatureManager(provider, &Options{
+		Overrides: map[string]bool{"Removed": true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := manager.IsEnabled("AlwaysOn"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := manager.IsEnabled("AlwaysOff"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, user := range []string{"Alice", "Bob"} {
+		if _, err := manager.IsEnabledWithAppContext("Mixed", TargetingContext{UserID: user}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := manager.IsEnabled("Removed"); err != nil {
+		t.Fatal(err)
+	}
+
+	report := manager.GetLifecycleReport()
+	if report.TrackingSince.IsZero() || report.GeneratedAt.Before(report.TrackingSince) {
+		t.Errorf("Unexpected report times: %v, %v", report.TrackingSince, report.GeneratedAt)
+	}
+
+	expected := []struct {
+		name     string
+		status   FeatureLifecycleStatus
+		enabled  uint64
+		disabled uint64
+	}{
+		{"AlwaysOff", FeatureLifecycleStatusAlwaysDisabled, 0, 3},
+		{"AlwaysOn", FeatureLifecycleStatusAlwaysEnabled, 3, 0},
+		{"Mixed", FeatureLifecycleStatusActive, 1, 1},
+		{"Removed", FeatureLifecycleStatusAlwaysEnabled, 1, 0},
+		{"Unused", FeatureLifecycleStatusNeverEvaluated, 0, 0},
+	}
+
+	if len(report.Features) != len(expected) {
+		t.Fatalf("Expected %d features, got %d", len(expected), len(report.Features))
+	}
+	for i, e := range expected {
+		got := report.Features[i]
+		if got.FeatureName != e.name || got.Status != e.status || got.EnabledCount != e.enabled || got.DisabledCount != e.disabled {
+			t.Errorf("Unexpected lifecycle at index %d: %+v", i, got)
+		}
+		if (got.Status == FeatureLifecycleStatusNeverEvaluated) != got.LastEvaluated.IsZero() {
+			t.Errorf("Unexpected last evaluated time for %s: %v", got.FeatureName, got.LastEvaluated)
+		}
+	}
+
+	if stale := report.Stale(); len(stale) != 4 {
+		t.Errorf("Expected 4 stale features, got %d", len(stale))
+	}
+}
+
+func TestEvaluationTrackingIsBounded(t *testing.T) {
+	definitions := func(prefix string) []FeatureFlag {
+		flags := make([]FeatureFlag, minPruneThreshold)
+		for i := range flags {
+			flags[i] = FeatureFlag{ID: fmt.Sprintf("%s%d", prefix, i), Enabled: true}
+		}
+		return flags
+	}
+	provider := &SnapshotProvider{}
+	provider.StoreSnapshot(NewFeatureFlagSnapshot(definitions("Old"), nil))
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	evaluateAll := func() {
+		for flag := range provider.All() {
+			if _, err := manager.IsEnabled(flag.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	evaluateAll()
+	provider.StoreSnapshot(NewFeatureFlagSnapshot(definitions("New"), nil))
+	evaluateAll()
+
+	report := manager.GetLifecycleReport()
+	if len(report.Features) != minPruneThreshold {
+		t.Errorf("Expected the statistics of the removed features to be discarded, got %d features", len(report.Features))
+	}
+	for _, feature := range report.Features {
+		if feature.Status != FeatureLifecycleStatusAlwaysEnabled {
+			t.Fatalf("Expected the defined features to keep their statistics, got %+v", feature)
+		}
+	}
+
+	for i := 0; i < maxMissingFeatures+5; i++ {
+		_, _ = manager.IsEnabled(fmt.Sprintf("Missing%d", i))
+	}
+	_, _ = manager.IsEnabled("Missing0")
+	if count, untracked := manager.tracker.missingCount.Load(), manager.tracker.untracked.Load(); count != maxMissingFeatures || untracked != 5 {
+		t.Errorf("Expected %d undefined features to be tracked and 5 evaluations left out, got %d and %d", maxMissingFeatures, count, untracked)
+	}
+	if value, ok := manager.tracker.missing.Load("Missing0"); !ok || value.(*evaluationStats).disabled.Load() != 2 {
+		t.Error("Expected the tracked undefined features to keep counting their evaluations")
+	}
+}
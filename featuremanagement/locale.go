@@ -0,0 +1,212 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// CountryAttribute is the targeting context attribute read by the locale filter for the
+	// ISO 3166-1 alpha-2 country code of the user, for example "US"
+	CountryAttribute = "country"
+
+	// LocaleAttribute is the targeting context attribute read by the locale filter for the
+	// locales of the user. It holds a single language tag such as "fr-CA" or the value of an
+	// Accept-Language header.
+	LocaleAttribute = "locale"
+)
+
+// LocaleFilter enables a feature for users in the configured countries or locales, for launches
+// limited to some markets. It is registered by default under the name "Microsoft.Locale".
+//
+// The country and locales of the user are taken from a LocaleContext app context, or from the
+// CountryAttribute and LocaleAttribute attributes of a targeting context. When no country is
+// given, it is derived from the region of the preferred locale, so that "en-GB" counts as "GB".
+type LocaleFilter struct {
+	paramCache parameterCache[LocaleFilterParameters]
+}
+
+// LocaleFilterParameters defines the parameters for the locale filter. The feature is enabled
+// when the country or any locale of the user matches; all comparisons ignore case.
+type LocaleFilterParameters struct {
+	// Countries are ISO 3166-1 alpha-2 country codes, for example "US" or "DE"
+	Countries []string
+	// Locales are language tags. A tag with a region such as "fr-CA" matches that locale only,
+	// while a language such as "fr" matches it in every region.
+	Locales []string
+}
+
+// LocaleContext provides the country and locales of a user to the locale filter.
+type LocaleContext struct {
+	// Country is the ISO 3166-1 alpha-2 country code of the user, for example from a GeoIP lookup
+	Country string
+	// Locales are the language tags of the user, most preferred first
+	Locales []string
+}
+
+func (l *LocaleFilter) Name() string {
+	return "Microsoft.Locale"
+}
+
+func (l *LocaleFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appContext any) (bool, error) {
+	params, err := l.paramCache.get(evalCtx.FeatureName, evalCtx.Parameters, decodeLocaleParams)
+	if err != nil {
+		return false, err
+	}
+
+	localeCtx, ok := localeContextFrom(appContext)
+	if !ok {
+		return false, fmt.Errorf("the app context is required for locale filter and must be a LocaleContext or a targeting context")
+	}
+
+	if country := localeCtx.country(); country != "" {
+		for _, target := range params.Countries {
+			if strings.EqualFold(country, target) {
+				return true, nil
+			}
+		}
+	}
+
+	for _, locale := range localeCtx.Locales {
+		for _, target := range params.Locales {
+			if matchLocale(locale, target) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func (l *LocaleFilter) preloadParameters(featureName string, parameters map[string]any) error {
+	_, err := l.paramCache.get(featureName, parameters, decodeLocaleParams)
+	return err
+}
+
+func decodeLocaleParams(featureName string, parameters map[string]any) (LocaleFilterParameters, error) {
+	var params LocaleFilterParameters
+	if err := BindParameters(parameters, &params); err != nil {
+		return LocaleFilterParameters{}, fmt.Errorf("invalid locale parameters format for feature %s: %w", featureName, err)
+	}
+
+	for i, locale := range params.Locales {
+		params.Locales[i] = normalizeLocale(locale)
+	}
+
+	return params, nil
+}
+
+// country returns the country of the context, or the region of its preferred locale
+func (c LocaleContext) country() string {
+	if c.Country != "" {
+		return c.Country
+	}
+	for _, locale := range c.Locales {
+		// The region is the first two-letter subtag after the language, as in "zh-Hant-TW"
+		subtags := strings.Split(normalizeLocale(locale), "-")
+		for _, subtag := range subtags[1:] {
+			if len(subtag) == 2 {
+				return subtag
+			}
+		}
+	}
+
+	return ""
+}
+
+// localeContextFrom returns the locale context of an app context that is a LocaleContext, a non-nil
+// *LocaleContext or a targeting context
+func localeContextFrom(appContext any) (LocaleContext, bool) {
+	switch lc := appContext.(type) {
+	case LocaleContext:
+		return lc, true
+	case *LocaleContext:
+		if lc == nil {
+			return LocaleContext{}, false
+		}
+		return *lc, true
+	}
+
+	tc, ok := targetingContextFrom(appContext)
+	if !ok {
+		return LocaleContext{}, false
+	}
+
+	return LocaleContext{
+		Country: tc.Attributes[CountryAttribute],
+		Locales: ParseAcceptLanguage(tc.Attributes[LocaleAttribute]),
+	}, true
+}
+
+// matchLocale reports whether a locale of the user matches a normalized locale of the filter
+func matchLocale(locale, target string) bool {
+	locale = normalizeLocale(locale)
+	if locale == target {
+		return true
+	}
+
+	// A bare language matches the language in any region or script
+	return !strings.Contains(target, "-") && strings.HasPrefix(locale, target+"-")
+}
+
+// normalizeLocale lowercases a language tag and uses '-' as its separator, so that "en_US" and
+// "EN-us" both become "en-us"
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// ParseAcceptLanguage parses the value of an Accept-Language header into its language tags,
+// most preferred first. Tags with a quality of zero and the "*" wildcard are dropped.
+//
+// Parameters:
+//   - header: The header value, for example "fr-CH, fr;q=0.9, en;q=0.8"
+//
+// Returns:
+//   - []string: The language tags ordered by quality, or nil if there are none
+func ParseAcceptLanguage(header string) []string {
+	type weightedLocale struct {
+		tag     string
+		quality float64
+	}
+
+	var weighted []weightedLocale
+	for _, part := range strings.Split(header, ",") {
+		tag, rawParams, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range strings.Split(rawParams, ";") {
+			name, value, found := strings.Cut(strings.TrimSpace(param), "=")
+			if !found || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				quality = q
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		weighted = append(weighted, weightedLocale{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(weighted, func(i, j int) bool {
+		return weighted[i].quality > weighted[j].quality
+	})
+
+	var tags []string
+	for _, locale := range weighted {
+		tags = append(tags, locale.tag)
+	}
+
+	return tags
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	for _, tc := range []struct {
+		header   string
+		expected []string
+	}{
+		{"", nil},
+		{"en-US", []string{"en-US"}},
+		{"fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5", []string{"fr-CH", "fr", "en", "de"}},
+		{"en;q=0.5, de, it;q=0", []string{"de", "en"}},
+	} {
+		if actual := ParseAcceptLanguage(tc.header); !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("Expected %q to parse to %v, got %v", tc.header, tc.expected, actual)
+		}
+	}
+}
+
+func TestLocaleFilter(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Launch": {
+			Enabled: true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{
+				Name: "Microsoft.Locale",
+				Parameters: map[string]any{
+					"Countries": []any{"DE", "at"},
+					"Locales":   []any{"fr", "en-GB"},
+				},
+			}}},
+		},
+	})
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	for _, tc := range []struct {
+		appContext any
+		expected   bool
+	}{
+		{LocaleContext{Country: "de"}, true},
+		{&LocaleContext{Country: "US", Locales: []string{"en-US"}}, false},
+		{LocaleContext{Locales: []string{"en_GB"}}, true},
+		{LocaleContext{Locales: []string{"de-AT"}}, true},
+		{LocaleContext{Locales: []string{"fr-CA"}}, true},
+		{LocaleContext{Country: "US", Locales: []string{"es-US", "fr-FR"}}, true},
+		{TargetingContext{Attributes: map[string]string{CountryAttribute: "AT"}}, true},
+		{TargetingContext{Attributes: map[string]string{LocaleAttribute: "en-US, en-GB;q=0.5"}}, true},
+		{TargetingContext{Attributes: map[string]string{LocaleAttribute: "it-IT"}}, false},
+		{TargetingContext{}, false},
+	} {
+		if enabled, err := manager.IsEnabledWithAppContext("Launch", tc.appContext); err != nil || enabled != tc.expected {
+			t.Errorf("Expected Launch enabled=%v for %+v, got %v, %v", tc.expected, tc.appContext, enabled, err)
+		}
+	}
+
+	if _, err := manager.IsEnabledWithAppContext("Launch", "en-US"); err == nil {
+		t.Error("Expected an error for an app context without locale information")
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+)
+
+// LogLevel is the minimum severity of the messages logged by the library.
+// The zero value is LogLevelWarn.
+type LogLevel int
+
+const (
+	// LogLevelDebug logs every message, including the result of each evaluation
+	LogLevelDebug LogLevel = iota - 2
+	// LogLevelInfo logs informational messages, such as overridden feature flags and rollout progress
+	LogLevelInfo
+	// LogLevelWarn logs configuration problems that don't fail evaluations, such as an expired
+	// time window or an allocation to a variant that isn't defined. It is the default.
+	LogLevelWarn
+	// LogLevelError logs only failures, such as a missing feature filter or a provider error
+	LogLevelError
+	// LogLevelSilent logs nothing
+	LogLevelSilent
+)
+
+// String returns the name of the level.
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	case LogLevelSilent:
+		return "SILENT"
+	default:
+		return fmt.Sprintf("LogLevel(%d)", int(l))
+	}
+}
+
+// slogLevel maps the level to the equivalent slog level
+func (l LogLevel) slogLevel() slog.Level {
+	switch l {
+	case LogLevelDebug:
+		return slog.LevelDebug
+	case LogLevelInfo:
+		return slog.LevelInfo
+	case LogLevelWarn:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// logger writes the messages at or above a level to a slog.Logger, or to the standard logger.
+// A nil logger logs warnings and errors to the standard logger.
+type logger struct {
+	level LogLevel
+	slog  *slog.Logger
+}
+
+func newLogger(level LogLevel, slogger *slog.Logger) *logger {
+	return &logger{level: level, slog: slogger}
+}
+
+// enabled reports whether messages at the given level are logged
+func (l *logger) enabled(level LogLevel) bool {
+	if level >= LogLevelSilent {
+		return false
+	}
+	if l == nil {
+		return level >= LogLevelWarn
+	}
+
+	return level >= l.level
+}
+
+func (l *logger) logf(level LogLevel, format string, args ...any) {
+	if !l.enabled(level) {
+		return
+	}
+
+	message := fmt.Sprintf(format, args...)
+	if l != nil && l.slog != nil {
+		l.slog.Log(context.Background(), level.slogLevel(), message)
+		return
+	}
+
+	log.Print(message)
+}
+
+func (l *logger) debugf(format string, args ...any) {
+	l.logf(LogLevelDebug, format, args...)
+}
+
+func (l *logger) infof(format string, args ...any) {
+	l.logf(LogLevelInfo, format, args...)
+}
+
+func (l *logger) warnf(format string, args ...any) {
+	l.logf(LogLevelWarn, format, args...)
+}
+
+func (l *logger) errorf(format string, args ...any) {
+	l.logf(LogLevelError, format, args...)
+}
+
+// ProviderLogger logs the messages of a feature flag provider, such as rejected feature flags and
+// failed refreshes, the way the feature manager logs its own: at or above a level, to a
+// slog.Logger or to the standard logger. Providers create one from a LogLevel and a *slog.Logger
+// of their options, so that applications can silence them or route them to structured logging.
+// A nil *ProviderLogger logs warnings and errors to the standard logger.
+type ProviderLogger struct {
+	logger *logger
+}
+
+// NewProviderLogger creates a logger for the messages of a provider.
+//
+// Parameters:
+//   - level: The minimum severity of the messages logged; LogLevelSilent logs nothing
+//   - slogger: The logger receiving the messages at their severity, or nil for the standard logger
+//
+// Returns:
+//   - *ProviderLogger: The logger
+func NewProviderLogger(level LogLevel, slogger *slog.Logger) *ProviderLogger {
+	return &ProviderLogger{logger: newLogger(level, slogger)}
+}
+
+// Debugf logs a debug message, formatted as by fmt.Sprintf.
+func (l *ProviderLogger) Debugf(format string, args ...any) {
+	l.target().debugf(format, args...)
+}
+
+// Infof logs an informational message, formatted as by fmt.Sprintf.
+func (l *ProviderLogger) Infof(format string, args ...any) {
+	l.target().infof(format, args...)
+}
+
+// Warnf logs a warning, formatted as by fmt.Sprintf.
+func (l *ProviderLogger) Warnf(format string, args ...any) {
+	l.target().warnf(format, args...)
+}
+
+// Errorf logs an error, formatted as by fmt.Sprintf.
+func (l *ProviderLogger) Errorf(format string, args ...any) {
+	l.target().errorf(format, args...)
+}
+
+// target returns the logger writing the messages, nil for the default one
+func (l *ProviderLogger) target() *logger {
+	if l == nil {
+		return nil
+	}
+
+	return l.logger
+}
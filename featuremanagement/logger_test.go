@@ -0,0 +1,108 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func loggingTestProvider() *StaticProvider {
+	return NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {
+			Enabled:    true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{Name: "Missing"}}},
+		},
+	})
+}
+
+func TestLogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	manager, err := NewFeatureManager(loggingTestProvider(), &Options{
+		Overrides: map[string]bool{"Gamma": true},
+		Logger:    logger,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	_, _ = manager.IsEnabled("Beta")
+
+	output := buf.String()
+	if strings.Contains(output, "overridden") {
+		t.Errorf("Expected informational messages to be hidden by default, got %q", output)
+	}
+	if !strings.Contains(output, "level=ERROR") || !strings.Contains(output, "feature filter Missing is not found") {
+		t.Errorf("Expected the missing filter to be logged as an error, got %q", output)
+	}
+
+	buf.Reset()
+	manager, err = NewFeatureManager(loggingTestProvider(), &Options{
+		Overrides: map[string]bool{"Gamma": true},
+		Logger:    logger,
+		LogLevel:  LogLevelDebug,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	_, _ = manager.IsEnabled("Gamma")
+
+	output = buf.String()
+	if !strings.Contains(output, "level=INFO msg=\"Feature flag Gamma is overridden to enabled=true\"") {
+		t.Errorf("Expected the override to be logged, got %q", output)
+	}
+	if !strings.Contains(output, "level=DEBUG msg=\"Feature flag Gamma evaluated to enabled=true\"") {
+		t.Errorf("Expected the evaluation to be logged, got %q", output)
+	}
+}
+
+func TestSilentLogLevel(t *testing.T) {
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	manager, err := NewFeatureManager(loggingTestProvider(), &Options{LogLevel: LogLevelSilent})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	_, _ = manager.IsEnabled("Beta")
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing to be logged, got %q", buf.String())
+	}
+}
+
+func TestProviderLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewProviderLogger(LogLevelInfo, slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	logger.Debugf("Polling %s", "flags.json")
+	logger.Warnf("Ignoring invalid feature flag %s", "Beta")
+
+	output := buf.String()
+	if strings.Contains(output, "Polling") {
+		t.Errorf("Expected debug messages to be hidden at LogLevelInfo, got %q", output)
+	}
+	if !strings.Contains(output, "level=WARN msg=\"Ignoring invalid feature flag Beta\"") {
+		t.Errorf("Expected the warning to be logged, got %q", output)
+	}
+
+	buf.Reset()
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+	NewProviderLogger(LogLevelSilent, nil).Errorf("Error refreshing feature flags")
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing to be logged when silent, got %q", buf.String())
+	}
+
+	var defaultLogger *ProviderLogger
+	defaultLogger.Infof("Loaded feature flags")
+	defaultLogger.Errorf("Error refreshing feature flags")
+	if output := buf.String(); strings.Contains(output, "Loaded") || !strings.Contains(output, "Error refreshing feature flags") {
+		t.Errorf("Expected a nil logger to log errors to the standard logger, got %q", output)
+	}
+}
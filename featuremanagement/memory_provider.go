@@ -0,0 +1,106 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// MemoryProvider is a WritableFeatureFlagProvider holding its feature flags in memory. Unlike
+// StaticProvider, its feature flags can be changed at runtime with SetFeatureFlag, for example by
+// a RolloutController, and the feature manager reports the changes to OnFeatureChanged callbacks.
+//
+// Each change swaps in a new set of feature flags rather than modifying the current one, so
+// evaluations never observe a partially applied change.
+type MemoryProvider struct {
+	// mu serializes writes; reads load the current flags without locking
+	mu        sync.Mutex
+	flags     atomic.Pointer[StaticProvider]
+	listeners RefreshListeners
+}
+
+// NewMemoryProvider creates a writable provider serving copies of the given feature flags, keyed
+// by feature name. A flag with an empty ID takes the ID of its key.
+//
+// Example:
+//
+//	provider := featuremanagement.NewMemoryProvider(map[string]featuremanagement.FeatureFlag{
+//		"Beta": {Enabled: true},
+//	})
+//	controller, _ := featuremanagement.NewRolloutController(provider, "Beta", options)
+//
+// Parameters:
+//   - featureFlags: The initial feature flag definitions keyed by feature name
+//
+// Returns:
+//   - *MemoryProvider: A provider serving the feature flags in order of their IDs
+func NewMemoryProvider(featureFlags map[string]FeatureFlag) *MemoryProvider {
+	provider := &MemoryProvider{}
+	provider.flags.Store(NewStaticProvider(featureFlags))
+	return provider
+}
+
+// GetFeatureFlag returns a copy of the current definition of a feature flag.
+//
+// Parameters:
+//   - name: The ID of the feature flag
+//
+// Returns:
+//   - FeatureFlag: The feature flag definition
+//   - error: An error if no feature flag has the ID
+func (p *MemoryProvider) GetFeatureFlag(name string) (FeatureFlag, error) {
+	return p.flags.Load().GetFeatureFlag(name)
+}
+
+// GetFeatureFlags returns copies of the current feature flags, in order of their IDs.
+//
+// Returns:
+//   - []FeatureFlag: The feature flags
+//   - error: Always nil
+func (p *MemoryProvider) GetFeatureFlags() ([]FeatureFlag, error) {
+	return p.flags.Load().GetFeatureFlags()
+}
+
+// All returns an iterator over copies of the current feature flags. A change after All is called
+// doesn't affect the flags yielded by the returned iterator.
+func (p *MemoryProvider) All() iter.Seq[FeatureFlag] {
+	return p.flags.Load().All()
+}
+
+// SetFeatureFlag creates or replaces the feature flag with the same ID, then notifies the
+// callbacks registered with OnRefresh. The provider stores a copy of the flag, so later changes
+// to the flag given don't affect it.
+//
+// Parameters:
+//   - flag: The feature flag definition to store
+//
+// Returns:
+//   - error: An error if the feature flag has no ID
+func (p *MemoryProvider) SetFeatureFlag(flag FeatureFlag) error {
+	if flag.ID == "" {
+		return fmt.Errorf("feature flag ID cannot be empty")
+	}
+
+	p.mu.Lock()
+	current := p.flags.Load()
+	featureFlags := make(map[string]FeatureFlag, len(current.featureFlags)+1)
+	for _, existing := range current.featureFlags {
+		featureFlags[existing.ID] = existing
+	}
+	featureFlags[flag.ID] = flag
+	p.flags.Store(NewStaticProvider(featureFlags))
+	p.mu.Unlock()
+
+	p.listeners.Notify()
+	return nil
+}
+
+// OnRefresh registers a callback called after each SetFeatureFlag, so that the feature manager
+// can report changed feature flags to OnFeatureChanged callbacks.
+func (p *MemoryProvider) OnRefresh(callback func()) func() {
+	return p.listeners.Add(callback)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMemoryProvider(t *testing.T) {
+	provider := NewMemoryProvider(map[string]FeatureFlag{
+		"Beta":   {Enabled: false},
+		"Legacy": {Enabled: true},
+	})
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	var changes []FeatureChange
+	manager.OnFeatureChanged("Beta", func(change FeatureChange) {
+		changes = append(changes, change)
+	})
+
+	flag := FeatureFlag{ID: "Beta", Enabled: true}
+	if err := provider.SetFeatureFlag(flag); err != nil {
+		t.Fatalf("Failed to set feature flag: %v", err)
+	}
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || !enabled {
+		t.Errorf("Expected Beta to be enabled, got %v, %v", enabled, err)
+	}
+	if len(changes) != 1 || changes[0].Previous.Enabled || !changes[0].Current.Enabled {
+		t.Errorf("Expected Beta to change to enabled, got %+v", changes)
+	}
+
+	// The provider stores a copy of the flag given
+	flag.Enabled = false
+	if stored, _ := provider.GetFeatureFlag("Beta"); !stored.Enabled {
+		t.Error("Expected the stored flag to be unaffected by changes to the flag given")
+	}
+
+	if err := provider.SetFeatureFlag(FeatureFlag{ID: "Gamma"}); err != nil {
+		t.Fatalf("Failed to set feature flag: %v", err)
+	}
+	if names, err := manager.GetFeatureNames(); err != nil || fmt.Sprint(names) != "[Beta Gamma Legacy]" {
+		t.Errorf("Expected feature names in sorted order, got %v, %v", names, err)
+	}
+
+	if err := provider.SetFeatureFlag(FeatureFlag{}); err == nil {
+		t.Error("Expected error for a feature flag without an ID")
+	}
+}
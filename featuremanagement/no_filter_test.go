@@ -2,7 +2,9 @@ package featuremanagement
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -170,3 +172,93 @@ func TestInvalidEnabledFeatureFlag(t *testing.T) {
 		t.Logf("Got expected error: %v", err)
 	}
 }
+
+func TestFeatureManagerAll(t *testing.T) {
+	provider := &mockFeatureFlagProvider{
+		featureFlags: createTestFeatureFlags(),
+	}
+
+	fm, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	var ids []string
+	for flag := range fm.All() {
+		ids = append(ids, flag.ID)
+		if len(ids) == 2 {
+			break
+		}
+	}
+	if fmt.Sprint(ids) != "[BooleanTrue BooleanFalse]" {
+		t.Errorf("Unexpected feature flags: %v", ids)
+	}
+
+	names, err := fm.GetFeatureNames()
+	if err != nil || fmt.Sprint(names) != "[BooleanTrue BooleanFalse Minimal NoEnabled EmptyConditions]" {
+		t.Errorf("Unexpected feature names: %v, %v", names, err)
+	}
+}
+
+// failingProvider fails to enumerate its feature flags
+type failingProvider struct {
+	mockFeatureFlagProvider
+	err error
+}
+
+func (p *failingProvider) GetFeatureFlags() ([]FeatureFlag, error) {
+	return nil, p.err
+}
+
+func TestGetFeatureNames(t *testing.T) {
+	provider := &mockFeatureFlagProvider{featureFlags: []FeatureFlag{
+		{ID: "Search", Enabled: true},
+		{ID: "Beta", Enabled: true},
+		{ID: "Search", Enabled: false},
+	}}
+	manager, err := NewFeatureManager(provider, &Options{LogLevel: LogLevelSilent})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	if names, err := manager.GetFeatureNames(); err != nil || fmt.Sprint(names) != "[Search Beta]" {
+		t.Errorf("Expected deduplicated names in provider order, got %v, %v", names, err)
+	}
+
+	provider.featureFlags = nil
+	if names, err := manager.GetFeatureNames(); err != nil || names == nil || len(names) != 0 {
+		t.Errorf("Expected an empty list of names, got %#v, %v", names, err)
+	}
+
+	failing, err := NewFeatureManager(&failingProvider{err: errors.New("store unavailable")}, &Options{LogLevel: LogLevelSilent})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	if names, err := failing.GetFeatureNames(); err == nil || !strings.Contains(err.Error(), "store unavailable") || names != nil {
+		t.Errorf("Expected the provider error to be returned, got %v, %v", names, err)
+	}
+}
+
+func TestGetFeaturesByTag(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Checkout": {Enabled: true, Tags: []string{"team:payments", "stage:beta"}},
+		"Invoices": {Enabled: true, Tags: []string{"team:payments"}},
+		"Search":   {Enabled: true, Tags: []string{"team:discovery"}},
+	})
+
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	var names []string
+	for _, flag := range manager.GetFeaturesByTag("team:payments") {
+		names = append(names, flag.ID)
+	}
+	if fmt.Sprint(names) != "[Checkout Invoices]" {
+		t.Errorf("Expected payments features, got %v", names)
+	}
+
+	if flags := manager.GetFeaturesByTag("stage:ga"); len(flags) != 0 {
+		t.Errorf("Expected no features for an unused tag, got %v", flags)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NumericOperator compares a numeric attribute of the targeting context with a value
+type NumericOperator string
+
+const (
+	// NumericOperatorGreaterThan matches attributes greater than the value
+	NumericOperatorGreaterThan NumericOperator = "gt"
+	// NumericOperatorGreaterThanOrEqual matches attributes greater than or equal to the value
+	NumericOperatorGreaterThanOrEqual NumericOperator = "gte"
+	// NumericOperatorLessThan matches attributes less than the value
+	NumericOperatorLessThan NumericOperator = "lt"
+	// NumericOperatorLessThanOrEqual matches attributes less than or equal to the value
+	NumericOperatorLessThanOrEqual NumericOperator = "lte"
+	// NumericOperatorBetween matches attributes from the value to the max, both inclusive
+	NumericOperatorBetween NumericOperator = "between"
+)
+
+// NumericFilter enables a feature when numeric attributes of the targeting context, such as the
+// age of an account in days, a spend or an app build number, meet every configured condition.
+// An attribute that is missing or isn't a number fails its condition. It is registered by
+// default under the name "Microsoft.Numeric".
+type NumericFilter struct {
+	paramCache parameterCache[NumericFilterParameters]
+}
+
+// NumericFilterParameters defines the parameters for the numeric filter
+type NumericFilterParameters struct {
+	// Conditions must all be met for the feature to be enabled
+	Conditions []NumericCondition
+}
+
+// NumericCondition compares an attribute of the targeting context with a value
+type NumericCondition struct {
+	// Attribute is the name of the targeting context attribute, for example "accountAgeDays"
+	Attribute string
+	// Operator is one of "gt", "gte", "lt", "lte" and "between"
+	Operator NumericOperator
+	// Value is the value compared with, or the lower bound for "between"
+	Value float64
+	// Max is the upper bound for "between"
+	Max float64
+}
+
+func (n *NumericFilter) Name() string {
+	return "Microsoft.Numeric"
+}
+
+func (n *NumericFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appContext any) (bool, error) {
+	params, err := n.paramCache.get(evalCtx.FeatureName, evalCtx.Parameters, decodeNumericParams)
+	if err != nil {
+		return false, err
+	}
+
+	targetingCtx, ok := targetingContextFrom(appContext)
+	if !ok {
+		return false, fmt.Errorf("the app context is required for numeric filter and must be a TargetingContext or implement TargetingContexter")
+	}
+
+	for _, condition := range params.Conditions {
+		raw, ok := targetingCtx.Attributes[condition.Attribute]
+		if !ok {
+			return false, nil
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || !condition.matches(value) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (n *NumericFilter) preloadParameters(featureName string, parameters map[string]any) error {
+	_, err := n.paramCache.get(featureName, parameters, decodeNumericParams)
+	return err
+}
+
+// matches reports whether a value meets the condition
+func (c NumericCondition) matches(value float64) bool {
+	switch c.Operator {
+	case NumericOperatorGreaterThan:
+		return value > c.Value
+	case NumericOperatorGreaterThanOrEqual:
+		return value >= c.Value
+	case NumericOperatorLessThan:
+		return value < c.Value
+	case NumericOperatorLessThanOrEqual:
+		return value <= c.Value
+	case NumericOperatorBetween:
+		return value >= c.Value && value <= c.Max
+	default:
+		return false
+	}
+}
+
+func decodeNumericParams(featureName string, parameters map[string]any) (NumericFilterParameters, error) {
+	var params NumericFilterParameters
+	if err := BindParameters(parameters, &params); err != nil {
+		return NumericFilterParameters{}, fmt.Errorf("invalid numeric parameters format for feature %s: %w", featureName, err)
+	}
+
+	for i, condition := range params.Conditions {
+		if condition.Attribute == "" {
+			return NumericFilterParameters{}, fmt.Errorf("invalid feature flag: %s. Numeric condition at index %d missing attribute", featureName, i)
+		}
+
+		switch condition.Operator {
+		case NumericOperatorGreaterThan, NumericOperatorGreaterThanOrEqual, NumericOperatorLessThan, NumericOperatorLessThanOrEqual:
+		case NumericOperatorBetween:
+			if condition.Value > condition.Max {
+				return NumericFilterParameters{}, fmt.Errorf("invalid feature flag: %s. Numeric condition at index %d must have a Value not larger than its Max", featureName, i)
+			}
+		default:
+			return NumericFilterParameters{}, fmt.Errorf("invalid feature flag: %s. Numeric condition at index %d has unknown operator '%s'", featureName, i, condition.Operator)
+		}
+	}
+
+	return params, nil
+}
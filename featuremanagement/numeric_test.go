@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import "testing"
+
+func TestNumericFilter(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Loyalty": {
+			Enabled: true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{
+				Name: "Microsoft.Numeric",
+				Parameters: map[string]any{"Conditions": []any{
+					map[string]any{"Attribute": "accountAgeDays", "Operator": "gte", "Value": 30},
+					map[string]any{"Attribute": "build", "Operator": "between", "Value": 1200, "Max": 1299},
+				}},
+			}}},
+		},
+	})
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	for _, tc := range []struct {
+		attributes map[string]string
+		expected   bool
+	}{
+		{map[string]string{"accountAgeDays": "30", "build": "1200"}, true},
+		{map[string]string{"accountAgeDays": "365", "build": "1299"}, true},
+		{map[string]string{"accountAgeDays": "29.5", "build": "1250"}, false},
+		{map[string]string{"accountAgeDays": "90", "build": "1300"}, false},
+		{map[string]string{"accountAgeDays": "ninety", "build": "1250"}, false},
+		{map[string]string{"build": "1250"}, false},
+	} {
+		appContext := TargetingContext{Attributes: tc.attributes}
+		if enabled, err := manager.IsEnabledWithAppContext("Loyalty", appContext); err != nil || enabled != tc.expected {
+			t.Errorf("Expected Loyalty enabled=%v for %v, got %v, %v", tc.expected, tc.attributes, enabled, err)
+		}
+	}
+}
+
+func TestNumericConditionMatches(t *testing.T) {
+	for _, tc := range []struct {
+		condition NumericCondition
+		value     float64
+		expected  bool
+	}{
+		{NumericCondition{Operator: NumericOperatorGreaterThan, Value: 10}, 10, false},
+		{NumericCondition{Operator: NumericOperatorGreaterThan, Value: 10}, 10.1, true},
+		{NumericCondition{Operator: NumericOperatorLessThan, Value: 10}, 9, true},
+		{NumericCondition{Operator: NumericOperatorLessThan, Value: 10}, 10, false},
+		{NumericCondition{Operator: NumericOperatorLessThanOrEqual, Value: 10}, 10, true},
+		{NumericCondition{Operator: NumericOperatorBetween, Value: 1, Max: 2}, 2, true},
+		{NumericCondition{Operator: NumericOperatorBetween, Value: 1, Max: 2}, 0.5, false},
+	} {
+		if actual := tc.condition.matches(tc.value); actual != tc.expected {
+			t.Errorf("Expected %+v to match %v: %v", tc.condition, tc.value, tc.expected)
+		}
+	}
+}
+
+func TestNumericFilterInvalidParameters(t *testing.T) {
+	filter := &NumericFilter{}
+	for _, condition := range []map[string]any{
+		{"Operator": "gt", "Value": 1},
+		{"Attribute": "spend", "Operator": "eq", "Value": 1},
+		{"Attribute": "spend", "Operator": "between", "Value": 10, "Max": 5},
+	} {
+		evalCtx := FeatureFilterEvaluationContext{
+			FeatureName: "Invalid",
+			Parameters:  map[string]any{"Conditions": []any{condition}},
+		}
+		if _, err := filter.Evaluate(evalCtx, TargetingContext{}); err == nil {
+			t.Errorf("Expected an error for condition %v", condition)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// OverridesEnvVar is the environment variable read by LoadLocalOverrides.
+	// Its value is a comma-separated list of name=bool pairs, for example "Beta=true,Legacy=false".
+	OverridesEnvVar = "FM_OVERRIDES"
+
+	// OverridesFileName is the file read by LoadLocalOverrides from the working directory.
+	// It contains a JSON object mapping feature names to booleans, for example {"Beta": true}.
+	OverridesFileName = ".feature-overrides.json"
+)
+
+// WithOverrides returns a feature manager that forces the given features on or off and evaluates
+// every other feature exactly like the original manager. The derived manager shares the provider,
+// filters and evaluation tracking of the original; the original manager is not affected.
+// Overrides given here take precedence over those configured in Options.Overrides.
+//
+// This is intended for tests and for emergency operations where a feature must be switched
+// without waiting for a configuration change to propagate.
+//
+// Parameters:
+//   - overrides: The features to override and their forced enabled state
+//
+// Returns:
+//   - *FeatureManager: A derived feature manager applying the overrides
+func (fm *FeatureManager) WithOverrides(overrides map[string]bool) *FeatureManager {
+	derived := *fm
+	derived.overrides = make(map[string]bool, len(fm.overrides)+len(overrides))
+	for name, enabled := range fm.overrides {
+		derived.overrides[name] = enabled
+	}
+	for name, enabled := range overrides {
+		fm.logger.infof("Feature flag %s is overridden to enabled=%t", name, enabled)
+		derived.overrides[name] = enabled
+	}
+	// The interceptors must wrap the evaluation of the derived manager
+	derived.evaluator = derived.intercept(derived.evaluateFlag)
+
+	return &derived
+}
+
+// LoadLocalOverrides reads developer overrides from the .feature-overrides.json file in the
+// working directory and the FM_OVERRIDES environment variable. When a feature appears in both,
+// the environment variable wins. A missing file or empty variable is not an error.
+//
+// The result is intended to be passed as Options.Overrides so that gated code paths can be
+// exercised locally without modifying a shared configuration store:
+//
+//	overrides, err := featuremanagement.LoadLocalOverrides()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	manager, _ := featuremanagement.NewFeatureManager(provider, &featuremanagement.Options{
+//		Overrides: overrides,
+//	})
+//
+// Returns:
+//   - map[string]bool: The overridden features and their forced enabled state
+//   - error: An error if the file or environment variable is malformed
+func LoadLocalOverrides() (map[string]bool, error) {
+	overrides, err := loadOverridesFile(OverridesFileName)
+	if err != nil {
+		return nil, err
+	}
+
+	envOverrides, err := parseOverridesEnv(os.Getenv(OverridesEnvVar))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, enabled := range envOverrides {
+		overrides[name] = enabled
+	}
+
+	return overrides, nil
+}
+
+func loadOverridesFile(path string) (map[string]bool, error) {
+	overrides := make(map[string]bool)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return overrides, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overrides file %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid overrides file %s: %w", path, err)
+	}
+	// A file holding null leaves no map, which the environment overrides are merged into
+	if overrides == nil {
+		overrides = make(map[string]bool)
+	}
+
+	return overrides, nil
+}
+
+func parseOverridesEnv(value string) (map[string]bool, error) {
+	overrides := make(map[string]bool)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, rawEnabled, found := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid %s entry %q: expected name=true|false", OverridesEnvVar, pair)
+		}
+
+		enabled, err := strconv.ParseBool(strings.TrimSpace(rawEnabled))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s entry %q: %w", OverridesEnvVar, pair, err)
+		}
+		overrides[name] = enabled
+	}
+
+	return overrides, nil
+}
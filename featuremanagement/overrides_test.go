@@ -0,0 +1,182 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFeatureManagerOverrides(t *testing.T) {
+	provider := &mockFeatureFlagProvider{
+		featureFlags: []FeatureFlag{
+			{ID: "Off", Enabled: false},
+			{
+				ID:      "Targeted",
+				Enabled: true,
+				Conditions: &Conditions{
+					ClientFilters: []ClientFilter{{Name: "Microsoft.Targeting"}},
+				},
+			},
+			{
+				ID:       "StatusOverride",
+				Enabled:  true,
+				Variants: []VariantDefinition{{Name: "Big", StatusOverride: StatusOverrideDisabled}},
+				Allocation: &VariantAllocation{
+					DefaultWhenEnabled: "Big",
+				},
+			},
+			{ID: "NotOverridden", Enabled: true},
+		},
+	}
+
+	manager, err := NewFeatureManager(provider, &Options{
+		Overrides: map[string]bool{
+			"Off":            true,
+			"Targeted":       true,
+			"StatusOverride": true,
+			"Missing":        true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		featureName string
+		expected    bool
+	}{
+		{name: "Disabled flag forced on", featureName: "Off", expected: true},
+		{name: "Filters are skipped", featureName: "Targeted", expected: true},
+		{name: "Variant status override is ignored", featureName: "StatusOverride", expected: true},
+		{name: "Flag missing from provider", featureName: "Missing", expected: true},
+		{name: "Other flags evaluate normally", featureName: "NotOverridden", expected: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			enabled, err := manager.IsEnabled(tc.featureName)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if enabled != tc.expected {
+				t.Errorf("Expected %v, got %v", tc.expected, enabled)
+			}
+		})
+	}
+
+	if _, err := manager.IsEnabled("Unknown"); err == nil {
+		t.Error("Expected error for a flag that is neither defined nor overridden")
+	}
+}
+
+func TestParseOverridesEnv(t *testing.T) {
+	overrides, err := parseOverridesEnv(" Beta=true, Legacy=0 ,,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(overrides) != 2 || !overrides["Beta"] || overrides["Legacy"] {
+		t.Errorf("Unexpected overrides: %v", overrides)
+	}
+
+	for _, value := range []string{"Beta", "=true", "Beta=maybe"} {
+		if _, err := parseOverridesEnv(value); err == nil {
+			t.Errorf("Expected error for %q", value)
+		}
+	}
+}
+
+func TestLoadOverridesFile(t *testing.T) {
+	dir := t.TempDir()
+
+	overrides, err := loadOverridesFile(filepath.Join(dir, "missing.json"))
+	if err != nil || len(overrides) != 0 {
+		t.Fatalf("Expected empty overrides for a missing file, got %v, %v", overrides, err)
+	}
+
+	path := filepath.Join(dir, OverridesFileName)
+	if err := os.WriteFile(path, []byte(`{"Beta": true, "Legacy": false}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	overrides, err = loadOverridesFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(overrides) != 2 || !overrides["Beta"] || overrides["Legacy"] {
+		t.Errorf("Unexpected overrides: %v", overrides)
+	}
+
+	if err := os.WriteFile(path, []byte(`null`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	overrides, err = loadOverridesFile(path)
+	if err != nil || overrides == nil || len(overrides) != 0 {
+		t.Errorf("Expected empty overrides for a null file, got %v, %v", overrides, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"Beta": "yes"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadOverridesFile(path); err == nil {
+		t.Error("Expected error for malformed overrides file")
+	}
+}
+
+func TestLoadLocalOverrides(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	// The environment overrides are merged into those of a file holding null
+	if err := os.WriteFile(OverridesFileName, []byte(`null`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(OverridesEnvVar, "Beta=true")
+	overrides, err := LoadLocalOverrides()
+	if err != nil || len(overrides) != 1 || !overrides["Beta"] {
+		t.Errorf("Expected the environment override of Beta, got %v, %v", overrides, err)
+	}
+}
+
+func TestWithOverrides(t *testing.T) {
+	provider := NewBoolProvider(map[string]bool{"Beta": false, "Gamma": true, "Delta": false})
+	manager, err := NewFeatureManager(provider, &Options{
+		Overrides: map[string]bool{"Delta": true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	derived := manager.WithOverrides(map[string]bool{"Beta": true, "Gamma": false})
+
+	expected := map[string][2]bool{
+		// feature: {original, derived}
+		"Beta":  {false, true},
+		"Gamma": {true, false},
+		"Delta": {true, true},
+	}
+	for name, states := range expected {
+		if enabled, _ := manager.IsEnabled(name); enabled != states[0] {
+			t.Errorf("Expected original manager to evaluate %s as %v, got %v", name, states[0], enabled)
+		}
+		if enabled, _ := derived.IsEnabled(name); enabled != states[1] {
+			t.Errorf("Expected derived manager to evaluate %s as %v, got %v", name, states[1], enabled)
+		}
+	}
+
+	// Evaluations through either manager are tracked together
+	report := derived.GetLifecycleReport()
+	for _, feature := range report.Features {
+		if feature.EnabledCount+feature.DisabledCount != 2 {
+			t.Errorf("Expected 2 tracked evaluations of %s, got %+v", feature.FeatureName, feature)
+		}
+	}
+}
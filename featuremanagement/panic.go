@@ -0,0 +1,129 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strconv"
+)
+
+// PanicError reports a panic raised by an extension during an evaluation or an enumeration of the
+// feature flags, such as a feature flag provider or a feature filter. The feature manager recovers
+// the panic so that a misbehaving extension fails the evaluation instead of the process. Panics
+// of assignment stores and telemetry publishers, and of providers enumerating their feature flags
+// for reports, are passed to Options.OnError instead, as they don't fail an evaluation.
+type PanicError struct {
+	// Component is the kind of extension that panicked: "feature flag provider", "feature filter",
+	// "allocation strategy", "assignment store", "telemetry publisher" or "evaluation interceptor"
+	Component string
+	// Name identifies the extension: the name of a filter, the position of an interceptor in
+	// Options.Interceptors, or the type of other extensions
+	Name string
+	// Value is the value passed to panic
+	Value any
+	// Stack is the stack trace of the goroutine when it panicked
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s %s panicked: %v", e.Component, e.Name, e.Value)
+}
+
+// Unwrap returns the value passed to panic if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// recoverPanic converts a panic of an extension into a *PanicError stored in err, counting it.
+// It must be deferred directly. The name of the extension defaults to the type of impl.
+func (fm *FeatureManager) recoverPanic(component, name string, impl any, err *error) {
+	value := recover()
+	if value == nil {
+		return
+	}
+
+	*err = fm.panicError(component, name, impl, value)
+}
+
+// panicError counts a recovered panic and describes it as a *PanicError
+func (fm *FeatureManager) panicError(component, name string, impl any, value any) *PanicError {
+	if name == "" {
+		name = fmt.Sprintf("%T", impl)
+	}
+	fm.panics.Add(1)
+	return &PanicError{Component: component, Name: name, Value: value, Stack: debug.Stack()}
+}
+
+// getFeatureFlag retrieves a feature flag from the provider, recovering panics
+func (fm *FeatureManager) getFeatureFlag(featureName string) (featureFlag FeatureFlag, err error) {
+	defer fm.recoverPanic("feature flag provider", "", fm.featureProvider, &err)
+	return fm.featureProvider.GetFeatureFlag(featureName)
+}
+
+// getFeatureFlags retrieves the feature flags from the provider, recovering panics
+func (fm *FeatureManager) getFeatureFlags() (featureFlags []FeatureFlag, err error) {
+	defer fm.recoverPanic("feature flag provider", "", fm.featureProvider, &err)
+	return fm.featureProvider.GetFeatureFlags()
+}
+
+// evaluateFilter evaluates a feature filter, recovering panics
+func (fm *FeatureManager) evaluateFilter(filter FeatureFilter, name string, evalCtx FeatureFilterEvaluationContext, appContext any) (enabled bool, err error) {
+	defer fm.recoverPanic("feature filter", name, filter, &err)
+	return filter.Evaluate(evalCtx, appContext)
+}
+
+// allocateWithStrategy allocates a variant with the allocation strategy, recovering panics
+func (fm *FeatureManager) allocateWithStrategy(featureFlag *FeatureFlag, targetingContext TargetingContext) (variantName string, reason VariantAssignmentReason, err error) {
+	defer fm.recoverPanic("allocation strategy", "", fm.allocationStrategy, &err)
+	variantName, reason = fm.allocationStrategy.Allocate(featureFlag, targetingContext)
+	return variantName, reason, nil
+}
+
+// getAssignment reads an assignment from the assignment store, recovering panics
+func (fm *FeatureManager) getAssignment(featureName, targetingID string) (assignment Assignment, found bool, err error) {
+	defer fm.recoverPanic("assignment store", "", fm.assignmentStore, &err)
+	return fm.assignmentStore.GetAssignment(featureName, targetingID)
+}
+
+// putAssignment writes an assignment to the assignment store, recovering panics
+func (fm *FeatureManager) putAssignment(featureName, targetingID string, assignment Assignment) (err error) {
+	defer fm.recoverPanic("assignment store", "", fm.assignmentStore, &err)
+	return fm.assignmentStore.PutAssignment(featureName, targetingID, assignment)
+}
+
+// publishTelemetry publishes the telemetry of an evaluation, recovering panics of the publisher,
+// which are reported rather than failing the evaluation
+func (fm *FeatureManager) publishTelemetry(featureName string, res EvaluationResult) {
+	var err error
+	defer func() {
+		if err != nil {
+			fm.reportError(featureName, withEvaluationID(res.EvaluationID, fmt.Errorf("failed to publish the telemetry of feature %s: %w", featureName, err)))
+		}
+	}()
+	defer fm.recoverPanic("telemetry publisher", "", fm.telemetry.publisher, &err)
+	fm.telemetry.publish(featureName, res.Feature, res)
+}
+
+// intercept wraps the evaluator with the interceptors, the first being the outermost. The
+// evaluators returned by the interceptors are guarded, so that a panic of an interceptor fails
+// the evaluation it intercepts.
+func (fm *FeatureManager) intercept(evaluator Evaluator) Evaluator {
+	for i := len(fm.interceptors) - 1; i >= 0; i-- {
+		if fm.interceptors[i] != nil {
+			evaluator = fm.guardInterceptor(strconv.Itoa(i), fm.interceptors[i](evaluator))
+		}
+	}
+
+	return evaluator
+}
+
+// guardInterceptor returns an evaluator calling the evaluator of an interceptor, recovering panics
+func (fm *FeatureManager) guardInterceptor(name string, intercepted Evaluator) Evaluator {
+	return func(featureName string, appContext any) (res EvaluationResult, err error) {
+		defer fm.recoverPanic("evaluation interceptor", name, nil, &err)
+		return intercepted(featureName, appContext)
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+)
+
+type panickingFilter struct{}
+
+func (f *panickingFilter) Name() string {
+	return "Panicking"
+}
+
+func (f *panickingFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appContext any) (bool, error) {
+	panic("filter bug")
+}
+
+type panickingProvider struct {
+	*StaticProvider
+}
+
+func (p *panickingProvider) GetFeatureFlag(name string) (FeatureFlag, error) {
+	if name == "Broken" {
+		panic(errors.New("provider bug"))
+	}
+	return p.StaticProvider.GetFeatureFlag(name)
+}
+
+func TestPanicIsolation(t *testing.T) {
+	provider := &panickingProvider{StaticProvider: NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {
+			Enabled:    true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{Name: "Panicking"}}},
+		},
+		"Gamma": {Enabled: true},
+	})}
+	manager, err := NewFeatureManager(provider, &Options{Filters: []FeatureFilter{&panickingFilter{}}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	_, err = manager.IsEnabled("Beta")
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Component != "feature filter" || panicErr.Name != "Panicking" || len(panicErr.Stack) == 0 {
+		t.Errorf("Expected the filter panic to be returned as an error, got %v", err)
+	}
+
+	_, err = manager.IsEnabled("Broken")
+	if !errors.As(err, &panicErr) || panicErr.Component != "feature flag provider" || panicErr.Name != "*featuremanagement.panickingProvider" {
+		t.Errorf("Expected the provider panic to be returned as an error, got %v", err)
+	}
+	if err == nil || err.Error() != "failed to get feature flag Broken: feature flag provider *featuremanagement.panickingProvider panicked: provider bug" {
+		t.Errorf("Unexpected error message %v", err)
+	}
+
+	if enabled, err := manager.IsEnabled("Gamma"); err != nil || !enabled {
+		t.Errorf("Expected other features to keep evaluating, got %v, %v", enabled, err)
+	}
+	if panics := manager.Stats().Panics; panics != 2 {
+		t.Errorf("Expected 2 recovered panics, got %d", panics)
+	}
+}
+
+type panickingAssignmentStore struct{}
+
+func (s *panickingAssignmentStore) GetAssignment(featureName string, targetingID string) (Assignment, bool, error) {
+	panic("store bug")
+}
+
+func (s *panickingAssignmentStore) PutAssignment(featureName string, targetingID string, assignment Assignment) error {
+	panic("store bug")
+}
+
+func TestAssignmentStorePanic(t *testing.T) {
+	var reported []error
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{"Beta": {
+		Enabled:    true,
+		Variants:   []VariantDefinition{{Name: "Big"}},
+		Allocation: &VariantAllocation{DefaultWhenEnabled: "Big"},
+	}}), &Options{
+		AssignmentStore: &panickingAssignmentStore{},
+		OnError:         func(featureName string, err error) { reported = append(reported, err) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	res, err := manager.Evaluate("Beta", TargetingContext{UserID: "alice"})
+	if err != nil || res.Variant == nil || res.Variant.Name != "Big" {
+		t.Fatalf("Expected the variant to be allocated despite the store, got %+v, %v", res, err)
+	}
+	var panicErr *PanicError
+	if len(reported) != 1 || !errors.As(reported[0], &panicErr) || panicErr.Component != "assignment store" {
+		t.Errorf("Expected the store panic to be reported, got %v", reported)
+	}
+	if panics := manager.Stats().Panics; panics != 1 {
+		t.Errorf("Expected 1 recovered panic, got %d", panics)
+	}
+}
+
+type panickingTelemetryPublisher struct{}
+
+func (p *panickingTelemetryPublisher) Publish(event TelemetryEvent) {
+	panic("publisher bug")
+}
+
+func TestTelemetryPublisherPanic(t *testing.T) {
+	var reported []error
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{"Beta": {
+		Enabled:   true,
+		Telemetry: &Telemetry{Enabled: true},
+	}}), &Options{
+		TelemetryPublisher: &panickingTelemetryPublisher{},
+		OnError:            func(featureName string, err error) { reported = append(reported, err) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || !enabled {
+		t.Errorf("Expected the evaluation to succeed despite the publisher, got %v, %v", enabled, err)
+	}
+	var panicErr *PanicError
+	if len(reported) != 1 || !errors.As(reported[0], &panicErr) || panicErr.Component != "telemetry publisher" || panicErr.Name != "*featuremanagement.panickingTelemetryPublisher" {
+		t.Errorf("Expected the publisher panic to be reported, got %v", reported)
+	}
+}
+
+func TestInterceptorPanic(t *testing.T) {
+	passThrough := func(next Evaluator) Evaluator { return next }
+	panicking := func(next Evaluator) Evaluator {
+		return func(featureName string, appContext any) (EvaluationResult, error) {
+			if featureName == "Broken" {
+				panic("interceptor bug")
+			}
+			return next(featureName, appContext)
+		}
+	}
+	manager, err := NewFeatureManager(NewBoolProvider(map[string]bool{"Beta": true, "Broken": true}), &Options{
+		Interceptors: []Interceptor{passThrough, panicking},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	_, err = manager.IsEnabled("Broken")
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) || panicErr.Component != "evaluation interceptor" || panicErr.Name != "1" {
+		t.Errorf("Expected the interceptor panic to be returned as an error, got %v", err)
+	}
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || !enabled {
+		t.Errorf("Expected other features to keep evaluating, got %v, %v", enabled, err)
+	}
+
+	// Evaluations with call options chain the interceptors for each call
+	if enabled, err := manager.IsEnabledContext(context.Background(), "Broken", WithDefault(false)); err != nil || enabled {
+		t.Errorf("Expected the default to be returned when the interceptor panics, got %v, %v", enabled, err)
+	}
+}
+
+type panickingEnumerationProvider struct {
+	FeatureFlagProvider
+}
+
+func (p *panickingEnumerationProvider) GetFeatureFlags() ([]FeatureFlag, error) {
+	panic("enumeration bug")
+}
+
+type panickingIteratorProvider struct {
+	*StaticProvider
+}
+
+func (p *panickingIteratorProvider) All() iter.Seq[FeatureFlag] {
+	return func(yield func(FeatureFlag) bool) {
+		for flag := range p.StaticProvider.All() {
+			if !yield(flag) {
+				return
+			}
+		}
+		panic("iterator bug")
+	}
+}
+
+func TestProviderEnumerationPanic(t *testing.T) {
+	flags := map[string]FeatureFlag{"Beta": {Enabled: true}}
+	var reported []error
+	options := &Options{OnError: func(featureName string, err error) { reported = append(reported, err) }}
+	var panicErr *PanicError
+
+	// Providers enumerated with GetFeatureFlags
+	manager, err := NewFeatureManager(&panickingEnumerationProvider{FeatureFlagProvider: NewStaticProvider(flags)}, options)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	reported = nil
+	_, _ = manager.IsEnabled("Beta")
+	if usage := manager.UsageReport(); len(usage.Features) != 1 || usage.Features[0].Defined {
+		t.Errorf("Expected only the evaluations to be reported, got %+v", usage.Features)
+	}
+	if len(reported) != 1 || !errors.As(reported[0], &panicErr) || panicErr.Component != "feature flag provider" {
+		t.Errorf("Expected the provider panic to be reported, got %v", reported)
+	}
+	if _, err := manager.GetFeatureNames(); !errors.As(err, &panicErr) {
+		t.Errorf("Expected the provider panic to be returned, got %v", err)
+	}
+
+	// Providers enumerated with All
+	manager, err = NewFeatureManager(&panickingIteratorProvider{StaticProvider: NewStaticProvider(flags)}, options)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	reported = nil
+	if _, err := manager.ExportFeatureManagement(context.Background(), ExportOptions{}); err != nil {
+		t.Errorf("Expected the flags enumerated before the panic to be exported, got %v", err)
+	}
+	if len(reported) != 1 || !errors.As(reported[0], &panicErr) || panicErr.Value != "iterator bug" {
+		t.Errorf("Expected the iterator panic to be reported, got %v", reported)
+	}
+
+	// Panics of the loop body aren't attributed to the provider
+	defer func() {
+		if value := recover(); value != "caller bug" {
+			t.Errorf("Expected the panic of the loop body to be propagated, got %v", value)
+		}
+	}()
+	for range manager.All() {
+		panic("caller bug")
+	}
+}
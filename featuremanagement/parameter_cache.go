@@ -0,0 +1,129 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"sync"
+)
+
+// parameterCache memoizes the decoded form of filter parameters so that filters don't
+// re-decode the same configuration on every evaluation.
+//
+// Entries are keyed by feature name and tied to the parameters map they were decoded from.
+// Providers replace flag definitions (and therefore their parameter maps) when they refresh,
+// which invalidates the cached entry on the next evaluation. Providers returning a copy of the
+// definition on each lookup hand over a new map with the same content, which still hits the
+// entry: maps are compared by identity first, then by content.
+type parameterCache[T any] struct {
+	entries sync.Map // feature name -> *parameterCacheEntry[T]
+}
+
+type parameterCacheEntry[T any] struct {
+	parameters map[string]any
+	value      T
+	err        error
+}
+
+// get returns the cached decoded parameters for the feature, calling decode on a miss
+func (c *parameterCache[T]) get(featureName string, parameters map[string]any, decode func(featureName string, parameters map[string]any) (T, error)) (T, error) {
+	if cached, ok := c.entries.Load(featureName); ok {
+		entry := cached.(*parameterCacheEntry[T])
+		if sameParameters(entry.parameters, parameters) {
+			return entry.value, entry.err
+		}
+	}
+
+	value, err := decode(featureName, parameters)
+	c.entries.Store(featureName, &parameterCacheEntry[T]{
+		parameters: parameters,
+		value:      value,
+		err:        err,
+	})
+
+	return value, err
+}
+
+// sameParameters reports whether a and b are the same map instance or have the same content
+func sameParameters(a, b map[string]any) bool {
+	if reflect.ValueOf(a).UnsafePointer() == reflect.ValueOf(b).UnsafePointer() {
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// parameterPreloader is implemented by built-in filters that can decode and validate their
+// parameters ahead of the first evaluation
+type parameterPreloader interface {
+	preloadParameters(featureName string, parameters map[string]any) error
+}
+
+// FilterParameterError reports client filter parameters that the filter rejects
+type FilterParameterError struct {
+	// FeatureName is the ID of the feature flag
+	FeatureName string
+	// FilterName is the name of the client filter
+	FilterName string
+	// Index is the index of the client filter within the conditions of the feature flag
+	Index int
+	// Err describes why the parameters are invalid
+	Err error
+}
+
+func (e *FilterParameterError) Error() string {
+	return fmt.Sprintf("invalid parameters for filter %s of feature %s: %v", e.FilterName, e.FeatureName, e.Err)
+}
+
+func (e *FilterParameterError) Unwrap() error {
+	return e.Err
+}
+
+// CheckFilterParameters decodes the parameters of the client filters of a feature flag with the
+// filters registered with the manager, as they are decoded when the manager is created, so that
+// tools can report invalid parameters before the flag is deployed. Only the built-in filters
+// check their parameters ahead of evaluation; the parameters of other filters are accepted.
+//
+// Parameters:
+//   - flag: The feature flag definition to check
+//
+// Returns:
+//   - error: A *FilterParameterError for each client filter with invalid parameters, joined, or
+//     nil if all are valid
+func (fm *FeatureManager) CheckFilterParameters(flag FeatureFlag) error {
+	return errors.Join(checkFilterParameters(fm.featureFilters, flag)...)
+}
+
+func checkFilterParameters(featureFilters map[string]FeatureFilter, flag FeatureFlag) []error {
+	if flag.Conditions == nil {
+		return nil
+	}
+
+	var errs []error
+	for i, clientFilter := range flag.Conditions.ClientFilters {
+		preloader, ok := featureFilters[clientFilter.Name].(parameterPreloader)
+		if !ok {
+			continue
+		}
+
+		if err := preloader.preloadParameters(flag.ID, clientFilter.Parameters); err != nil {
+			errs = append(errs, &FilterParameterError{FeatureName: flag.ID, FilterName: clientFilter.Name, Index: i, Err: err})
+		}
+	}
+
+	return errs
+}
+
+// preloadFilterParameters decodes the parameters of every client filter of the given flags
+// into the filters' caches, reporting parameters that are invalid
+func preloadFilterParameters(featureFilters map[string]FeatureFilter, flags iter.Seq[FeatureFlag], report func(featureName string, err error)) {
+	for flag := range flags {
+		for _, err := range checkFilterParameters(featureFilters, flag) {
+			report(flag.ID, err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParameterCache(t *testing.T) {
+	var cache parameterCache[int]
+	decodes := 0
+	decode := func(featureName string, parameters map[string]any) (int, error) {
+		decodes++
+		if parameters == nil {
+			return 0, errors.New("missing parameters")
+		}
+		return len(parameters), nil
+	}
+
+	params := map[string]any{"Start": "Mon, 01 Jan 2024 00:00:00 GMT"}
+	for i := 0; i < 3; i++ {
+		value, err := cache.get("Beta", params, decode)
+		if err != nil || value != 1 {
+			t.Fatalf("Unexpected result %d, %v", value, err)
+		}
+	}
+	if decodes != 1 {
+		t.Errorf("Expected parameters to be decoded once, got %d", decodes)
+	}
+
+	// A copy of the definition, as returned by providers on each lookup, reuses the entry
+	if value, _ := cache.get("Beta", cloneMap(params), decode); value != 1 || decodes != 1 {
+		t.Errorf("Expected copied parameters to hit the cache, got %d after %d decodes", value, decodes)
+	}
+
+	// A refreshed flag carries a new parameters map, even with equal content
+	refreshed := map[string]any{"Start": "Mon, 01 Jan 2024 00:00:00 GMT", "End": "Tue, 02 Jan 2024 00:00:00 GMT"}
+	if value, _ := cache.get("Beta", refreshed, decode); value != 2 || decodes != 2 {
+		t.Errorf("Expected refreshed parameters to be decoded, got %d after %d decodes", value, decodes)
+	}
+
+	// Features are cached independently
+	if value, _ := cache.get("Gamma", params, decode); value != 1 || decodes != 3 {
+		t.Errorf("Expected a separate entry per feature, got %d after %d decodes", value, decodes)
+	}
+
+	// Decode errors are cached as well
+	for i := 0; i < 2; i++ {
+		if _, err := cache.get("Delta", nil, decode); err == nil {
+			t.Error("Expected cached decode error")
+		}
+	}
+	if decodes != 4 {
+		t.Errorf("Expected decode errors to be cached, got %d decodes", decodes)
+	}
+}
+
+func TestCheckFilterParameters(t *testing.T) {
+	manager, err := NewFeatureManager(NewStaticProvider(nil), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	flag := FeatureFlag{ID: "Beta", Conditions: &Conditions{ClientFilters: []ClientFilter{
+		{Name: "Custom", Parameters: map[string]any{"Anything": true}},
+		{Name: "Microsoft.Targeting", Parameters: map[string]any{"Audience": map[string]any{"DefaultRolloutPercentage": 150}}},
+	}}}
+
+	err = manager.CheckFilterParameters(flag)
+	var paramErr *FilterParameterError
+	if !errors.As(err, &paramErr) || paramErr.Index != 1 || paramErr.FilterName != "Microsoft.Targeting" || paramErr.FeatureName != "Beta" {
+		t.Errorf("Expected a FilterParameterError for the targeting filter, got %v", err)
+	}
+
+	flag.Conditions.ClientFilters = flag.Conditions.ClientFilters[:1]
+	if err := manager.CheckFilterParameters(flag); err != nil {
+		t.Errorf("Expected the parameters of custom filters to be accepted, got %v", err)
+	}
+}
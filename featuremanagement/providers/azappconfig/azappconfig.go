@@ -4,64 +4,332 @@
 package azappconfig
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/Azure/AppConfiguration-GoProvider/azureappconfiguration"
 	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
 )
 
+// FeatureFlagProvider serves the feature flags of one or more AzureAppConfiguration clients. The
+// callbacks registered with OnRefresh are called whenever a refresh, a label switch or a snapshot
+// switch reloads the served feature flags.
 type FeatureFlagProvider struct {
+	fm.SnapshotProvider
+
+	sources         []*source
+	decodeOptions   fm.DecodeOptions
+	duplicatePolicy fm.DuplicatePolicy
+	logger          *fm.ProviderLogger
+
+	onError           func(err error)
+	degradedThreshold int
+	section           []string
+	unwrapped         bool
+
+	// refreshInterval and refreshLoop are guarded by mu; the loop starts with the first positive interval
+	refreshInterval time.Duration
+	refreshLoop     *refreshLoop
+
+	// snapshotLoader is set when the feature flags are loaded from an App Configuration snapshot
+	snapshotLoader *snapshotLoader
+
+	// loader is set when the provider loads its own configuration, allowing the label to be switched
+	loader *labelLoader
+
+	// refreshMu serializes Refresh: a client skips a refresh requested while another is in
+	// progress, so without it a concurrent Refresh could return before the sources are refreshed
+	refreshMu sync.Mutex
+
+	// mergeMu serializes merging, as each source refreshes independently, so that snapshots are
+	// stored in the order they were merged
+	mergeMu sync.Mutex
+
+	// mu guards the sources and refresh status
+	mu     sync.Mutex
+	status refreshStatus
+}
+
+// source holds the feature flags most recently loaded from one AzureAppConfiguration client,
+// or from an App Configuration snapshot when azappcfg is nil
+type source struct {
 	azappcfg     *azureappconfiguration.AzureAppConfiguration
 	featureFlags []fm.FeatureFlag
-	mu           sync.RWMutex
 }
 
-func NewFeatureFlagProvider(azappcfg *azureappconfiguration.AzureAppConfiguration) (*FeatureFlagProvider, error) {
-	type featureConfig struct {
-		FeatureManagement fm.FeatureManagement `json:"feature_management"`
+// Options configures the FeatureFlagProvider.
+type Options struct {
+	// StrictDecoding rejects feature flags with unknown fields or values of the wrong type when
+	// loading and refreshing, instead of ignoring or converting them. A failed refresh keeps the
+	// previously loaded flags.
+	StrictDecoding bool
+
+	// OnError, if set, is called with the error of every failed refresh, after the provider has
+	// recorded it. The provider keeps serving the last good feature flags.
+	OnError func(err error)
+
+	// FeatureFlagSelectors selects the feature flags loaded by a provider that loads its own
+	// configuration, created with NewFeatureFlagOnlyProvider or NewLabeledFeatureFlagProvider,
+	// in place of the feature flag selectors of the configuration options. Selecting only the
+	// feature flags a service uses keeps the payload small in stores with many flags. The key
+	// filter matches the feature flag ID. It has no effect on providers of existing clients.
+	FeatureFlagSelectors []azureappconfiguration.Selector
+
+	// Section is the path of the configuration section holding the feature management document,
+	// with levels separated by ".", for example "myapp.flags". Defaults to the root of the
+	// configuration, where Azure App Configuration places its feature flags.
+	Section string
+
+	// Unwrapped reports that the section holds the contents of the feature_management section,
+	// a feature_flags array, rather than a document wrapping it in a feature_management or .NET
+	// FeatureManagement section.
+	Unwrapped bool
+
+	// SnapshotName is the name of the App Configuration snapshot that a provider created with
+	// NewSnapshotFeatureFlagProvider loads its feature flags from.
+	SnapshotName string
+
+	// RefreshInterval, when positive, makes the provider refresh its sources in the background at
+	// this interval, so the application doesn't need its own ticker calling Refresh. It can be
+	// changed at runtime with SetRefreshInterval. Call Close to stop refreshing.
+	RefreshInterval time.Duration
+
+	// DegradedThreshold is the number of consecutive failed refreshes after which the provider
+	// reports itself as degraded. Defaults to 3.
+	DegradedThreshold int
+
+	// DuplicatePolicy decides which definition of a feature flag defined more than once is
+	// served, in source order, then in the order in which each source loaded its flags. By
+	// default, the last source defining a flag wins, and the first definition within it.
+	// With fm.DuplicateError, every definition of the feature is logged and left out, so it
+	// evaluates as not found.
+	DuplicatePolicy fm.DuplicatePolicy
+
+	// LogLevel is the minimum severity of the messages logged by the provider, such as rejected
+	// feature flags, failed refreshes and degradation. Defaults to fm.LogLevelWarn;
+	// fm.LogLevelSilent disables logging.
+	LogLevel fm.LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+}
+
+// sectionSeparator separates the levels of configuration keys and section paths
+const sectionSeparator = "."
+
+// NewFeatureFlagProvider creates a FeatureFlagProvider that serves the feature flags of one or
+// more AzureAppConfiguration clients. See NewMergedFeatureFlagProvider for how the flags of
+// several clients are merged.
+func NewFeatureFlagProvider(azappcfg *azureappconfiguration.AzureAppConfiguration, additional ...*azureappconfiguration.AzureAppConfiguration) (*FeatureFlagProvider, error) {
+	return NewMergedFeatureFlagProvider(append([]*azureappconfiguration.AzureAppConfiguration{azappcfg}, additional...), nil)
+}
+
+// NewFeatureFlagProviderWithOptions is like NewFeatureFlagProvider, with the given options.
+func NewFeatureFlagProviderWithOptions(azappcfg *azureappconfiguration.AzureAppConfiguration, options *Options) (*FeatureFlagProvider, error) {
+	return NewMergedFeatureFlagProvider([]*azureappconfiguration.AzureAppConfiguration{azappcfg}, options)
+}
+
+// NewMergedFeatureFlagProvider creates a FeatureFlagProvider that merges the feature flags of
+// several AzureAppConfiguration clients, for example a shared organization-level store followed
+// by a service-specific store.
+//
+// Sources are listed in increasing order of precedence: when several sources define a flag with
+// the same ID, the definition from the last of them is used, unless Options.DuplicatePolicy
+// is set. Each source refreshes on its own
+// schedule; when a source refreshes, only its flags are reloaded and merged again with the flags
+// last loaded from the others. A source that fails to reload keeps its previous flags.
+//
+// Parameters:
+//   - sources: The AzureAppConfiguration clients to load feature flags from, lowest precedence first
+//   - options: Configuration options for the provider, or nil for the defaults
+//
+// Returns:
+//   - *FeatureFlagProvider: The provider serving the merged feature flags
+//   - error: An error if no source is given or if the feature flags of a source fail to load
+func NewMergedFeatureFlagProvider(sources []*azureappconfiguration.AzureAppConfiguration, options *Options) (*FeatureFlagProvider, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("at least one App Configuration source is required")
+	}
+
+	provider := newProvider(options)
+	provider.sources = make([]*source, len(sources))
+	for i, azappcfg := range sources {
+		if azappcfg == nil {
+			return nil, fmt.Errorf("App Configuration source at index %d is nil", i)
+		}
+
+		featureManagement, err := provider.loadFeatureManagement(azappcfg)
+		if err != nil {
+			return nil, err
+		}
+		provider.sources[i] = &source{azappcfg: azappcfg, featureFlags: featureManagement.FeatureFlags}
 	}
-	
-	var fc featureConfig
-	if err := azappcfg.Unmarshal(&fc, nil); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal feature management: %w", err)
+	provider.merge()
+	provider.recordRefresh(nil)
+
+	// Register refresh callbacks to update feature management on configuration changes
+	for _, src := range provider.sources {
+		provider.watch(src)
 	}
-	provider := &FeatureFlagProvider{
-		azappcfg:     azappcfg,
-		featureFlags: fc.FeatureManagement.FeatureFlags,
+	if options != nil && options.RefreshInterval > 0 {
+		_ = provider.SetRefreshInterval(options.RefreshInterval)
 	}
 
-	// Register refresh callback to update feature management on configuration changes
-	azappcfg.OnRefreshSuccess(func() {
-		var updatedFC featureConfig
-		err := azappcfg.Unmarshal(&updatedFC, nil)
+	return provider, nil
+}
+
+// newProvider creates a provider without sources from the options
+func newProvider(options *Options) *FeatureFlagProvider {
+	if options == nil {
+		options = &Options{}
+	}
+
+	degradedThreshold := options.DegradedThreshold
+	if degradedThreshold <= 0 {
+		degradedThreshold = defaultDegradedThreshold
+	}
+
+	var section []string
+	if options.Section != "" {
+		section = strings.Split(options.Section, sectionSeparator)
+	}
+
+	return &FeatureFlagProvider{
+		decodeOptions:     fm.DecodeOptions{Strict: options.StrictDecoding},
+		duplicatePolicy:   options.DuplicatePolicy,
+		logger:            fm.NewProviderLogger(options.LogLevel, options.Logger),
+		onError:           options.OnError,
+		degradedThreshold: degradedThreshold,
+		section:           section,
+		unwrapped:         options.Unwrapped,
+	}
+}
+
+// watch reloads the feature flags of a source whenever its configuration is refreshed
+func (p *FeatureFlagProvider) watch(src *source) {
+	src.azappcfg.OnRefreshSuccess(func() {
+		updated, err := p.loadFeatureManagement(src.azappcfg)
 		if err != nil {
-			log.Printf("Error unmarshalling updated configuration: %s", err)
+			p.logger.Errorf("Error unmarshalling updated configuration: %s", err)
+			p.recordRefresh(err)
 			return
 		}
-		provider.mu.Lock()
-		defer provider.mu.Unlock()
-		provider.featureFlags = updatedFC.FeatureManagement.FeatureFlags
+
+		p.mu.Lock()
+		current := slices.Contains(p.sources, src)
+		src.featureFlags = updated.FeatureFlags
+		p.mu.Unlock()
+
+		// A source replaced by a label switch may still be refreshed; its flags are no longer served
+		if current {
+			p.merge()
+			p.recordRefresh(nil)
+		}
 	})
+}
 
-	return provider, nil
+// merge combines the feature flags last loaded from each source into a new snapshot. Flags
+// keep the order of their sources, leaving out those overridden by a later source unless a
+// duplicate policy is set, in which case the policy resolves them.
+func (p *FeatureFlagProvider) merge() {
+	p.mergeMu.Lock()
+	defer p.mergeMu.Unlock()
+
+	p.mu.Lock()
+
+	var merged []fm.FeatureFlag
+	if p.duplicatePolicy != "" {
+		for _, src := range p.sources {
+			merged = append(merged, src.featureFlags...)
+		}
+	} else {
+		overridden := make(map[string]int)
+		for i, src := range p.sources {
+			for _, flag := range src.featureFlags {
+				overridden[flag.ID] = i
+			}
+		}
+
+		for i, src := range p.sources {
+			for _, flag := range src.featureFlags {
+				if overridden[flag.ID] == i {
+					merged = append(merged, flag)
+				}
+			}
+		}
+	}
+
+	p.mu.Unlock()
+
+	p.StoreSnapshot(fm.NewFeatureFlagSnapshot(merged, &fm.SnapshotOptions{
+		DuplicatePolicy: p.duplicatePolicy,
+		Version:         computeETag(merged),
+		Logger:          p.logger,
+	}))
 }
 
-func (p *FeatureFlagProvider) GetFeatureFlags() ([]fm.FeatureFlag, error) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	return p.featureFlags, nil
+// Refresh refreshes the configuration of every source of the provider, reloading the feature
+// flags of the sources that changed. Concurrent calls, including background refreshes, run one
+// after the other.
+//
+// Parameters:
+//   - ctx: The context for the operation
+//
+// Returns:
+//   - error: The first error encountered while refreshing a source, after refreshing all of them
+func (p *FeatureFlagProvider) Refresh(ctx context.Context) error {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	p.mu.Lock()
+	sources := append([]*source(nil), p.sources...)
+	p.mu.Unlock()
+
+	var firstErr error
+	for _, src := range sources {
+		// Snapshots are immutable and never need refreshing
+		if src.azappcfg == nil {
+			continue
+		}
+
+		if err := src.azappcfg.Refresh(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	p.recordRefresh(firstErr)
+
+	return firstErr
 }
 
-func (p *FeatureFlagProvider) GetFeatureFlag(id string) (fm.FeatureFlag, error) {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
-	for _, flag := range p.featureFlags {
-		if flag.ID == id {
-			return flag, nil
+// loadFeatureManagement reads the feature flags from the configured section, accepting both the
+// feature_management section and the .NET FeatureManagement section
+func (p *FeatureFlagProvider) loadFeatureManagement(azappcfg *azureappconfiguration.AzureAppConfiguration) (fm.FeatureManagement, error) {
+	var config map[string]any
+	if err := azappcfg.Unmarshal(&config, &azureappconfiguration.ConstructionOptions{Separator: sectionSeparator}); err != nil {
+		return fm.FeatureManagement{}, fmt.Errorf("failed to unmarshal feature management: %w", err)
+	}
+
+	for _, name := range p.section {
+		section, ok := config[name].(map[string]any)
+		if !ok {
+			return fm.FeatureManagement{}, fmt.Errorf("configuration section %s not found", strings.Join(p.section, sectionSeparator))
 		}
+		config = section
+	}
+
+	if p.unwrapped {
+		config = map[string]any{"feature_management": config}
+	}
+
+	featureManagement, err := fm.DecodeFeatureManagementWithOptions(config, &p.decodeOptions)
+	if err != nil {
+		return fm.FeatureManagement{}, fmt.Errorf("failed to unmarshal feature management: %w", err)
 	}
 
-	return fm.FeatureFlag{}, fmt.Errorf("feature flag with ID %s not found", id)
+	return featureManagement, nil
 }
@@ -0,0 +1,324 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azappconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/AppConfiguration-GoProvider/azureappconfiguration"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azappconfig"
+)
+
+// testSetting is a key-value of a testStore
+type testSetting struct {
+	Key         string  `json:"key"`
+	Label       *string `json:"label"`
+	Value       string  `json:"value"`
+	ContentType string  `json:"content_type"`
+}
+
+// featureFlagSetting returns the key-value of a feature flag with the given label, or no label
+// when the label is empty
+func featureFlagSetting(id, label, value string) testSetting {
+	setting := testSetting{
+		Key:         featureFlagKeyPrefix + id,
+		Value:       value,
+		ContentType: featureFlagContentType + ";charset=utf-8",
+	}
+	if label != "" {
+		setting.Label = &label
+	}
+	return setting
+}
+
+// testStore serves the key-values of a store, and of its snapshots, from the /kv endpoint. Pages
+// carry an ETag derived from their content, so refreshes see a page change only when it changes.
+type testStore struct {
+	mu        sync.Mutex
+	settings  []testSetting
+	snapshots map[string][]testSetting
+	failing   bool
+	labels    []string
+}
+
+func (s *testStore) set(settings ...testSetting) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settings = settings
+}
+
+func (s *testStore) setFailing(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = failing
+}
+
+func (s *testStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failing || r.URL.Path != "/kv" {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	items := []testSetting{}
+	if name := query.Get("snapshot"); name != "" {
+		snapshot, ok := s.snapshots[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		items = append(items, snapshot...)
+	} else {
+		key, label := query.Get("key"), query.Get("label")
+		s.labels = append(s.labels, label)
+		for _, setting := range s.settings {
+			if matchesFilter(setting.Key, key) && matchesLabel(setting.Label, label) {
+				items = append(items, setting)
+			}
+		}
+	}
+
+	body, _ := json.Marshal(map[string]any{"items": items})
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("Sync-Token", "token=1;sn=1")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.microsoft.appconfig.kvset+json")
+	w.Header().Set("ETag", etag)
+	_, _ = w.Write(body)
+}
+
+// matchesFilter reports whether a key matches a key filter, which may end with a wildcard
+func matchesFilter(key, filter string) bool {
+	if prefix, ok := strings.CutSuffix(filter, "*"); ok {
+		return strings.HasPrefix(key, prefix)
+	}
+	return key == filter
+}
+
+// matchesLabel reports whether a label matches a label filter, where "\x00" selects no label
+func matchesLabel(label *string, filter string) bool {
+	switch {
+	case filter == "*":
+		return true
+	case filter == "\x00" || filter == "":
+		return label == nil
+	default:
+		return label != nil && *label == filter
+	}
+}
+
+func newTestServer(t *testing.T, store *testStore) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(store)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testAuthentication(server *httptest.Server) azureappconfiguration.AuthenticationOptions {
+	return azureappconfiguration.AuthenticationOptions{
+		ConnectionString: "Endpoint=" + server.URL + ";Id=test;Secret=c2VjcmV0",
+	}
+}
+
+// testOptions returns the load options of a client of the test server, refreshing its feature
+// flags at the minimum interval
+func testOptions(server *httptest.Server) *azureappconfiguration.Options {
+	replicaDiscovery := false
+	return &azureappconfiguration.Options{
+		FeatureFlagOptions: azureappconfiguration.FeatureFlagOptions{
+			Enabled:        true,
+			RefreshOptions: azureappconfiguration.RefreshOptions{Enabled: true, Interval: time.Second},
+		},
+		ClientOptions: &azappconfig.ClientOptions{ClientOptions: azcore.ClientOptions{
+			Transport: server.Client(),
+			Retry:     policy.RetryOptions{MaxRetries: -1},
+		}},
+		ReplicaDiscoveryEnabled: &replicaDiscovery,
+	}
+}
+
+func loadTestConfiguration(t *testing.T, server *httptest.Server) *azureappconfiguration.AzureAppConfiguration {
+	t.Helper()
+	azappcfg, err := azureappconfiguration.Load(context.Background(), testAuthentication(server), testOptions(server))
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	return azappcfg
+}
+
+// waitForRefreshInterval waits until the clients of the test server are due to refresh
+func waitForRefreshInterval() {
+	time.Sleep(1100 * time.Millisecond)
+}
+
+// skipRefreshUnderRace skips the rest of a test refreshing an AzureAppConfiguration client when
+// the race detector is enabled: the Refresh of azureappconfiguration v1.2.0 assigns one error
+// variable from the two goroutines refreshing key values and feature flags.
+func skipRefreshUnderRace(t *testing.T) {
+	t.Helper()
+	if raceEnabled {
+		t.Skip("azureappconfiguration v1.2.0 Refresh races with itself")
+	}
+}
+
+func TestMergedFeatureFlagProvider(t *testing.T) {
+	shared := &testStore{}
+	shared.set(
+		featureFlagSetting("Beta", "", `{"id": "Beta", "enabled": false}`),
+		featureFlagSetting("Shared", "", `{"id": "Shared", "enabled": true}`),
+	)
+	service := &testStore{}
+	service.set(featureFlagSetting("Beta", "", `{"id": "Beta", "enabled": true}`))
+	sharedServer, serviceServer := newTestServer(t, shared), newTestServer(t, service)
+
+	provider, err := NewFeatureFlagProvider(loadTestConfiguration(t, sharedServer), loadTestConfiguration(t, serviceServer))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	// The last source defining a flag wins
+	if flag, err := provider.GetFeatureFlag("Beta"); err != nil || !flag.Enabled {
+		t.Errorf("Expected Beta of the service store, got %+v, %v", flag, err)
+	}
+	if flags, _ := provider.GetFeatureFlags(); len(flags) != 2 || flags[0].ID != "Shared" || flags[1].ID != "Beta" {
+		t.Errorf("Expected Shared and Beta in source order, got %+v", flags)
+	}
+
+	// A refresh reloads the source that changed and merges it again with the other
+	skipRefreshUnderRace(t)
+	refreshes := 0
+	provider.OnRefresh(func() { refreshes++ })
+	etag := provider.ETag()
+	service.set()
+	waitForRefreshInterval()
+	if err := provider.Refresh(context.Background()); err != nil {
+		t.Fatalf("Failed to refresh: %v", err)
+	}
+	if flag, err := provider.GetFeatureFlag("Beta"); err != nil || flag.Enabled {
+		t.Errorf("Expected Beta of the shared store after the service store removed it, got %+v, %v", flag, err)
+	}
+	if refreshes != 1 || provider.ETag() == etag {
+		t.Errorf("Expected one refresh notification and a new ETag, got %d and %s", refreshes, provider.ETag())
+	}
+
+	// A refresh finding no changes keeps the ETag and doesn't notify
+	etag = provider.ETag()
+	waitForRefreshInterval()
+	if err := provider.Refresh(context.Background()); err != nil {
+		t.Fatalf("Failed to refresh: %v", err)
+	}
+	if refreshes != 1 || provider.ETag() != etag {
+		t.Errorf("Expected no notification and the same ETag, got %d and %s", refreshes, provider.ETag())
+	}
+	if metadata := provider.RefreshMetadata(); metadata.LastRefreshTime.IsZero() || metadata.LastError != nil || metadata.ETag != etag {
+		t.Errorf("Unexpected refresh metadata %+v", metadata)
+	}
+}
+
+func TestMergedFeatureFlagProviderErrors(t *testing.T) {
+	if _, err := NewMergedFeatureFlagProvider(nil, nil); err == nil {
+		t.Error("Expected an error without sources")
+	}
+	if _, err := NewMergedFeatureFlagProvider([]*azureappconfiguration.AzureAppConfiguration{nil}, nil); err == nil {
+		t.Error("Expected an error for a nil source")
+	}
+}
+
+func TestDegradedHealth(t *testing.T) {
+	skipRefreshUnderRace(t)
+	store := &testStore{}
+	store.set(featureFlagSetting("Beta", "prod", `{"id": "Beta", "enabled": true}`))
+	server := newTestServer(t, store)
+
+	var reported []error
+	provider, err := NewLabeledFeatureFlagProvider(context.Background(), testAuthentication(server), testOptions(server), "prod",
+		&Options{DegradedThreshold: 2, OnError: func(err error) { reported = append(reported, err) }},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	store.setFailing(true)
+	waitForRefreshInterval()
+	for i := 1; i <= 2; i++ {
+		if err := provider.Refresh(context.Background()); err == nil {
+			t.Fatalf("Expected refresh %d to fail", i)
+		}
+		if health := provider.Health(); health.Degraded != (i == 2) || health.ConsecutiveFailures != i || health.LastError == nil {
+			t.Errorf("Unexpected health after %d failures: %+v", i, health)
+		}
+	}
+	if len(reported) != 2 {
+		t.Errorf("Expected both failures to be reported, got %v", reported)
+	}
+
+	// The provider keeps serving the last loaded flags, and recovers on its next successful load
+	if flag, err := provider.GetFeatureFlag("Beta"); err != nil || !flag.Enabled {
+		t.Errorf("Expected the last loaded flags, got %+v, %v", flag, err)
+	}
+	metadata := provider.RefreshMetadata()
+	if metadata.LastError == nil || metadata.LastErrorTime.IsZero() {
+		t.Errorf("Expected the refresh error in the metadata, got %+v", metadata)
+	}
+
+	store.setFailing(false)
+	if err := provider.SwitchLabel(context.Background(), "prod"); err != nil {
+		t.Fatalf("Failed to reload the label: %v", err)
+	}
+	if health := provider.Health(); health.Degraded || health.ConsecutiveFailures != 0 {
+		t.Errorf("Expected the provider to recover, got %+v", health)
+	}
+	if metadata := provider.RefreshMetadata(); metadata.LastError != nil || metadata.LastErrorTime.IsZero() {
+		t.Errorf("Expected the last error time to be kept after recovering, got %+v", metadata)
+	}
+	if status := provider.ProviderStatus(); !status.Loaded || status.LastRefreshError != nil {
+		t.Errorf("Unexpected provider status %+v", status)
+	}
+}
+
+func TestSection(t *testing.T) {
+	store := &testStore{}
+	store.set(
+		testSetting{Key: "myapp.flags.feature_flags.0.id", Value: "Beta"},
+		testSetting{Key: "myapp.flags.feature_flags.0.enabled", Value: "true"},
+	)
+	server := newTestServer(t, store)
+
+	options := testOptions(server)
+	options.FeatureFlagOptions = azureappconfiguration.FeatureFlagOptions{}
+	azappcfg, err := azureappconfiguration.Load(context.Background(), testAuthentication(server), options)
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	provider, err := NewFeatureFlagProviderWithOptions(azappcfg, &Options{Section: "myapp.flags", Unwrapped: true})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if flag, err := provider.GetFeatureFlag("Beta"); err != nil || !flag.Enabled {
+		t.Errorf("Expected Beta from the section, got %+v, %v", flag, err)
+	}
+
+	if _, err := NewFeatureFlagProviderWithOptions(azappcfg, &Options{Section: "other"}); err == nil {
+		t.Error("Expected an error for a missing section")
+	}
+}
@@ -4,11 +4,13 @@ go 1.23.0
 
 require github.com/Azure/AppConfiguration-GoProvider/azureappconfiguration v1.2.0
 
-require github.com/microsoft/Featuremanagement-Go/featuremanagement v1.1.0
+require (
+	github.com/Azure/azure-sdk-for-go/sdk/azcore v1.18.2
+	github.com/Azure/azure-sdk-for-go/sdk/data/azappconfig v1.2.0
+	github.com/microsoft/Featuremanagement-Go/featuremanagement v1.1.0
+)
 
 require (
-	github.com/Azure/azure-sdk-for-go/sdk/azcore v1.18.2 // indirect
-	github.com/Azure/azure-sdk-for-go/sdk/data/azappconfig v1.2.0 // indirect
 	github.com/Azure/azure-sdk-for-go/sdk/internal v1.11.2 // indirect
 	github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets v1.4.0 // indirect
 	github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/internal v1.2.0 // indirect
@@ -17,3 +19,5 @@ require (
 	golang.org/x/sync v0.16.0 // indirect
 	golang.org/x/text v0.27.0 // indirect
 )
+
+replace github.com/microsoft/Featuremanagement-Go/featuremanagement => ../..
@@ -0,0 +1,136 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azappconfig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/AppConfiguration-GoProvider/azureappconfiguration"
+)
+
+// labelLoader loads the configuration of the provider with the feature flag selectors
+// narrowed to a single label
+type labelLoader struct {
+	authentication azureappconfiguration.AuthenticationOptions
+	options        azureappconfiguration.Options
+	selectors      []azureappconfiguration.Selector
+	label          string
+}
+
+// NewLabeledFeatureFlagProvider creates a FeatureFlagProvider that loads its own configuration
+// from Azure App Configuration, selecting the feature flags with the given label. The label can
+// later be switched with SwitchLabel, for example to move a deployment from the staging ring to
+// the production ring without recreating the provider.
+//
+// The label replaces the label filter of every feature flag selector, taken from the
+// FeatureFlagSelectors of providerOptions or else from options; without feature flag selectors,
+// all feature flags with the label are selected. Feature flags are
+// always enabled. As the provider owns the configuration, call Refresh to pick up changes.
+//
+// Parameters:
+//   - ctx: The context for the initial load
+//   - authentication: Authentication options for connecting to Azure App Configuration
+//   - options: Options for loading the configuration, or nil for the defaults
+//   - label: The label of the feature flags to select
+//   - providerOptions: Configuration options for the provider, or nil for the defaults
+//
+// Returns:
+//   - *FeatureFlagProvider: The provider serving the feature flags with the label
+//   - error: An error if the configuration fails to load
+func NewLabeledFeatureFlagProvider(ctx context.Context, authentication azureappconfiguration.AuthenticationOptions, options *azureappconfiguration.Options, label string, providerOptions *Options) (*FeatureFlagProvider, error) {
+	loader := &labelLoader{authentication: authentication}
+	if options != nil {
+		loader.options = *options
+		loader.selectors = options.FeatureFlagOptions.Selectors
+	}
+	if providerOptions != nil && len(providerOptions.FeatureFlagSelectors) > 0 {
+		loader.selectors = providerOptions.FeatureFlagSelectors
+	}
+
+	azappcfg, err := loader.load(ctx, label)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := NewMergedFeatureFlagProvider([]*azureappconfiguration.AzureAppConfiguration{azappcfg}, providerOptions)
+	if err != nil {
+		return nil, err
+	}
+	loader.label = label
+	provider.loader = loader
+
+	return provider, nil
+}
+
+// SwitchLabel re-selects the feature flags of a provider created with NewLabeledFeatureFlagProvider,
+// loading the feature flags with the given label and serving them in place of the current ones.
+// If loading fails, the provider keeps serving the feature flags of the current label.
+//
+// Parameters:
+//   - ctx: The context for loading the configuration
+//   - label: The label of the feature flags to select
+//
+// Returns:
+//   - error: An error if the provider doesn't load its own configuration or if loading fails
+func (p *FeatureFlagProvider) SwitchLabel(ctx context.Context, label string) error {
+	if p.loader == nil {
+		return fmt.Errorf("switching labels requires a provider created with NewLabeledFeatureFlagProvider")
+	}
+
+	azappcfg, err := p.loader.load(ctx, label)
+	if err != nil {
+		return err
+	}
+
+	featureManagement, err := p.loadFeatureManagement(azappcfg)
+	if err != nil {
+		return err
+	}
+
+	src := &source{azappcfg: azappcfg, featureFlags: featureManagement.FeatureFlags}
+	p.mu.Lock()
+	p.sources = []*source{src}
+	p.loader.label = label
+	p.mu.Unlock()
+
+	p.watch(src)
+	p.merge()
+	p.recordRefresh(nil)
+	return nil
+}
+
+// Label returns the label of the feature flags currently selected by a provider created with
+// NewLabeledFeatureFlagProvider, or an empty string for other providers.
+func (p *FeatureFlagProvider) Label() string {
+	if p.loader == nil {
+		return ""
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.loader.label
+}
+
+func (l *labelLoader) load(ctx context.Context, label string) (*azureappconfiguration.AzureAppConfiguration, error) {
+	options := l.options
+	options.FeatureFlagOptions.Enabled = true
+
+	selectors := l.selectors
+	if len(selectors) == 0 {
+		selectors = []azureappconfiguration.Selector{{KeyFilter: "*"}}
+	}
+	labeled := make([]azureappconfiguration.Selector, len(selectors))
+	for i, selector := range selectors {
+		labeled[i] = azureappconfiguration.Selector{KeyFilter: selector.KeyFilter, LabelFilter: label}
+	}
+	options.FeatureFlagOptions.Selectors = labeled
+
+	azappcfg, err := azureappconfiguration.Load(ctx, l.authentication, &options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load feature flags with label %q: %w", label, err)
+	}
+
+	return azappcfg, nil
+}
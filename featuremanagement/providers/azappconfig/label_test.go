@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azappconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/AppConfiguration-GoProvider/azureappconfiguration"
+)
+
+func TestSwitchLabel(t *testing.T) {
+	store := &testStore{}
+	store.set(
+		featureFlagSetting("Beta", "staging", `{"id": "Beta", "enabled": true}`),
+		featureFlagSetting("Beta", "prod", `{"id": "Beta", "enabled": false}`),
+		featureFlagSetting("Gamma", "prod", `{"id": "Gamma", "enabled": true}`),
+	)
+	server := newTestServer(t, store)
+
+	provider, err := NewLabeledFeatureFlagProvider(context.Background(), testAuthentication(server), testOptions(server), "staging", nil)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if flags, _ := provider.GetFeatureFlags(); len(flags) != 1 || !flags[0].Enabled || provider.Label() != "staging" {
+		t.Fatalf("Expected the enabled Beta of staging, got %+v with label %q", flags, provider.Label())
+	}
+
+	refreshes := 0
+	provider.OnRefresh(func() { refreshes++ })
+	if err := provider.SwitchLabel(context.Background(), "prod"); err != nil {
+		t.Fatalf("Failed to switch label: %v", err)
+	}
+	if beta, _ := provider.GetFeatureFlag("Beta"); beta.Enabled || provider.Label() != "prod" {
+		t.Errorf("Expected the disabled Beta of prod, got %+v with label %q", beta, provider.Label())
+	}
+	if _, err := provider.GetFeatureFlag("Gamma"); err != nil {
+		t.Errorf("Expected Gamma of prod, got %v", err)
+	}
+	if refreshes != 1 {
+		t.Errorf("Expected the label switch to notify once, got %d", refreshes)
+	}
+
+	// A failed switch keeps the flags of the current label
+	store.setFailing(true)
+	if err := provider.SwitchLabel(context.Background(), "staging"); err == nil {
+		t.Error("Expected an error when the store is unreachable")
+	}
+	if flags, _ := provider.GetFeatureFlags(); len(flags) != 2 || provider.Label() != "prod" {
+		t.Errorf("Expected the flags of prod to be kept, got %+v with label %q", flags, provider.Label())
+	}
+}
+
+func TestSwitchLabelSelectors(t *testing.T) {
+	store := &testStore{}
+	store.set(
+		featureFlagSetting("Beta", "prod", `{"id": "Beta", "enabled": true}`),
+		featureFlagSetting("Gamma", "prod", `{"id": "Gamma", "enabled": true}`),
+	)
+	server := newTestServer(t, store)
+
+	// The label replaces the label filter of the provider's feature flag selectors
+	provider, err := NewLabeledFeatureFlagProvider(context.Background(), testAuthentication(server), testOptions(server), "prod", &Options{
+		FeatureFlagSelectors: []azureappconfiguration.Selector{{KeyFilter: "B*", LabelFilter: "other"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if flags, _ := provider.GetFeatureFlags(); len(flags) != 1 || flags[0].ID != "Beta" {
+		t.Errorf("Expected only the selected Beta, got %+v", flags)
+	}
+
+	other, err := NewFeatureFlagProvider(loadTestConfiguration(t, server))
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	if err := other.SwitchLabel(context.Background(), "prod"); err == nil || other.Label() != "" {
+		t.Error("Expected switching labels to require a labeled provider")
+	}
+}
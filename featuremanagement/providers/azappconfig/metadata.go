@@ -0,0 +1,139 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azappconfig
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// RefreshMetadata describes the freshness of the feature flags served by a provider, so that
+// applications can display staleness and alert when flags haven't been updated for a while.
+//
+// The provider observes refreshes made through its Refresh method, and refreshes of the
+// AzureAppConfiguration clients that changed the configuration. A refresh made directly on a
+// client that finds no changes isn't observed.
+type RefreshMetadata struct {
+	// LastRefreshTime is the time of the last successful load or refresh
+	LastRefreshTime time.Time
+	// LastError is the error of the most recent failed refresh, or nil if the most recent
+	// refresh succeeded
+	LastError error
+	// LastErrorTime is the time of the most recent failed refresh, kept after later successes
+	LastErrorTime time.Time
+	// ETag identifies the feature flags currently served; it changes whenever they change
+	ETag string
+}
+
+// refreshStatus records the outcome of the refreshes of a provider
+type refreshStatus struct {
+	lastRefreshTime     time.Time
+	lastError           error
+	lastErrorTime       time.Time
+	consecutiveFailures int
+}
+
+// RefreshMetadata returns the time of the last successful refresh, the last refresh error and
+// the ETag of the feature flags currently served.
+func (p *FeatureFlagProvider) RefreshMetadata() RefreshMetadata {
+	p.mu.Lock()
+	status := p.status
+	p.mu.Unlock()
+
+	return RefreshMetadata{
+		LastRefreshTime: status.lastRefreshTime,
+		LastError:       status.lastError,
+		LastErrorTime:   status.lastErrorTime,
+		ETag:            p.ETag(),
+	}
+}
+
+// ProviderStatus reports the outcome of the last refresh to the feature manager, which surfaces it
+// through FeatureManager.Ready and FeatureManager.Stats. The provider is loaded once constructed.
+func (p *FeatureFlagProvider) ProviderStatus() fm.ProviderStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return fm.ProviderStatus{
+		Loaded:           true,
+		LastRefreshTime:  p.status.lastRefreshTime,
+		LastRefreshError: p.status.lastError,
+	}
+}
+
+// ETag returns an opaque identifier of the feature flags currently served, derived from their
+// content. It changes whenever a refresh or label switch changes the served feature flags.
+func (p *FeatureFlagProvider) ETag() string {
+	return p.Snapshot().Version()
+}
+
+// defaultDegradedThreshold is the number of consecutive failed refreshes after which a provider is degraded
+const defaultDegradedThreshold = 3
+
+// Health describes whether a provider is keeping its feature flags up to date.
+type Health struct {
+	// Degraded reports that the most recent refreshes failed at least DegradedThreshold times in
+	// a row; the provider keeps serving the feature flags of the last successful refresh
+	Degraded bool
+	// ConsecutiveFailures is the number of refreshes that failed since the last successful one
+	ConsecutiveFailures int
+	// LastError is the error of the most recent failed refresh, or nil if it succeeded
+	LastError error
+	// LastRefreshTime is the time of the last successful load or refresh
+	LastRefreshTime time.Time
+}
+
+// Health reports whether the provider is degraded, for use by health checks. A provider
+// recovers from a degraded state on its next successful refresh.
+func (p *FeatureFlagProvider) Health() Health {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return Health{
+		Degraded:            p.status.consecutiveFailures >= p.degradedThreshold,
+		ConsecutiveFailures: p.status.consecutiveFailures,
+		LastError:           p.status.lastError,
+		LastRefreshTime:     p.status.lastRefreshTime,
+	}
+}
+
+// recordRefresh records the outcome of a load or refresh, reporting failures to the error callback
+func (p *FeatureFlagProvider) recordRefresh(err error) {
+	p.mu.Lock()
+	now := time.Now()
+	if err == nil {
+		p.status.lastRefreshTime = now
+		p.status.lastError = nil
+		p.status.consecutiveFailures = 0
+		p.mu.Unlock()
+		return
+	}
+
+	p.status.lastError = err
+	p.status.lastErrorTime = now
+	p.status.consecutiveFailures++
+	if p.status.consecutiveFailures == p.degradedThreshold {
+		p.logger.Errorf("Feature flag provider is degraded after %d consecutive refresh failures, serving the last loaded feature flags: %s", p.status.consecutiveFailures, err)
+	}
+	p.mu.Unlock()
+
+	if p.onError != nil {
+		p.onError(err)
+	}
+}
+
+// computeETag hashes the JSON encoding of the feature flags
+func computeETag(featureFlags []fm.FeatureFlag) string {
+	data, err := json.Marshal(featureFlags)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
@@ -0,0 +1,9 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+//go:build !race
+
+package azappconfig
+
+// raceEnabled reports whether the tests run with the race detector
+const raceEnabled = false
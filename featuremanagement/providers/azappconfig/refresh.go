@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azappconfig
+
+import (
+	"context"
+	"time"
+)
+
+// refreshLoop refreshes the sources of a provider in the background
+type refreshLoop struct {
+	cancel     context.CancelFunc
+	done       chan struct{}
+	reschedule chan struct{}
+}
+
+// RefreshInterval returns the interval between background refreshes. Zero or a negative interval
+// means the provider doesn't refresh in the background.
+func (p *FeatureFlagProvider) RefreshInterval() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.refreshInterval
+}
+
+// SetRefreshInterval changes the interval between background refreshes, scheduling the next
+// refresh one interval from now. The first positive interval starts refreshing in the background;
+// a negative interval pauses it. Each refresh is still subject to the refresh interval of the
+// AzureAppConfiguration clients, which skip refreshes requested before their own interval elapses.
+//
+// Parameters:
+//   - interval: The new refresh interval
+//
+// Returns:
+//   - error: Always nil
+func (p *FeatureFlagProvider) SetRefreshInterval(interval time.Duration) error {
+	p.mu.Lock()
+	p.refreshInterval = interval
+	loop := p.refreshLoop
+	if loop == nil && interval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		loop = &refreshLoop{cancel: cancel, done: make(chan struct{}), reschedule: make(chan struct{}, 1)}
+		p.refreshLoop = loop
+		go p.runRefreshLoop(ctx, loop)
+	}
+	p.mu.Unlock()
+
+	if loop != nil {
+		select {
+		case loop.reschedule <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Close stops refreshing in the background. The provider keeps serving the last loaded flags.
+//
+// Returns:
+//   - error: Always nil
+func (p *FeatureFlagProvider) Close() error {
+	p.mu.Lock()
+	loop := p.refreshLoop
+	p.refreshLoop = nil
+	p.refreshInterval = 0
+	p.mu.Unlock()
+
+	if loop != nil {
+		loop.cancel()
+		<-loop.done
+	}
+	return nil
+}
+
+func (p *FeatureFlagProvider) runRefreshLoop(ctx context.Context, loop *refreshLoop) {
+	defer close(loop.done)
+	for {
+		// A nil channel never fires, leaving refresh paused until the interval changes
+		var tick <-chan time.Time
+		if interval := p.RefreshInterval(); interval > 0 {
+			tick = time.After(interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-loop.reschedule:
+		case <-tick:
+			if err := p.Refresh(ctx); err != nil && ctx.Err() == nil {
+				p.logger.Errorf("Error refreshing feature flags: %s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azappconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/AppConfiguration-GoProvider/azureappconfiguration"
+)
+
+func TestRefreshInterval(t *testing.T) {
+	skipRefreshUnderRace(t)
+	store := &testStore{}
+	store.set(featureFlagSetting("Beta", "", `{"id": "Beta", "enabled": false}`))
+	server := newTestServer(t, store)
+
+	provider, err := NewMergedFeatureFlagProvider(
+		[]*azureappconfiguration.AzureAppConfiguration{loadTestConfiguration(t, server)},
+		&Options{RefreshInterval: 20 * time.Millisecond},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+	if interval := provider.RefreshInterval(); interval != 20*time.Millisecond {
+		t.Errorf("Expected the configured interval, got %v", interval)
+	}
+
+	// The provider refreshes in the background once the client is due to refresh
+	store.set(featureFlagSetting("Beta", "", `{"id": "Beta", "enabled": true}`))
+	deadline := time.Now().Add(5 * time.Second)
+	for flag, _ := provider.GetFeatureFlag("Beta"); !flag.Enabled; flag, _ = provider.GetFeatureFlag("Beta") {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the background refresh")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// A negative interval pauses refreshing
+	if err := provider.SetRefreshInterval(-1); err != nil {
+		t.Fatalf("Failed to pause refreshing: %v", err)
+	}
+	store.set(featureFlagSetting("Beta", "", `{"id": "Beta", "enabled": false}`))
+	waitForRefreshInterval()
+	time.Sleep(100 * time.Millisecond)
+	if flag, _ := provider.GetFeatureFlag("Beta"); !flag.Enabled {
+		t.Error("Expected no refresh while paused")
+	}
+
+	provider.Close()
+	if interval := provider.RefreshInterval(); interval != 0 {
+		t.Errorf("Expected Close to stop refreshing, got interval %v", interval)
+	}
+}
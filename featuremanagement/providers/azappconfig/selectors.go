@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azappconfig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/AppConfiguration-GoProvider/azureappconfiguration"
+)
+
+// featureFlagKeyPrefix is the key prefix of feature flags in Azure App Configuration. No
+// key-value has this exact key, so a key-value selector for it loads no key-values.
+const featureFlagKeyPrefix = ".appconfig.featureflag/"
+
+// NewFeatureFlagOnlyProvider creates a FeatureFlagProvider that loads its own configuration from
+// Azure App Configuration, containing only the feature flags selected by the FeatureFlagSelectors
+// of options, independent of the configuration loaded by the host application. Key-values aren't
+// loaded. Without selectors, all feature flags with no label are loaded.
+//
+// Refresh is enabled for the selected feature flags with the default interval; call Refresh to
+// pick up changes.
+//
+// Parameters:
+//   - ctx: The context for the initial load
+//   - authentication: Authentication options for connecting to Azure App Configuration
+//   - options: Configuration options for the provider, or nil for the defaults
+//
+// Returns:
+//   - *FeatureFlagProvider: The provider serving the selected feature flags
+//   - error: An error if the configuration fails to load
+func NewFeatureFlagOnlyProvider(ctx context.Context, authentication azureappconfiguration.AuthenticationOptions, options *Options) (*FeatureFlagProvider, error) {
+	var selectors []azureappconfiguration.Selector
+	if options != nil {
+		selectors = options.FeatureFlagSelectors
+	}
+
+	azappcfg, err := azureappconfiguration.Load(ctx, authentication, &azureappconfiguration.Options{
+		Selectors: []azureappconfiguration.Selector{{KeyFilter: featureFlagKeyPrefix}},
+		FeatureFlagOptions: azureappconfiguration.FeatureFlagOptions{
+			Enabled:        true,
+			Selectors:      selectors,
+			RefreshOptions: azureappconfiguration.RefreshOptions{Enabled: true},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+
+	return NewMergedFeatureFlagProvider([]*azureappconfiguration.AzureAppConfiguration{azappcfg}, options)
+}
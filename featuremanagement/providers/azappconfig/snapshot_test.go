@@ -0,0 +1,59 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azappconfig
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azappconfig"
+)
+
+func TestSnapshotFeatureFlagProvider(t *testing.T) {
+	store := &testStore{snapshots: map[string][]testSetting{
+		"release-1": {
+			featureFlagSetting("Beta", "", `{"id": "Beta", "enabled": false}`),
+			featureFlagSetting("Null", "", `null`),
+			{Key: featureFlagKeyPrefix + "Plain", Value: `{"id": "Plain", "enabled": true}`, ContentType: "application/json"},
+		},
+		"release-2": {featureFlagSetting("Beta", "", `{"id": "Beta", "enabled": true}`)},
+	}}
+	server := newTestServer(t, store)
+	client, err := azappconfig.NewClientFromConnectionString(testAuthentication(server).ConnectionString, &azappconfig.ClientOptions{
+		ClientOptions: azcore.ClientOptions{Transport: server.Client(), Retry: policy.RetryOptions{MaxRetries: -1}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if _, err := NewSnapshotFeatureFlagProvider(context.Background(), client, nil); err == nil {
+		t.Error("Expected an error without a snapshot name")
+	}
+
+	provider, err := NewSnapshotFeatureFlagProvider(context.Background(), client, &Options{SnapshotName: "release-1"})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	// Settings that aren't feature flags, or hold no feature flag, are left out
+	if flags, _ := provider.GetFeatureFlags(); len(flags) != 1 || flags[0].ID != "Beta" || flags[0].Enabled {
+		t.Errorf("Expected the disabled Beta of release-1, got %+v", flags)
+	}
+
+	if err := provider.SwitchSnapshot(context.Background(), "release-2"); err != nil {
+		t.Fatalf("Failed to switch snapshot: %v", err)
+	}
+	if flag, _ := provider.GetFeatureFlag("Beta"); !flag.Enabled || provider.SnapshotName() != "release-2" {
+		t.Errorf("Expected the enabled Beta of release-2, got %+v from %s", flag, provider.SnapshotName())
+	}
+
+	// A snapshot that fails to load keeps the current one
+	if err := provider.SwitchSnapshot(context.Background(), "missing"); err == nil {
+		t.Error("Expected an error for a missing snapshot")
+	}
+	if provider.SnapshotName() != "release-2" || provider.Health().ConsecutiveFailures != 1 {
+		t.Errorf("Expected release-2 to be kept after a failed switch, got %s and %+v", provider.SnapshotName(), provider.Health())
+	}
+}
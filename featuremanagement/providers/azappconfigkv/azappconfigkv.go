@@ -0,0 +1,253 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package azappconfigkv provides a feature flag provider that reads the feature flag key-values
+// of an Azure App Configuration store directly with the azappconfig data-plane client, for
+// applications that need feature flags without the full App Configuration provider.
+package azappconfigkv
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azappconfig"
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+const (
+	// featureFlagKeyPrefix is the key prefix of feature flags in Azure App Configuration
+	featureFlagKeyPrefix = ".appconfig.featureflag/"
+	// featureFlagContentType is the content type of feature flag key-values
+	featureFlagContentType = "application/vnd.microsoft.appconfig.ff+json"
+
+	defaultPollInterval = 30 * time.Second
+)
+
+// Options configures the FeatureFlagProvider.
+type Options struct {
+	// KeyFilter selects the feature flags to load by ID, and may end with a "*" wildcard.
+	// Defaults to all feature flags.
+	KeyFilter string
+
+	// Label selects the label of the feature flags to load. Defaults to no label.
+	Label string
+
+	// PollInterval is how often the feature flags are reloaded. Defaults to 30 seconds.
+	// A negative interval disables polling; call Refresh to reload. It can be changed at
+	// runtime with SetRefreshInterval.
+	PollInterval time.Duration
+
+	// StrictDecoding rejects feature flags with unknown fields or values of the wrong type,
+	// instead of ignoring or converting them.
+	StrictDecoding bool
+
+	// DuplicatePolicy decides which definition of a feature flag defined more than once is
+	// served, in the order in which the store lists the key-values. Defaults to fm.DuplicateFirstWins. With fm.DuplicateError,
+	// every definition of the feature is logged and left out, so it evaluates as not found.
+	DuplicatePolicy fm.DuplicatePolicy
+
+	// LogLevel is the minimum severity of the messages logged by the provider, such as rejected
+	// key-values and failed refreshes. Defaults to fm.LogLevelWarn; fm.LogLevelSilent disables
+	// logging.
+	LogLevel fm.LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+}
+
+// FeatureFlagProvider serves the feature flags of an Azure App Configuration store. OnRefresh
+// callbacks are called after each reload of the key-values, on the goroutine calling Refresh or
+// on the poll goroutine.
+type FeatureFlagProvider struct {
+	fm.SnapshotProvider
+
+	client          *azappconfig.Client
+	selector        azappconfig.SettingSelector
+	decodeOptions   fm.DecodeOptions
+	duplicatePolicy fm.DuplicatePolicy
+	logger          *fm.ProviderLogger
+
+	// refreshMu serializes reloads, so that they are applied in order
+	refreshMu sync.Mutex
+	cancel    context.CancelFunc
+	done      chan struct{}
+
+	// intervalMu guards the poll interval; reschedule wakes the poll loop when it changes
+	intervalMu   sync.Mutex
+	pollInterval time.Duration
+	reschedule   chan struct{}
+}
+
+// NewFeatureFlagProvider loads the feature flag key-values of a store and starts polling them.
+// Call Close to stop polling.
+//
+// Parameters:
+//   - ctx: The context of the initial load; it doesn't bound the lifetime of the provider
+//   - client: The App Configuration client, created with azappconfig.NewClient or
+//     azappconfig.NewClientFromConnectionString
+//   - options: The provider options, or nil for the defaults
+//
+// Returns:
+//   - *FeatureFlagProvider: The provider
+//   - error: An error if the feature flags can't be loaded
+func NewFeatureFlagProvider(ctx context.Context, client *azappconfig.Client, options *Options) (*FeatureFlagProvider, error) {
+	if client == nil {
+		return nil, fmt.Errorf("an App Configuration client is required")
+	}
+	if options == nil {
+		options = &Options{}
+	}
+
+	keyFilter := options.KeyFilter
+	if keyFilter == "" {
+		keyFilter = "*"
+	}
+	keyFilter = featureFlagKeyPrefix + keyFilter
+	label := options.Label
+	if label == "" {
+		// "\0" selects the key-values without a label
+		label = "\x00"
+	}
+
+	provider := &FeatureFlagProvider{
+		client:          client,
+		selector:        azappconfig.SettingSelector{KeyFilter: &keyFilter, LabelFilter: &label},
+		decodeOptions:   fm.DecodeOptions{Strict: options.StrictDecoding},
+		duplicatePolicy: options.DuplicatePolicy,
+		logger:          fm.NewProviderLogger(options.LogLevel, options.Logger),
+		pollInterval:    options.PollInterval,
+		done:            make(chan struct{}),
+		reschedule:      make(chan struct{}, 1),
+	}
+	if provider.pollInterval == 0 {
+		provider.pollInterval = defaultPollInterval
+	}
+
+	if err := provider.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	pollCtx, cancel := context.WithCancel(context.Background())
+	provider.cancel = cancel
+	go provider.poll(pollCtx)
+
+	return provider, nil
+}
+
+// Refresh reloads the feature flag key-values. A refresh that fails keeps the previously
+// loaded flags.
+//
+// Parameters:
+//   - ctx: The context of the requests
+//
+// Returns:
+//   - error: An error if the key-values can't be listed
+func (p *FeatureFlagProvider) Refresh(ctx context.Context) error {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	var featureFlags []fm.FeatureFlag
+	pager := p.client.NewListSettingsPager(p.selector, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			err = fmt.Errorf("failed to list feature flags: %w", err)
+			p.RecordRefresh(err)
+			return err
+		}
+
+		for _, setting := range page.Settings {
+			if setting.Key == nil || setting.Value == nil || !isFeatureFlag(setting) {
+				continue
+			}
+
+			flag, err := fm.DecodeFeatureFlag([]byte(*setting.Value), strings.TrimPrefix(*setting.Key, featureFlagKeyPrefix), &p.decodeOptions)
+			if err != nil {
+				p.logger.Warnf("Ignoring invalid feature flag setting %s: %s", *setting.Key, err)
+				continue
+			}
+			featureFlags = append(featureFlags, flag)
+		}
+	}
+
+	p.StoreSnapshot(fm.NewFeatureFlagSnapshot(featureFlags, &fm.SnapshotOptions{
+		DuplicatePolicy: p.duplicatePolicy,
+		Logger:          p.logger,
+	}))
+	return nil
+}
+
+// RefreshInterval returns the poll interval; a negative interval means polling is paused.
+func (p *FeatureFlagProvider) RefreshInterval() time.Duration {
+	p.intervalMu.Lock()
+	defer p.intervalMu.Unlock()
+	return p.pollInterval
+}
+
+// SetRefreshInterval changes the poll interval, scheduling the next reload one interval from now.
+// A negative interval pauses polling and zero restores the default interval.
+//
+// Parameters:
+//   - interval: The new poll interval
+//
+// Returns:
+//   - error: Always nil
+func (p *FeatureFlagProvider) SetRefreshInterval(interval time.Duration) error {
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	p.intervalMu.Lock()
+	p.pollInterval = interval
+	p.intervalMu.Unlock()
+
+	select {
+	case p.reschedule <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Close stops polling. The provider keeps serving the last loaded flags.
+//
+// Returns:
+//   - error: Always nil
+func (p *FeatureFlagProvider) Close() error {
+	p.cancel()
+	<-p.done
+	return nil
+}
+
+func (p *FeatureFlagProvider) poll(ctx context.Context) {
+	defer close(p.done)
+	for {
+		// A nil channel never fires, leaving polling paused until the interval changes
+		var tick <-chan time.Time
+		if interval := p.RefreshInterval(); interval > 0 {
+			tick = time.After(interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.reschedule:
+		case <-tick:
+			if err := p.Refresh(ctx); err != nil && ctx.Err() == nil {
+				p.logger.Errorf("Error refreshing feature flags: %s", err)
+			}
+		}
+	}
+}
+
+func isFeatureFlag(setting azappconfig.Setting) bool {
+	if setting.ContentType == nil {
+		return false
+	}
+
+	contentType, _, _ := strings.Cut(*setting.ContentType, ";")
+	return strings.EqualFold(strings.TrimSpace(contentType), featureFlagContentType)
+}
@@ -0,0 +1,141 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package azappconfigkv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/data/azappconfig"
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// testStore serves the key-values of a store from the /kv endpoint
+type testStore struct {
+	mu       sync.Mutex
+	settings []map[string]any
+	queries  []string
+}
+
+func (s *testStore) set(settings ...map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.settings = settings
+}
+
+func (s *testStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queries = append(s.queries, r.URL.Query().Get("key")+"|"+r.URL.Query().Get("label"))
+	w.Header().Set("Content-Type", "application/vnd.microsoft.appconfig.kvset+json")
+	w.Header().Set("Sync-Token", "token=1;sn=1")
+	_ = json.NewEncoder(w).Encode(map[string]any{"items": s.settings})
+}
+
+func featureFlagSetting(id string, value string) map[string]any {
+	return map[string]any{
+		"key":          featureFlagKeyPrefix + id,
+		"value":        value,
+		"content_type": featureFlagContentType + ";charset=utf-8",
+	}
+}
+
+func newTestClient(t *testing.T, server *httptest.Server) *azappconfig.Client {
+	client, err := azappconfig.NewClientFromConnectionString(
+		"Endpoint="+server.URL+";Id=test;Secret=c2VjcmV0",
+		&azappconfig.ClientOptions{ClientOptions: azcore.ClientOptions{
+			Transport: server.Client(),
+			Retry:     policy.RetryOptions{MaxRetries: -1},
+		}},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestFeatureFlagProvider(t *testing.T) {
+	store := &testStore{}
+	store.set(
+		featureFlagSetting("Beta", `{"id": "Beta", "enabled": true}`),
+		featureFlagSetting("Gamma", `{"enabled": false}`),
+		featureFlagSetting("Broken", `{"id": `),
+		featureFlagSetting("Null", `null`),
+		map[string]any{"key": featureFlagKeyPrefix + "Plain", "value": `{"enabled": true}`, "content_type": "application/json"},
+	)
+	server := httptest.NewTLSServer(store)
+	defer server.Close()
+
+	provider, err := NewFeatureFlagProvider(context.Background(), newTestClient(t, server), &Options{KeyFilter: "B*", Label: "prod", PollInterval: -1})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	store.mu.Lock()
+	query := store.queries[0]
+	store.mu.Unlock()
+	if query != featureFlagKeyPrefix+"B*|prod" {
+		t.Errorf("Expected the selector to be sent, got %s", query)
+	}
+
+	if flag, err := provider.GetFeatureFlag("Beta"); err != nil || !flag.Enabled {
+		t.Errorf("Expected Beta to be enabled, got %+v, %v", flag, err)
+	}
+	if _, err := provider.GetFeatureFlag("Gamma"); err != nil {
+		t.Errorf("Expected a flag without an ID to take it from its key, got %v", err)
+	}
+	if flags, _ := provider.GetFeatureFlags(); len(flags) != 2 {
+		t.Errorf("Expected 2 feature flags, got %d", len(flags))
+	}
+
+	manager, err := fm.NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	var changes []fm.FeatureChange
+	manager.OnFeatureChanged("Beta", func(change fm.FeatureChange) {
+		changes = append(changes, change)
+	})
+
+	store.set(featureFlagSetting("Beta", `{"id": "Beta", "enabled": false}`))
+	if err := provider.Refresh(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if flag, _ := provider.GetFeatureFlag("Beta"); flag.Enabled {
+		t.Error("Expected Beta to be disabled after refresh")
+	}
+	if len(changes) != 1 || changes[0].Current == nil || changes[0].Current.Enabled {
+		t.Errorf("Expected the refresh to be reported to OnFeatureChanged callbacks, got %+v", changes)
+	}
+	if _, err := provider.GetFeatureFlag("Gamma"); err == nil {
+		t.Error("Expected Gamma to be removed after refresh")
+	}
+}
+
+func TestRefreshFailureKeepsFlags(t *testing.T) {
+	store := &testStore{}
+	store.set(featureFlagSetting("Beta", `{"id": "Beta", "enabled": true}`))
+	server := httptest.NewTLSServer(store)
+
+	provider, err := NewFeatureFlagProvider(context.Background(), newTestClient(t, server), &Options{PollInterval: -1})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	server.Close()
+	if err := provider.Refresh(context.Background()); err == nil {
+		t.Error("Expected an error when the store is unreachable")
+	}
+	if flag, err := provider.GetFeatureFlag("Beta"); err != nil || !flag.Enabled {
+		t.Errorf("Expected the previously loaded flags, got %+v, %v", flag, err)
+	}
+}
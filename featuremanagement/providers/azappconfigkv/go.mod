@@ -0,0 +1,19 @@
+module github.com/microsoft/Featuremanagement-Go/featuremanagement/providers/azappconfigkv
+
+go 1.23.0
+
+require (
+	github.com/Azure/azure-sdk-for-go/sdk/azcore v1.18.2
+	github.com/Azure/azure-sdk-for-go/sdk/data/azappconfig v1.2.0
+	github.com/microsoft/Featuremanagement-Go/featuremanagement v1.1.0
+)
+
+require (
+	github.com/Azure/azure-sdk-for-go/sdk/internal v1.11.2 // indirect
+	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
+	golang.org/x/net v0.42.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
+
+replace github.com/microsoft/Featuremanagement-Go/featuremanagement => ../..
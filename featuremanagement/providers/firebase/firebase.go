@@ -0,0 +1,300 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package firebase adapts Firebase Remote Config server templates to feature flags, so backends
+// that share flags with mobile clients through Firebase can evaluate them with a FeatureManager.
+//
+// Each Remote Config parameter becomes a feature flag with the same ID. The default value becomes
+// the "default" variant, and each conditional value becomes a variant named after its condition,
+// allocated as follows:
+//   - percent conditions become percentile allocations
+//   - custom signal conditions matching the user signal exactly become user allocations
+//   - custom signal conditions matching the group signal exactly become group allocations
+//   - the condition "true" becomes a percentile allocation of every user
+//
+// Conditions combining several of these, and other custom signal operators, can't be expressed
+// as allocations and are skipped with a log message, so their users get the default value.
+// Boolean parameters are enabled or disabled by their assigned value.
+//
+// Pass the Firebase randomization ID as TargetingContext.UserID. Percent conditions keep their
+// share of users, but this SDK buckets users differently from Firebase, so a given user may
+// fall into a different range than on a mobile client.
+package firebase
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// DefaultVariant is the name of the variant holding the default value of a parameter
+const DefaultVariant = "default"
+
+// Options configures the conversion of Remote Config templates.
+type Options struct {
+	// UserSignal is the custom signal key whose exact matches become user allocations.
+	// Defaults to "userId".
+	UserSignal string
+
+	// GroupSignal is the custom signal key whose exact matches become group allocations.
+	// Defaults to "group".
+	GroupSignal string
+
+	// LogLevel is the minimum severity of the messages logged by the provider, such as parameters
+	// that can't be converted. Defaults to fm.LogLevelWarn; fm.LogLevelSilent disables logging.
+	LogLevel fm.LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+}
+
+// FeatureFlagProvider serves the feature flags converted from a Remote Config server template.
+// Callbacks registered with OnRefresh are called by Update once it has swapped in the new flags.
+type FeatureFlagProvider struct {
+	fm.SnapshotProvider
+
+	options Options
+	logger  *fm.ProviderLogger
+}
+
+// NewFeatureFlagProvider creates a provider serving the feature flags of a server template.
+// Call Update with newer templates, for example from a loop polling the Remote Config API.
+//
+// Parameters:
+//   - template: The JSON server template, as returned by ServerTemplate.ToJSON in the Firebase Admin SDK
+//   - options: The conversion options, or nil for the defaults
+//
+// Returns:
+//   - *FeatureFlagProvider: The provider
+//   - error: An error if the template can't be parsed
+func NewFeatureFlagProvider(template []byte, options *Options) (*FeatureFlagProvider, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	provider := &FeatureFlagProvider{options: *options, logger: fm.NewProviderLogger(options.LogLevel, options.Logger)}
+	if err := provider.Update(template); err != nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+// Update replaces the served feature flags with those of a newer server template.
+// A template that can't be parsed leaves the current flags in place.
+//
+// Parameters:
+//   - template: The JSON server template
+//
+// Returns:
+//   - error: An error if the template can't be parsed
+func (p *FeatureFlagProvider) Update(template []byte) error {
+	var parsed serverTemplate
+	if err := json.Unmarshal(template, &parsed); err != nil {
+		err = fmt.Errorf("failed to parse Remote Config template: %w", err)
+		p.RecordRefresh(err)
+		return err
+	}
+
+	p.StoreSnapshot(fm.NewFeatureFlagSnapshot(convertTemplate(parsed, p.options, p.logger), &fm.SnapshotOptions{
+		Version: parsed.ETag,
+		Logger:  p.logger,
+	}))
+	return nil
+}
+
+// ETag returns the ETag of the most recently loaded template, to skip updates to an unchanged template.
+func (p *FeatureFlagProvider) ETag() string {
+	return p.Snapshot().Version()
+}
+
+// ConvertTemplate converts the parameters of a Remote Config server template to feature flags,
+// sorted by ID.
+//
+// Parameters:
+//   - template: The JSON server template
+//   - options: The conversion options, or nil for the defaults
+//
+// Returns:
+//   - []fm.FeatureFlag: A feature flag for each parameter
+//   - error: An error if the template can't be parsed
+func ConvertTemplate(template []byte, options *Options) ([]fm.FeatureFlag, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	var parsed serverTemplate
+	if err := json.Unmarshal(template, &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse Remote Config template: %w", err)
+	}
+
+	return convertTemplate(parsed, *options, fm.NewProviderLogger(options.LogLevel, options.Logger)), nil
+}
+
+func convertTemplate(template serverTemplate, options Options, logger *fm.ProviderLogger) []fm.FeatureFlag {
+	if options.UserSignal == "" {
+		options.UserSignal = "userId"
+	}
+	if options.GroupSignal == "" {
+		options.GroupSignal = "group"
+	}
+
+	names := make([]string, 0, len(template.Parameters))
+	for name := range template.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	flags := make([]fm.FeatureFlag, 0, len(names))
+	for _, name := range names {
+		flags = append(flags, convertParameter(name, template.Parameters[name], template.Conditions, options, logger))
+	}
+
+	return flags
+}
+
+// convertParameter converts a parameter, allocating its conditional values in the priority
+// order of the template's conditions
+func convertParameter(name string, param parameter, conditions []namedCondition, options Options, logger *fm.ProviderLogger) fm.FeatureFlag {
+	flag := fm.FeatureFlag{
+		ID:          name,
+		Description: param.Description,
+		Enabled:     true,
+		Variants:    []fm.VariantDefinition{convertValue(logger, name, DefaultVariant, param.DefaultValue, param.ValueType)},
+		Allocation:  &fm.VariantAllocation{DefaultWhenEnabled: DefaultVariant},
+	}
+
+	for _, condition := range conditions {
+		value, ok := param.ConditionalValues[condition.Name]
+		if !ok {
+			continue
+		}
+
+		if !allocateCondition(flag.Allocation, condition, options) {
+			logger.Warnf("Skipping conditional value of parameter %s: condition %s can't be converted to an allocation", name, condition.Name)
+			continue
+		}
+		flag.Variants = append(flag.Variants, convertValue(logger, name, condition.Name, value, param.ValueType))
+	}
+
+	return flag
+}
+
+// convertValue converts a parameter value to a variant, decoding it according to the value type
+func convertValue(logger *fm.ProviderLogger, parameterName string, variantName string, value parameterValue, valueType string) fm.VariantDefinition {
+	variant := fm.VariantDefinition{Name: variantName}
+	if value.Value == nil {
+		return variant
+	}
+
+	raw := *value.Value
+	variant.ConfigurationValue = raw
+	switch valueType {
+	case valueTypeBoolean:
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			logger.Warnf("Invalid boolean value %q of parameter %s", raw, parameterName)
+			break
+		}
+		variant.ConfigurationValue = enabled
+		variant.StatusOverride = fm.StatusOverrideDisabled
+		if enabled {
+			variant.StatusOverride = fm.StatusOverrideEnabled
+		}
+	case valueTypeNumber:
+		if number, err := strconv.ParseFloat(raw, 64); err == nil {
+			variant.ConfigurationValue = number
+		}
+	case valueTypeJSON:
+		var decoded any
+		if err := json.Unmarshal([]byte(raw), &decoded); err == nil {
+			variant.ConfigurationValue = decoded
+		}
+	}
+
+	return variant
+}
+
+// allocateCondition adds the allocation of a condition's variant, reporting whether the
+// condition could be expressed as an allocation
+func allocateCondition(allocation *fm.VariantAllocation, condition namedCondition, options Options) bool {
+	leaf := singleCondition(condition.Condition)
+	if leaf == nil {
+		return false
+	}
+
+	switch {
+	case leaf.Boolean != nil:
+		if *leaf.Boolean {
+			allocation.Percentile = append(allocation.Percentile, fm.PercentileAllocation{Variant: condition.Name, From: 0, To: 100})
+		}
+		return true
+	case leaf.Percent != nil:
+		from, to, ok := percentRange(leaf.Percent)
+		if !ok {
+			return false
+		}
+		if allocation.Seed == "" && leaf.Percent.Seed != "" {
+			allocation.Seed = leaf.Percent.Seed
+		}
+		allocation.Percentile = append(allocation.Percentile, fm.PercentileAllocation{Variant: condition.Name, From: from, To: to})
+		return true
+	case leaf.CustomSignal != nil && leaf.CustomSignal.CustomSignalOperator == stringExactlyMatches:
+		switch leaf.CustomSignal.CustomSignalKey {
+		case options.UserSignal:
+			allocation.User = append(allocation.User, fm.UserAllocation{Variant: condition.Name, Users: leaf.CustomSignal.TargetCustomSignalValues})
+			return true
+		case options.GroupSignal:
+			allocation.Group = append(allocation.Group, fm.GroupAllocation{Variant: condition.Name, Groups: leaf.CustomSignal.TargetCustomSignalValues})
+			return true
+		}
+	}
+
+	return false
+}
+
+// singleCondition unwraps the or and and conditions of a single child, as created by the
+// Firebase console, returning nil for compound conditions with several children
+func singleCondition(condition *oneOfCondition) *oneOfCondition {
+	for condition != nil {
+		var compound *compoundCondition
+		switch {
+		case condition.OrCondition != nil:
+			compound = condition.OrCondition
+		case condition.AndCondition != nil:
+			compound = condition.AndCondition
+		default:
+			return condition
+		}
+
+		if len(compound.Conditions) != 1 {
+			return nil
+		}
+		condition = &compound.Conditions[0]
+	}
+
+	return nil
+}
+
+// percentRange converts a percent condition to a percentile range
+func percentRange(percent *percentCondition) (float64, float64, bool) {
+	toPercent := func(microPercent uint32) float64 {
+		return min(float64(microPercent)/totalMicroPercentiles*100, 100)
+	}
+
+	switch percent.PercentOperator {
+	case percentLessOrEqual:
+		return 0, toPercent(percent.MicroPercent), true
+	case percentGreaterThan:
+		return toPercent(percent.MicroPercent), 100, true
+	case percentBetween:
+		from, to := toPercent(percent.MicroPercentRange.MicroPercentLowerBound), toPercent(percent.MicroPercentRange.MicroPercentUpperBound)
+		return from, to, from <= to
+	default:
+		return 0, 0, false
+	}
+}
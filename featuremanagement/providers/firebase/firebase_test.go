@@ -0,0 +1,142 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package firebase
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+const testTemplate = `{
+  "conditions": [
+    {"name": "internal", "condition": {"orCondition": {"conditions": [{"andCondition": {"conditions": [
+      {"customSignal": {"customSignalOperator": "STRING_EXACTLY_MATCHES", "customSignalKey": "userId", "targetCustomSignalValues": ["Alice"]}}
+    ]}}]}}},
+    {"name": "beta_testers", "condition": {"customSignal": {"customSignalOperator": "STRING_EXACTLY_MATCHES", "customSignalKey": "group", "targetCustomSignalValues": ["Beta"]}}},
+    {"name": "half", "condition": {"orCondition": {"conditions": [{"andCondition": {"conditions": [
+      {"percent": {"percentOperator": "BETWEEN", "seed": "rollout", "microPercentRange": {"microPercentLowerBound": 0, "microPercentUpperBound": 50000000}}}
+    ]}}]}}},
+    {"name": "ios", "condition": {"customSignal": {"customSignalOperator": "STRING_CONTAINS", "customSignalKey": "platform", "targetCustomSignalValues": ["ios"]}}}
+  ],
+  "parameters": {
+    "new_checkout": {
+      "defaultValue": {"value": "false"},
+      "conditionalValues": {"half": {"value": "true"}, "internal": {"value": "true"}},
+      "valueType": "BOOLEAN"
+    },
+    "banner": {
+      "defaultValue": {"value": "{\"color\": \"blue\"}"},
+      "conditionalValues": {"beta_testers": {"value": "{\"color\": \"red\"}"}, "ios": {"value": "{\"color\": \"green\"}"}},
+      "valueType": "JSON"
+    },
+    "retries": {"defaultValue": {"value": "3"}, "valueType": "NUMBER"}
+  },
+  "etag": "etag-1"
+}`
+
+func TestConvertTemplate(t *testing.T) {
+	flags, err := ConvertTemplate([]byte(testTemplate), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(flags) != 3 || flags[0].ID != "banner" || flags[1].ID != "new_checkout" || flags[2].ID != "retries" {
+		t.Fatalf("Expected flags sorted by ID, got %+v", flags)
+	}
+
+	checkout := flags[1]
+	if len(checkout.Variants) != 3 {
+		t.Fatalf("Expected default, internal and half variants, got %+v", checkout.Variants)
+	}
+	if checkout.Variants[0].StatusOverride != fm.StatusOverrideDisabled || checkout.Variants[1].StatusOverride != fm.StatusOverrideEnabled {
+		t.Errorf("Expected boolean values to override the status, got %+v", checkout.Variants)
+	}
+	allocation := checkout.Allocation
+	if len(allocation.User) != 1 || allocation.User[0].Variant != "internal" || allocation.User[0].Users[0] != "Alice" {
+		t.Errorf("Expected a user allocation for internal, got %+v", allocation.User)
+	}
+	if len(allocation.Percentile) != 1 || allocation.Percentile[0].From != 0 || allocation.Percentile[0].To != 50 {
+		t.Errorf("Expected a 0-50 percentile allocation, got %+v", allocation.Percentile)
+	}
+	if allocation.Seed != "rollout" {
+		t.Errorf("Expected the percent seed, got %q", allocation.Seed)
+	}
+
+	// The STRING_CONTAINS condition is skipped
+	banner := flags[0]
+	if len(banner.Variants) != 2 || banner.Variants[1].Name != "beta_testers" {
+		t.Errorf("Expected default and beta_testers variants, got %+v", banner.Variants)
+	}
+	if value, ok := banner.Variants[0].ConfigurationValue.(map[string]any); !ok || value["color"] != "blue" {
+		t.Errorf("Expected a decoded JSON value, got %v", banner.Variants[0].ConfigurationValue)
+	}
+
+	if flags[2].Variants[0].ConfigurationValue != 3.0 {
+		t.Errorf("Expected a decoded number, got %v", flags[2].Variants[0].ConfigurationValue)
+	}
+}
+
+func TestConvertTemplateLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	if _, err := ConvertTemplate([]byte(testTemplate), &Options{Logger: logger}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output := buf.String(); !strings.Contains(output, "level=WARN msg=\"Skipping conditional value of parameter banner: condition ios") {
+		t.Errorf("Expected the skipped condition to be logged to the logger, got %q", output)
+	}
+
+	buf.Reset()
+	if _, err := NewFeatureFlagProvider([]byte(testTemplate), &Options{Logger: logger, LogLevel: fm.LogLevelError}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected warnings to be hidden at LogLevelError, got %q", buf.String())
+	}
+}
+
+func TestFeatureFlagProvider(t *testing.T) {
+	provider, err := NewFeatureFlagProvider([]byte(testTemplate), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if provider.ETag() != "etag-1" {
+		t.Errorf("Expected etag-1, got %s", provider.ETag())
+	}
+
+	manager, err := fm.NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if enabled, _ := manager.IsEnabledWithAppContext("new_checkout", fm.TargetingContext{UserID: "Alice"}); !enabled {
+		t.Error("Expected new_checkout to be enabled for Alice")
+	}
+	if enabled, _ := manager.IsEnabled("new_checkout"); enabled {
+		t.Error("Expected new_checkout to be disabled by default")
+	}
+
+	variant, err := manager.GetVariant("banner", fm.TargetingContext{UserID: "Bob", Groups: []string{"Beta"}})
+	if err != nil || variant.Name != "beta_testers" {
+		t.Errorf("Expected the beta_testers variant, got %v, %v", variant, err)
+	}
+
+	if err := provider.Update([]byte(`{"parameters": {"retries": {"defaultValue": {"value": "5"}, "valueType": "NUMBER"}}, "etag": "etag-2"}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := provider.GetFeatureFlag("banner"); err == nil {
+		t.Error("Expected banner to be removed by the update")
+	}
+
+	if err := provider.Update([]byte(`not json`)); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+	if provider.ETag() != "etag-2" {
+		t.Errorf("Expected a failed update to keep etag-2, got %s", provider.ETag())
+	}
+}
@@ -0,0 +1,13 @@
+module github.com/microsoft/Featuremanagement-Go/featuremanagement/providers/firebase
+
+go 1.23.0
+
+require github.com/microsoft/Featuremanagement-Go/featuremanagement v1.1.0
+
+require (
+	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
+
+replace github.com/microsoft/Featuremanagement-Go/featuremanagement => ../..
@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package firebase
+
+// The types below mirror the JSON representation of a Remote Config server template, as
+// returned by the Remote Config REST API and by ServerTemplate.ToJSON in the Firebase Admin SDK.
+
+type serverTemplate struct {
+	Parameters map[string]parameter `json:"parameters"`
+	Conditions []namedCondition     `json:"conditions"`
+	ETag       string               `json:"etag"`
+}
+
+type namedCondition struct {
+	Name      string          `json:"name"`
+	Condition *oneOfCondition `json:"condition"`
+}
+
+type oneOfCondition struct {
+	OrCondition  *compoundCondition     `json:"orCondition"`
+	AndCondition *compoundCondition     `json:"andCondition"`
+	Percent      *percentCondition      `json:"percent"`
+	CustomSignal *customSignalCondition `json:"customSignal"`
+	Boolean      *bool                  `json:"boolean"`
+}
+
+type compoundCondition struct {
+	Conditions []oneOfCondition `json:"conditions"`
+}
+
+type percentCondition struct {
+	PercentOperator   string            `json:"percentOperator"`
+	Seed              string            `json:"seed"`
+	MicroPercent      uint32            `json:"microPercent"`
+	MicroPercentRange microPercentRange `json:"microPercentRange"`
+}
+
+type microPercentRange struct {
+	MicroPercentLowerBound uint32 `json:"microPercentLowerBound"`
+	MicroPercentUpperBound uint32 `json:"microPercentUpperBound"`
+}
+
+type customSignalCondition struct {
+	CustomSignalOperator     string   `json:"customSignalOperator"`
+	CustomSignalKey          string   `json:"customSignalKey"`
+	TargetCustomSignalValues []string `json:"targetCustomSignalValues"`
+}
+
+type parameter struct {
+	DefaultValue      parameterValue            `json:"defaultValue"`
+	ConditionalValues map[string]parameterValue `json:"conditionalValues"`
+	Description       string                    `json:"description"`
+	ValueType         string                    `json:"valueType"`
+}
+
+type parameterValue struct {
+	Value           *string `json:"value"`
+	UseInAppDefault *bool   `json:"useInAppDefault"`
+}
+
+const (
+	percentLessOrEqual = "LESS_OR_EQUAL"
+	percentGreaterThan = "GREATER_THAN"
+	percentBetween     = "BETWEEN"
+
+	stringExactlyMatches = "STRING_EXACTLY_MATCHES"
+
+	valueTypeBoolean = "BOOLEAN"
+	valueTypeNumber  = "NUMBER"
+	valueTypeJSON    = "JSON"
+
+	totalMicroPercentiles = 100_000_000
+)
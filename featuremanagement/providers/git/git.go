@@ -0,0 +1,397 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package git provides a feature flag provider that loads flags from a Git repository, so teams
+// can manage flags as code through pull requests and have services pick up merges automatically.
+//
+// The provider clones the repository and pulls it on an interval, loading the feature management
+// configuration from a JSON or YAML file, or from every such file of a directory. It runs the git
+// command line, so the credentials configured for git, such as SSH keys and credential helpers,
+// are used to access the repository.
+package git
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultPath         = "featureflags.json"
+	defaultPollInterval = time.Minute
+)
+
+// Options configures the FeatureFlagProvider.
+type Options struct {
+	// Branch is the branch to follow. Defaults to the default branch of the repository.
+	Branch string
+
+	// Path is the file, or the directory of files, holding the feature management configuration,
+	// relative to the root of the repository. Files ending in .json, .yaml or .yml are loaded.
+	// Defaults to "featureflags.json".
+	Path string
+
+	// Directory is where the repository is cloned. An existing clone is reused. Defaults to a
+	// temporary directory that is removed by Close.
+	Directory string
+
+	// PollInterval is how often the repository is pulled. Defaults to one minute.
+	// A negative interval disables polling; call Refresh to pull. It can be changed at runtime
+	// with SetRefreshInterval.
+	PollInterval time.Duration
+
+	// StrictDecoding rejects feature flags with unknown fields or values of the wrong type,
+	// instead of ignoring or converting them. A failed refresh keeps the previously loaded flags.
+	StrictDecoding bool
+
+	// DuplicatePolicy decides which definition of a feature flag defined more than once is
+	// served, in file name order. Defaults to fm.DuplicateFirstWins. With fm.DuplicateError,
+	// every definition of the feature is logged and left out, so it evaluates as not found.
+	DuplicatePolicy fm.DuplicatePolicy
+
+	// LogLevel is the minimum severity of the messages logged by the provider, such as rejected
+	// feature flags and failed pulls. Defaults to fm.LogLevelWarn; fm.LogLevelSilent disables
+	// logging.
+	LogLevel fm.LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+}
+
+// FeatureFlagProvider serves the feature flags of a Git repository. Callbacks registered with
+// OnRefresh are called once a refresh loads a new revision, on the goroutine calling Refresh or on
+// the poll goroutine.
+type FeatureFlagProvider struct {
+	fm.SnapshotProvider
+
+	repository      string
+	options         Options
+	decodeOptions   fm.DecodeOptions
+	duplicatePolicy fm.DuplicatePolicy
+	logger          *fm.ProviderLogger
+	removeClone     bool
+
+	// refreshMu serializes pulls of the clone
+	refreshMu sync.Mutex
+	cancel    context.CancelFunc
+	done      chan struct{}
+
+	// intervalMu guards the poll interval; reschedule wakes the poll loop when it changes
+	intervalMu   sync.Mutex
+	pollInterval time.Duration
+	reschedule   chan struct{}
+}
+
+// NewFeatureFlagProvider clones a repository, loads its feature flags and starts pulling it on
+// the poll interval. Call Close to stop polling.
+//
+// Parameters:
+//   - ctx: The context of the clone and initial load; it doesn't bound the lifetime of the provider
+//   - repository: The URL of the repository, as accepted by git clone
+//   - options: The provider options, or nil for the defaults
+//
+// Returns:
+//   - *FeatureFlagProvider: The provider
+//   - error: An error if the repository can't be cloned or its configuration can't be loaded
+func NewFeatureFlagProvider(ctx context.Context, repository string, options *Options) (*FeatureFlagProvider, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	provider := &FeatureFlagProvider{
+		repository:      repository,
+		options:         *options,
+		decodeOptions:   fm.DecodeOptions{Strict: options.StrictDecoding},
+		duplicatePolicy: options.DuplicatePolicy,
+		logger:          fm.NewProviderLogger(options.LogLevel, options.Logger),
+		done:            make(chan struct{}),
+		reschedule:      make(chan struct{}, 1),
+	}
+	if provider.options.Path == "" {
+		provider.options.Path = defaultPath
+	}
+	provider.pollInterval = options.PollInterval
+	if provider.pollInterval == 0 {
+		provider.pollInterval = defaultPollInterval
+	}
+	if provider.options.Directory == "" {
+		directory, err := os.MkdirTemp("", "featureflags-git-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create clone directory: %w", err)
+		}
+		provider.options.Directory = directory
+		provider.removeClone = true
+	}
+
+	if err := provider.clone(ctx); err != nil {
+		provider.removeDirectory()
+		return nil, err
+	}
+	if err := provider.load(ctx); err != nil {
+		provider.removeDirectory()
+		return nil, err
+	}
+
+	pollCtx, cancel := context.WithCancel(context.Background())
+	provider.cancel = cancel
+	go provider.poll(pollCtx)
+
+	return provider, nil
+}
+
+// Refresh pulls the repository and reloads the feature flags if the followed branch has moved.
+// A refresh that fails keeps the previously loaded flags.
+//
+// Parameters:
+//   - ctx: The context of the pull
+//
+// Returns:
+//   - error: An error if the repository can't be pulled or its configuration can't be loaded
+func (p *FeatureFlagProvider) Refresh(ctx context.Context) error {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	err := p.pull(ctx)
+	if err != nil {
+		p.RecordRefresh(err)
+	}
+	return err
+}
+
+// pull fetches the followed branch, resets the clone to it and loads its feature flags
+func (p *FeatureFlagProvider) pull(ctx context.Context) error {
+	ref := p.options.Branch
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if _, err := p.git(ctx, "fetch", "--depth", "1", "origin", ref); err != nil {
+		return err
+	}
+	if _, err := p.git(ctx, "reset", "--hard", "FETCH_HEAD"); err != nil {
+		return err
+	}
+
+	return p.load(ctx)
+}
+
+// Revision returns the commit the current feature flags were loaded from.
+func (p *FeatureFlagProvider) Revision() string {
+	return p.Snapshot().Version()
+}
+
+// RefreshInterval returns the poll interval; a negative interval means polling is paused.
+func (p *FeatureFlagProvider) RefreshInterval() time.Duration {
+	p.intervalMu.Lock()
+	defer p.intervalMu.Unlock()
+	return p.pollInterval
+}
+
+// SetRefreshInterval changes the poll interval, scheduling the next pull one interval from now.
+// A negative interval pauses polling and zero restores the default interval.
+//
+// Parameters:
+//   - interval: The new poll interval
+//
+// Returns:
+//   - error: Always nil
+func (p *FeatureFlagProvider) SetRefreshInterval(interval time.Duration) error {
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	p.intervalMu.Lock()
+	p.pollInterval = interval
+	p.intervalMu.Unlock()
+
+	select {
+	case p.reschedule <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Close stops polling and removes the clone if it is in a temporary directory.
+// The provider keeps serving the last loaded flags.
+//
+// Returns:
+//   - error: Always nil
+func (p *FeatureFlagProvider) Close() error {
+	p.cancel()
+	<-p.done
+
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+	p.removeDirectory()
+	return nil
+}
+
+func (p *FeatureFlagProvider) poll(ctx context.Context) {
+	defer close(p.done)
+	for {
+		// A nil channel never fires, leaving polling paused until the interval changes
+		var tick <-chan time.Time
+		if interval := p.RefreshInterval(); interval > 0 {
+			tick = time.After(interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.reschedule:
+		case <-tick:
+			if err := p.Refresh(ctx); err != nil && ctx.Err() == nil {
+				p.logger.Errorf("Error refreshing feature flags from %s: %s", p.repository, err)
+			}
+		}
+	}
+}
+
+// clone clones the repository unless the directory already holds a clone
+func (p *FeatureFlagProvider) clone(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(p.options.Directory, ".git")); err == nil {
+		return p.Refresh(ctx)
+	}
+
+	args := []string{"clone", "--depth", "1"}
+	if p.options.Branch != "" {
+		args = append(args, "--branch", p.options.Branch)
+	}
+	args = append(args, "--", p.repository, p.options.Directory)
+	_, err := runGit(ctx, "", args...)
+	return err
+}
+
+// load reloads the feature flags from the checked out revision, unless it is already loaded
+func (p *FeatureFlagProvider) load(ctx context.Context) error {
+	revision, err := p.git(ctx, "rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+	if current := p.Snapshot(); current != nil && current.Version() == revision {
+		p.RecordRefresh(nil)
+		return nil
+	}
+
+	featureFlags, err := p.loadFeatureFlags()
+	if err != nil {
+		return err
+	}
+
+	p.StoreSnapshot(fm.NewFeatureFlagSnapshot(featureFlags, &fm.SnapshotOptions{
+		DuplicatePolicy: p.duplicatePolicy,
+		Version:         revision,
+		Logger:          p.logger,
+	}))
+	return nil
+}
+
+// loadFeatureFlags reads the configured file, or every configuration file of the configured
+// directory in name order
+func (p *FeatureFlagProvider) loadFeatureFlags() ([]fm.FeatureFlag, error) {
+	path := filepath.Join(p.options.Directory, filepath.FromSlash(p.options.Path))
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feature flags: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read feature flags: %w", err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			if !entry.IsDir() && isConfigurationFile(entry.Name()) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+		sort.Strings(files)
+	}
+
+	var featureFlags []fm.FeatureFlag
+	for _, file := range files {
+		featureManagement, err := p.parseFile(file)
+		if err != nil {
+			return nil, err
+		}
+		featureFlags = append(featureFlags, featureManagement.FeatureFlags...)
+	}
+
+	return featureFlags, nil
+}
+
+// parseFile parses a JSON or YAML configuration file, converting YAML to JSON so both formats
+// are decoded alike
+func (p *FeatureFlagProvider) parseFile(file string) (fm.FeatureManagement, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fm.FeatureManagement{}, fmt.Errorf("failed to read feature flags: %w", err)
+	}
+
+	if ext := strings.ToLower(filepath.Ext(file)); ext == ".yaml" || ext == ".yml" {
+		var document any
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return fm.FeatureManagement{}, fmt.Errorf("failed to parse %s: %w", filepath.Base(file), err)
+		}
+		if data, err = json.Marshal(document); err != nil {
+			return fm.FeatureManagement{}, fmt.Errorf("failed to parse %s: %w", filepath.Base(file), err)
+		}
+	}
+
+	featureManagement, err := fm.ParseFeatureManagementWithOptions(data, &p.decodeOptions)
+	if err != nil {
+		return fm.FeatureManagement{}, fmt.Errorf("failed to parse %s: %w", filepath.Base(file), err)
+	}
+
+	return featureManagement, nil
+}
+
+func isConfigurationFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *FeatureFlagProvider) removeDirectory() {
+	if p.removeClone {
+		if err := os.RemoveAll(p.options.Directory); err != nil {
+			p.logger.Errorf("Error removing clone directory %s: %s", p.options.Directory, err)
+		}
+	}
+}
+
+// git runs a git command in the clone
+func (p *FeatureFlagProvider) git(ctx context.Context, args ...string) (string, error) {
+	return runGit(ctx, p.options.Directory, args...)
+}
+
+// runGit runs a git command without prompting for credentials, returning its trimmed output
+func runGit(ctx context.Context, directory string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = directory
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
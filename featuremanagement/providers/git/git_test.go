@@ -0,0 +1,165 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// testRepository is a local repository standing in for a remote
+type testRepository struct {
+	t   *testing.T
+	dir string
+}
+
+func newTestRepository(t *testing.T) *testRepository {
+	t.Helper()
+	repo := &testRepository{t: t, dir: t.TempDir()}
+	repo.git("init", "--initial-branch", "main")
+	return repo
+}
+
+func (r *testRepository) git(args ...string) {
+	r.t.Helper()
+	args = append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)
+	if _, err := runGit(context.Background(), r.dir, args...); err != nil {
+		r.t.Fatalf("Failed to set up repository: %v", err)
+	}
+}
+
+func (r *testRepository) commit(files map[string]string) {
+	r.t.Helper()
+	for name, content := range files {
+		path := filepath.Join(r.dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			r.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+	r.git("add", "-A")
+	r.git("commit", "-m", "Update feature flags")
+}
+
+func (r *testRepository) url() string {
+	return "file://" + filepath.ToSlash(r.dir)
+}
+
+func TestFeatureFlagProvider(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.commit(map[string]string{
+		"featureflags.json": `{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": true}]}}`,
+	})
+
+	provider, err := NewFeatureFlagProvider(context.Background(), repo.url(), &Options{PollInterval: -1})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	if flag, err := provider.GetFeatureFlag("Beta"); err != nil || !flag.Enabled {
+		t.Fatalf("Expected Beta to be enabled, got %+v, %v", flag, err)
+	}
+	revision := provider.Revision()
+
+	manager, err := fm.NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	var changes []fm.FeatureChange
+	manager.OnFeatureChanged("Beta", func(change fm.FeatureChange) {
+		changes = append(changes, change)
+	})
+
+	repo.commit(map[string]string{
+		"featureflags.json": `{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": false}]}}`,
+	})
+	if err := provider.Refresh(context.Background()); err != nil {
+		t.Fatalf("Failed to refresh: %v", err)
+	}
+	if flag, _ := provider.GetFeatureFlag("Beta"); flag.Enabled {
+		t.Error("Expected the merged change to disable Beta")
+	}
+	if len(changes) != 1 || changes[0].Current == nil || changes[0].Current.Enabled {
+		t.Errorf("Expected the new revision to be reported to OnFeatureChanged callbacks, got %+v", changes)
+	}
+	if provider.Revision() == revision {
+		t.Error("Expected the revision to change")
+	}
+
+	// A broken configuration keeps the previously loaded flags
+	repo.commit(map[string]string{"featureflags.json": `{"feature_management": `})
+	if err := provider.Refresh(context.Background()); err == nil {
+		t.Error("Expected an error for a broken configuration")
+	}
+	if _, err := provider.GetFeatureFlag("Beta"); err != nil {
+		t.Errorf("Expected Beta to still be served: %v", err)
+	}
+}
+
+func TestFeatureFlagProviderDirectory(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.commit(map[string]string{
+		"flags/a.yaml":    "feature_management:\n  feature_flags:\n    - id: Alpha\n      enabled: true\n      conditions:\n        client_filters:\n          - name: Microsoft.Targeting\n            parameters:\n              Audience:\n                DefaultRolloutPercentage: 50\n",
+		"flags/b.json":    `{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": true}, {"id": "Alpha", "enabled": false}]}}`,
+		"flags/README.md": "Feature flags of the service",
+	})
+
+	clone := t.TempDir()
+	provider, err := NewFeatureFlagProvider(context.Background(), repo.url(), &Options{
+		Branch:       "main",
+		Path:         "flags",
+		Directory:    clone,
+		PollInterval: -1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+
+	flags, _ := provider.GetFeatureFlags()
+	if len(flags) != 2 {
+		t.Fatalf("Expected each feature of both files once, got %+v", flags)
+	}
+	if flag, _ := provider.GetFeatureFlag("Alpha"); !flag.Enabled || len(flag.Conditions.ClientFilters) != 1 {
+		t.Errorf("Expected the first definition of Alpha to win, got %+v", flag)
+	}
+
+	provider.Close()
+	if _, err := os.Stat(filepath.Join(clone, ".git")); err != nil {
+		t.Errorf("Expected a configured clone directory to be kept: %v", err)
+	}
+
+	// An existing clone is reused
+	reopened, err := NewFeatureFlagProvider(context.Background(), repo.url(), &Options{
+		Path:            "flags",
+		Directory:       clone,
+		PollInterval:    -1,
+		DuplicatePolicy: fm.DuplicateLastWins,
+	})
+	if err != nil {
+		t.Fatalf("Failed to reopen provider: %v", err)
+	}
+	defer reopened.Close()
+	if reopened.Revision() != provider.Revision() {
+		t.Errorf("Expected the same revision, got %s and %s", reopened.Revision(), provider.Revision())
+	}
+	if flag, _ := reopened.GetFeatureFlag("Alpha"); flag.Enabled {
+		t.Errorf("Expected the last definition of Alpha to win, got %+v", flag)
+	}
+}
+
+func TestFeatureFlagProviderMissingPath(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.commit(map[string]string{"other.json": `{}`})
+
+	if _, err := NewFeatureFlagProvider(context.Background(), repo.url(), &Options{PollInterval: -1}); err == nil {
+		t.Error("Expected an error when the configuration file is missing")
+	}
+}
@@ -0,0 +1,25 @@
+module github.com/microsoft/Featuremanagement-Go/featuremanagement/providers/kafka
+
+go 1.23.0
+
+require (
+	github.com/twmb/franz-go v1.18.1
+	github.com/twmb/franz-go/pkg/kadm v1.15.0
+	github.com/twmb/franz-go/pkg/kfake v0.0.0-20250320172111-35ab5e5f5327
+)
+
+require (
+	github.com/microsoft/Featuremanagement-Go/featuremanagement v1.1.0
+	github.com/twmb/franz-go/pkg/kmsg v1.9.0
+)
+
+require (
+	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
+	github.com/klauspost/compress v1.17.11 // indirect
+	github.com/pierrec/lz4/v4 v4.1.22 // indirect
+	golang.org/x/crypto v0.32.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
+
+replace github.com/microsoft/Featuremanagement-Go/featuremanagement => ../..
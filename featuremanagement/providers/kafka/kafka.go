@@ -0,0 +1,279 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package kafka provides a feature flag provider fed by a compacted Kafka topic. Each record is
+// keyed by feature flag ID and holds the flag in the feature_management schema as JSON; a flag
+// without an "id" takes the key as its ID, and a record without a value (a tombstone) deletes
+// the flag. With log compaction, the topic retains the latest definition of every flag.
+//
+// The provider reads every partition of the topic from the beginning without a consumer group,
+// so each instance builds the complete set of flags, then keeps applying new records as updates.
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// catchUpIdleTimeout is how long the bootstrap waits for more records before deciding that the
+// partitions it is reading have no records left before their end offset, as when their last
+// offsets were removed by compaction
+var catchUpIdleTimeout = 10 * time.Second
+
+// Options configures the FeatureFlagProvider.
+type Options struct {
+	// ClientOptions configure the Kafka client, for example with kgo.SeedBrokers, TLS and SASL.
+	// The provider adds the options consuming the topic.
+	ClientOptions []kgo.Opt
+
+	// StrictDecoding rejects feature flags with unknown fields or values of the wrong type,
+	// instead of ignoring or converting them. Rejected records are logged and left out.
+	StrictDecoding bool
+
+	// DuplicatePolicy decides which definition of a feature flag defined more than once is
+	// served, in record key order. Defaults to fm.DuplicateFirstWins. With fm.DuplicateError,
+	// every definition of the feature is logged and left out, so it evaluates as not found.
+	DuplicatePolicy fm.DuplicatePolicy
+
+	// LogLevel is the minimum severity of the messages logged by the provider, such as rejected
+	// records and read failures. Defaults to fm.LogLevelWarn; fm.LogLevelSilent disables logging.
+	LogLevel fm.LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+}
+
+// FeatureFlagProvider serves the feature flags of a compacted Kafka topic. It calls the callbacks
+// registered with OnRefresh on the consuming goroutine, so they must not block.
+type FeatureFlagProvider struct {
+	fm.SnapshotProvider
+
+	client          *kgo.Client
+	decodeOptions   fm.DecodeOptions
+	duplicatePolicy fm.DuplicatePolicy
+	logger          *fm.ProviderLogger
+
+	// mu guards records, the decoded feature flags by record key
+	mu      sync.Mutex
+	records map[string]fm.FeatureFlag
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewFeatureFlagProvider reads a topic up to its current end, then keeps applying new records
+// in the background. Call Close to stop consuming.
+//
+// Parameters:
+//   - ctx: The context of the bootstrap; it doesn't bound the lifetime of the provider
+//   - topic: The compacted topic holding the feature flags
+//   - options: The provider options, including the client options locating the brokers
+//
+// Returns:
+//   - *FeatureFlagProvider: The provider
+//   - error: An error if the client can't be created or the topic can't be read to its end
+func NewFeatureFlagProvider(ctx context.Context, topic string, options *Options) (*FeatureFlagProvider, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	clientOptions := append(slices.Clone(options.ClientOptions),
+		kgo.ConsumeTopics(topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		kgo.KeepControlRecords(),
+	)
+	client, err := kgo.NewClient(clientOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+
+	provider := &FeatureFlagProvider{
+		client:          client,
+		decodeOptions:   fm.DecodeOptions{Strict: options.StrictDecoding},
+		duplicatePolicy: options.DuplicatePolicy,
+		logger:          fm.NewProviderLogger(options.LogLevel, options.Logger),
+		records:         make(map[string]fm.FeatureFlag),
+		done:            make(chan struct{}),
+	}
+	if err := provider.bootstrap(ctx, topic); err != nil {
+		client.Close()
+		return nil, err
+	}
+	provider.publish()
+
+	consumeCtx, cancel := context.WithCancel(context.Background())
+	provider.cancel = cancel
+	go provider.consume(consumeCtx)
+
+	return provider, nil
+}
+
+// Close stops consuming the topic and closes the client. The provider keeps serving the last
+// loaded flags.
+//
+// Returns:
+//   - error: Always nil
+func (p *FeatureFlagProvider) Close() error {
+	p.cancel()
+	<-p.done
+	p.client.Close()
+	return nil
+}
+
+// bootstrap consumes every partition up to the end offset it had when the bootstrap started.
+// The end offset of a partition can be past its last record, for example when the partition
+// ends with a transaction marker or its last records were compacted. The position of a
+// partition is tracked through its control records as well as its data records. Once no records
+// arrive for catchUpIdleTimeout, the partitions that have returned records are caught up: the
+// broker has nothing left to return past their fetch position.
+func (p *FeatureFlagProvider) bootstrap(ctx context.Context, topic string) error {
+	admin := kadm.NewClient(p.client)
+	startOffsets, err := admin.ListStartOffsets(ctx, topic)
+	if err == nil {
+		err = startOffsets.Error()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list offsets of topic %s: %w", topic, err)
+	}
+	endOffsets, err := admin.ListEndOffsets(ctx, topic)
+	if err == nil {
+		err = endOffsets.Error()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list offsets of topic %s: %w", topic, err)
+	}
+
+	// remaining holds the end offset of each partition that hasn't been read to its end
+	remaining := make(map[int32]int64)
+	endOffsets.Each(func(offset kadm.ListedOffset) {
+		if start, ok := startOffsets.Lookup(topic, offset.Partition); !ok || start.Offset < offset.Offset {
+			remaining[offset.Partition] = offset.Offset
+		}
+	})
+	// fetching holds the partitions that have returned records
+	fetching := make(map[int32]bool)
+
+	for len(remaining) > 0 {
+		pollCtx, cancel := context.WithTimeout(ctx, catchUpIdleTimeout)
+		fetches := p.client.PollFetches(pollCtx)
+		cancel()
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("failed to read topic %s: %w", topic, err)
+		}
+		fetches.EachError(func(topic string, partition int32, err error) {
+			if !errors.Is(err, context.DeadlineExceeded) {
+				p.logger.Errorf("Error reading topic %s partition %d: %s", topic, partition, err)
+			}
+		})
+
+		polled := false
+		fetches.EachRecord(func(record *kgo.Record) {
+			polled = true
+			fetching[record.Partition] = true
+			p.apply(record)
+			if end, ok := remaining[record.Partition]; ok && record.Offset+1 >= end {
+				delete(remaining, record.Partition)
+			}
+		})
+		if !polled && pollCtx.Err() != nil {
+			for partition := range remaining {
+				if fetching[partition] {
+					delete(remaining, partition)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// consume applies new records until the context is canceled
+func (p *FeatureFlagProvider) consume(ctx context.Context) {
+	defer close(p.done)
+
+	for {
+		fetches := p.client.PollFetches(ctx)
+		if ctx.Err() != nil || fetches.IsClientClosed() {
+			return
+		}
+		fetches.EachError(func(topic string, partition int32, err error) {
+			if !errors.Is(err, context.Canceled) {
+				p.logger.Errorf("Error reading topic %s partition %d: %s", topic, partition, err)
+			}
+		})
+
+		updated := false
+		fetches.EachRecord(func(record *kgo.Record) {
+			p.apply(record)
+			updated = true
+		})
+		if updated {
+			p.publish()
+		}
+	}
+}
+
+// apply updates the feature flags with a record
+func (p *FeatureFlagProvider) apply(record *kgo.Record) {
+	// Control records mark the end of transactions; they only advance the position
+	if record.Attrs.IsControl() {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := string(record.Key)
+	if key == "" {
+		p.logger.Warnf("Ignoring record without key at partition %d offset %d", record.Partition, record.Offset)
+		return
+	}
+	if record.Value == nil {
+		delete(p.records, key)
+		return
+	}
+
+	flag, err := fm.DecodeFeatureFlag(record.Value, key, &p.decodeOptions)
+	if err != nil {
+		p.logger.Warnf("Ignoring feature flag %s at partition %d offset %d: %s", key, record.Partition, record.Offset, err)
+		delete(p.records, key)
+		return
+	}
+	p.records[key] = flag
+}
+
+// publish swaps in a snapshot of the current records, sorted by feature flag ID. Records
+// defining the same ID are passed in key order to the duplicate policy.
+func (p *FeatureFlagProvider) publish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keys := make([]string, 0, len(p.records))
+	for key := range p.records {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	flags := make([]fm.FeatureFlag, 0, len(keys))
+	for _, key := range keys {
+		flags = append(flags, p.records[key])
+	}
+	sort.SliceStable(flags, func(i, j int) bool {
+		return flags[i].ID < flags[j].ID
+	})
+
+	p.StoreSnapshot(fm.NewFeatureFlagSnapshot(flags, &fm.SnapshotOptions{
+		DuplicatePolicy: p.duplicatePolicy,
+		Logger:          p.logger,
+	}))
+}
@@ -0,0 +1,187 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+const testTopic = "feature-flags"
+
+func newTestCluster(t *testing.T) (*kfake.Cluster, *kgo.Client) {
+	t.Helper()
+	return newTestClusterWithPartitions(t, 3)
+}
+
+func newTestClusterWithPartitions(t *testing.T, partitions int32) (*kfake.Cluster, *kgo.Client) {
+	t.Helper()
+	cluster, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(partitions, testTopic))
+	if err != nil {
+		t.Fatalf("Failed to start cluster: %v", err)
+	}
+	t.Cleanup(cluster.Close)
+
+	producer, err := kgo.NewClient(kgo.SeedBrokers(cluster.ListenAddrs()...), kgo.DefaultProduceTopic(testTopic))
+	if err != nil {
+		t.Fatalf("Failed to create producer: %v", err)
+	}
+	t.Cleanup(producer.Close)
+
+	return cluster, producer
+}
+
+func produce(t *testing.T, producer *kgo.Client, key string, value string) {
+	t.Helper()
+	record := &kgo.Record{Key: []byte(key)}
+	if value != "" {
+		record.Value = []byte(value)
+	}
+	if err := producer.ProduceSync(context.Background(), record).FirstErr(); err != nil {
+		t.Fatalf("Failed to produce: %v", err)
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the update")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestFeatureFlagProvider(t *testing.T) {
+	cluster, producer := newTestCluster(t)
+	produce(t, producer, "Beta", `{"id": "Beta", "enabled": false}`)
+	produce(t, producer, "Beta", `{"id": "Beta", "enabled": true}`)
+	produce(t, producer, "Alpha", `{"enabled": true}`)
+	produce(t, producer, "Gamma", `{"id": "Gamma", "enabled": true}`)
+	produce(t, producer, "Gamma", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	provider, err := NewFeatureFlagProvider(ctx, testTopic, &Options{
+		ClientOptions: []kgo.Opt{kgo.SeedBrokers(cluster.ListenAddrs()...)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	// The bootstrap applies the latest record of every key
+	flags, _ := provider.GetFeatureFlags()
+	if len(flags) != 2 || flags[0].ID != "Alpha" || flags[1].ID != "Beta" || !flags[1].Enabled {
+		t.Fatalf("Expected Alpha and the enabled Beta, got %+v", flags)
+	}
+
+	manager, err := fm.NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	changes := make(chan fm.FeatureChange, 10)
+	manager.OnFeatureChanged("Beta", func(change fm.FeatureChange) {
+		changes <- change
+	})
+
+	produce(t, producer, "Beta", `{"id": "Beta", "enabled": false}`)
+	waitFor(t, func() bool {
+		flag, _ := provider.GetFeatureFlag("Beta")
+		return !flag.Enabled
+	})
+	select {
+	case change := <-changes:
+		if change.Current == nil || change.Current.Enabled {
+			t.Errorf("Expected Beta to change to disabled, got %+v", change)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the record to be reported to OnFeatureChanged callbacks")
+	}
+
+	produce(t, producer, "Alpha", "")
+	waitFor(t, func() bool {
+		_, err := provider.GetFeatureFlag("Alpha")
+		return err != nil
+	})
+
+	produce(t, producer, "Delta", `{"id": "Delta", "conditions": {"requirement_type": "Some"}}`)
+	waitFor(t, func() bool {
+		return len(provider.ValidationErrors()) == 1
+	})
+}
+
+func TestFeatureFlagProviderEmptyTopic(t *testing.T) {
+	cluster, _ := newTestCluster(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	provider, err := NewFeatureFlagProvider(ctx, testTopic, &Options{
+		ClientOptions: []kgo.Opt{kgo.SeedBrokers(cluster.ListenAddrs()...)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	if flags, _ := provider.GetFeatureFlags(); len(flags) != 0 {
+		t.Errorf("Expected no flags, got %+v", flags)
+	}
+}
+
+func TestFeatureFlagProviderEndPastLastRecord(t *testing.T) {
+	defer func(timeout time.Duration) { catchUpIdleTimeout = timeout }(catchUpIdleTimeout)
+	catchUpIdleTimeout = 100 * time.Millisecond
+
+	cluster, producer := newTestClusterWithPartitions(t, 1)
+	produce(t, producer, "Beta", `{"enabled": true}`)
+	produce(t, producer, "Null", `null`)
+
+	// Report an end offset past the last record, as when the partition ends with a transaction
+	// marker or its last records were compacted
+	cluster.ControlKey(int16(kmsg.ListOffsets), func(request kmsg.Request) (kmsg.Response, error, bool) {
+		cluster.KeepControl()
+		req := request.(*kmsg.ListOffsetsRequest)
+		resp := req.ResponseKind().(*kmsg.ListOffsetsResponse)
+		resp.Version = req.Version
+		for _, topic := range req.Topics {
+			respTopic := kmsg.NewListOffsetsResponseTopic()
+			respTopic.Topic = topic.Topic
+			for _, partition := range topic.Partitions {
+				if partition.Timestamp != -1 {
+					return nil, nil, false
+				}
+				respPartition := kmsg.NewListOffsetsResponseTopicPartition()
+				respPartition.Partition = partition.Partition
+				respPartition.Offset = 10
+				respTopic.Partitions = append(respTopic.Partitions, respPartition)
+			}
+			resp.Topics = append(resp.Topics, respTopic)
+		}
+		return resp, nil, true
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	provider, err := NewFeatureFlagProvider(ctx, testTopic, &Options{
+		ClientOptions: []kgo.Opt{kgo.SeedBrokers(cluster.ListenAddrs()...)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	// The partition is caught up once it returns no more records, and the null value is left out
+	flags, _ := provider.GetFeatureFlags()
+	if len(flags) != 1 || flags[0].ID != "Beta" {
+		t.Errorf("Expected Beta, got %+v", flags)
+	}
+}
@@ -0,0 +1,22 @@
+module github.com/microsoft/Featuremanagement-Go/featuremanagement/providers/mongodb
+
+go 1.23.0
+
+require go.mongodb.org/mongo-driver/v2 v2.3.1
+
+require github.com/microsoft/Featuremanagement-Go/featuremanagement v1.1.0
+
+require (
+	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
+	github.com/golang/snappy v1.0.0 // indirect
+	github.com/klauspost/compress v1.16.7 // indirect
+	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
+	github.com/xdg-go/scram v1.1.2 // indirect
+	github.com/xdg-go/stringprep v1.0.4 // indirect
+	github.com/youmark/pkcs8 v0.0.0-20240726163527-a2c0da244d78 // indirect
+	golang.org/x/crypto v0.33.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
+
+replace github.com/microsoft/Featuremanagement-Go/featuremanagement => ../..
@@ -0,0 +1,297 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package mongodb provides a feature flag provider backed by a MongoDB collection. Each document
+// of the collection is a feature flag in the feature_management schema, for example:
+//
+//	{ "id": "Beta", "enabled": true, "conditions": { "client_filters": [ ... ] } }
+//
+// The provider loads every document up front, then follows the collection's change stream to
+// apply inserts, updates and deletes as they happen. Change streams require a replica set or a
+// sharded cluster.
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	mongooptions "go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const defaultRetryInterval = 5 * time.Second
+
+// Options configures the FeatureFlagProvider.
+type Options struct {
+	// StrictDecoding rejects documents with unknown fields or values of the wrong type,
+	// instead of ignoring or converting them. Rejected documents are logged and left out.
+	StrictDecoding bool
+
+	// RetryInterval is how long the provider waits before reopening a failed change stream.
+	// Defaults to 5 seconds.
+	RetryInterval time.Duration
+
+	// DuplicatePolicy decides which definition of a feature flag defined more than once is
+	// served, in document key order. Defaults to fm.DuplicateFirstWins. With fm.DuplicateError,
+	// every definition of the feature is logged and left out, so it evaluates as not found.
+	DuplicatePolicy fm.DuplicatePolicy
+
+	// LogLevel is the minimum severity of the messages logged by the provider, such as rejected
+	// documents and change stream failures. Defaults to fm.LogLevelWarn; fm.LogLevelSilent disables
+	// logging.
+	LogLevel fm.LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+}
+
+// FeatureFlagProvider serves the feature flags stored in a MongoDB collection. Callbacks
+// registered with OnRefresh run on the goroutine following the change stream and must not block.
+type FeatureFlagProvider struct {
+	fm.SnapshotProvider
+
+	collection      *mongo.Collection
+	decodeOptions   fm.DecodeOptions
+	duplicatePolicy fm.DuplicatePolicy
+	logger          *fm.ProviderLogger
+	retryInterval   time.Duration
+
+	// mu guards documents, the decoded feature flags by document key
+	mu        sync.Mutex
+	documents map[string]fm.FeatureFlag
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// changeEvent is the part of a change stream event the provider uses
+type changeEvent struct {
+	OperationType string `bson:"operationType"`
+	DocumentKey   struct {
+		ID bson.RawValue `bson:"_id"`
+	} `bson:"documentKey"`
+	FullDocument bson.Raw `bson:"fullDocument"`
+}
+
+// NewFeatureFlagProvider loads the feature flags of a collection and starts following its
+// change stream. Call Close to stop following it.
+//
+// Parameters:
+//   - ctx: The context of the initial load; it doesn't bound the lifetime of the provider
+//   - collection: The collection holding a feature flag document per flag
+//   - options: The provider options, or nil for the defaults
+//
+// Returns:
+//   - *FeatureFlagProvider: The provider
+//   - error: An error if the change stream can't be opened or the collection can't be read
+func NewFeatureFlagProvider(ctx context.Context, collection *mongo.Collection, options *Options) (*FeatureFlagProvider, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	provider := &FeatureFlagProvider{
+		collection:      collection,
+		decodeOptions:   fm.DecodeOptions{Strict: options.StrictDecoding},
+		duplicatePolicy: options.DuplicatePolicy,
+		logger:          fm.NewProviderLogger(options.LogLevel, options.Logger),
+		retryInterval:   options.RetryInterval,
+		done:            make(chan struct{}),
+	}
+	if provider.retryInterval <= 0 {
+		provider.retryInterval = defaultRetryInterval
+	}
+
+	// Open the change stream before loading, so no change is missed between the two
+	stream, err := provider.watch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := provider.load(ctx); err != nil {
+		_ = stream.Close(ctx)
+		return nil, err
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	provider.cancel = cancel
+	go provider.follow(watchCtx, stream)
+
+	return provider, nil
+}
+
+// Close stops following the change stream. The provider keeps serving the last loaded flags.
+//
+// Returns:
+//   - error: Always nil
+func (p *FeatureFlagProvider) Close() error {
+	p.cancel()
+	<-p.done
+	return nil
+}
+
+func (p *FeatureFlagProvider) watch(ctx context.Context) (*mongo.ChangeStream, error) {
+	stream, err := p.collection.Watch(ctx, mongo.Pipeline{}, mongooptions.ChangeStream().SetFullDocument(mongooptions.UpdateLookup))
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch feature flag collection: %w", err)
+	}
+
+	return stream, nil
+}
+
+// load replaces the feature flags with every document of the collection
+func (p *FeatureFlagProvider) load(ctx context.Context) error {
+	cursor, err := p.collection.Find(ctx, bson.D{})
+	if err != nil {
+		return fmt.Errorf("failed to load feature flags: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	documents := make(map[string]fm.FeatureFlag)
+	for cursor.Next(ctx) {
+		key := cursor.Current.Lookup("_id").String()
+		flag, err := p.decodeDocument(cursor.Current)
+		if err != nil {
+			p.logger.Warnf("Ignoring feature flag document %s: %s", key, err)
+			continue
+		}
+		documents[key] = flag
+	}
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("failed to load feature flags: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.documents = documents
+	p.publish()
+	return nil
+}
+
+// follow applies the changes of the stream until the context is canceled, reopening the stream
+// and reloading the collection after a failure so that missed changes are picked up
+func (p *FeatureFlagProvider) follow(ctx context.Context, stream *mongo.ChangeStream) {
+	defer close(p.done)
+
+	for {
+		for stream.Next(ctx) {
+			var event changeEvent
+			if err := stream.Decode(&event); err != nil {
+				p.logger.Errorf("Failed to decode feature flag change: %s", err)
+				continue
+			}
+			p.apply(event)
+		}
+
+		err := stream.Err()
+		_ = stream.Close(context.Background())
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			p.logger.Errorf("Feature flag change stream failed: %s", err)
+		}
+
+		for stream = nil; stream == nil; {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.retryInterval):
+			}
+
+			if stream, err = p.watch(ctx); err != nil {
+				p.logger.Errorf("Error reopening feature flag change stream: %s", err)
+				continue
+			}
+			if err := p.load(ctx); err != nil {
+				p.logger.Errorf("Error reloading feature flags: %s", err)
+			}
+		}
+	}
+}
+
+// apply updates the feature flags with a change stream event
+func (p *FeatureFlagProvider) apply(event changeEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := event.DocumentKey.ID.String()
+	switch event.OperationType {
+	case "insert", "update", "replace":
+		// The document may have been deleted before the update was looked up
+		if len(event.FullDocument) == 0 {
+			delete(p.documents, key)
+			break
+		}
+		flag, err := p.decodeDocument(event.FullDocument)
+		if err != nil {
+			p.logger.Warnf("Ignoring feature flag document %s: %s", key, err)
+			delete(p.documents, key)
+			break
+		}
+		p.documents[key] = flag
+	case "delete":
+		delete(p.documents, key)
+	case "drop", "dropDatabase", "rename":
+		p.documents = make(map[string]fm.FeatureFlag)
+	default:
+		return
+	}
+
+	p.publish()
+}
+
+// decodeDocument decodes a feature flag document through its relaxed extended JSON form,
+// so that the flag is decoded like flags of any other configuration source
+func (p *FeatureFlagProvider) decodeDocument(document bson.Raw) (fm.FeatureFlag, error) {
+	data, err := bson.MarshalExtJSON(document, false, false)
+	if err != nil {
+		return fm.FeatureFlag{}, fmt.Errorf("failed to convert document: %w", err)
+	}
+
+	var flag map[string]any
+	if err := json.Unmarshal(data, &flag); err != nil {
+		return fm.FeatureFlag{}, fmt.Errorf("failed to convert document: %w", err)
+	}
+	delete(flag, "_id")
+
+	featureManagement, err := fm.DecodeFeatureManagementWithOptions(map[string]any{
+		"feature_management": map[string]any{"feature_flags": []any{flag}},
+	}, &p.decodeOptions)
+	if err != nil {
+		return fm.FeatureFlag{}, err
+	}
+	if len(featureManagement.FeatureFlags) != 1 {
+		return fm.FeatureFlag{}, fmt.Errorf("document is not a feature flag")
+	}
+
+	return featureManagement.FeatureFlags[0], nil
+}
+
+// publish swaps in a snapshot of the current documents, sorted by feature flag ID. Documents
+// defining the same ID are passed in key order to the duplicate policy. Callers must hold p.mu.
+func (p *FeatureFlagProvider) publish() {
+	keys := make([]string, 0, len(p.documents))
+	for key := range p.documents {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	flags := make([]fm.FeatureFlag, 0, len(keys))
+	for _, key := range keys {
+		flags = append(flags, p.documents[key])
+	}
+	sort.SliceStable(flags, func(i, j int) bool {
+		return flags[i].ID < flags[j].ID
+	})
+
+	p.StoreSnapshot(fm.NewFeatureFlagSnapshot(flags, &fm.SnapshotOptions{
+		DuplicatePolicy: p.duplicatePolicy,
+		Logger:          p.logger,
+	}))
+}
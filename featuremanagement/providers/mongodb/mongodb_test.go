@@ -0,0 +1,119 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package mongodb
+
+import (
+	"testing"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func mustMarshal(t *testing.T, document bson.D) bson.Raw {
+	t.Helper()
+	data, err := bson.Marshal(document)
+	if err != nil {
+		t.Fatalf("Failed to marshal document: %v", err)
+	}
+	return data
+}
+
+func changeOf(t *testing.T, operation string, id string, document bson.D) changeEvent {
+	event := changeEvent{OperationType: operation}
+	_, value, err := bson.MarshalValue(id)
+	if err != nil {
+		t.Fatalf("Failed to marshal ID: %v", err)
+	}
+	event.DocumentKey.ID = bson.RawValue{Type: bson.TypeString, Value: value}
+	if document != nil {
+		event.FullDocument = mustMarshal(t, document)
+	}
+	return event
+}
+
+func newTestProvider() *FeatureFlagProvider {
+	provider := &FeatureFlagProvider{documents: make(map[string]fm.FeatureFlag)}
+	provider.publish()
+	return provider
+}
+
+func TestDecodeDocument(t *testing.T) {
+	provider := newTestProvider()
+	flag, err := provider.decodeDocument(mustMarshal(t, bson.D{
+		{Key: "_id", Value: bson.NewObjectID()},
+		{Key: "id", Value: "Beta"},
+		{Key: "enabled", Value: true},
+		{Key: "conditions", Value: bson.D{{Key: "client_filters", Value: bson.A{
+			bson.D{{Key: "name", Value: "Microsoft.Targeting"}, {Key: "parameters", Value: bson.D{
+				{Key: "Audience", Value: bson.D{{Key: "DefaultRolloutPercentage", Value: int32(50)}}},
+			}}},
+		}}}},
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if flag.ID != "Beta" || !flag.Enabled || len(flag.Conditions.ClientFilters) != 1 {
+		t.Errorf("Unexpected flag: %+v", flag)
+	}
+	audience := flag.Conditions.ClientFilters[0].Parameters["Audience"].(map[string]any)
+	if audience["DefaultRolloutPercentage"] != 50.0 {
+		t.Errorf("Expected the rollout percentage as a number, got %v", audience["DefaultRolloutPercentage"])
+	}
+
+	provider.decodeOptions.Strict = true
+	if _, err := provider.decodeDocument(mustMarshal(t, bson.D{{Key: "id", Value: "Beta"}, {Key: "enabeld", Value: true}})); err == nil {
+		t.Error("Expected strict decoding to reject an unknown field")
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+	provider := newTestProvider()
+
+	provider.apply(changeOf(t, "insert", "1", bson.D{{Key: "id", Value: "Beta"}, {Key: "enabled", Value: true}}))
+	provider.apply(changeOf(t, "insert", "2", bson.D{{Key: "id", Value: "Alpha"}, {Key: "enabled", Value: false}}))
+	flags, _ := provider.GetFeatureFlags()
+	if len(flags) != 2 || flags[0].ID != "Alpha" || flags[1].ID != "Beta" {
+		t.Fatalf("Expected Alpha and Beta sorted by ID, got %+v", flags)
+	}
+
+	manager, err := fm.NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	var changes []fm.FeatureChange
+	manager.OnFeatureChanged("Beta", func(change fm.FeatureChange) {
+		changes = append(changes, change)
+	})
+
+	provider.apply(changeOf(t, "update", "1", bson.D{{Key: "id", Value: "Beta"}, {Key: "enabled", Value: false}}))
+	if flag, _ := provider.GetFeatureFlag("Beta"); flag.Enabled {
+		t.Error("Expected the update to disable Beta")
+	}
+	if len(changes) != 1 || changes[0].Current == nil || changes[0].Current.Enabled {
+		t.Errorf("Expected the change event to be reported to OnFeatureChanged callbacks, got %+v", changes)
+	}
+
+	// An update looked up after the document was deleted removes the flag
+	provider.apply(changeOf(t, "update", "2", nil))
+	if _, err := provider.GetFeatureFlag("Alpha"); err == nil {
+		t.Error("Expected Alpha to be removed")
+	}
+
+	// Invalid flags are quarantined
+	provider.apply(changeOf(t, "replace", "1", bson.D{{Key: "id", Value: "Beta"}, {Key: "conditions", Value: bson.D{{Key: "requirement_type", Value: "Some"}}}}))
+	if _, err := provider.GetFeatureFlag("Beta"); err == nil {
+		t.Error("Expected the invalid Beta to be left out")
+	}
+	if errs := provider.ValidationErrors(); len(errs) != 1 || errs[0].FeatureName != "Beta" {
+		t.Errorf("Expected a validation error for Beta, got %v", errs)
+	}
+
+	provider.apply(changeOf(t, "delete", "1", nil))
+	provider.apply(changeOf(t, "insert", "3", bson.D{{Key: "id", Value: "Gamma"}, {Key: "enabled", Value: true}}))
+	provider.apply(changeOf(t, "drop", "", nil))
+	if flags, _ := provider.GetFeatureFlags(); len(flags) != 0 {
+		t.Errorf("Expected dropping the collection to remove every flag, got %+v", flags)
+	}
+}
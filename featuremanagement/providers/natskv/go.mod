@@ -0,0 +1,20 @@
+module github.com/microsoft/Featuremanagement-Go/featuremanagement/providers/natskv
+
+go 1.23.0
+
+require github.com/nats-io/nats.go v1.41.0
+
+require github.com/microsoft/Featuremanagement-Go/featuremanagement v1.1.0
+
+require (
+	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/nats-io/nkeys v0.4.9 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	golang.org/x/crypto v0.31.0 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
+
+replace github.com/microsoft/Featuremanagement-Go/featuremanagement => ../..
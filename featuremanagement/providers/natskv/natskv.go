@@ -0,0 +1,184 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package natskv provides a feature flag provider backed by a NATS JetStream key-value bucket.
+// Each key holds a feature flag in the feature_management schema as JSON; a flag without an "id"
+// takes the key as its ID. The provider watches the bucket, so changes propagate as soon as they
+// are written.
+package natskv
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Options configures the FeatureFlagProvider.
+type Options struct {
+	// Keys is the subject filter of the keys holding feature flags, for example "flags.>".
+	// Defaults to every key of the bucket.
+	Keys string
+
+	// StrictDecoding rejects feature flags with unknown fields or values of the wrong type,
+	// instead of ignoring or converting them. Rejected values are logged and left out.
+	StrictDecoding bool
+
+	// DuplicatePolicy decides which definition of a feature flag defined more than once is
+	// served, in key order. Defaults to fm.DuplicateFirstWins. With fm.DuplicateError,
+	// every definition of the feature is logged and left out, so it evaluates as not found.
+	DuplicatePolicy fm.DuplicatePolicy
+
+	// LogLevel is the minimum severity of the messages logged by the provider, such as rejected
+	// values. Defaults to fm.LogLevelWarn; fm.LogLevelSilent disables logging.
+	LogLevel fm.LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+}
+
+// FeatureFlagProvider serves the feature flags of a JetStream key-value bucket. The callbacks
+// registered with OnRefresh are called on the watching goroutine, so they must not block.
+type FeatureFlagProvider struct {
+	fm.SnapshotProvider
+
+	decodeOptions   fm.DecodeOptions
+	duplicatePolicy fm.DuplicatePolicy
+	logger          *fm.ProviderLogger
+	watcher         jetstream.KeyWatcher
+
+	// mu guards entries, the decoded feature flags by key
+	mu      sync.Mutex
+	entries map[string]fm.FeatureFlag
+
+	done chan struct{}
+}
+
+// NewFeatureFlagProvider watches a bucket and returns once its current values are loaded.
+// Call Close to stop watching.
+//
+// Parameters:
+//   - ctx: The context of the initial load; it doesn't bound the lifetime of the provider
+//   - kv: The bucket holding the feature flags
+//   - options: The provider options, or nil for the defaults
+//
+// Returns:
+//   - *FeatureFlagProvider: The provider
+//   - error: An error if the bucket can't be watched or the initial load is canceled
+func NewFeatureFlagProvider(ctx context.Context, kv jetstream.KeyValue, options *Options) (*FeatureFlagProvider, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	keys := options.Keys
+	if keys == "" {
+		keys = jetstream.AllKeys
+	}
+
+	// The watcher outlives ctx, which only bounds the initial load
+	watcher, err := kv.Watch(context.Background(), keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch feature flag bucket: %w", err)
+	}
+
+	provider := &FeatureFlagProvider{
+		decodeOptions:   fm.DecodeOptions{Strict: options.StrictDecoding},
+		duplicatePolicy: options.DuplicatePolicy,
+		logger:          fm.NewProviderLogger(options.LogLevel, options.Logger),
+		watcher:         watcher,
+		entries:         make(map[string]fm.FeatureFlag),
+		done:            make(chan struct{}),
+	}
+
+	// The watcher delivers the current values, then nil, then updates
+	for loaded := false; !loaded; {
+		select {
+		case entry, ok := <-watcher.Updates():
+			if !ok {
+				return nil, fmt.Errorf("feature flag bucket watch stopped")
+			}
+			if entry == nil {
+				loaded = true
+				break
+			}
+			provider.update(entry)
+		case <-ctx.Done():
+			_ = watcher.Stop()
+			return nil, ctx.Err()
+		}
+	}
+	provider.publish()
+
+	go provider.follow()
+	return provider, nil
+}
+
+// Close stops watching the bucket. The provider keeps serving the last loaded flags.
+//
+// Returns:
+//   - error: An error if the watch can't be stopped
+func (p *FeatureFlagProvider) Close() error {
+	err := p.watcher.Stop()
+	<-p.done
+	return err
+}
+
+// follow applies updates until the watch is stopped
+func (p *FeatureFlagProvider) follow() {
+	defer close(p.done)
+	for entry := range p.watcher.Updates() {
+		if entry == nil {
+			continue
+		}
+		p.update(entry)
+		p.publish()
+	}
+}
+
+// update applies a key-value entry to the feature flags
+func (p *FeatureFlagProvider) update(entry jetstream.KeyValueEntry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if entry.Operation() != jetstream.KeyValuePut {
+		delete(p.entries, entry.Key())
+		return
+	}
+
+	flag, err := fm.DecodeFeatureFlag(entry.Value(), entry.Key(), &p.decodeOptions)
+	if err != nil {
+		p.logger.Warnf("Ignoring feature flag %s at revision %d: %s", entry.Key(), entry.Revision(), err)
+		delete(p.entries, entry.Key())
+		return
+	}
+	p.entries[entry.Key()] = flag
+}
+
+// publish swaps in a snapshot of the current entries, sorted by feature flag ID. Entries defining
+// the same ID are passed in key order to the duplicate policy.
+func (p *FeatureFlagProvider) publish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	keys := make([]string, 0, len(p.entries))
+	for key := range p.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	flags := make([]fm.FeatureFlag, 0, len(keys))
+	for _, key := range keys {
+		flags = append(flags, p.entries[key])
+	}
+	sort.SliceStable(flags, func(i, j int) bool {
+		return flags[i].ID < flags[j].ID
+	})
+
+	p.StoreSnapshot(fm.NewFeatureFlagSnapshot(flags, &fm.SnapshotOptions{
+		DuplicatePolicy: p.duplicatePolicy,
+		Logger:          p.logger,
+	}))
+}
@@ -0,0 +1,132 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package natskv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type fakeEntry struct {
+	key      string
+	value    string
+	op       jetstream.KeyValueOp
+	revision uint64
+}
+
+func (e fakeEntry) Bucket() string                  { return "flags" }
+func (e fakeEntry) Key() string                     { return e.key }
+func (e fakeEntry) Value() []byte                   { return []byte(e.value) }
+func (e fakeEntry) Revision() uint64                { return e.revision }
+func (e fakeEntry) Created() time.Time              { return time.Time{} }
+func (e fakeEntry) Delta() uint64                   { return 0 }
+func (e fakeEntry) Operation() jetstream.KeyValueOp { return e.op }
+
+type fakeWatcher struct {
+	updates chan jetstream.KeyValueEntry
+}
+
+func (w *fakeWatcher) Updates() <-chan jetstream.KeyValueEntry { return w.updates }
+
+func (w *fakeWatcher) Stop() error {
+	close(w.updates)
+	return nil
+}
+
+// fakeKV implements the Watch method of a bucket; other methods are not used
+type fakeKV struct {
+	jetstream.KeyValue
+	watcher *fakeWatcher
+	keys    string
+}
+
+func (kv *fakeKV) Watch(ctx context.Context, keys string, opts ...jetstream.WatchOpt) (jetstream.KeyWatcher, error) {
+	kv.keys = keys
+	return kv.watcher, nil
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the update")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFeatureFlagProvider(t *testing.T) {
+	watcher := &fakeWatcher{updates: make(chan jetstream.KeyValueEntry, 10)}
+	watcher.updates <- fakeEntry{key: "flags.Beta", value: `{"id": "Beta", "enabled": true}`, op: jetstream.KeyValuePut}
+	watcher.updates <- fakeEntry{key: "flags.Alpha", value: `{"enabled": true}`, op: jetstream.KeyValuePut}
+	watcher.updates <- fakeEntry{key: "flags.Broken", value: `{"enabled": `, op: jetstream.KeyValuePut}
+	watcher.updates <- nil
+
+	kv := &fakeKV{watcher: watcher}
+	provider, err := NewFeatureFlagProvider(context.Background(), kv, &Options{Keys: "flags.>"})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	if kv.keys != "flags.>" {
+		t.Errorf("Expected the configured keys to be watched, got %s", kv.keys)
+	}
+	flags, _ := provider.GetFeatureFlags()
+	if len(flags) != 2 || flags[0].ID != "Beta" || flags[1].ID != "flags.Alpha" {
+		t.Fatalf("Expected Beta and the key-named flags.Alpha, got %+v", flags)
+	}
+
+	manager, err := fm.NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	changes := make(chan fm.FeatureChange, 10)
+	manager.OnFeatureChanged("Beta", func(change fm.FeatureChange) {
+		changes <- change
+	})
+
+	watcher.updates <- fakeEntry{key: "flags.Beta", value: `{"id": "Beta", "enabled": false}`, op: jetstream.KeyValuePut}
+	waitFor(t, func() bool {
+		flag, _ := provider.GetFeatureFlag("Beta")
+		return !flag.Enabled
+	})
+	select {
+	case change := <-changes:
+		if change.Current == nil || change.Current.Enabled {
+			t.Errorf("Expected Beta to change to disabled, got %+v", change)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the update to be reported to OnFeatureChanged callbacks")
+	}
+
+	watcher.updates <- fakeEntry{key: "flags.Alpha", op: jetstream.KeyValueDelete}
+	waitFor(t, func() bool {
+		_, err := provider.GetFeatureFlag("flags.Alpha")
+		return err != nil
+	})
+
+	watcher.updates <- fakeEntry{key: "flags.Gamma", value: `{"id": "Gamma", "conditions": {"requirement_type": "Some"}}`, op: jetstream.KeyValuePut}
+	waitFor(t, func() bool {
+		return len(provider.ValidationErrors()) == 1
+	})
+	if _, err := provider.GetFeatureFlag("Gamma"); err == nil {
+		t.Error("Expected the invalid Gamma to be left out")
+	}
+}
+
+func TestFeatureFlagProviderCanceled(t *testing.T) {
+	watcher := &fakeWatcher{updates: make(chan jetstream.KeyValueEntry)}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := NewFeatureFlagProvider(ctx, &fakeKV{watcher: watcher}, nil); err == nil {
+		t.Error("Expected an error when the initial load is canceled")
+	}
+}
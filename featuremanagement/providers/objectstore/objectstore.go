@@ -0,0 +1,336 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package objectstore provides a feature flag provider that polls a configuration document from
+// any source able to fetch bytes with an ETag, such as S3, Google Cloud Storage or an HTTP server.
+//
+// A new storage backend only needs a Fetcher. For example, with the AWS SDK:
+//
+//	type s3Fetcher struct {
+//		client *s3.Client
+//		bucket, key string
+//	}
+//
+//	func (f s3Fetcher) Fetch(ctx context.Context, etag string) (objectstore.FetchResult, error) {
+//		input := &s3.GetObjectInput{Bucket: &f.bucket, Key: &f.key}
+//		if etag != "" {
+//			input.IfNoneMatch = &etag
+//		}
+//		output, err := f.client.GetObject(ctx, input)
+//		var apiErr smithy.APIError
+//		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotModified" {
+//			return objectstore.FetchResult{NotModified: true}, nil
+//		}
+//		if err != nil {
+//			return objectstore.FetchResult{}, err
+//		}
+//		defer output.Body.Close()
+//		data, err := io.ReadAll(output.Body)
+//		return objectstore.FetchResult{Data: data, ETag: aws.ToString(output.ETag)}, err
+//	}
+package objectstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+const defaultPollInterval = 30 * time.Second
+
+// FetchResult is the outcome of a fetch
+type FetchResult struct {
+	// Data is the fetched document. It is ignored when NotModified is set.
+	Data []byte
+	// ETag identifies the version of the fetched document, or is empty if the source has no ETags
+	ETag string
+	// NotModified reports that the document still has the ETag passed to Fetch
+	NotModified bool
+}
+
+// Fetcher fetches the configuration document from a source.
+type Fetcher interface {
+	// Fetch returns the current document. When etag is not empty, the fetcher should make a
+	// conditional request and report NotModified if the document still has that ETag.
+	Fetch(ctx context.Context, etag string) (FetchResult, error)
+}
+
+// FetcherFunc adapts a function to the Fetcher interface.
+type FetcherFunc func(ctx context.Context, etag string) (FetchResult, error)
+
+// Fetch calls f(ctx, etag).
+func (f FetcherFunc) Fetch(ctx context.Context, etag string) (FetchResult, error) {
+	return f(ctx, etag)
+}
+
+// DecodeFunc decodes a fetched document into feature flags.
+type DecodeFunc func(data []byte) ([]fm.FeatureFlag, error)
+
+// Options configures the FeatureFlagProvider.
+type Options struct {
+	// PollInterval is how often the document is fetched. Defaults to 30 seconds.
+	// A negative interval disables polling; call Refresh to fetch. It can be changed at runtime
+	// with SetRefreshInterval.
+	PollInterval time.Duration
+
+	// Decode decodes the fetched document, for example to decrypt or decompress it, or to read
+	// a custom format. Defaults to parsing a JSON feature management configuration.
+	Decode DecodeFunc
+
+	// StrictDecoding rejects feature flags with unknown fields or values of the wrong type when
+	// parsing with the default decoder. A failed refresh keeps the previously loaded flags.
+	StrictDecoding bool
+
+	// DuplicatePolicy decides which definition of a feature flag defined more than once is
+	// served, in document order. Defaults to fm.DuplicateFirstWins. With fm.DuplicateError,
+	// every definition of the feature is logged and left out, so it evaluates as not found.
+	DuplicatePolicy fm.DuplicatePolicy
+
+	// LogLevel is the minimum severity of the messages logged by the provider, such as rejected
+	// feature flags and failed fetches. Defaults to fm.LogLevelWarn; fm.LogLevelSilent disables
+	// logging.
+	LogLevel fm.LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+}
+
+// FeatureFlagProvider serves the feature flags of a polled document. It reports the outcome of
+// the last fetch through FeatureManager.Ready and FeatureManager.Stats, and calls the callbacks
+// registered with OnRefresh after each fetch that loaded a changed document.
+type FeatureFlagProvider struct {
+	fm.SnapshotProvider
+
+	fetcher         Fetcher
+	decode          DecodeFunc
+	duplicatePolicy fm.DuplicatePolicy
+	logger          *fm.ProviderLogger
+
+	// refreshMu serializes fetches, so that ETags are applied in order
+	refreshMu sync.Mutex
+	cancel    context.CancelFunc
+	done      chan struct{}
+
+	// intervalMu guards the poll interval; reschedule wakes the poll loop when it changes
+	intervalMu   sync.Mutex
+	pollInterval time.Duration
+	reschedule   chan struct{}
+}
+
+// NewFeatureFlagProvider fetches the document and starts polling it. Call Close to stop polling.
+//
+// Parameters:
+//   - ctx: The context of the initial fetch; it doesn't bound the lifetime of the provider
+//   - fetcher: The source of the document
+//   - options: The provider options, or nil for the defaults
+//
+// Returns:
+//   - *FeatureFlagProvider: The provider
+//   - error: An error if the document can't be fetched or decoded
+func NewFeatureFlagProvider(ctx context.Context, fetcher Fetcher, options *Options) (*FeatureFlagProvider, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	provider := &FeatureFlagProvider{
+		fetcher:         fetcher,
+		decode:          options.Decode,
+		duplicatePolicy: options.DuplicatePolicy,
+		logger:          fm.NewProviderLogger(options.LogLevel, options.Logger),
+		pollInterval:    options.PollInterval,
+		done:            make(chan struct{}),
+		reschedule:      make(chan struct{}, 1),
+	}
+	if provider.decode == nil {
+		decodeOptions := fm.DecodeOptions{Strict: options.StrictDecoding}
+		provider.decode = func(data []byte) ([]fm.FeatureFlag, error) {
+			featureManagement, err := fm.ParseFeatureManagementWithOptions(data, &decodeOptions)
+			return featureManagement.FeatureFlags, err
+		}
+	}
+	if provider.pollInterval == 0 {
+		provider.pollInterval = defaultPollInterval
+	}
+
+	if err := provider.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	pollCtx, cancel := context.WithCancel(context.Background())
+	provider.cancel = cancel
+	go provider.poll(pollCtx)
+
+	return provider, nil
+}
+
+// Refresh fetches the document, conditionally on the ETag of the loaded one, and reloads the
+// feature flags if it changed. A refresh that fails keeps the previously loaded flags.
+//
+// Parameters:
+//   - ctx: The context of the fetch
+//
+// Returns:
+//   - error: An error if the document can't be fetched or decoded
+func (p *FeatureFlagProvider) Refresh(ctx context.Context) error {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	err := p.refresh(ctx)
+	if err != nil {
+		p.RecordRefresh(err)
+	}
+	return err
+}
+
+func (p *FeatureFlagProvider) refresh(ctx context.Context) error {
+	etag := p.Snapshot().Version()
+
+	result, err := p.fetcher.Fetch(ctx, etag)
+	if err != nil {
+		return fmt.Errorf("failed to fetch feature flags: %w", err)
+	}
+	if result.NotModified && etag != "" {
+		p.RecordRefresh(nil)
+		return nil
+	}
+
+	featureFlags, err := p.decode(result.Data)
+	if err != nil {
+		return fmt.Errorf("failed to decode feature flags: %w", err)
+	}
+
+	p.StoreSnapshot(fm.NewFeatureFlagSnapshot(featureFlags, &fm.SnapshotOptions{
+		DuplicatePolicy: p.duplicatePolicy,
+		Version:         result.ETag,
+		Logger:          p.logger,
+	}))
+	return nil
+}
+
+// ETag returns the ETag of the loaded document.
+func (p *FeatureFlagProvider) ETag() string {
+	return p.Snapshot().Version()
+}
+
+// RefreshInterval returns the poll interval; a negative interval means polling is paused.
+func (p *FeatureFlagProvider) RefreshInterval() time.Duration {
+	p.intervalMu.Lock()
+	defer p.intervalMu.Unlock()
+	return p.pollInterval
+}
+
+// SetRefreshInterval changes the poll interval, scheduling the next fetch one interval from now.
+// A negative interval pauses polling and zero restores the default interval.
+//
+// Parameters:
+//   - interval: The new poll interval
+//
+// Returns:
+//   - error: Always nil
+func (p *FeatureFlagProvider) SetRefreshInterval(interval time.Duration) error {
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	p.intervalMu.Lock()
+	p.pollInterval = interval
+	p.intervalMu.Unlock()
+
+	select {
+	case p.reschedule <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Close stops polling. The provider keeps serving the last loaded flags.
+//
+// Returns:
+//   - error: Always nil
+func (p *FeatureFlagProvider) Close() error {
+	p.cancel()
+	<-p.done
+	return nil
+}
+
+func (p *FeatureFlagProvider) poll(ctx context.Context) {
+	defer close(p.done)
+	for {
+		// A nil channel never fires, leaving polling paused until the interval changes
+		var tick <-chan time.Time
+		if interval := p.RefreshInterval(); interval > 0 {
+			tick = time.After(interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.reschedule:
+		case <-tick:
+			if err := p.Refresh(ctx); err != nil && ctx.Err() == nil {
+				p.logger.Errorf("Error refreshing feature flags: %s", err)
+			}
+		}
+	}
+}
+
+// HTTPFetcher fetches the document from a URL with conditional GET requests.
+type HTTPFetcher struct {
+	// URL is the location of the document
+	URL string
+	// Client sends the requests. Defaults to http.DefaultClient.
+	Client *http.Client
+	// Header is added to every request, for example to authenticate
+	Header http.Header
+}
+
+// Fetch gets the document, sending If-None-Match when etag is not empty.
+//
+// Parameters:
+//   - ctx: The context of the request
+//   - etag: The ETag of the loaded document, or empty
+//
+// Returns:
+//   - FetchResult: The document and its ETag, or NotModified on a 304 response
+//   - error: An error if the request fails or the response status is not 200 or 304
+func (f *HTTPFetcher) Fetch(ctx context.Context, etag string) (FetchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.URL, nil)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	for name, values := range f.Header {
+		req.Header[name] = values
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return FetchResult{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return FetchResult{NotModified: true}, nil
+	case http.StatusOK:
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return FetchResult{}, err
+		}
+		return FetchResult{Data: data, ETag: resp.Header.Get("ETag")}, nil
+	default:
+		return FetchResult{}, fmt.Errorf("unexpected status %s from %s", resp.Status, f.URL)
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package objectstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// testServer serves a document with an ETag, honoring If-None-Match
+type testServer struct {
+	mu          sync.Mutex
+	document    string
+	etag        string
+	notModified int
+}
+
+func (s *testServer) set(document, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.document, s.etag = document, etag
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Header.Get("If-None-Match") == s.etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", s.etag)
+	_, _ = io.WriteString(w, s.document)
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	server := &testServer{}
+	server.set(`{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": true}]}}`, `"v1"`)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	fetcher := &HTTPFetcher{URL: httpServer.URL, Header: http.Header{"Authorization": {"Bearer token"}}}
+	provider, err := NewFeatureFlagProvider(context.Background(), fetcher, &Options{PollInterval: -1})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	if flag, err := provider.GetFeatureFlag("Beta"); err != nil || !flag.Enabled {
+		t.Fatalf("Expected Beta to be enabled, got %+v, %v", flag, err)
+	}
+	if provider.ETag() != `"v1"` {
+		t.Errorf("Expected ETag \"v1\", got %s", provider.ETag())
+	}
+
+	if err := provider.Refresh(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	server.mu.Lock()
+	notModified := server.notModified
+	server.mu.Unlock()
+	if notModified != 1 {
+		t.Errorf("Expected a conditional request, got %d not modified responses", notModified)
+	}
+
+	server.set(`{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": false}]}}`, `"v2"`)
+	if err := provider.Refresh(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if flag, _ := provider.GetFeatureFlag("Beta"); flag.Enabled || provider.ETag() != `"v2"` {
+		t.Errorf("Expected the updated document, got %+v with ETag %s", flag, provider.ETag())
+	}
+
+	// A broken document keeps the previously loaded flags
+	server.set(`{"feature_management": `, `"v3"`)
+	if err := provider.Refresh(context.Background()); err == nil {
+		t.Error("Expected an error for a broken document")
+	}
+	if provider.ETag() != `"v2"` {
+		t.Errorf("Expected a failed refresh to keep ETag \"v2\", got %s", provider.ETag())
+	}
+
+	fetcher.Header = nil
+	if err := provider.Refresh(context.Background()); err == nil {
+		t.Error("Expected an error for an unauthorized request")
+	}
+}
+
+func TestFetcherFuncWithDecode(t *testing.T) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, _ = io.WriteString(writer, `{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": true}]}}`)
+	_ = writer.Close()
+
+	fetcher := FetcherFunc(func(ctx context.Context, etag string) (FetchResult, error) {
+		return FetchResult{Data: compressed.Bytes()}, nil
+	})
+	decode := func(data []byte) ([]fm.FeatureFlag, error) {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		document, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		featureManagement, err := fm.ParseFeatureManagement(document)
+		return featureManagement.FeatureFlags, err
+	}
+
+	provider, err := NewFeatureFlagProvider(context.Background(), fetcher, &Options{PollInterval: -1, Decode: decode})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	if flag, err := provider.GetFeatureFlag("Beta"); err != nil || !flag.Enabled {
+		t.Errorf("Expected Beta to be enabled, got %+v, %v", flag, err)
+	}
+}
+
+func TestDuplicatePolicy(t *testing.T) {
+	fetcher := FetcherFunc(func(ctx context.Context, etag string) (FetchResult, error) {
+		document := `{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": true}, {"id": "Alpha"}, {"id": "Beta"}]}}`
+		return FetchResult{Data: []byte(document)}, nil
+	})
+
+	tests := []struct {
+		policy  fm.DuplicatePolicy
+		found   bool
+		enabled bool
+		served  int
+	}{
+		{policy: "", found: true, enabled: true, served: 2},
+		{policy: fm.DuplicateLastWins, found: true, enabled: false, served: 2},
+		{policy: fm.DuplicateError, found: false, served: 1},
+	}
+	for _, test := range tests {
+		provider, err := NewFeatureFlagProvider(context.Background(), fetcher, &Options{PollInterval: -1, DuplicatePolicy: test.policy})
+		if err != nil {
+			t.Fatalf("Failed to create provider: %v", err)
+		}
+		defer provider.Close()
+
+		flag, err := provider.GetFeatureFlag("Beta")
+		if (err == nil) != test.found || flag.Enabled != test.enabled {
+			t.Errorf("Policy %q: expected found=%v enabled=%v, got %+v, %v", test.policy, test.found, test.enabled, flag, err)
+		}
+		if flags, _ := provider.GetFeatureFlags(); len(flags) != test.served {
+			t.Errorf("Policy %q: expected each feature served once, got %+v", test.policy, flags)
+		}
+
+		rejected := provider.ValidationErrors()
+		if test.found != (len(rejected) == 0) || (!test.found && !errors.Is(rejected[0], fm.ErrDuplicateFeature)) {
+			t.Errorf("Policy %q: unexpected validation errors %v", test.policy, rejected)
+		}
+	}
+}
+
+func TestLogging(t *testing.T) {
+	fetcher := FetcherFunc(func(ctx context.Context, etag string) (FetchResult, error) {
+		return FetchResult{Data: []byte(`{"feature_management": {"feature_flags": [{"id": ""}]}}`)}, nil
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	provider, err := NewFeatureFlagProvider(context.Background(), fetcher, &Options{PollInterval: -1, Logger: logger})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+	if output := buf.String(); !strings.Contains(output, "level=WARN msg=\"Ignoring invalid feature flag") {
+		t.Errorf("Expected the invalid flag to be logged to the logger, got %q", output)
+	}
+
+	buf.Reset()
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+	provider, err = NewFeatureFlagProvider(context.Background(), fetcher, &Options{PollInterval: -1, LogLevel: fm.LogLevelSilent})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing to be logged when silent, got %q", buf.String())
+	}
+}
+
+func TestFetchError(t *testing.T) {
+	fetcher := FetcherFunc(func(ctx context.Context, etag string) (FetchResult, error) {
+		return FetchResult{}, errors.New("bucket not found")
+	})
+
+	if _, err := NewFeatureFlagProvider(context.Background(), fetcher, nil); err == nil {
+		t.Error("Expected an error when the initial fetch fails")
+	}
+}
+
+func TestSetRefreshInterval(t *testing.T) {
+	fetches := make(chan struct{}, 10)
+	fetcher := FetcherFunc(func(ctx context.Context, etag string) (FetchResult, error) {
+		select {
+		case fetches <- struct{}{}:
+		default:
+		}
+		return FetchResult{Data: []byte(`{"feature_management": {"feature_flags": []}}`)}, nil
+	})
+
+	provider, err := NewFeatureFlagProvider(context.Background(), fetcher, &Options{PollInterval: -1})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+	<-fetches
+
+	if err := provider.SetRefreshInterval(10 * time.Millisecond); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if provider.RefreshInterval() != 10*time.Millisecond {
+		t.Errorf("Expected a 10ms interval, got %v", provider.RefreshInterval())
+	}
+
+	select {
+	case <-fetches:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected polling to resume after setting the interval")
+	}
+
+	if err := provider.SetRefreshInterval(0); err != nil || provider.RefreshInterval() != defaultPollInterval {
+		t.Errorf("Expected zero to restore the default interval, got %v, %v", provider.RefreshInterval(), err)
+	}
+}
+
+func TestProviderStatus(t *testing.T) {
+	var fail bool
+	fetcher := FetcherFunc(func(ctx context.Context, etag string) (FetchResult, error) {
+		if fail {
+			return FetchResult{}, errors.New("bucket not found")
+		}
+		return FetchResult{Data: []byte(`{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": true}]}}`)}, nil
+	})
+
+	provider, err := NewFeatureFlagProvider(context.Background(), fetcher, &Options{PollInterval: -1})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	manager, err := fm.NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	stats := manager.Stats()
+	if !manager.Ready() || stats.FeatureFlagCount != 1 || stats.LastRefreshTime.IsZero() || stats.LastRefreshError != nil {
+		t.Errorf("Expected a ready manager after the initial fetch, got %+v", stats)
+	}
+
+	fail = true
+	if err := provider.Refresh(context.Background()); err == nil {
+		t.Fatal("Expected the refresh to fail")
+	}
+	if stats := manager.Stats(); stats.LastRefreshError == nil || stats.FeatureFlagCount != 1 {
+		t.Errorf("Expected the failed refresh to be reported, got %+v", stats)
+	}
+}
+
+func TestOnFeatureChanged(t *testing.T) {
+	document := `{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": false}]}}`
+	fetcher := FetcherFunc(func(ctx context.Context, etag string) (FetchResult, error) {
+		return FetchResult{Data: []byte(document)}, nil
+	})
+
+	provider, err := NewFeatureFlagProvider(context.Background(), fetcher, &Options{PollInterval: -1})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	manager, err := fm.NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	var changes []fm.FeatureChange
+	manager.OnFeatureChanged("Beta", func(change fm.FeatureChange) {
+		changes = append(changes, change)
+	})
+
+	if err := provider.Refresh(context.Background()); err != nil || len(changes) != 0 {
+		t.Fatalf("Expected an unchanged document not to report changes, got %+v, %v", changes, err)
+	}
+
+	document = `{"feature_management": {"feature_flags": [{"id": "Beta", "enabled": true}]}}`
+	if err := provider.Refresh(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(changes) != 1 || !changes[0].Current.Enabled {
+		t.Errorf("Expected Beta to change to enabled, got %+v", changes)
+	}
+}
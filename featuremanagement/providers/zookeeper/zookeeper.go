@@ -0,0 +1,301 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package zookeeper provides a feature flag provider backed by ZooKeeper. Each child of a parent
+// znode holds a feature flag in the feature_management schema as JSON; a flag without an "id"
+// takes the name of its znode as its ID. The provider watches the parent for added and removed
+// children and each child for changed data, so changes propagate as soon as they are written.
+package zookeeper
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+const defaultRetryInterval = 5 * time.Second
+
+// Options configures the FeatureFlagProvider.
+type Options struct {
+	// StrictDecoding rejects feature flags with unknown fields or values of the wrong type,
+	// instead of ignoring or converting them. Rejected znodes are logged and left out.
+	StrictDecoding bool
+
+	// RetryInterval is how long the provider waits before watching again after its watches were
+	// lost, for example when the session expired. Defaults to 5 seconds.
+	RetryInterval time.Duration
+
+	// DuplicatePolicy decides which definition of a feature flag defined more than once is
+	// served, in znode name order. Defaults to fm.DuplicateFirstWins. With fm.DuplicateError,
+	// every definition of the feature is logged and left out, so it evaluates as not found.
+	DuplicatePolicy fm.DuplicatePolicy
+
+	// LogLevel is the minimum severity of the messages logged by the provider, such as rejected
+	// znodes and lost watches. Defaults to fm.LogLevelWarn; fm.LogLevelSilent disables logging.
+	LogLevel fm.LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+}
+
+// conn is the part of a ZooKeeper connection the provider uses
+type conn interface {
+	ChildrenW(path string) ([]string, *zk.Stat, <-chan zk.Event, error)
+	GetW(path string) ([]byte, *zk.Stat, <-chan zk.Event, error)
+}
+
+// FeatureFlagProvider serves the feature flags stored under a ZooKeeper znode. OnRefresh
+// callbacks run on the watching goroutine after each change to the znodes, so they must not block.
+type FeatureFlagProvider struct {
+	fm.SnapshotProvider
+
+	conn            conn
+	path            string
+	decodeOptions   fm.DecodeOptions
+	duplicatePolicy fm.DuplicatePolicy
+	logger          *fm.ProviderLogger
+	retryInterval   time.Duration
+
+	// The state below is only used by the watch loop, after the initial sync
+	nodes            map[string]fm.FeatureFlag
+	watchingChildren bool
+	watching         map[string]bool
+	// generation identifies the current set of watches, so that events of watches lost with a
+	// previous session are ignored
+	generation int
+	events     chan watchEvent
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// watchEvent is an event of a watch on the parent, when child is empty, or on a child
+type watchEvent struct {
+	generation int
+	child      string
+	event      zk.Event
+}
+
+// NewFeatureFlagProvider loads the feature flags under a znode and starts watching it.
+// Call Close to stop watching.
+//
+// Parameters:
+//   - conn: The ZooKeeper connection
+//   - parent: The path of the znode whose children hold the feature flags, for example "/featureflags"
+//   - options: The provider options, or nil for the defaults
+//
+// Returns:
+//   - *FeatureFlagProvider: The provider
+//   - error: An error if the znode can't be read
+func NewFeatureFlagProvider(conn *zk.Conn, parent string, options *Options) (*FeatureFlagProvider, error) {
+	return newFeatureFlagProvider(conn, parent, options)
+}
+
+func newFeatureFlagProvider(conn conn, parent string, options *Options) (*FeatureFlagProvider, error) {
+	if options == nil {
+		options = &Options{}
+	}
+
+	provider := &FeatureFlagProvider{
+		conn:            conn,
+		path:            parent,
+		decodeOptions:   fm.DecodeOptions{Strict: options.StrictDecoding},
+		duplicatePolicy: options.DuplicatePolicy,
+		logger:          fm.NewProviderLogger(options.LogLevel, options.Logger),
+		retryInterval:   options.RetryInterval,
+		nodes:           make(map[string]fm.FeatureFlag),
+		watching:        make(map[string]bool),
+		events:          make(chan watchEvent),
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+	if provider.retryInterval <= 0 {
+		provider.retryInterval = defaultRetryInterval
+	}
+
+	if err := provider.sync(); err != nil {
+		close(provider.stop)
+		return nil, err
+	}
+	provider.publish()
+
+	go provider.watch()
+	return provider, nil
+}
+
+// Close stops watching the znode. The provider keeps serving the last loaded flags.
+// Calling Close more than once has no further effect.
+//
+// Returns:
+//   - error: Always nil
+func (p *FeatureFlagProvider) Close() error {
+	p.stopOnce.Do(func() { close(p.stop) })
+	<-p.done
+	return nil
+}
+
+// watch handles watch events until the provider is closed. Watches fire once, so each event
+// sets a new watch on the znode it concerns.
+func (p *FeatureFlagProvider) watch() {
+	defer close(p.done)
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case event := <-p.events:
+			if event.generation != p.generation {
+				continue
+			}
+			if err := p.handle(event); err != nil {
+				p.logger.Errorf("Lost feature flag watches on %s: %s", p.path, err)
+				if !p.resync() {
+					return
+				}
+			}
+			p.publish()
+		}
+	}
+}
+
+// handle applies a watch event, returning an error if the watches were lost
+func (p *FeatureFlagProvider) handle(event watchEvent) error {
+	if event.event.Type == zk.EventNotWatching {
+		return event.event.Err
+	}
+
+	if event.child == "" {
+		p.watchingChildren = false
+		return p.sync()
+	}
+
+	p.watching[event.child] = false
+	if event.event.Type == zk.EventNodeDeleted {
+		delete(p.nodes, event.child)
+		return nil
+	}
+	return p.read(event.child)
+}
+
+// resync drops every watch and syncs again after the retry interval, until it succeeds or the
+// provider is closed. It reports whether the provider is still open.
+func (p *FeatureFlagProvider) resync() bool {
+	for {
+		p.generation++
+		p.watchingChildren = false
+		clear(p.watching)
+
+		select {
+		case <-p.stop:
+			return false
+		case <-time.After(p.retryInterval):
+		}
+
+		err := p.sync()
+		if err == nil {
+			return true
+		}
+		p.logger.Errorf("Error watching feature flags on %s: %s", p.path, err)
+	}
+}
+
+// sync watches the children of the parent, reading the children that aren't watched yet and
+// dropping those that were removed
+func (p *FeatureFlagProvider) sync() error {
+	if !p.watchingChildren {
+		children, _, events, err := p.conn.ChildrenW(p.path)
+		if err != nil {
+			return fmt.Errorf("failed to list feature flags on %s: %w", p.path, err)
+		}
+		p.watchingChildren = true
+		p.forward("", events)
+
+		listed := make(map[string]bool, len(children))
+		for _, child := range children {
+			listed[child] = true
+		}
+		for child := range p.nodes {
+			if !listed[child] {
+				delete(p.nodes, child)
+			}
+		}
+		for _, child := range children {
+			if !p.watching[child] {
+				if err := p.read(child); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// read reads and watches a child
+func (p *FeatureFlagProvider) read(child string) error {
+	data, _, events, err := p.conn.GetW(path.Join(p.path, child))
+	if errors.Is(err, zk.ErrNoNode) {
+		delete(p.nodes, child)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read feature flag %s: %w", child, err)
+	}
+	p.watching[child] = true
+	p.forward(child, events)
+
+	flag, err := fm.DecodeFeatureFlag(data, child, &p.decodeOptions)
+	if err != nil {
+		p.logger.Warnf("Ignoring feature flag %s: %s", child, err)
+		delete(p.nodes, child)
+		return nil
+	}
+	p.nodes[child] = flag
+	return nil
+}
+
+// forward delivers the single event of a watch to the watch loop
+func (p *FeatureFlagProvider) forward(child string, events <-chan zk.Event) {
+	generation := p.generation
+	go func() {
+		select {
+		case event := <-events:
+			select {
+			case p.events <- watchEvent{generation: generation, child: child, event: event}:
+			case <-p.stop:
+			}
+		case <-p.stop:
+		}
+	}()
+}
+
+// publish swaps in a snapshot of the current znodes, sorted by feature flag ID. Znodes defining
+// the same ID are passed in name order to the duplicate policy.
+func (p *FeatureFlagProvider) publish() {
+	names := make([]string, 0, len(p.nodes))
+	for name := range p.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	flags := make([]fm.FeatureFlag, 0, len(names))
+	for _, name := range names {
+		flags = append(flags, p.nodes[name])
+	}
+	sort.SliceStable(flags, func(i, j int) bool {
+		return flags[i].ID < flags[j].ID
+	})
+
+	p.StoreSnapshot(fm.NewFeatureFlagSnapshot(flags, &fm.SnapshotOptions{
+		DuplicatePolicy: p.duplicatePolicy,
+		Logger:          p.logger,
+	}))
+}
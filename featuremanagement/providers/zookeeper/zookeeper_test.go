@@ -0,0 +1,211 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package zookeeper
+
+import (
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-zookeeper/zk"
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// fakeConn is an in-memory znode tree with one-shot watches, holding the children of a single parent
+type fakeConn struct {
+	mu           sync.Mutex
+	parent       string
+	children     map[string]string
+	childWatches []chan zk.Event
+	dataWatches  map[string][]chan zk.Event
+}
+
+func newFakeConn(parent string) *fakeConn {
+	return &fakeConn{parent: parent, children: make(map[string]string), dataWatches: make(map[string][]chan zk.Event)}
+}
+
+func (c *fakeConn) ChildrenW(p string) ([]string, *zk.Stat, <-chan zk.Event, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if p != c.parent {
+		return nil, nil, nil, zk.ErrNoNode
+	}
+	children := make([]string, 0, len(c.children))
+	for child := range c.children {
+		children = append(children, child)
+	}
+	events := make(chan zk.Event, 1)
+	c.childWatches = append(c.childWatches, events)
+	return children, &zk.Stat{}, events, nil
+}
+
+func (c *fakeConn) GetW(p string) ([]byte, *zk.Stat, <-chan zk.Event, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.children[path.Base(p)]
+	if !ok {
+		return nil, nil, nil, zk.ErrNoNode
+	}
+	events := make(chan zk.Event, 1)
+	c.dataWatches[path.Base(p)] = append(c.dataWatches[path.Base(p)], events)
+	return []byte(data), &zk.Stat{}, events, nil
+}
+
+func (c *fakeConn) set(child string, data string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, exists := c.children[child]
+	c.children[child] = data
+	if exists {
+		c.fire(c.dataWatches[child], zk.EventNodeDataChanged)
+		delete(c.dataWatches, child)
+	} else {
+		c.fire(c.childWatches, zk.EventNodeChildrenChanged)
+		c.childWatches = nil
+	}
+}
+
+func (c *fakeConn) delete(child string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.children, child)
+	c.fire(c.dataWatches[child], zk.EventNodeDeleted)
+	delete(c.dataWatches, child)
+	c.fire(c.childWatches, zk.EventNodeChildrenChanged)
+	c.childWatches = nil
+}
+
+// expire ends every watch, as when the session expires
+func (c *fakeConn) expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fire(c.childWatches, zk.EventNotWatching)
+	c.childWatches = nil
+	for child, watches := range c.dataWatches {
+		c.fire(watches, zk.EventNotWatching)
+		delete(c.dataWatches, child)
+	}
+}
+
+func (c *fakeConn) fire(watches []chan zk.Event, eventType zk.EventType) {
+	for _, events := range watches {
+		event := zk.Event{Type: eventType}
+		if eventType == zk.EventNotWatching {
+			event.Err = zk.ErrSessionExpired
+		}
+		events <- event
+	}
+}
+
+func (c *fakeConn) watchCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := len(c.childWatches)
+	for _, watches := range c.dataWatches {
+		count += len(watches)
+	}
+	return count
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the update")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFeatureFlagProvider(t *testing.T) {
+	conn := newFakeConn("/featureflags")
+	conn.set("Beta", `{"enabled": true}`)
+	conn.set("alpha", `{"id": "Alpha", "enabled": false}`)
+	conn.set("Broken", `{"enabled": `)
+
+	provider, err := newFeatureFlagProvider(conn, "/featureflags", &Options{RetryInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	defer provider.Close()
+
+	flags, _ := provider.GetFeatureFlags()
+	if len(flags) != 2 || flags[0].ID != "Alpha" || flags[1].ID != "Beta" {
+		t.Fatalf("Expected Alpha and the znode-named Beta, got %+v", flags)
+	}
+	// The parent and every child are watched once
+	if count := conn.watchCount(); count != 4 {
+		t.Errorf("Expected 4 watches, got %d", count)
+	}
+
+	manager, err := fm.NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	changes := make(chan fm.FeatureChange, 10)
+	manager.OnFeatureChanged("Beta", func(change fm.FeatureChange) {
+		changes <- change
+	})
+
+	conn.set("Beta", `{"enabled": false}`)
+	waitFor(t, func() bool {
+		flag, _ := provider.GetFeatureFlag("Beta")
+		return !flag.Enabled
+	})
+	select {
+	case change := <-changes:
+		if change.Current == nil || change.Current.Enabled {
+			t.Errorf("Expected Beta to change to disabled, got %+v", change)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the change to be reported to OnFeatureChanged callbacks")
+	}
+
+	conn.set("Gamma", `{"enabled": true}`)
+	waitFor(t, func() bool {
+		_, err := provider.GetFeatureFlag("Gamma")
+		return err == nil
+	})
+
+	// A value that isn't a flag object is left out rather than crashing the watch loop
+	conn.set("Gamma", `null`)
+	waitFor(t, func() bool {
+		_, err := provider.GetFeatureFlag("Gamma")
+		return err != nil
+	})
+
+	conn.delete("alpha")
+	waitFor(t, func() bool {
+		_, err := provider.GetFeatureFlag("Alpha")
+		return err != nil
+	})
+
+	// Watches lost with the session are set again
+	conn.expire()
+	waitFor(t, func() bool {
+		return conn.watchCount() == 4
+	})
+	conn.set("Gamma", `{"enabled": false}`)
+	waitFor(t, func() bool {
+		flag, err := provider.GetFeatureFlag("Gamma")
+		return err == nil && !flag.Enabled
+	})
+}
+
+func TestFeatureFlagProviderCloseTwice(t *testing.T) {
+	provider, err := newFeatureFlagProvider(newFakeConn("/featureflags"), "/featureflags", nil)
+	if err != nil {
+		t.Fatalf("Failed to create provider: %v", err)
+	}
+	provider.Close()
+	provider.Close()
+}
+
+func TestFeatureFlagProviderMissingParent(t *testing.T) {
+	if _, err := newFeatureFlagProvider(newFakeConn("/featureflags"), "/other", nil); err == nil {
+		t.Error("Expected an error when the parent znode doesn't exist")
+	}
+}
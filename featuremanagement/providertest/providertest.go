@@ -0,0 +1,238 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package providertest provides a conformance test suite for FeatureFlagProvider implementations.
+//
+// Authors of custom providers run the suite from their own tests to check the semantics the
+// FeatureManager relies on:
+//
+//	func TestProviderConformance(t *testing.T) {
+//		providertest.Run(t, func(t *testing.T, flags []featuremanagement.FeatureFlag) (featuremanagement.FeatureFlagProvider, providertest.UpdateFunc) {
+//			provider := newMyProvider(flags)
+//			return provider, provider.replaceAll
+//		})
+//	}
+package providertest
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// UpdateFunc replaces the feature flags served by a provider, as a refresh from its source would.
+// It returns once the new flags are visible to readers.
+type UpdateFunc func(flags []fm.FeatureFlag)
+
+// Factory creates the provider under test, serving the given feature flags.
+// It returns a nil UpdateFunc for providers that cannot be refreshed; the refresh tests are then skipped.
+type Factory func(t *testing.T, flags []fm.FeatureFlag) (fm.FeatureFlagProvider, UpdateFunc)
+
+// Run validates the provider created by newProvider against the FeatureFlagProvider contract:
+//   - GetFeatureFlag returns the flag with the requested ID, and an error for unknown IDs
+//   - GetFeatureFlags returns every flag, and an empty result without error when there are none
+//   - All, when implemented, yields the same flags as GetFeatureFlags and supports stopping early
+//   - reads are safe for concurrent use, including while the provider refreshes
+//   - refreshed flags, including removals, become visible to subsequent reads
+//
+// Parameters:
+//   - t: The test to run the suite in
+//   - newProvider: Creates a new provider under test for each case
+func Run(t *testing.T, newProvider Factory) {
+	t.Helper()
+
+	t.Run("GetFeatureFlag", func(t *testing.T) {
+		provider, _ := newProvider(t, sampleFlags())
+		for _, expected := range sampleFlags() {
+			flag, err := provider.GetFeatureFlag(expected.ID)
+			if err != nil {
+				t.Fatalf("GetFeatureFlag(%q) returned error: %v", expected.ID, err)
+			}
+			if flag.ID != expected.ID || flag.Enabled != expected.Enabled {
+				t.Errorf("GetFeatureFlag(%q) = {ID: %q, Enabled: %v}, want {ID: %q, Enabled: %v}",
+					expected.ID, flag.ID, flag.Enabled, expected.ID, expected.Enabled)
+			}
+		}
+
+		if flag, _ := provider.GetFeatureFlag("Variants"); flag.Allocation == nil || len(flag.Variants) != 2 {
+			t.Errorf("GetFeatureFlag(%q) lost its variants or allocation: %+v", "Variants", flag)
+		}
+		if flag, _ := provider.GetFeatureFlag("Targeted"); flag.Conditions == nil || len(flag.Conditions.ClientFilters) != 1 {
+			t.Errorf("GetFeatureFlag(%q) lost its conditions: %+v", "Targeted", flag)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		provider, _ := newProvider(t, sampleFlags())
+		if _, err := provider.GetFeatureFlag("Missing"); err == nil {
+			t.Error("GetFeatureFlag returned no error for an unknown feature flag")
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		provider, _ := newProvider(t, nil)
+		flags, err := provider.GetFeatureFlags()
+		if err != nil {
+			t.Fatalf("GetFeatureFlags returned error for an empty provider: %v", err)
+		}
+		if len(flags) != 0 {
+			t.Errorf("GetFeatureFlags returned %d flags for an empty provider", len(flags))
+		}
+		if _, err := provider.GetFeatureFlag("Beta"); err == nil {
+			t.Error("GetFeatureFlag returned no error for an empty provider")
+		}
+	})
+
+	t.Run("GetFeatureFlags", func(t *testing.T) {
+		provider, _ := newProvider(t, sampleFlags())
+		flags, err := provider.GetFeatureFlags()
+		if err != nil {
+			t.Fatalf("GetFeatureFlags returned error: %v", err)
+		}
+		if got, want := flagIDs(flags), flagIDs(sampleFlags()); got != want {
+			t.Errorf("GetFeatureFlags returned %s, want %s", got, want)
+		}
+	})
+
+	t.Run("All", func(t *testing.T) {
+		provider, _ := newProvider(t, sampleFlags())
+		iterator, ok := provider.(fm.FeatureFlagIterator)
+		if !ok {
+			t.Skip("provider does not implement FeatureFlagIterator")
+		}
+
+		var flags []fm.FeatureFlag
+		for flag := range iterator.All() {
+			flags = append(flags, flag)
+		}
+		listed, _ := provider.GetFeatureFlags()
+		if got, want := flagIDs(flags), flagIDs(listed); got != want {
+			t.Errorf("All yielded %s, GetFeatureFlags returned %s", got, want)
+		}
+
+		count := 0
+		for range iterator.All() {
+			count++
+			break
+		}
+		if count != 1 {
+			t.Errorf("All yielded %d flags after the loop stopped, want 1", count)
+		}
+	})
+
+	t.Run("ConcurrentReads", func(t *testing.T) {
+		provider, update := newProvider(t, sampleFlags())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					_, _ = provider.GetFeatureFlag("Beta")
+					_, _ = provider.GetFeatureFlags()
+					if iterator, ok := provider.(fm.FeatureFlagIterator); ok {
+						for range iterator.All() {
+						}
+					}
+				}
+			}()
+		}
+
+		if update != nil {
+			for j := 0; j < 20; j++ {
+				flags := sampleFlags()
+				flags[0].Enabled = j%2 == 0
+				update(flags)
+			}
+		}
+		wg.Wait()
+	})
+
+	t.Run("RefreshVisibility", func(t *testing.T) {
+		provider, update := newProvider(t, sampleFlags())
+		if update == nil {
+			t.Skip("provider cannot be refreshed")
+		}
+
+		update([]fm.FeatureFlag{
+			{ID: "Beta", Enabled: false},
+			{ID: "Added", Enabled: true},
+		})
+
+		if flag, err := provider.GetFeatureFlag("Beta"); err != nil || flag.Enabled {
+			t.Errorf("GetFeatureFlag(%q) after refresh = %+v, %v, want disabled", "Beta", flag, err)
+		}
+		if flag, err := provider.GetFeatureFlag("Added"); err != nil || !flag.Enabled {
+			t.Errorf("GetFeatureFlag(%q) after refresh = %+v, %v, want enabled", "Added", flag, err)
+		}
+		if _, err := provider.GetFeatureFlag("Variants"); err == nil {
+			t.Errorf("GetFeatureFlag(%q) returned a flag removed by the refresh", "Variants")
+		}
+
+		flags, err := provider.GetFeatureFlags()
+		if err != nil {
+			t.Fatalf("GetFeatureFlags after refresh returned error: %v", err)
+		}
+		if got := flagIDs(flags); got != "[Added Beta]" {
+			t.Errorf("GetFeatureFlags after refresh returned %s, want [Added Beta]", got)
+		}
+	})
+
+	t.Run("FeatureManager", func(t *testing.T) {
+		provider, _ := newProvider(t, sampleFlags())
+		manager, err := fm.NewFeatureManager(provider, nil)
+		if err != nil {
+			t.Fatalf("NewFeatureManager returned error: %v", err)
+		}
+
+		if enabled, err := manager.IsEnabled("Beta"); err != nil || !enabled {
+			t.Errorf("IsEnabled(%q) = %v, %v, want true", "Beta", enabled, err)
+		}
+		if variant, err := manager.GetVariant("Variants", nil); err != nil || variant == nil || variant.Name != "Small" {
+			t.Errorf("GetVariant(%q) = %v, %v, want Small", "Variants", variant, err)
+		}
+	})
+}
+
+func sampleFlags() []fm.FeatureFlag {
+	return []fm.FeatureFlag{
+		{ID: "Beta", Enabled: true},
+		{ID: "Legacy", Enabled: false},
+		{
+			ID:      "Targeted",
+			Enabled: true,
+			Conditions: &fm.Conditions{
+				ClientFilters: []fm.ClientFilter{
+					{
+						Name: "Microsoft.Targeting",
+						Parameters: map[string]any{
+							"Audience": map[string]any{"Users": []any{"Alice"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			ID:       "Variants",
+			Enabled:  true,
+			Variants: []fm.VariantDefinition{{Name: "Small"}, {Name: "Big"}},
+			Allocation: &fm.VariantAllocation{
+				DefaultWhenEnabled: "Small",
+			},
+		},
+	}
+}
+
+// flagIDs formats the sorted IDs of the flags for comparison
+func flagIDs(flags []fm.FeatureFlag) string {
+	ids := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		ids = append(ids, flag.ID)
+	}
+	sort.Strings(ids)
+	return fmt.Sprint(ids)
+}
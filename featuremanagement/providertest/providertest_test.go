@@ -0,0 +1,58 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package providertest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+)
+
+// mutableProvider is a minimal refreshable provider used to exercise the suite
+type mutableProvider struct {
+	mu    sync.RWMutex
+	flags []fm.FeatureFlag
+}
+
+func (p *mutableProvider) GetFeatureFlag(name string) (fm.FeatureFlag, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, flag := range p.flags {
+		if flag.ID == name {
+			return flag, nil
+		}
+	}
+	return fm.FeatureFlag{}, fmt.Errorf("feature flag with ID %s not found", name)
+}
+
+func (p *mutableProvider) GetFeatureFlags() ([]fm.FeatureFlag, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.flags, nil
+}
+
+func (p *mutableProvider) update(flags []fm.FeatureFlag) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flags = flags
+}
+
+func TestStaticProvider(t *testing.T) {
+	Run(t, func(t *testing.T, flags []fm.FeatureFlag) (fm.FeatureFlagProvider, UpdateFunc) {
+		byName := make(map[string]fm.FeatureFlag, len(flags))
+		for _, flag := range flags {
+			byName[flag.ID] = flag
+		}
+		return fm.NewStaticProvider(byName), nil
+	})
+}
+
+func TestMutableProvider(t *testing.T) {
+	Run(t, func(t *testing.T, flags []fm.FeatureFlag) (fm.FeatureFlagProvider, UpdateFunc) {
+		provider := &mutableProvider{flags: flags}
+		return provider, provider.update
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// QuotaPeriodTotal counts activations over the whole lifetime of the counter store
+	QuotaPeriodTotal = "Total"
+	// QuotaPeriodDay counts activations per UTC day, starting again at midnight
+	QuotaPeriodDay = "Day"
+)
+
+// QuotaCounterStore counts the activations of features for the quota filter. Counters shared
+// through an external store such as Redis apply the quota across all instances of an application.
+//
+// Implementations must be safe for concurrent use.
+type QuotaCounterStore interface {
+	// Increment adds one to the counter of the key and returns the new count. A counter that
+	// doesn't exist starts at zero and expires after ttl, or never when ttl is zero.
+	Increment(key string, ttl time.Duration) (int64, error)
+}
+
+// QuotaFilter enables a feature for only the first Limit activations, in total or per day, for
+// example to cap the cost of a beta program or of an expensive feature. Every evaluation that
+// reaches the filter consumes an activation, whether or not it is admitted.
+//
+// The filter isn't registered by default since it needs a counter store; register it with
+// Options.Filters. Errors of the store fail the evaluation of the filter.
+type QuotaFilter struct {
+	store      QuotaCounterStore
+	now        func() time.Time
+	paramCache parameterCache[QuotaFilterParameters]
+}
+
+// QuotaFilterParameters defines the parameters for the quota filter
+type QuotaFilterParameters struct {
+	// Limit is the number of activations admitted per period
+	Limit int64
+	// Period is QuotaPeriodTotal, the default, or QuotaPeriodDay
+	Period string
+}
+
+// NewQuotaFilter creates a quota filter counting activations in the given store.
+//
+// Parameters:
+//   - store: The store holding the activation counters
+//
+// Returns:
+//   - *QuotaFilter: The quota filter
+func NewQuotaFilter(store QuotaCounterStore) *QuotaFilter {
+	return &QuotaFilter{store: store, now: time.Now}
+}
+
+func (q *QuotaFilter) Name() string {
+	return "Microsoft.Quota"
+}
+
+func (q *QuotaFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appContext any) (bool, error) {
+	params, err := q.paramCache.get(evalCtx.FeatureName, evalCtx.Parameters, decodeQuotaParams)
+	if err != nil {
+		return false, err
+	}
+	if params.Limit == 0 {
+		return false, nil
+	}
+
+	key, ttl := quotaKey(evalCtx.FeatureName, params.Period, q.now().UTC())
+	count, err := q.store.Increment(key, ttl)
+	if err != nil {
+		return false, fmt.Errorf("failed to count the activation of feature %s: %w", evalCtx.FeatureName, err)
+	}
+
+	return count <= params.Limit, nil
+}
+
+func (q *QuotaFilter) preloadParameters(featureName string, parameters map[string]any) error {
+	_, err := q.paramCache.get(featureName, parameters, decodeQuotaParams)
+	return err
+}
+
+func decodeQuotaParams(featureName string, parameters map[string]any) (QuotaFilterParameters, error) {
+	var params QuotaFilterParameters
+	if err := BindParameters(parameters, &params); err != nil {
+		return QuotaFilterParameters{}, fmt.Errorf("invalid quota parameters format for feature %s: %w", featureName, err)
+	}
+
+	if params.Limit < 0 {
+		return QuotaFilterParameters{}, fmt.Errorf("invalid feature flag: %s. Quota limit must not be negative", featureName)
+	}
+	if params.Period != "" && params.Period != QuotaPeriodTotal && params.Period != QuotaPeriodDay {
+		return QuotaFilterParameters{}, fmt.Errorf("invalid feature flag: %s. Quota period must be '%s' or '%s'", featureName, QuotaPeriodTotal, QuotaPeriodDay)
+	}
+
+	return params, nil
+}
+
+// quotaKey returns the counter key of a feature for the period containing now, and how long the
+// counter must be kept
+func quotaKey(featureName string, period string, now time.Time) (string, time.Duration) {
+	if period != QuotaPeriodDay {
+		return featureName, 0
+	}
+
+	day := now.Truncate(24 * time.Hour)
+	return featureName + "/" + day.Format(time.DateOnly), day.Add(24 * time.Hour).Sub(now)
+}
+
+// MemoryQuotaCounterStore is a QuotaCounterStore that keeps counters in memory, for tests and
+// single-instance applications. The zero value is ready to use.
+type MemoryQuotaCounterStore struct {
+	mu       sync.Mutex
+	counters map[string]*quotaCounter
+}
+
+type quotaCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// Increment adds one to the counter of the key and returns the new count.
+//
+// Parameters:
+//   - key: The key of the counter
+//   - ttl: How long a new counter is kept, or zero to keep it forever
+//
+// Returns:
+//   - int64: The count including this activation
+//   - error: Always nil
+func (s *MemoryQuotaCounterStore) Increment(key string, ttl time.Duration) (int64, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.counters == nil {
+		s.counters = make(map[string]*quotaCounter)
+	}
+
+	counter, ok := s.counters[key]
+	if !ok || (!counter.expiresAt.IsZero() && !now.Before(counter.expiresAt)) {
+		// Drop the counters of past periods so that daily keys don't accumulate
+		for k, c := range s.counters {
+			if !c.expiresAt.IsZero() && !now.Before(c.expiresAt) {
+				delete(s.counters, k)
+			}
+		}
+
+		counter = &quotaCounter{}
+		if ttl > 0 {
+			counter.expiresAt = now.Add(ttl)
+		}
+		s.counters[key] = counter
+	}
+	counter.count++
+
+	return counter.count, nil
+}
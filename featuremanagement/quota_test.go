@@ -0,0 +1,92 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type failingCounterStore struct{}
+
+func (failingCounterStore) Increment(key string, ttl time.Duration) (int64, error) {
+	return 0, errors.New("store unavailable")
+}
+
+func TestQuotaFilter(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {
+			Enabled: true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{
+				Name:       "Microsoft.Quota",
+				Parameters: map[string]any{"Limit": 3},
+			}}},
+		},
+	})
+	manager, err := NewFeatureManager(provider, &Options{
+		Filters: []FeatureFilter{NewQuotaFilter(&MemoryQuotaCounterStore{})},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	for i, expected := range []bool{true, true, true, false, false} {
+		if enabled, err := manager.IsEnabled("Beta"); err != nil || enabled != expected {
+			t.Errorf("Expected activation %d to be enabled=%v, got %v, %v", i+1, expected, enabled, err)
+		}
+	}
+}
+
+func TestQuotaFilterPerDay(t *testing.T) {
+	now := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)
+	filter := NewQuotaFilter(&MemoryQuotaCounterStore{})
+	filter.now = func() time.Time { return now }
+	evalCtx := FeatureFilterEvaluationContext{
+		FeatureName: "Beta",
+		Parameters:  map[string]any{"Limit": 1, "Period": QuotaPeriodDay},
+	}
+
+	for _, expected := range []bool{true, false} {
+		if enabled, err := filter.Evaluate(evalCtx, nil); err != nil || enabled != expected {
+			t.Errorf("Expected enabled=%v, got %v, %v", expected, enabled, err)
+		}
+	}
+
+	now = now.Add(2 * time.Hour)
+	if enabled, err := filter.Evaluate(evalCtx, nil); err != nil || !enabled {
+		t.Errorf("Expected the quota to start again the next day, got %v, %v", enabled, err)
+	}
+
+	if key, ttl := quotaKey("Beta", QuotaPeriodDay, now); key != "Beta/2024-03-02" || ttl != 23*time.Hour {
+		t.Errorf("Unexpected daily key %s with ttl %v", key, ttl)
+	}
+}
+
+func TestQuotaFilterErrors(t *testing.T) {
+	filter := NewQuotaFilter(failingCounterStore{})
+	if _, err := filter.Evaluate(FeatureFilterEvaluationContext{FeatureName: "Beta", Parameters: map[string]any{"Limit": 1}}, nil); err == nil {
+		t.Error("Expected an error when the counter store fails")
+	}
+
+	for _, parameters := range []map[string]any{{"Limit": -1}, {"Limit": 1, "Period": "Week"}} {
+		if _, err := filter.Evaluate(FeatureFilterEvaluationContext{FeatureName: "Invalid", Parameters: parameters}, nil); err == nil {
+			t.Errorf("Expected an error for parameters %v", parameters)
+		}
+	}
+}
+
+func TestMemoryQuotaCounterStoreExpiry(t *testing.T) {
+	store := &MemoryQuotaCounterStore{}
+	if count, _ := store.Increment("Beta", time.Nanosecond); count != 1 {
+		t.Fatalf("Expected a new counter, got %d", count)
+	}
+	time.Sleep(time.Millisecond)
+	if count, _ := store.Increment("Beta", 0); count != 1 {
+		t.Errorf("Expected the expired counter to start again, got %d", count)
+	}
+	if count, _ := store.Increment("Beta", 0); count != 2 {
+		t.Errorf("Expected the counter to be incremented, got %d", count)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"time"
+)
+
+// RefreshInterval returns the interval at which the provider refreshes its feature flags in the
+// background.
+//
+// Returns:
+//   - time.Duration: The refresh interval; a negative interval means background refresh is paused
+//   - error: An error if the provider doesn't implement RefreshIntervalController
+func (fm *FeatureManager) RefreshInterval() (time.Duration, error) {
+	controller, ok := fm.featureProvider.(RefreshIntervalController)
+	if !ok {
+		return 0, fmt.Errorf("feature flag provider %T does not support refresh interval control", fm.featureProvider)
+	}
+
+	return controller.RefreshInterval(), nil
+}
+
+// SetRefreshInterval changes the interval at which the provider refreshes its feature flags in
+// the background, for example to pick up changes faster during an incident or to refresh less
+// often overnight. The provider's refresh loop is rescheduled, so the application doesn't need to
+// manage its own ticker.
+//
+// Parameters:
+//   - interval: The new refresh interval; a negative interval pauses background refresh
+//
+// Returns:
+//   - error: An error if the provider doesn't implement RefreshIntervalController or rejects the interval
+func (fm *FeatureManager) SetRefreshInterval(interval time.Duration) error {
+	controller, ok := fm.featureProvider.(RefreshIntervalController)
+	if !ok {
+		return fmt.Errorf("feature flag provider %T does not support refresh interval control", fm.featureProvider)
+	}
+
+	return controller.SetRefreshInterval(interval)
+}
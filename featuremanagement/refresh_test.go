@@ -0,0 +1,60 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type refreshingProvider struct {
+	*StaticProvider
+	interval time.Duration
+}
+
+func (p *refreshingProvider) RefreshInterval() time.Duration {
+	return p.interval
+}
+
+func (p *refreshingProvider) SetRefreshInterval(interval time.Duration) error {
+	if interval == 0 {
+		return fmt.Errorf("refresh interval cannot be zero")
+	}
+	p.interval = interval
+	return nil
+}
+
+func TestSetRefreshInterval(t *testing.T) {
+	provider := &refreshingProvider{StaticProvider: NewStaticProvider(nil), interval: time.Minute}
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if err := manager.SetRefreshInterval(5 * time.Second); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if interval, err := manager.RefreshInterval(); err != nil || interval != 5*time.Second {
+		t.Errorf("Expected a 5s refresh interval, got %v, %v", interval, err)
+	}
+
+	if err := manager.SetRefreshInterval(0); err == nil {
+		t.Error("Expected the provider to reject the interval")
+	}
+}
+
+func TestSetRefreshIntervalUnsupported(t *testing.T) {
+	manager, err := NewFeatureManager(NewStaticProvider(nil), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if err := manager.SetRefreshInterval(time.Second); err == nil {
+		t.Error("Expected an error for a provider without refresh interval control")
+	}
+	if _, err := manager.RefreshInterval(); err == nil {
+		t.Error("Expected an error for a provider without refresh interval control")
+	}
+}
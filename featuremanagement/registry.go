@@ -0,0 +1,116 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FeatureDescription summarizes the definition of a feature
+type FeatureDescription struct {
+	// Name is the ID of the feature
+	Name string
+	// DisplayName is the human-friendly name of the feature, if any
+	DisplayName string
+	// Description details the purpose of the feature, if any
+	Description string
+	// Enabled is the state of the feature before its filters are evaluated
+	Enabled bool
+	// Overridden reports that the feature is forced on or off by an override
+	Overridden bool
+	// RequirementType is how the filters are combined, when the feature has filters
+	RequirementType RequirementType
+	// Filters contains the names of the feature's filters, in evaluation order
+	Filters []string
+	// Variants contains the names of the feature's variants
+	Variants []string
+	// Tags contains the tags of the feature
+	Tags []string
+	// TelemetryEnabled reports that evaluations of the feature publish telemetry events
+	TelemetryEnabled bool
+}
+
+// HasFeature reports whether a feature is defined by the provider or overridden, so that code can
+// check a feature exists before relying on its evaluation.
+//
+// Parameters:
+//   - featureName: The name of the feature
+//
+// Returns:
+//   - bool: true if the feature can be evaluated
+func (fm *FeatureManager) HasFeature(featureName string) bool {
+	_, err := fm.lookupFeature(featureName)
+	return err == nil
+}
+
+// DescribeFeature returns a summary of the definition of a feature, with any override applied.
+//
+// Parameters:
+//   - featureName: The name of the feature
+//
+// Returns:
+//   - FeatureDescription: The summary of the feature's definition
+//   - error: An error if the feature is neither defined by the provider nor overridden
+func (fm *FeatureManager) DescribeFeature(featureName string) (FeatureDescription, error) {
+	featureFlag, err := fm.lookupFeature(featureName)
+	if err != nil {
+		return FeatureDescription{}, err
+	}
+
+	description := FeatureDescription{
+		Name:             featureName,
+		DisplayName:      featureFlag.DisplayName,
+		Description:      featureFlag.Description,
+		Enabled:          featureFlag.Enabled,
+		Tags:             slices.Clone(featureFlag.Tags),
+		TelemetryEnabled: featureFlag.Telemetry != nil && featureFlag.Telemetry.Enabled,
+	}
+	if enabled, overridden := fm.overrides[featureName]; overridden {
+		description.Enabled = enabled
+		description.Overridden = true
+	} else if conditions := featureFlag.Conditions; conditions != nil && len(conditions.ClientFilters) > 0 {
+		description.RequirementType = RequirementTypeAny
+		if conditions.RequirementType != "" {
+			description.RequirementType = conditions.RequirementType
+		}
+		for _, filter := range conditions.ClientFilters {
+			description.Filters = append(description.Filters, filter.Name)
+		}
+	}
+	for _, variant := range featureFlag.Variants {
+		description.Variants = append(description.Variants, variant.Name)
+	}
+
+	return description, nil
+}
+
+// lookupFeature returns the definition of a feature that can be evaluated
+func (fm *FeatureManager) lookupFeature(featureName string) (FeatureFlag, error) {
+	featureFlag, err := fm.getFeatureFlag(featureName)
+	if err != nil {
+		if _, overridden := fm.overrides[featureName]; overridden {
+			return FeatureFlag{ID: featureName}, nil
+		}
+		return FeatureFlag{}, fmt.Errorf("feature %s is not defined: %w", featureName, err)
+	}
+
+	return featureFlag, nil
+}
+
+// checkExpectedFeatures returns an error listing the expected features that can't be evaluated
+func (fm *FeatureManager) checkExpectedFeatures(expected []string) error {
+	var missing []string
+	for _, name := range expected {
+		if !fm.HasFeature(name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("expected features are not defined: %s", strings.Join(missing, ", "))
+}
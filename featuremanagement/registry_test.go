@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"testing"
+)
+
+func registryTestProvider() *StaticProvider {
+	return NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {
+			Enabled:     true,
+			Description: "The beta experience",
+			Conditions: &Conditions{
+				RequirementType: RequirementTypeAll,
+				ClientFilters:   []ClientFilter{{Name: "Microsoft.TimeWindow"}, {Name: "Microsoft.Targeting"}},
+			},
+			Variants:  []VariantDefinition{{Name: "Big"}, {Name: "Small"}},
+			Tags:      []string{"checkout"},
+			Telemetry: &Telemetry{Enabled: true},
+		},
+	})
+}
+
+func TestDescribeFeature(t *testing.T) {
+	manager, err := NewFeatureManager(registryTestProvider(), &Options{
+		Overrides: map[string]bool{"Local": false},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if !manager.HasFeature("Beta") || !manager.HasFeature("Local") || manager.HasFeature("Btea") {
+		t.Error("Expected only Beta and the overridden Local feature to exist")
+	}
+
+	description, err := manager.DescribeFeature("Beta")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if description.Description != "The beta experience" || !description.Enabled || description.Overridden ||
+		description.RequirementType != RequirementTypeAll || !description.TelemetryEnabled {
+		t.Errorf("Unexpected description %+v", description)
+	}
+	if fmt.Sprint(description.Filters, description.Variants, description.Tags) != "[Microsoft.TimeWindow Microsoft.Targeting] [Big Small] [checkout]" {
+		t.Errorf("Unexpected filters, variants or tags in %+v", description)
+	}
+
+	if local, err := manager.DescribeFeature("Local"); err != nil || !local.Overridden || local.Enabled {
+		t.Errorf("Expected Local to be overridden off, got %+v, %v", local, err)
+	}
+
+	if _, err := manager.DescribeFeature("Btea"); err == nil {
+		t.Error("Expected an error describing an undefined feature")
+	}
+}
+
+func TestExpectedFeatures(t *testing.T) {
+	if _, err := NewFeatureManager(registryTestProvider(), &Options{
+		ExpectedFeatures: []string{"Beta", "Btea"},
+	}); err != nil {
+		t.Errorf("Expected missing features to only be logged, got %v", err)
+	}
+
+	_, err := NewFeatureManager(registryTestProvider(), &Options{
+		ExpectedFeatures:        []string{"Beta", "Btea", "Gamma"},
+		RequireExpectedFeatures: true,
+	})
+	if err == nil || err.Error() != "expected features are not defined: Btea, Gamma" {
+		t.Errorf("Expected the missing features to fail creation, got %v", err)
+	}
+}
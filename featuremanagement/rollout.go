@@ -0,0 +1,328 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRolloutRolledBack is returned by RolloutController.Run when a failed health check
+// caused the rollout to be rolled back.
+var ErrRolloutRolledBack = errors.New("rollout rolled back")
+
+// RolloutStep is a single stage of a progressive rollout
+type RolloutStep struct {
+	// Percentage is the default rollout percentage of the targeting filter at this step (0-100)
+	Percentage float64
+	// Hold is how long the rollout stays at this step before advancing to the next one
+	Hold time.Duration
+}
+
+// RolloutOptions configures a RolloutController
+type RolloutOptions struct {
+	// Steps is the rollout schedule, applied in order
+	Steps []RolloutStep
+
+	// HealthCheck is called before each step is applied. A non-nil error pauses the rollout,
+	// or rolls it back when RollbackOnFailure is set. When nil, the rollout is always considered healthy.
+	HealthCheck func(featureName string) error
+
+	// RollbackOnFailure sets the rollout percentage to 0 and stops the rollout when the
+	// health check fails, instead of pausing until it succeeds again.
+	RollbackOnFailure bool
+
+	// RetryInterval is how often the health check is retried while the rollout is paused.
+	// Defaults to one minute.
+	RetryInterval time.Duration
+
+	// LogLevel is the minimum severity of the messages logged about the rollout's progress.
+	// Defaults to LogLevelWarn, which logs failed health checks but not completed steps.
+	LogLevel LogLevel
+
+	// Logger receives the logged messages at their severity. Defaults to the standard logger.
+	Logger *slog.Logger
+}
+
+// RolloutState describes the progress of a RolloutController
+type RolloutState string
+
+const (
+	// RolloutStatePending indicates the rollout has not started
+	RolloutStatePending RolloutState = "Pending"
+	// RolloutStateRunning indicates the rollout is progressing through its steps
+	RolloutStateRunning RolloutState = "Running"
+	// RolloutStatePaused indicates the rollout is waiting for the health check to succeed
+	RolloutStatePaused RolloutState = "Paused"
+	// RolloutStateRolledBack indicates the rollout was rolled back after a failed health check
+	RolloutStateRolledBack RolloutState = "RolledBack"
+	// RolloutStateCompleted indicates every step has been applied
+	RolloutStateCompleted RolloutState = "Completed"
+)
+
+// RolloutStatus is a point-in-time view of a RolloutController
+type RolloutStatus struct {
+	// State is the current state of the rollout
+	State RolloutState
+	// Step is the index of the most recently applied step, or -1 if none has been applied
+	Step int
+	// Percentage is the most recently applied rollout percentage
+	Percentage float64
+	// LastError is the most recent health check or provider error
+	LastError error
+}
+
+// RolloutController gradually increases the default rollout percentage of a feature flag's
+// targeting filter according to a schedule, writing each step to a writable provider such as
+// MemoryProvider. The first step of a feature without a targeting filter adds one and enables the
+// feature; the steps of a feature with a targeting filter only change its percentage and leave
+// the feature enabled or disabled as they find it.
+//
+// Example:
+//
+//	controller, _ := featuremanagement.NewRolloutController(provider, "Beta", featuremanagement.RolloutOptions{
+//		Steps: []featuremanagement.RolloutStep{
+//			{Percentage: 5, Hold: time.Hour},
+//			{Percentage: 25, Hold: time.Hour},
+//			{Percentage: 100},
+//		},
+//		HealthCheck: func(featureName string) error {
+//			return errorRateAbove(0.01)
+//		},
+//		RollbackOnFailure: true,
+//	})
+//	go controller.Run(ctx)
+type RolloutController struct {
+	provider    WritableFeatureFlagProvider
+	featureName string
+	options     RolloutOptions
+	logger      *logger
+
+	mu     sync.Mutex
+	status RolloutStatus
+}
+
+// NewRolloutController creates a controller for the progressive rollout of a feature flag.
+//
+// Parameters:
+//   - provider: A provider that supplies and stores the feature flag
+//   - featureName: The name of the feature to roll out
+//   - options: The rollout schedule and health check configuration
+//
+// Returns:
+//   - *RolloutController: A controller ready to be started with Run
+//   - error: An error if the schedule is invalid
+func NewRolloutController(provider WritableFeatureFlagProvider, featureName string, options RolloutOptions) (*RolloutController, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("feature provider cannot be nil")
+	}
+
+	if featureName == "" {
+		return nil, fmt.Errorf("feature name cannot be empty")
+	}
+
+	if len(options.Steps) == 0 {
+		return nil, fmt.Errorf("rollout for feature %s must have at least one step", featureName)
+	}
+
+	for i, step := range options.Steps {
+		if step.Percentage < 0 || step.Percentage > 100 {
+			return nil, fmt.Errorf("rollout step at index %d: percentage must be between 0 and 100", i)
+		}
+		if step.Hold < 0 {
+			return nil, fmt.Errorf("rollout step at index %d: hold cannot be negative", i)
+		}
+	}
+
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = time.Minute
+	}
+
+	return &RolloutController{
+		provider:    provider,
+		featureName: featureName,
+		options:     options,
+		logger:      newLogger(options.LogLevel, options.Logger),
+		status: RolloutStatus{
+			State: RolloutStatePending,
+			Step:  -1,
+		},
+	}, nil
+}
+
+// Status returns the current progress of the rollout.
+func (c *RolloutController) Status() RolloutStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+// Run applies the rollout steps in order, blocking until every step has been applied,
+// the rollout is rolled back, or the context is cancelled.
+//
+// Returns:
+//   - error: nil when the rollout completed, ErrRolloutRolledBack if it was rolled back,
+//     the context error if cancelled, or an error if the provider rejected an update
+func (c *RolloutController) Run(ctx context.Context) error {
+	for i, step := range c.options.Steps {
+		if err := c.waitUntilHealthy(ctx); err != nil {
+			return err
+		}
+
+		if err := c.apply(step.Percentage); err != nil {
+			c.setStatus(RolloutStateRunning, i-1, err)
+			return err
+		}
+
+		c.mu.Lock()
+		c.status.Step = i
+		c.status.Percentage = step.Percentage
+		c.mu.Unlock()
+		c.logger.infof("Feature flag %s rolled out to %v%%", c.featureName, step.Percentage)
+
+		if i < len(c.options.Steps)-1 {
+			if err := sleep(ctx, step.Hold); err != nil {
+				return err
+			}
+		}
+	}
+
+	c.setStatus(RolloutStateCompleted, len(c.options.Steps)-1, nil)
+	return nil
+}
+
+func (c *RolloutController) waitUntilHealthy(ctx context.Context) error {
+	for {
+		var err error
+		if c.options.HealthCheck != nil {
+			err = c.options.HealthCheck(c.featureName)
+		}
+
+		step := c.Status().Step
+		if err == nil {
+			c.setStatus(RolloutStateRunning, step, nil)
+			return nil
+		}
+
+		if c.options.RollbackOnFailure {
+			c.logger.warnf("Health check failed for feature flag %s, rolling back: %v", c.featureName, err)
+			if applyErr := c.apply(0); applyErr != nil {
+				c.setStatus(RolloutStateRolledBack, step, applyErr)
+				return applyErr
+			}
+			c.mu.Lock()
+			c.status.Percentage = 0
+			c.mu.Unlock()
+			c.setStatus(RolloutStateRolledBack, step, err)
+			return fmt.Errorf("%w: %v", ErrRolloutRolledBack, err)
+		}
+
+		c.logger.warnf("Health check failed for feature flag %s, pausing rollout: %v", c.featureName, err)
+		c.setStatus(RolloutStatePaused, step, err)
+		if err := sleep(ctx, c.options.RetryInterval); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *RolloutController) setStatus(state RolloutState, step int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status.State = state
+	c.status.Step = step
+	c.status.LastError = err
+}
+
+// apply writes the given default rollout percentage to the feature's targeting filter. A feature
+// without a targeting filter gets one and is enabled, starting its rollout; otherwise only the
+// percentage changes, so a feature an operator disabled during the rollout stays disabled.
+func (c *RolloutController) apply(percentage float64) error {
+	flag, err := c.provider.GetFeatureFlag(c.featureName)
+	if err != nil {
+		return fmt.Errorf("failed to get feature flag %s: %w", c.featureName, err)
+	}
+
+	conditions := Conditions{}
+	if flag.Conditions != nil {
+		conditions = *flag.Conditions
+	}
+
+	filters := make([]ClientFilter, len(conditions.ClientFilters))
+	copy(filters, conditions.ClientFilters)
+
+	targetingIndex := -1
+	for i, filter := range filters {
+		if filter.Name == (&TargetingFilter{}).Name() {
+			targetingIndex = i
+			break
+		}
+	}
+
+	if targetingIndex == -1 {
+		filters = append(filters, ClientFilter{Name: (&TargetingFilter{}).Name()})
+		targetingIndex = len(filters) - 1
+		flag.Enabled = true
+	}
+
+	filters[targetingIndex].Parameters = withDefaultRolloutPercentage(filters[targetingIndex].Parameters, percentage)
+	conditions.ClientFilters = filters
+	flag.Conditions = &conditions
+
+	if err := c.provider.SetFeatureFlag(flag); err != nil {
+		return fmt.Errorf("failed to update feature flag %s: %w", c.featureName, err)
+	}
+
+	return nil
+}
+
+// withDefaultRolloutPercentage returns a copy of the targeting parameters with the audience's
+// default rollout percentage replaced. The original parameters are not modified since they
+// may be shared with concurrent evaluations.
+func withDefaultRolloutPercentage(parameters map[string]any, percentage float64) map[string]any {
+	params := make(map[string]any, len(parameters)+1)
+	for k, v := range parameters {
+		params[k] = v
+	}
+
+	audienceKey := findKey(params, "Audience")
+	audience := make(map[string]any)
+	if existing, ok := params[audienceKey].(map[string]any); ok {
+		for k, v := range existing {
+			audience[k] = v
+		}
+	}
+
+	audience[findKey(audience, "DefaultRolloutPercentage")] = percentage
+	params[audienceKey] = audience
+
+	return params
+}
+
+// findKey returns the key of the map matching name case-insensitively, or name if there is none
+func findKey(m map[string]any, name string) string {
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+
+	return name
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type writableMockProvider struct {
+	mu           sync.Mutex
+	featureFlags map[string]FeatureFlag
+	history      []float64
+}
+
+func (p *writableMockProvider) GetFeatureFlag(name string) (FeatureFlag, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	flag, ok := p.featureFlags[name]
+	if !ok {
+		return FeatureFlag{}, fmt.Errorf("feature flag '%s' not found", name)
+	}
+	return flag, nil
+}
+
+func (p *writableMockProvider) GetFeatureFlags() ([]FeatureFlag, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	flags := make([]FeatureFlag, 0, len(p.featureFlags))
+	for _, flag := range p.featureFlags {
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+func (p *writableMockProvider) SetFeatureFlag(flag FeatureFlag) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.featureFlags[flag.ID] = flag
+	audience := flag.Conditions.ClientFilters[0].Parameters["Audience"].(map[string]any)
+	p.history = append(p.history, audience["DefaultRolloutPercentage"].(float64))
+	return nil
+}
+
+func TestRolloutControllerCompletes(t *testing.T) {
+	users := []any{"Alice"}
+	provider := &writableMockProvider{
+		featureFlags: map[string]FeatureFlag{
+			"Beta": {
+				ID:      "Beta",
+				Enabled: true,
+				Conditions: &Conditions{
+					ClientFilters: []ClientFilter{
+						{
+							Name: "Microsoft.Targeting",
+							Parameters: map[string]any{
+								"Audience": map[string]any{"Users": users},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	original := provider.featureFlags["Beta"].Conditions.ClientFilters[0].Parameters
+
+	controller, err := NewRolloutController(provider, "Beta", RolloutOptions{
+		Steps: []RolloutStep{
+			{Percentage: 10, Hold: time.Millisecond},
+			{Percentage: 50, Hold: time.Millisecond},
+			{Percentage: 100},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create rollout controller: %v", err)
+	}
+
+	if err := controller.Run(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if fmt.Sprint(provider.history) != "[10 50 100]" {
+		t.Errorf("Unexpected rollout history: %v", provider.history)
+	}
+
+	status := controller.Status()
+	if status.State != RolloutStateCompleted || status.Step != 2 || status.Percentage != 100 {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+
+	flag := provider.featureFlags["Beta"]
+	if !flag.Enabled {
+		t.Error("Expected the feature flag to stay enabled")
+	}
+	audience := flag.Conditions.ClientFilters[0].Parameters["Audience"].(map[string]any)
+	if fmt.Sprint(audience["Users"]) != "[Alice]" {
+		t.Errorf("Expected existing audience users to be preserved, got %v", audience["Users"])
+	}
+	if _, ok := original["Audience"].(map[string]any)["DefaultRolloutPercentage"]; ok {
+		t.Error("Expected the original parameters to be left unmodified")
+	}
+}
+
+func TestRolloutControllerHealthCheck(t *testing.T) {
+	t.Run("Rollback on failure", func(t *testing.T) {
+		provider := &writableMockProvider{featureFlags: map[string]FeatureFlag{"Beta": {ID: "Beta"}}}
+		checks := 0
+		controller, err := NewRolloutController(provider, "Beta", RolloutOptions{
+			Steps: []RolloutStep{{Percentage: 10}, {Percentage: 50}},
+			HealthCheck: func(featureName string) error {
+				checks++
+				if checks > 1 {
+					return errors.New("error rate too high")
+				}
+				return nil
+			},
+			RollbackOnFailure: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create rollout controller: %v", err)
+		}
+
+		if err := controller.Run(context.Background()); !errors.Is(err, ErrRolloutRolledBack) {
+			t.Fatalf("Expected rollback error, got %v", err)
+		}
+		if fmt.Sprint(provider.history) != "[10 0]" {
+			t.Errorf("Unexpected rollout history: %v", provider.history)
+		}
+		if status := controller.Status(); status.State != RolloutStateRolledBack || status.LastError == nil {
+			t.Errorf("Unexpected status: %+v", status)
+		}
+	})
+
+	t.Run("Pause until healthy", func(t *testing.T) {
+		provider := &writableMockProvider{featureFlags: map[string]FeatureFlag{"Beta": {ID: "Beta"}}}
+		checks := 0
+		controller, err := NewRolloutController(provider, "Beta", RolloutOptions{
+			Steps: []RolloutStep{{Percentage: 10}, {Percentage: 50}},
+			HealthCheck: func(featureName string) error {
+				checks++
+				if checks == 2 || checks == 3 {
+					return errors.New("dependency unavailable")
+				}
+				return nil
+			},
+			RetryInterval: time.Millisecond,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create rollout controller: %v", err)
+		}
+
+		if err := controller.Run(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if fmt.Sprint(provider.history) != "[10 50]" || checks != 4 {
+			t.Errorf("Unexpected rollout history %v after %d checks", provider.history, checks)
+		}
+	})
+
+	t.Run("Cancelled while paused", func(t *testing.T) {
+		provider := &writableMockProvider{featureFlags: map[string]FeatureFlag{"Beta": {ID: "Beta"}}}
+		controller, err := NewRolloutController(provider, "Beta", RolloutOptions{
+			Steps:         []RolloutStep{{Percentage: 10}},
+			HealthCheck:   func(string) error { return errors.New("unhealthy") },
+			RetryInterval: time.Hour,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create rollout controller: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := controller.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected deadline exceeded, got %v", err)
+		}
+		if status := controller.Status(); status.State != RolloutStatePaused || len(provider.history) != 0 {
+			t.Errorf("Unexpected status %+v with history %v", status, provider.history)
+		}
+	})
+}
+
+func TestRolloutControllerEnabledState(t *testing.T) {
+	provider := NewMemoryProvider(map[string]FeatureFlag{"Beta": {Enabled: false}})
+	checks := 0
+	controller, err := NewRolloutController(provider, "Beta", RolloutOptions{
+		Steps: []RolloutStep{{Percentage: 10}, {Percentage: 50}, {Percentage: 100}},
+		HealthCheck: func(featureName string) error {
+			checks++
+			switch checks {
+			case 2:
+				// The first step added a targeting filter and enabled the feature
+				if flag, _ := provider.GetFeatureFlag(featureName); !flag.Enabled {
+					t.Error("Expected the first step to enable the feature")
+				}
+				// An operator disables the feature during the rollout
+				flag, _ := provider.GetFeatureFlag(featureName)
+				flag.Enabled = false
+				provider.SetFeatureFlag(flag)
+			case 3:
+				return errors.New("error rate too high")
+			}
+			return nil
+		},
+		RollbackOnFailure: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create rollout controller: %v", err)
+	}
+
+	if err := controller.Run(context.Background()); !errors.Is(err, ErrRolloutRolledBack) {
+		t.Fatalf("Expected rollback error, got %v", err)
+	}
+
+	flag, _ := provider.GetFeatureFlag("Beta")
+	if flag.Enabled {
+		t.Error("Expected later steps and the rollback to leave the feature disabled")
+	}
+	audience := flag.Conditions.ClientFilters[0].Parameters["Audience"].(map[string]any)
+	if audience["DefaultRolloutPercentage"] != 0.0 {
+		t.Errorf("Expected the rollback to set the percentage to 0, got %v", audience["DefaultRolloutPercentage"])
+	}
+}
+
+func TestNewRolloutControllerValidation(t *testing.T) {
+	provider := &writableMockProvider{featureFlags: map[string]FeatureFlag{}}
+	invalid := []RolloutOptions{
+		{},
+		{Steps: []RolloutStep{{Percentage: 101}}},
+		{Steps: []RolloutStep{{Percentage: 10, Hold: -time.Second}}},
+	}
+
+	for _, options := range invalid {
+		if _, err := NewRolloutController(provider, "Beta", options); err == nil {
+			t.Errorf("Expected error for options %+v", options)
+		}
+	}
+}
@@ -5,6 +5,9 @@ package featuremanagement
 
 type FeatureManagement struct {
 	FeatureFlags []FeatureFlag `json:"feature_flags"`
+	// FilterTemplates are named client filters that the client filters of feature flags can
+	// reference by their Template, so that a shared audience is defined once
+	FilterTemplates map[string]ClientFilter `json:"filter_templates,omitempty"`
 }
 
 // FeatureFlag represents a feature flag definition according to the v2.0.0 schema
@@ -25,6 +28,8 @@ type FeatureFlag struct {
 	Allocation *VariantAllocation `json:"allocation,omitempty"`
 	// Telemetry contains feature flag telemetry configuration
 	Telemetry *Telemetry `json:"telemetry,omitempty"`
+	// Tags group the feature with others, for example by team, service or lifecycle stage
+	Tags []string `json:"tags,omitempty"`
 }
 
 // Conditions defines the rules for enabling a feature dynamically
@@ -42,6 +47,9 @@ type ClientFilter struct {
 	Name string `json:"name"`
 	// Parameters are the configuration values for the filter
 	Parameters map[string]any `json:"parameters,omitempty"`
+	// Template is the name of a filter template of the document. When set, the name and
+	// parameters of the template replace those of the filter when the document is decoded.
+	Template string `json:"template,omitempty"`
 }
 
 // VariantDefinition represents a feature configuration variant
@@ -69,6 +77,24 @@ type VariantAllocation struct {
 	Percentile []PercentileAllocation `json:"percentile,omitempty"`
 	// Seed is used to ensure consistent percentile calculations across features
 	Seed string `json:"seed,omitempty"`
+	// ExclusionGroup makes the allocation mutually exclusive with the other allocations of the group
+	ExclusionGroup *ExclusionGroup `json:"exclusion_group,omitempty"`
+	// TargetingAttribute names the targeting context attribute that user, percentile and exclusion
+	// group rules target instead of the user ID, for example "tenantId" for tenant-level experiments
+	TargetingAttribute string `json:"targeting_attribute,omitempty"`
+}
+
+// ExclusionGroup reserves a slice of the users of a group of mutually exclusive experiments.
+// Each user falls into one bucket of the group, and only the experiment whose slice contains the
+// bucket allocates variants to them; other users get the default variant. Experiments of a group
+// must not overlap, so that a user is only ever allocated into one of them.
+type ExclusionGroup struct {
+	// Name identifies the group shared by the experiments
+	Name string `json:"name"`
+	// From is the lower end of the slice of the group's users (0-100)
+	From float64 `json:"from"`
+	// To is the upper end of the slice of the group's users (0-100)
+	To float64 `json:"to"`
 }
 
 // UserAllocation assigns a variant to specific users
@@ -121,6 +147,8 @@ const (
 	VariantAssignmentReasonGroup VariantAssignmentReason = "Group"
 	// VariantAssignmentReasonPercentile indicates the variant was assigned based on percentile calculations
 	VariantAssignmentReasonPercentile VariantAssignmentReason = "Percentile"
+	// VariantAssignmentReasonOverride indicates the variant was forced by the targeting context
+	VariantAssignmentReasonOverride VariantAssignmentReason = "Override"
 )
 
 type RequirementType string
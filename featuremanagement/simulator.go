@@ -0,0 +1,167 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// simulationBoundaryTolerance is how close, in percentile points, a user's bucket must be to a
+// percentile boundary for the user to be reported as a boundary user
+const simulationBoundaryTolerance = 0.01
+
+// AllocationSimulation reports the variant allocation of a feature flag over a synthetic population
+type AllocationSimulation struct {
+	// FeatureName is the ID of the simulated feature flag
+	FeatureName string
+	// Population is the number of simulated users
+	Population int
+	// Counts is the number of users assigned to each variant. Users assigned no variant are
+	// counted under the empty name.
+	Counts map[string]int
+	// Expected is the share of users, in percent, that the allocation is configured to assign to
+	// each variant, counting the range not covered by percentile allocations as the default variant
+	Expected map[string]float64
+	// BoundaryUsers are the simulated users whose bucket lies on or next to a percentile boundary,
+	// sorted by bucket. Small changes to the ranges or seed move these users between variants.
+	BoundaryUsers []BoundaryUser
+}
+
+// BoundaryUser is a simulated user whose bucket lies close to a percentile boundary
+type BoundaryUser struct {
+	// UserID is the ID of the simulated user
+	UserID string
+	// Bucket is the percentile the user falls into
+	Bucket float64
+	// Boundary is the percentile boundary closest to the bucket
+	Boundary float64
+	// Variant is the name of the variant assigned to the user
+	Variant string
+}
+
+// Distribution returns the share of the simulated users, in percent, assigned to each variant.
+//
+// Returns:
+//   - map[string]float64: The share of users per variant name, with the empty name for users
+//     assigned no variant
+func (s AllocationSimulation) Distribution() map[string]float64 {
+	distribution := make(map[string]float64, len(s.Counts))
+	if s.Population == 0 {
+		return distribution
+	}
+
+	for variant, count := range s.Counts {
+		distribution[variant] = float64(count) / float64(s.Population) * 100
+	}
+
+	return distribution
+}
+
+// SimulateAllocation assigns variants of an enabled feature flag to a synthetic population of
+// users "user-0" through "user-<n-1>" and reports the resulting distribution, so that percentile
+// ranges and seeds can be sanity-checked before launch. Users carry no groups, so only the
+// percentile allocation and the default variant take effect; the exclusion group, if any, applies.
+//
+// Parameters:
+//   - flag: The feature flag to simulate, which must define an allocation
+//   - populationSize: The number of users to simulate
+//
+// Returns:
+//   - AllocationSimulation: The simulated distribution
+//   - error: An error if the feature flag is invalid or has no allocation
+func SimulateAllocation(flag FeatureFlag, populationSize int) (AllocationSimulation, error) {
+	if err := validateFeatureFlag(flag); err != nil {
+		return AllocationSimulation{}, fmt.Errorf("invalid feature flag: %w", err)
+	}
+	if flag.Allocation == nil {
+		return AllocationSimulation{}, fmt.Errorf("feature flag %s has no allocation", flag.ID)
+	}
+	if populationSize < 0 {
+		return AllocationSimulation{}, fmt.Errorf("population size must not be negative")
+	}
+
+	simulation := AllocationSimulation{
+		FeatureName: flag.ID,
+		Population:  populationSize,
+		Counts:      make(map[string]int),
+		Expected:    expectedDistribution(flag.Allocation),
+	}
+
+	boundaries := percentileBoundaries(flag.Allocation)
+	hint := allocationHint(&flag)
+	for i := 0; i < populationSize; i++ {
+		userID := fmt.Sprintf("user-%d", i)
+
+		variant := ""
+		targetingContext := &TargetingContext{UserID: userID}
+		if flag.Allocation.TargetingAttribute != "" {
+			targetingContext.Attributes = map[string]string{flag.Allocation.TargetingAttribute: userID}
+		}
+		assignment := assignVariant(&flag, targetingContext, bucketer{}, userMatcher{})
+		if assignment.Variant != nil {
+			variant = assignment.Variant.Name
+		} else if assignment.Reason == VariantAssignmentReasonNone {
+			variant = flag.Allocation.DefaultWhenEnabled
+		}
+		simulation.Counts[variant]++
+
+		bucket := ComputeBucket(userID, hint...)
+		for _, boundary := range boundaries {
+			if math.Abs(bucket-boundary) <= simulationBoundaryTolerance {
+				simulation.BoundaryUsers = append(simulation.BoundaryUsers, BoundaryUser{
+					UserID:   userID,
+					Bucket:   bucket,
+					Boundary: boundary,
+					Variant:  variant,
+				})
+				break
+			}
+		}
+	}
+
+	sort.Slice(simulation.BoundaryUsers, func(i, j int) bool {
+		return simulation.BoundaryUsers[i].Bucket < simulation.BoundaryUsers[j].Bucket
+	})
+
+	return simulation, nil
+}
+
+// expectedDistribution returns the configured share of users per variant
+func expectedDistribution(allocation *VariantAllocation) map[string]float64 {
+	// Users outside the slice of the exclusion group are assigned the default variant
+	share := 1.0
+	if allocation.ExclusionGroup != nil {
+		share = (allocation.ExclusionGroup.To - allocation.ExclusionGroup.From) / 100
+	}
+
+	expected := make(map[string]float64)
+	covered := 0.0
+	for _, percentile := range allocation.Percentile {
+		expected[percentile.Variant] += (percentile.To - percentile.From) * share
+		covered += (percentile.To - percentile.From) * share
+	}
+	if covered < 100 {
+		expected[allocation.DefaultWhenEnabled] += 100 - covered
+	}
+
+	return expected
+}
+
+// percentileBoundaries returns the distinct inner boundaries of the percentile ranges
+func percentileBoundaries(allocation *VariantAllocation) []float64 {
+	seen := make(map[float64]bool)
+	var boundaries []float64
+	for _, percentile := range allocation.Percentile {
+		for _, boundary := range []float64{percentile.From, percentile.To} {
+			if boundary > 0 && boundary < 100 && !seen[boundary] {
+				seen[boundary] = true
+				boundaries = append(boundaries, boundary)
+			}
+		}
+	}
+
+	return boundaries
+}
@@ -0,0 +1,96 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimulateAllocation(t *testing.T) {
+	flag := FeatureFlag{
+		ID:       "Checkout",
+		Enabled:  true,
+		Variants: []VariantDefinition{{Name: "Control"}, {Name: "Treatment"}},
+		Allocation: &VariantAllocation{
+			DefaultWhenEnabled: "Control",
+			Percentile: []PercentileAllocation{
+				{Variant: "Control", From: 0, To: 30},
+				{Variant: "Treatment", From: 30, To: 60},
+			},
+			Seed: "checkout-2024",
+		},
+	}
+
+	simulation, err := SimulateAllocation(flag, 20000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if simulation.FeatureName != "Checkout" || simulation.Population != 20000 {
+		t.Errorf("Unexpected simulation header: %+v", simulation)
+	}
+	if total := simulation.Counts["Control"] + simulation.Counts["Treatment"]; total != 20000 {
+		t.Errorf("Expected every user to be assigned, got %v", simulation.Counts)
+	}
+
+	expected := map[string]float64{"Control": 70, "Treatment": 30}
+	for variant, share := range expected {
+		if simulation.Expected[variant] != share {
+			t.Errorf("Expected configured share %v for %s, got %v", share, variant, simulation.Expected[variant])
+		}
+		if actual := simulation.Distribution()[variant]; math.Abs(actual-share) > 2 {
+			t.Errorf("Expected about %v%% of users in %s, got %v", share, variant, actual)
+		}
+	}
+
+	if len(simulation.BoundaryUsers) == 0 {
+		t.Fatal("Expected boundary users around 30 and 60")
+	}
+	for i, user := range simulation.BoundaryUsers {
+		if user.Boundary != 30 && user.Boundary != 60 {
+			t.Errorf("Unexpected boundary %v", user.Boundary)
+		}
+		if math.Abs(user.Bucket-user.Boundary) > simulationBoundaryTolerance {
+			t.Errorf("User %s with bucket %v is not next to boundary %v", user.UserID, user.Bucket, user.Boundary)
+		}
+		if bucket := ComputeBucket(user.UserID, "checkout-2024"); bucket != user.Bucket {
+			t.Errorf("Expected bucket %v for %s, got %v", bucket, user.UserID, user.Bucket)
+		}
+		if i > 0 && simulation.BoundaryUsers[i-1].Bucket > user.Bucket {
+			t.Error("Expected boundary users sorted by bucket")
+		}
+	}
+}
+
+func TestSimulateAllocationExclusionGroup(t *testing.T) {
+	flag := exclusionGroupFlag("ButtonColor", 0, 50)
+
+	simulation, err := SimulateAllocation(flag, 10000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if simulation.Expected["Treatment"] != 50 || simulation.Expected["Control"] != 50 {
+		t.Errorf("Expected an even configured split, got %v", simulation.Expected)
+	}
+	if actual := simulation.Distribution()["Treatment"]; math.Abs(actual-50) > 2 {
+		t.Errorf("Expected about half of the users in Treatment, got %v", actual)
+	}
+}
+
+func TestSimulateAllocationErrors(t *testing.T) {
+	if _, err := SimulateAllocation(FeatureFlag{ID: "Beta", Enabled: true}, 100); err == nil {
+		t.Error("Expected an error for a flag without allocation")
+	}
+
+	invalid := FeatureFlag{ID: "Beta", Allocation: &VariantAllocation{Percentile: []PercentileAllocation{{Variant: "A", From: 0, To: 150}}}}
+	if _, err := SimulateAllocation(invalid, 100); err == nil {
+		t.Error("Expected an error for an invalid flag")
+	}
+
+	if _, err := SimulateAllocation(exclusionGroupFlag("Beta", 0, 50), -1); err == nil {
+		t.Error("Expected an error for a negative population")
+	}
+}
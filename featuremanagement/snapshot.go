@@ -0,0 +1,236 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FeatureFlagSnapshot is an immutable set of validated feature flags indexed by ID, built by a
+// provider each time it loads or refreshes its feature flags. Swapping in a new snapshot rather
+// than modifying the current one means that reads never wait on a lock and never observe a
+// partially applied refresh.
+type FeatureFlagSnapshot struct {
+	featureFlags     []FeatureFlag
+	featureFlagsByID map[string]FeatureFlag
+	validationErrors []ValidationError
+	version          string
+}
+
+// SnapshotOptions configures how NewFeatureFlagSnapshot builds a snapshot.
+type SnapshotOptions struct {
+	// DuplicatePolicy decides which definition of a feature defined more than once is served.
+	// Defaults to DuplicateFirstWins.
+	DuplicatePolicy DuplicatePolicy
+
+	// Version identifies the loaded content, such as the ETag of a document or a revision
+	Version string
+
+	// Logger logs the rejected feature flags and the definitions overridden by the duplicate
+	// policy. Defaults to the standard logger.
+	Logger *ProviderLogger
+}
+
+// NewFeatureFlagSnapshot validates feature flags loaded by a provider and indexes them by ID.
+// Invalid flags are quarantined: they are logged and left out of the snapshot, so they evaluate
+// as not found, and reported by ValidationErrors. When IDs are duplicated, the duplicate policy
+// decides which definition is served, in the order of the flags given.
+//
+// Example:
+//
+//	snapshot := featuremanagement.NewFeatureFlagSnapshot(flags, &featuremanagement.SnapshotOptions{
+//		DuplicatePolicy: options.DuplicatePolicy,
+//		Version:         etag,
+//		Logger:          logger,
+//	})
+//	provider.StoreSnapshot(snapshot)
+//
+// Parameters:
+//   - featureFlags: The feature flags loaded, which the snapshot keeps without copying them, so
+//     they must not be modified afterwards
+//   - options: The snapshot options, or nil for the defaults
+//
+// Returns:
+//   - *FeatureFlagSnapshot: The snapshot of the valid feature flags
+func NewFeatureFlagSnapshot(featureFlags []FeatureFlag, options *SnapshotOptions) *FeatureFlagSnapshot {
+	if options == nil {
+		options = &SnapshotOptions{}
+	}
+
+	valid, validationErrors := ValidateFeatureFlags(featureFlags)
+	valid, duplicateErrors, warnings := ResolveDuplicateFeatureFlags(valid, options.DuplicatePolicy)
+	validationErrors = append(validationErrors, duplicateErrors...)
+	for _, validationErr := range validationErrors {
+		options.Logger.Warnf("Ignoring invalid feature flag %s: %s", validationErr.FeatureName, validationErr.Err)
+	}
+	for _, warning := range warnings {
+		options.Logger.Warnf("Feature flag %s: %s", warning.FeatureName, warning.Message)
+	}
+
+	index := make(map[string]FeatureFlag, len(valid))
+	for _, flag := range valid {
+		index[flag.ID] = flag
+	}
+
+	return &FeatureFlagSnapshot{
+		featureFlags:     valid,
+		featureFlagsByID: index,
+		validationErrors: validationErrors,
+		version:          options.Version,
+	}
+}
+
+// GetFeatureFlag returns a copy of the feature flag with the given ID.
+//
+// Parameters:
+//   - name: The ID of the feature flag
+//
+// Returns:
+//   - FeatureFlag: The feature flag definition
+//   - error: An error if the snapshot has no feature flag with the ID
+func (s *FeatureFlagSnapshot) GetFeatureFlag(name string) (FeatureFlag, error) {
+	if s != nil {
+		if flag, ok := s.featureFlagsByID[name]; ok {
+			return flag.Clone(), nil
+		}
+	}
+
+	return FeatureFlag{}, fmt.Errorf("feature flag with ID %s not found", name)
+}
+
+// GetFeatureFlags returns copies of the feature flags of the snapshot, in the order they were
+// loaded.
+//
+// Returns:
+//   - []FeatureFlag: The feature flags
+//   - error: Always nil
+func (s *FeatureFlagSnapshot) GetFeatureFlags() ([]FeatureFlag, error) {
+	return slices.Collect(s.All()), nil
+}
+
+// All returns an iterator over copies of the feature flags of the snapshot.
+func (s *FeatureFlagSnapshot) All() iter.Seq[FeatureFlag] {
+	if s == nil {
+		return slices.Values([]FeatureFlag(nil))
+	}
+
+	return cloneFeatureFlags(slices.Values(s.featureFlags))
+}
+
+// ValidationErrors returns the invalid feature flags left out of the snapshot.
+func (s *FeatureFlagSnapshot) ValidationErrors() []ValidationError {
+	if s == nil {
+		return nil
+	}
+
+	return s.validationErrors
+}
+
+// Version returns the version given in the options of NewFeatureFlagSnapshot.
+func (s *FeatureFlagSnapshot) Version() string {
+	if s == nil {
+		return ""
+	}
+
+	return s.version
+}
+
+// SnapshotProvider serves the feature flags of the FeatureFlagSnapshot most recently stored in it.
+// Providers loading their feature flags from a remote source embed it, store a new snapshot on
+// each load and record the outcome of the refreshes that don't load one. It implements
+// FeatureFlagProvider, FeatureFlagIterator, ValidatingFeatureFlagProvider, ValidationReporter,
+// RefreshNotifier and ProviderStatusReporter for them.
+//
+// Its zero value serves no feature flags and reports that it hasn't loaded them.
+type SnapshotProvider struct {
+	snapshot  atomic.Pointer[FeatureFlagSnapshot]
+	listeners RefreshListeners
+
+	// statusMu guards the outcome of the last refresh
+	statusMu        sync.Mutex
+	lastRefreshTime time.Time
+	lastRefreshErr  error
+}
+
+// StoreSnapshot swaps in a snapshot, records a successful refresh and notifies the callbacks
+// registered with OnRefresh on the calling goroutine.
+//
+// Parameters:
+//   - snapshot: The snapshot to serve
+func (p *SnapshotProvider) StoreSnapshot(snapshot *FeatureFlagSnapshot) {
+	p.snapshot.Store(snapshot)
+	p.RecordRefresh(nil)
+	p.listeners.Notify()
+}
+
+// Snapshot returns the snapshot currently served, or nil if none was stored.
+func (p *SnapshotProvider) Snapshot() *FeatureFlagSnapshot {
+	return p.snapshot.Load()
+}
+
+// RecordRefresh records the outcome of a refresh that didn't store a snapshot, for example one
+// that found the feature flags unchanged or failed. A failed refresh keeps the current snapshot.
+//
+// Parameters:
+//   - err: The error of the refresh, or nil if it succeeded
+func (p *SnapshotProvider) RecordRefresh(err error) {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+
+	if err == nil {
+		p.lastRefreshTime = time.Now()
+	}
+	p.lastRefreshErr = err
+}
+
+// ProviderStatus reports whether a snapshot was stored and the outcome of the last refresh.
+func (p *SnapshotProvider) ProviderStatus() ProviderStatus {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+
+	return ProviderStatus{
+		Loaded:           p.snapshot.Load() != nil,
+		LastRefreshTime:  p.lastRefreshTime,
+		LastRefreshError: p.lastRefreshErr,
+	}
+}
+
+// GetFeatureFlag returns a copy of the feature flag with the given ID from the current snapshot.
+func (p *SnapshotProvider) GetFeatureFlag(name string) (FeatureFlag, error) {
+	return p.snapshot.Load().GetFeatureFlag(name)
+}
+
+// GetFeatureFlags returns copies of the feature flags of the current snapshot.
+func (p *SnapshotProvider) GetFeatureFlags() ([]FeatureFlag, error) {
+	return p.snapshot.Load().GetFeatureFlags()
+}
+
+// All returns an iterator over copies of the feature flags of the current snapshot. A refresh
+// after All is called doesn't affect the flags yielded by the returned iterator.
+func (p *SnapshotProvider) All() iter.Seq[FeatureFlag] {
+	return p.snapshot.Load().All()
+}
+
+// ValidatesFeatureFlags reports that flags are validated when a snapshot is built,
+// so the feature manager doesn't need to validate them on every evaluation.
+func (p *SnapshotProvider) ValidatesFeatureFlags() bool {
+	return true
+}
+
+// ValidationErrors returns the invalid feature flags left out of the current snapshot.
+func (p *SnapshotProvider) ValidationErrors() []ValidationError {
+	return p.snapshot.Load().ValidationErrors()
+}
+
+// OnRefresh registers a callback called after each snapshot stored, so that the feature manager
+// can report changed feature flags to OnFeatureChanged callbacks. The callback runs on the
+// goroutine storing the snapshot.
+func (p *SnapshotProvider) OnRefresh(callback func()) func() {
+	return p.listeners.Add(callback)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestFeatureFlagSnapshot(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewProviderLogger(LogLevelWarn, slog.New(slog.NewTextHandler(&buf, nil)))
+	snapshot := NewFeatureFlagSnapshot([]FeatureFlag{
+		{ID: "Beta", Enabled: true},
+		{ID: ""},
+		{ID: "Alpha"},
+		{ID: "Beta"},
+	}, &SnapshotOptions{DuplicatePolicy: DuplicateError, Version: `"v1"`, Logger: logger})
+
+	if flags, _ := snapshot.GetFeatureFlags(); len(flags) != 1 || flags[0].ID != "Alpha" {
+		t.Errorf("Expected only Alpha to be served, got %+v", flags)
+	}
+	if _, err := snapshot.GetFeatureFlag("Beta"); err == nil {
+		t.Error("Expected the duplicated Beta to be left out")
+	}
+	if flag, err := snapshot.GetFeatureFlag("Alpha"); err != nil || flag.ID != "Alpha" {
+		t.Errorf("Expected Alpha, got %+v, %v", flag, err)
+	}
+
+	rejected := snapshot.ValidationErrors()
+	if len(rejected) != 2 || !errors.Is(rejected[1], ErrDuplicateFeature) {
+		t.Errorf("Expected the invalid flag and the duplicate to be rejected, got %v", rejected)
+	}
+	if snapshot.Version() != `"v1"` {
+		t.Errorf("Expected version \"v1\", got %s", snapshot.Version())
+	}
+	if output := buf.String(); strings.Count(output, "Ignoring invalid feature flag") != 2 {
+		t.Errorf("Expected each rejected flag to be logged, got %q", output)
+	}
+}
+
+func TestSnapshotProvider(t *testing.T) {
+	var provider SnapshotProvider
+	if status := provider.ProviderStatus(); status.Loaded {
+		t.Errorf("Expected the zero value not to be loaded, got %+v", status)
+	}
+	if _, err := provider.GetFeatureFlag("Beta"); err == nil {
+		t.Error("Expected no feature flags before a snapshot is stored")
+	}
+	if flags, err := provider.GetFeatureFlags(); len(flags) != 0 || err != nil {
+		t.Errorf("Expected no feature flags, got %v, %v", flags, err)
+	}
+
+	provider.StoreSnapshot(NewFeatureFlagSnapshot([]FeatureFlag{{ID: "Beta"}}, nil))
+	manager, err := NewFeatureManager(&provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	if !manager.Ready() {
+		t.Error("Expected the feature manager to be ready once a snapshot is stored")
+	}
+
+	var changes []FeatureChange
+	manager.OnFeatureChanged("Beta", func(change FeatureChange) {
+		changes = append(changes, change)
+	})
+	provider.StoreSnapshot(NewFeatureFlagSnapshot([]FeatureFlag{{ID: "Beta", Enabled: true}}, nil))
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || !enabled {
+		t.Errorf("Expected Beta to be enabled, got %v, %v", enabled, err)
+	}
+	if len(changes) != 1 || !changes[0].Current.Enabled {
+		t.Errorf("Expected Beta to change to enabled, got %+v", changes)
+	}
+
+	refreshErr := errors.New("unavailable")
+	provider.RecordRefresh(refreshErr)
+	if status := provider.ProviderStatus(); !status.Loaded || status.LastRefreshError != refreshErr || status.LastRefreshTime.IsZero() {
+		t.Errorf("Expected the failed refresh to keep the snapshot, got %+v", status)
+	}
+	if enabled, _ := manager.IsEnabled("Beta"); !enabled {
+		t.Error("Expected the last snapshot to be served after a failed refresh")
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sort"
+)
+
+// StaticProvider is a FeatureFlagProvider serving a fixed set of feature flags held in memory.
+// It is intended for small tools, tests and samples that don't need an external configuration source.
+type StaticProvider struct {
+	featureFlags     []FeatureFlag
+	featureFlagsByID map[string]FeatureFlag
+}
+
+// NewStaticProvider creates a provider serving copies of the given feature flags, keyed by feature
+// name, so later changes to the flags given don't affect it. A flag with an empty ID takes the ID
+// of its key.
+//
+// Example:
+//
+//	provider := featuremanagement.NewStaticProvider(map[string]featuremanagement.FeatureFlag{
+//		"Beta": {Enabled: true},
+//	})
+//	manager, _ := featuremanagement.NewFeatureManager(provider, nil)
+//
+// Parameters:
+//   - featureFlags: The feature flag definitions keyed by feature name
+//
+// Returns:
+//   - *StaticProvider: A provider serving the feature flags in order of their names
+func NewStaticProvider(featureFlags map[string]FeatureFlag) *StaticProvider {
+	names := make([]string, 0, len(featureFlags))
+	for name := range featureFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	provider := &StaticProvider{
+		featureFlags:     make([]FeatureFlag, 0, len(names)),
+		featureFlagsByID: make(map[string]FeatureFlag, len(names)),
+	}
+	for _, name := range names {
+		flag := featureFlags[name].Clone()
+		if flag.ID == "" {
+			flag.ID = name
+		}
+		provider.featureFlags = append(provider.featureFlags, flag)
+		provider.featureFlagsByID[flag.ID] = flag
+	}
+
+	return provider
+}
+
+// NewBoolProvider creates a provider of unconditionally enabled or disabled feature flags.
+//
+// Example:
+//
+//	provider := featuremanagement.NewBoolProvider(map[string]bool{"Beta": true})
+//	manager, _ := featuremanagement.NewFeatureManager(provider, nil)
+//
+// Parameters:
+//   - featureFlags: The enabled state of each feature keyed by feature name
+//
+// Returns:
+//   - *StaticProvider: A provider serving the feature flags in order of their names
+func NewBoolProvider(featureFlags map[string]bool) *StaticProvider {
+	flags := make(map[string]FeatureFlag, len(featureFlags))
+	for name, enabled := range featureFlags {
+		flags[name] = FeatureFlag{ID: name, Enabled: enabled}
+	}
+
+	return NewStaticProvider(flags)
+}
+
+// GetFeatureFlag returns a copy of the definition of a feature flag, so that callers changing it
+// don't affect the flag served.
+//
+// Parameters:
+//   - name: The ID of the feature flag
+//
+// Returns:
+//   - FeatureFlag: The feature flag definition
+//   - error: An error if no feature flag has the ID
+func (p *StaticProvider) GetFeatureFlag(name string) (FeatureFlag, error) {
+	if flag, ok := p.featureFlagsByID[name]; ok {
+		return flag.Clone(), nil
+	}
+
+	return FeatureFlag{}, fmt.Errorf("feature flag with ID %s not found", name)
+}
+
+// GetFeatureFlags returns copies of the feature flags, in order of their names, so that callers
+// changing them don't affect the flags served.
+//
+// Returns:
+//   - []FeatureFlag: The feature flags
+//   - error: Always nil
+func (p *StaticProvider) GetFeatureFlags() ([]FeatureFlag, error) {
+	return slices.Collect(p.All()), nil
+}
+
+// All returns an iterator over copies of the feature flags, in order of their names.
+func (p *StaticProvider) All() iter.Seq[FeatureFlag] {
+	return cloneFeatureFlags(slices.Values(p.featureFlags))
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestStaticProvider(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {Enabled: true},
+		"Greeting": {
+			Enabled:    true,
+			Variants:   []VariantDefinition{{Name: "Casual"}},
+			Allocation: &VariantAllocation{DefaultWhenEnabled: "Casual"},
+		},
+	})
+
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || !enabled {
+		t.Errorf("Expected Beta to be enabled, got %v, %v", enabled, err)
+	}
+
+	if variant, err := manager.GetVariant("Greeting", nil); err != nil || variant == nil || variant.Name != "Casual" {
+		t.Errorf("Expected Casual variant, got %v, %v", variant, err)
+	}
+
+	if names, err := manager.GetFeatureNames(); err != nil || fmt.Sprint(names) != "[Beta Greeting]" {
+		t.Errorf("Expected feature names in sorted order, got %v, %v", names, err)
+	}
+
+	if _, err := provider.GetFeatureFlag("Unknown"); err == nil {
+		t.Error("Expected error for unknown feature flag")
+	}
+
+	// Changing the returned slice doesn't affect the flags served
+	flags, _ := provider.GetFeatureFlags()
+	flags[0] = FeatureFlag{ID: "Replaced"}
+	if flags, _ := provider.GetFeatureFlags(); flags[0].ID != "Beta" {
+		t.Errorf("Expected the flags served to be unaffected, got %+v", flags[0])
+	}
+}
+
+func TestBoolProvider(t *testing.T) {
+	manager, err := NewFeatureManager(NewBoolProvider(map[string]bool{"Beta": true, "Legacy": false}), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	for name, expected := range map[string]bool{"Beta": true, "Legacy": false} {
+		if enabled, err := manager.IsEnabled(name); err != nil || enabled != expected {
+			t.Errorf("Expected %s to be %v, got %v, %v", name, expected, enabled, err)
+		}
+	}
+}
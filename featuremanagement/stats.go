@@ -0,0 +1,84 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats describes the state of a feature manager, for readiness probes and diagnostics.
+type Stats struct {
+	// Ready reports that the provider completed its first load; see FeatureManager.Ready
+	Ready bool
+	// FeatureFlagCount is the number of feature flags currently served
+	FeatureFlagCount int
+	// Filters contains the names of the registered feature filters, sorted
+	Filters []string
+	// LastRefreshTime is the time of the provider's last successful load or refresh, or the zero
+	// time if the provider doesn't implement ProviderStatusReporter
+	LastRefreshTime time.Time
+	// LastRefreshError is the error of the provider's most recent refresh. For providers that
+	// don't implement ProviderStatusReporter, it is the error of enumerating the feature flags.
+	LastRefreshError error
+	// Panics is the number of panics raised by providers, filters, allocation strategies,
+	// assignment stores, telemetry publishers and interceptors, and recovered as a *PanicError
+	Panics uint64
+}
+
+// Ready reports whether the provider completed its first load of feature flags, so that an
+// application can delay accepting traffic, for example from a Kubernetes readiness probe:
+//
+//	http.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+//		if !manager.Ready() {
+//			w.WriteHeader(http.StatusServiceUnavailable)
+//		}
+//	})
+//
+// Providers implementing ProviderStatusReporter report their own readiness; any other provider
+// is ready when it can enumerate its feature flags.
+//
+// Returns:
+//   - bool: true if the feature flags are loaded
+func (fm *FeatureManager) Ready() bool {
+	if reporter, ok := fm.featureProvider.(ProviderStatusReporter); ok {
+		return reporter.ProviderStatus().Loaded
+	}
+
+	_, err := fm.getFeatureFlags()
+	return err == nil
+}
+
+// Stats returns the readiness of the provider, the number of feature flags served, the registered
+// feature filters and the outcome of the provider's last refresh.
+//
+// Returns:
+//   - Stats: The current state of the feature manager
+func (fm *FeatureManager) Stats() Stats {
+	stats := Stats{
+		Filters: make([]string, 0, len(fm.featureFilters)),
+		Panics:  fm.panics.Load(),
+	}
+	for name := range fm.featureFilters {
+		stats.Filters = append(stats.Filters, name)
+	}
+	sort.Strings(stats.Filters)
+
+	if reporter, ok := fm.featureProvider.(ProviderStatusReporter); ok {
+		status := reporter.ProviderStatus()
+		stats.Ready = status.Loaded
+		stats.LastRefreshTime = status.LastRefreshTime
+		stats.LastRefreshError = status.LastRefreshError
+	} else {
+		_, err := fm.getFeatureFlags()
+		stats.Ready = err == nil
+		stats.LastRefreshError = err
+	}
+
+	for range fm.flags() {
+		stats.FeatureFlagCount++
+	}
+
+	return stats
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type loadingProvider struct {
+	*StaticProvider
+	status ProviderStatus
+}
+
+func (p *loadingProvider) ProviderStatus() ProviderStatus {
+	return p.status
+}
+
+func TestStats(t *testing.T) {
+	provider := &loadingProvider{StaticProvider: NewStaticProvider(map[string]FeatureFlag{
+		"Alpha": {Enabled: true},
+		"Beta":  {Enabled: false},
+	})}
+	manager, err := NewFeatureManager(provider, &Options{Filters: []FeatureFilter{&slowFilter{}}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if manager.Ready() {
+		t.Error("Expected the manager not to be ready before the first load")
+	}
+
+	refreshed := time.Now()
+	refreshErr := errors.New("connection reset")
+	provider.status = ProviderStatus{Loaded: true, LastRefreshTime: refreshed, LastRefreshError: refreshErr}
+	if !manager.Ready() {
+		t.Error("Expected the manager to be ready after the first load")
+	}
+
+	stats := manager.Stats()
+	if !stats.Ready || stats.FeatureFlagCount != 2 {
+		t.Errorf("Expected a ready manager with 2 flags, got %+v", stats)
+	}
+	if fmt.Sprint(stats.Filters) != fmt.Sprint([]string{"Microsoft.Locale", "Microsoft.Numeric", "Microsoft.Targeting", "Microsoft.TimeWindow", "Slow"}) {
+		t.Errorf("Unexpected filters %v", stats.Filters)
+	}
+	if !stats.LastRefreshTime.Equal(refreshed) || stats.LastRefreshError != refreshErr {
+		t.Errorf("Expected the provider's refresh outcome, got %v, %v", stats.LastRefreshTime, stats.LastRefreshError)
+	}
+}
+
+func TestStatsWithoutStatusReporter(t *testing.T) {
+	manager, err := NewFeatureManager(&mockFeatureFlagProvider{featureFlags: []FeatureFlag{{ID: "Alpha"}}}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	stats := manager.Stats()
+	if !manager.Ready() || !stats.Ready || stats.FeatureFlagCount != 1 || stats.LastRefreshError != nil {
+		t.Errorf("Expected a ready manager with 1 flag, got %+v", stats)
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/microsoft/Featuremanagement-Go/featuremanagement/stores/redisquota
+
+go 1.23.0
+
+require (
+	github.com/alicebob/miniredis/v2 v2.33.0
+	github.com/microsoft/Featuremanagement-Go/featuremanagement v1.1.0
+	github.com/redis/go-redis/v9 v9.7.0
+)
+
+require (
+	github.com/alicebob/gopher-json v0.0.0-20200520072559-a9ecdc9d1d3a // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	golang.org/x/sync v0.16.0 // indirect
+	golang.org/x/text v0.27.0 // indirect
+)
+
+replace github.com/microsoft/Featuremanagement-Go/featuremanagement => ../..
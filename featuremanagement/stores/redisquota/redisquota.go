@@ -0,0 +1,86 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+// Package redisquota provides a quota counter store backed by Redis, so that the quota filter
+// applies its limits across all instances of an application.
+package redisquota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// incrementScript increments a counter and sets its expiry when it is created, atomically so
+// that a counter is never left without one
+var incrementScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// Options configures the CounterStore.
+type Options struct {
+	// KeyPrefix is prepended to the keys of the counters. Defaults to "featuremanagement:quota:".
+	KeyPrefix string
+
+	// Timeout bounds each call to Redis. Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// CounterStore is a featuremanagement.QuotaCounterStore keeping the counters in Redis.
+type CounterStore struct {
+	client    redis.Scripter
+	keyPrefix string
+	timeout   time.Duration
+}
+
+// NewCounterStore creates a counter store using the given client, which can be a *redis.Client,
+// a *redis.ClusterClient or any other redis.UniversalClient.
+//
+// Parameters:
+//   - client: The Redis client
+//   - options: The store options, or nil for the defaults
+//
+// Returns:
+//   - *CounterStore: The counter store
+func NewCounterStore(client redis.Scripter, options *Options) *CounterStore {
+	if options == nil {
+		options = &Options{}
+	}
+	keyPrefix := options.KeyPrefix
+	if keyPrefix == "" {
+		keyPrefix = "featuremanagement:quota:"
+	}
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	return &CounterStore{client: client, keyPrefix: keyPrefix, timeout: timeout}
+}
+
+// Increment adds one to the counter of the key and returns the new count.
+//
+// Parameters:
+//   - key: The key of the counter, without the key prefix
+//   - ttl: How long a new counter is kept, or zero to keep it forever
+//
+// Returns:
+//   - int64: The count including this activation
+//   - error: An error if Redis can't be reached or the call times out
+func (s *CounterStore) Increment(key string, ttl time.Duration) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	count, err := incrementScript.Run(ctx, s.client, []string{s.keyPrefix + key}, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment quota counter %s: %w", key, err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package redisquota
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	fm "github.com/microsoft/Featuremanagement-Go/featuremanagement"
+	"github.com/redis/go-redis/v9"
+)
+
+// The store must satisfy the interface of the quota filter
+var _ fm.QuotaCounterStore = (*CounterStore)(nil)
+
+func TestIncrement(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	store := NewCounterStore(client, &Options{KeyPrefix: "test:"})
+
+	for expected := int64(1); expected <= 3; expected++ {
+		count, err := store.Increment("Beta", time.Hour)
+		if err != nil || count != expected {
+			t.Fatalf("Expected count %d, got %d, %v", expected, count, err)
+		}
+	}
+	if ttl := server.TTL("test:Beta"); ttl != time.Hour {
+		t.Errorf("Expected the counter to expire in an hour, got %v", ttl)
+	}
+
+	server.FastForward(time.Hour)
+	if count, err := store.Increment("Beta", time.Hour); err != nil || count != 1 {
+		t.Errorf("Expected the expired counter to start again, got %d, %v", count, err)
+	}
+
+	if _, err := store.Increment("Total", 0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ttl := server.TTL("test:Total"); ttl != 0 {
+		t.Errorf("Expected a counter without expiry, got %v", ttl)
+	}
+}
+
+func TestQuotaFilter(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	provider := fm.NewStaticProvider(map[string]fm.FeatureFlag{
+		"Beta": {
+			Enabled: true,
+			Conditions: &fm.Conditions{ClientFilters: []fm.ClientFilter{{
+				Name:       "Microsoft.Quota",
+				Parameters: map[string]any{"Limit": 1},
+			}}},
+		},
+	})
+	// Two managers sharing the store share the quota
+	var managers []*fm.FeatureManager
+	for i := 0; i < 2; i++ {
+		manager, err := fm.NewFeatureManager(provider, &fm.Options{
+			Filters: []fm.FeatureFilter{fm.NewQuotaFilter(NewCounterStore(client, nil))},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create feature manager: %v", err)
+		}
+		managers = append(managers, manager)
+	}
+
+	if enabled, err := managers[0].IsEnabled("Beta"); err != nil || !enabled {
+		t.Errorf("Expected the first activation to be admitted, got %v, %v", enabled, err)
+	}
+	if enabled, err := managers[1].IsEnabled("Beta"); err != nil || enabled {
+		t.Errorf("Expected the quota to be exhausted, got %v, %v", enabled, err)
+	}
+}
+
+func TestIncrementError(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	defer client.Close()
+	server.Close()
+
+	if _, err := NewCounterStore(client, &Options{Timeout: time.Second}).Increment("Beta", 0); err == nil {
+		t.Error("Expected an error when Redis is unavailable")
+	}
+}
@@ -8,11 +8,53 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"math/big"
+	"strings"
+	"unicode"
 
-	"github.com/go-viper/mapstructure/v2"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
 )
 
-type TargetingFilter struct{}
+type TargetingFilter struct {
+	// IgnoreCase compares user IDs and group names case-insensitively, so that for example
+	// "Alice@Contoso.com" matches an audience listing "alice@contoso.com". Percentile
+	// bucketing still uses the user ID as given.
+	IgnoreCase bool
+	// TrimSpace ignores leading and trailing white space in user IDs and group names when
+	// comparing them
+	TrimSpace bool
+	// NormalizeUnicode converts user IDs and group names to Unicode normalization form C (NFC)
+	// before comparing them, and user IDs before bucketing them, so that an ID is the same user
+	// however its characters are composed, for example "Zoë" with a precomposed or a combining
+	// diaeresis. IDs already in NFC, including all ASCII IDs, keep their buckets.
+	NormalizeUnicode bool
+	// FoldCase case-folds user IDs and group names before comparing them, and user IDs before
+	// bucketing them, so that "Alice" and "alice" are the same user. Unlike IgnoreCase, it changes
+	// the buckets of IDs that aren't already folded, moving users between rollout percentiles.
+	FoldCase bool
+	// AllowAnonymous evaluates callers without a targeting context, whose app context is nil or
+	// neither a TargetingContext nor a TargetingContexter, as an anonymous user with an empty ID
+	// and no groups, instead of failing. Anonymous users are only targeted by the default rollout
+	// percentage, and all share one bucket, so that they are all enabled at 100% and all disabled
+	// at 0%. Register the filter with Options.Filters to replace the default one.
+	AllowAnonymous bool
+	// HighResolutionBuckets buckets users with 64-bit context markers, see ComputeBucket64, for
+	// rollouts finer than 32-bit buckets can split evenly. Only users within a 32-bit bucket of a
+	// rollout boundary are bucketed differently than by the other SDKs.
+	// Options.HighResolutionBuckets enables it for every targeting filter of the feature manager,
+	// including one registered with Options.Filters.
+	HighResolutionBuckets bool
+	// UserPatterns lets the users of the audience, and of its exclusion, contain '*' wildcards
+	// matching any sequence of characters, so that "*@contoso.com" targets a whole email domain.
+	// Patterns are compared with the same IgnoreCase, TrimSpace, NormalizeUnicode and FoldCase
+	// rules as other users. Without it, '*' is an ordinary character. Options.UserPatterns enables
+	// it for every targeting filter of the feature manager, including one registered with
+	// Options.Filters.
+	UserPatterns bool
+
+	paramCache parameterCache[TargetingFilterParameters]
+}
 
 // TargetingGroup defines a named group with a specific rollout percentage
 type TargetingGroup struct {
@@ -20,13 +62,35 @@ type TargetingGroup struct {
 	RolloutPercentage float64
 }
 
-// TargetingExclusion defines users and groups explicitly excluded from targeting
+// TargetingExclusion defines users and groups explicitly excluded from targeting, and
+// percentile ranges of users held back from the rollout
 type TargetingExclusion struct {
 	Users  []string
 	Groups []string
+	// Percentiles hold back the users whose bucket falls in any of the ranges, for example to
+	// keep a fixed slice of traffic out of a rollout for comparison. Users are bucketed
+	// independently of the rollout percentages, so a holdback removes the same proportion of
+	// users at every stage of the rollout and always the same users.
+	Percentiles []TargetingPercentileRange
+}
+
+// TargetingPercentileRange is a range of user buckets, from From inclusive to To exclusive,
+// or inclusive when To is 100
+type TargetingPercentileRange struct {
+	From float64
+	To   float64
 }
 
-// TargetingAudience defines the targeting configuration for feature rollout
+// exclusionHint returns the hint bucketing users for the percentile exclusions of a feature,
+// see ExclusionHint
+func exclusionHint(featureName string) []string {
+	return []string{featureName, "", ExclusionHint}
+}
+
+// TargetingAudience defines the targeting configuration for feature rollout.
+//
+// Entries of Users, and of the Users of the exclusion, may contain '*' wildcards matching any
+// sequence of characters when the filter enables UserPatterns.
 type TargetingAudience struct {
 	DefaultRolloutPercentage float64
 	Users                    []string
@@ -45,48 +109,68 @@ func (t *TargetingFilter) Name() string {
 
 func (t *TargetingFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appCtx any) (bool, error) {
 	// Validate parameters
-	params, err := getTargetingParams(evalCtx)
+	params, err := t.getParams(evalCtx)
 	if err != nil {
 		return false, err
 	}
 
 	// Check if app context is valid
-	targetingCtx, ok := appCtx.(TargetingContext)
+	targetingCtx, ok := targetingContextFrom(appCtx)
 	if !ok {
-		return false, fmt.Errorf("the app context is required for targeting filter and must be of type TargetingContext")
+		if !t.AllowAnonymous {
+			return false, fmt.Errorf("the app context is required for targeting filter and must be a TargetingContext or implement TargetingContexter")
+		}
+		targetingCtx = &TargetingContext{}
 	}
+	if t.NormalizeUnicode || t.FoldCase {
+		targetingCtx = t.normalizeContext(targetingCtx)
+	}
+
+	users := t.userMatcher(evalCtx)
+	groups := users
+	groups.patterns = false
 
 	// Check exclusions
 	if params.Audience.Exclusion != nil {
 		// Check if the user is in the exclusion list
 		if targetingCtx.UserID != "" &&
 			len(params.Audience.Exclusion.Users) > 0 &&
-			isTargetedUser(targetingCtx.UserID, params.Audience.Exclusion.Users) {
+			isTargetedUser(targetingCtx.UserID, params.Audience.Exclusion.Users, users.match) {
 			return false, nil
 		}
 
 		// Check if the user is in a group within exclusion list
 		if len(targetingCtx.Groups) > 0 &&
 			len(params.Audience.Exclusion.Groups) > 0 &&
-			isTargetedGroup(targetingCtx.Groups, params.Audience.Exclusion.Groups) {
+			isTargetedGroup(targetingCtx.Groups, params.Audience.Exclusion.Groups, groups.match) {
 			return false, nil
 		}
+
+		// Check if the user is in a held back percentile range
+		for _, holdback := range params.Audience.Exclusion.Percentiles {
+			excluded, err := isTargetedPercentile(t.bucketer(evalCtx), targetingCtx.UserID, holdback.From, holdback.To, exclusionHint(evalCtx.FeatureName)...)
+			if err != nil {
+				return false, err
+			}
+			if excluded {
+				return false, nil
+			}
+		}
 	}
 
 	// Check if the user is being targeted directly
 	if targetingCtx.UserID != "" &&
 		len(params.Audience.Users) > 0 &&
-		isTargetedUser(targetingCtx.UserID, params.Audience.Users) {
+		isTargetedUser(targetingCtx.UserID, params.Audience.Users, users.match) {
 		return true, nil
 	}
 
 	// Check if the user is in a group that is being targeted
 	if len(targetingCtx.Groups) > 0 && len(params.Audience.Groups) > 0 {
 		for _, group := range params.Audience.Groups {
-			if isTargetedGroup(targetingCtx.Groups, []string{group.Name}) {
+			if isTargetedGroup(targetingCtx.Groups, []string{group.Name}, groups.match) {
 				// Check if user is in the rollout percentage for this group
-				hint := fmt.Sprintf("%s\n%s", evalCtx.FeatureName, group.Name)
-				targeted, err := isTargetedPercentile(targetingCtx.UserID, hint, 0, group.RolloutPercentage)
+				targeted, err := isTargetedPercentile(t.bucketer(evalCtx), targetingCtx.UserID, 0, group.RolloutPercentage, evalCtx.FeatureName, group.Name)
 				if err != nil {
 					return false, err
 				}
@@ -98,27 +182,111 @@ func (t *TargetingFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appCt
 	}
 
 	// Check if the user is being targeted by a default rollout percentage
-	hint := evalCtx.FeatureName
-	return isTargetedPercentile(targetingCtx.UserID, hint, 0, params.Audience.DefaultRolloutPercentage)
+	return isTargetedPercentile(t.bucketer(evalCtx), targetingCtx.UserID, 0, params.Audience.DefaultRolloutPercentage, evalCtx.FeatureName)
+}
+
+// userMatcher returns the matcher comparing user IDs and group names with the audience, honoring
+// the comparison options of the filter, and the UserPatterns option of the filter and of the
+// feature manager evaluating it
+func (t *TargetingFilter) userMatcher(evalCtx FeatureFilterEvaluationContext) userMatcher {
+	return userMatcher{
+		ignoreCase: t.IgnoreCase,
+		trimSpace:  t.TrimSpace,
+		nfc:        t.NormalizeUnicode,
+		foldCase:   t.FoldCase,
+		patterns:   t.UserPatterns || evalCtx.userPatterns,
+	}
+}
+
+// bucketer returns the function bucketing users, honoring the HighResolutionBuckets option of
+// the filter and of the feature manager evaluating it
+func (t *TargetingFilter) bucketer(evalCtx FeatureFilterEvaluationContext) bucketer {
+	return bucketerFor(t.HighResolutionBuckets || evalCtx.highResolutionBuckets)
+}
+
+// normalizeContext returns a copy of the targeting context with its user ID and groups normalized
+func (t *TargetingFilter) normalizeContext(targetingCtx *TargetingContext) *TargetingContext {
+	normalized := *targetingCtx
+	normalized.UserID = t.normalize(targetingCtx.UserID)
+	if len(targetingCtx.Groups) > 0 {
+		normalized.Groups = make([]string, len(targetingCtx.Groups))
+		for i, group := range targetingCtx.Groups {
+			normalized.Groups[i] = t.normalize(group)
+		}
+	}
+
+	return &normalized
 }
 
-func getTargetingParams(evalCtx FeatureFilterEvaluationContext) (TargetingFilterParameters, error) {
+// normalize applies the NormalizeUnicode and FoldCase options to a user ID or group name
+func (t *TargetingFilter) normalize(id string) string {
+	return normalizeTargetingID(id, t.NormalizeUnicode, t.FoldCase)
+}
+
+// NormalizeTargetingID normalizes a user ID or group name as a TargetingFilter with the
+// NormalizeUnicode and FoldCase options does, so that applications can normalize the IDs of the
+// TargetingContext themselves, for example to bucket users consistently for variant allocation,
+// which uses the user ID as given.
+//
+// Parameters:
+//   - id: The user ID or group name
+//   - foldCase: Whether to case-fold the ID in addition to converting it to NFC
+//
+// Returns:
+//   - string: The ID in Unicode normalization form C, case-folded if requested
+func NormalizeTargetingID(id string, foldCase bool) string {
+	return normalizeTargetingID(id, true, foldCase)
+}
+
+func normalizeTargetingID(id string, nfc, foldCase bool) string {
+	if foldCase {
+		// A Caser holds state, so it can't be shared between evaluations
+		id = cases.Fold().String(id)
+	}
+	if nfc {
+		// Case folding can produce decomposed characters, so it comes first
+		id = norm.NFC.String(id)
+	}
+
+	return id
+}
+
+func (t *TargetingFilter) preloadParameters(featureName string, parameters map[string]any) error {
+	_, err := t.getParams(FeatureFilterEvaluationContext{FeatureName: featureName, Parameters: parameters})
+	return err
+}
+
+// getParams returns the decoded parameters, decoding them once per flag definition
+func (t *TargetingFilter) getParams(evalCtx FeatureFilterEvaluationContext) (TargetingFilterParameters, error) {
+	return t.paramCache.get(evalCtx.FeatureName, evalCtx.Parameters, decodeTargetingParams)
+}
+
+func decodeTargetingParams(featureName string, parameters map[string]any) (TargetingFilterParameters, error) {
 	var params TargetingFilterParameters
-	err := mapstructure.Decode(evalCtx.Parameters, &params)
+	err := BindParameters(parameters, &params)
 	if err != nil {
 		return TargetingFilterParameters{}, fmt.Errorf("failed to decode feature flag parameters: %v", err)
 	}
 
 	// Validate DefaultRolloutPercentage
 	if params.Audience.DefaultRolloutPercentage < 0 || params.Audience.DefaultRolloutPercentage > 100 {
-		return TargetingFilterParameters{}, fmt.Errorf("invalid feature flag: %s. Audience.DefaultRolloutPercentage must be a number between 0 and 100", evalCtx.FeatureName)
+		return TargetingFilterParameters{}, fmt.Errorf("invalid feature flag: %s. Audience.DefaultRolloutPercentage must be a number between 0 and 100", featureName)
+	}
+
+	// Validate the percentile ranges of the exclusion
+	if params.Audience.Exclusion != nil {
+		for i, holdback := range params.Audience.Exclusion.Percentiles {
+			if holdback.From < 0 || holdback.To > 100 || holdback.From > holdback.To {
+				return TargetingFilterParameters{}, fmt.Errorf("invalid feature flag: %s. Exclusion percentile range at index %d must be within 0 to 100 with From not larger than To", featureName, i)
+			}
+		}
 	}
 
 	// Validate RolloutPercentage for each group
 	if len(params.Audience.Groups) > 0 {
 		for _, group := range params.Audience.Groups {
 			if group.RolloutPercentage < 0 || group.RolloutPercentage > 100 {
-				return TargetingFilterParameters{}, fmt.Errorf("invalid feature flag: %s. RolloutPercentage of group %s must be a number between 0 and 100", evalCtx.FeatureName, group.Name)
+				return TargetingFilterParameters{}, fmt.Errorf("invalid feature flag: %s. RolloutPercentage of group %s must be a number between 0 and 100", featureName, group.Name)
 			}
 		}
 	}
@@ -126,8 +294,103 @@ func getTargetingParams(evalCtx FeatureFilterEvaluationContext) (TargetingFilter
 	return params, nil
 }
 
-// isTargetedPercentile determines if the user is part of the audience based on percentile range
-func isTargetedPercentile(userID string, hint string, from float64, to float64) (bool, error) {
+// ComputeBucket returns the percentile, between 0 and 100, that a user falls into for the given hint.
+// A user is inside a percentile range [From, To) when From <= bucket < To, or when the bucket is
+// at least From for a range ending at 100. The hint identifies what is being rolled out:
+//   - a targeting filter's default rollout uses the feature name: ComputeBucket(user, "Beta")
+//   - a targeting filter's group rollout uses the feature and group names: ComputeBucket(user, "Beta", "Ring1")
+//   - a variant percentile allocation uses the allocation seed when set: ComputeBucket(user, seed)
+//   - otherwise AllocationHint and the feature name: ComputeBucket(user, "allocation", "Beta")
+//   - a targeting filter's percentile exclusion uses the feature name, an empty part and
+//     ExclusionHint: ComputeBucket(user, "Beta", "", "exclusion")
+//
+// Parameters:
+//   - userID: The ID of the user being targeted
+//   - hint: The parts of the hint, joined with newlines as in the audience context ID
+//
+// Returns:
+//   - float64: The percentile the user falls into
+func ComputeBucket(userID string, hint ...string) float64 {
+	contextMarker := hashAudienceContextID(userID, hint...)
+	return (float64(contextMarker) / MaxContextMarker) * 100
+}
+
+// ComputeBucket64 is like ComputeBucket, with a 64-bit context marker read from the first
+// ContextMarkerSize64 bytes of the hash: its high 32 bits are the context marker of ComputeBucket
+// and its low 32 bits the next 4 bytes, little-endian. It refines the bucket of ComputeBucket,
+// which it differs from by less than the width of a 32-bit bucket.
+//
+// A float64 keeps only the 53 most significant bits of the marker, so the bucket returned is
+// rounded to about 1e-14 percentage points. Targeting filters and allocations with high
+// resolution buckets compare the full 64-bit marker with the bounds of percentile ranges instead,
+// so that ranges apart by less than the rounding still split users exactly.
+//
+// Parameters:
+//   - userID: The ID of the user being targeted
+//   - hint: The parts of the hint, as for ComputeBucket
+//
+// Returns:
+//   - float64: The percentile the user falls into, rounded to the precision of a float64
+func ComputeBucket64(userID string, hint ...string) float64 {
+	contextMarker := hashAudienceContextID64(userID, hint...)
+	return (float64(contextMarker) / MaxContextMarker64) * 100
+}
+
+// bucketer places users in percentile ranges for a hint, with ComputeBucket64 for high resolution
+// buckets and ComputeBucket otherwise
+type bucketer struct {
+	highResolution bool
+}
+
+func bucketerFor(highResolution bool) bucketer {
+	return bucketer{highResolution: highResolution}
+}
+
+// inRange reports whether the bucket of a user is in the percentile range [from, to), or
+// [from, 100] when to is 100
+func (b bucketer) inRange(userID string, from, to float64, hint ...string) bool {
+	if b.highResolution {
+		if math.IsNaN(from) || math.IsNaN(to) {
+			return false
+		}
+		contextMarker := hashAudienceContextID64(userID, hint...)
+		return reachesPercentile64(contextMarker, from) && (to == 100 || !reachesPercentile64(contextMarker, to))
+	}
+
+	contextPercentage := ComputeBucket(userID, hint...)
+
+	// Handle edge case of exact 100 bucket
+	if to == 100 {
+		return contextPercentage >= from
+	}
+
+	return contextPercentage >= from && contextPercentage < to
+}
+
+// bucketRoundingMargin bounds the rounding error of a 64-bit bucket computed as a float64, in
+// percentage points
+const bucketRoundingMargin = 1e-10
+
+// reachesPercentile64 reports whether the bucket of a 64-bit context marker is at least the
+// percentile. The float64 bucket decides unless it is within its rounding error of the
+// percentile, in which case the marker is compared exactly.
+func reachesPercentile64(contextMarker uint64, percentile float64) bool {
+	contextPercentage := (float64(contextMarker) / MaxContextMarker64) * 100
+	if math.Abs(contextPercentage-percentile) > bucketRoundingMargin {
+		return contextPercentage > percentile
+	}
+
+	// contextMarker * 100 >= percentile * MaxContextMarker64, exact at 128 bits of precision
+	scaled := new(big.Float).SetPrec(128).SetUint64(contextMarker)
+	scaled.Mul(scaled, big.NewFloat(100))
+	threshold := new(big.Float).SetPrec(128).SetFloat64(percentile)
+	threshold.Mul(threshold, new(big.Float).SetPrec(128).SetUint64(MaxContextMarker64))
+	return scaled.Cmp(threshold) >= 0
+}
+
+// isTargetedPercentile determines if the user is part of the audience based on percentile range.
+// The hint parts are joined with newlines to form the hint of the audience context ID.
+func isTargetedPercentile(bucket bucketer, userID string, from float64, to float64, hint ...string) (bool, error) {
 	// Validate percentile range
 	if from < 0 || from > 100 {
 		return false, fmt.Errorf("the 'from' value must be between 0 and 100")
@@ -139,27 +402,91 @@ func isTargetedPercentile(userID string, hint string, from float64, to float64)
 		return false, fmt.Errorf("the 'from' value cannot be larger than the 'to' value")
 	}
 
-	audienceContextID := constructAudienceContextID(userID, hint)
+	return bucket.inRange(userID, from, to, hint...), nil
+}
 
-	// Convert to uint32 for percentage calculation
-	contextMarker, err := hashStringToUint32(audienceContextID)
-	if err != nil {
-		return false, err
+// stringComparer reports whether a value of the targeting context matches a value of the audience
+type stringComparer func(actual, expected string) bool
+
+// userMatcher matches user IDs and group names of a targeting context with those listed by a
+// targeting audience or a variant allocation. Targeting filters and allocations share it, so that
+// listed users and patterns are compared with the same rules wherever they appear. The zero value
+// compares values exactly.
+type userMatcher struct {
+	// ignoreCase compares values case-insensitively, as strings.EqualFold does
+	ignoreCase bool
+	// trimSpace ignores leading and trailing white space
+	trimSpace bool
+	// nfc and foldCase normalize the listed values, see normalizeTargetingID. Values of the
+	// targeting context are normalized beforehand, see TargetingFilter.normalizeContext.
+	nfc      bool
+	foldCase bool
+	// patterns matches listed values containing '*' wildcards as patterns
+	patterns bool
+}
+
+// match reports whether a value of the targeting context matches a listed value, which is a
+// wildcard pattern if patterns are enabled and it contains a wildcard
+func (m userMatcher) match(actual, expected string) bool {
+	expected = normalizeTargetingID(expected, m.nfc, m.foldCase)
+	if m.trimSpace {
+		actual = strings.TrimSpace(actual)
+		expected = strings.TrimSpace(expected)
 	}
 
-	// Calculate percentage (0-100)
-	contextPercentage := (float64(contextMarker) / float64(math.MaxUint32)) * 100
+	if m.patterns && strings.Contains(expected, wildcard) {
+		if m.ignoreCase {
+			actual = simpleFold(actual)
+			expected = simpleFold(expected)
+		}
+		return matchWildcard(actual, expected)
+	}
 
-	// Handle edge case of exact 100 bucket
-	if to == 100 {
-		return contextPercentage >= from, nil
+	if m.ignoreCase {
+		return strings.EqualFold(actual, expected)
 	}
 
-	return contextPercentage >= from && contextPercentage < to, nil
+	return actual == expected
+}
+
+// simpleFold maps each rune to the smallest rune of its simple case folding orbit, so that two
+// strings are equal under strings.EqualFold exactly when their folded forms are equal
+func simpleFold(s string) string {
+	return strings.Map(func(r rune) rune {
+		folded := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			folded = min(folded, f)
+		}
+		return folded
+	}, s)
+}
+
+// wildcard matches any sequence of characters in user patterns, see userMatcher
+const wildcard = "*"
+
+// matchWildcard reports whether value matches pattern, in which each wildcard matches any
+// sequence of characters, including an empty one
+func matchWildcard(value, pattern string) bool {
+	parts := strings.Split(pattern, wildcard)
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+
+	return len(value) >= len(last) && strings.HasSuffix(value, last)
 }
 
 // isTargetedGroup determines if the user is part of the audience based on groups
-func isTargetedGroup(sourceGroups []string, targetedGroups []string) bool {
+func isTargetedGroup(sourceGroups []string, targetedGroups []string, compare stringComparer) bool {
 	if len(sourceGroups) == 0 {
 		return false
 	}
@@ -167,7 +494,7 @@ func isTargetedGroup(sourceGroups []string, targetedGroups []string) bool {
 	// Check if any source group is in the targeted groups
 	for _, sourceGroup := range sourceGroups {
 		for _, targetedGroup := range targetedGroups {
-			if sourceGroup == targetedGroup {
+			if compare(sourceGroup, targetedGroup) {
 				return true
 			}
 		}
@@ -177,14 +504,14 @@ func isTargetedGroup(sourceGroups []string, targetedGroups []string) bool {
 }
 
 // isTargetedUser determines if the user is part of the audience based on user ID
-func isTargetedUser(userID string, users []string) bool {
+func isTargetedUser(userID string, users []string, compare stringComparer) bool {
 	if userID == "" {
 		return false
 	}
 
 	// Check if the user is in the targeted users list
 	for _, user := range users {
-		if userID == user {
+		if compare(userID, user) {
 			return true
 		}
 	}
@@ -192,14 +519,37 @@ func isTargetedUser(userID string, users []string) bool {
 	return false
 }
 
-// constructAudienceContextID builds the context ID for the audience
-func constructAudienceContextID(userID string, hint string) string {
-	return fmt.Sprintf("%s\n%s", userID, hint)
+// audienceContextIDBufferSize is the size of the stack buffer used to build audience context IDs.
+// IDs that don't fit fall back to a heap allocation.
+const audienceContextIDBufferSize = 256
+
+// appendAudienceContextID appends the audience context ID, the user ID followed by each
+// hint part separated by newlines, to dst
+func appendAudienceContextID(dst []byte, userID string, hint ...string) []byte {
+	dst = append(dst, userID...)
+	for _, part := range hint {
+		dst = append(dst, AudienceContextIDSeparator...)
+		dst = append(dst, part...)
+	}
+
+	return dst
 }
 
-// hashStringToUint32 converts a string to a uint32 using SHA-256 hashing
-func hashStringToUint32(s string) (uint32, error) {
-	hash := sha256.Sum256([]byte(s))
+// hashAudienceContextID converts the audience context ID to a uint32 using SHA-256 hashing,
+// without allocating for IDs that fit in the stack buffer
+func hashAudienceContextID(userID string, hint ...string) uint32 {
+	var buf [audienceContextIDBufferSize]byte
+	hash := sha256.Sum256(appendAudienceContextID(buf[:0], userID, hint...))
 	// Extract first 4 bytes and convert to uint32 (little-endian)
-	return binary.LittleEndian.Uint32(hash[:4]), nil
+	return binary.LittleEndian.Uint32(hash[:ContextMarkerSize])
+}
+
+// hashAudienceContextID64 is like hashAudienceContextID, with a 64-bit context marker whose high
+// 32 bits are the 32-bit context marker, so that it refines the 32-bit bucket
+func hashAudienceContextID64(userID string, hint ...string) uint64 {
+	var buf [audienceContextIDBufferSize]byte
+	hash := sha256.Sum256(appendAudienceContextID(buf[:0], userID, hint...))
+	high := binary.LittleEndian.Uint32(hash[:ContextMarkerSize])
+	low := binary.LittleEndian.Uint32(hash[ContextMarkerSize:ContextMarkerSize64])
+	return uint64(high)<<32 | uint64(low)
 }
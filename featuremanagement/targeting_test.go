@@ -4,6 +4,8 @@
 package featuremanagement
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/go-viper/mapstructure/v2"
@@ -169,3 +171,456 @@ func TestTargetingFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestHashAudienceContextID(t *testing.T) {
+	// The audience context ID is the user ID and hint parts joined by newlines
+	id := appendAudienceContextID(nil, "Aiden", "ComplexTargeting", "Stage2")
+	if string(id) != "Aiden\nComplexTargeting\nStage2" {
+		t.Errorf("Unexpected audience context ID %q", id)
+	}
+
+	if hashAudienceContextID("Aiden", "ComplexTargeting\nStage2") != hashAudienceContextID("Aiden", "ComplexTargeting", "Stage2") {
+		t.Error("Expected hint parts to hash the same as the joined hint")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := isTargetedPercentile(bucketer{}, "Aiden", 0, 50, "ComplexTargeting", "Stage2"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Expected percentile targeting not to allocate, got %v allocations", allocs)
+	}
+}
+
+func TestComputeBucket(t *testing.T) {
+	bucket := ComputeBucket("Aiden", "ComplexTargeting", "Stage2")
+	if bucket < 0 || bucket > 100 {
+		t.Fatalf("Expected bucket between 0 and 100, got %v", bucket)
+	}
+
+	// The bucket predicts the result of percentile targeting
+	for _, to := range []float64{bucket, bucket + 0.001} {
+		targeted, err := isTargetedPercentile(bucketer{}, "Aiden", 0, to, "ComplexTargeting", "Stage2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if targeted != (bucket < to) {
+			t.Errorf("Expected targeted=%v for range [0, %v) and bucket %v", bucket < to, to, bucket)
+		}
+	}
+}
+
+// session is an application type passed directly as the app context
+type session struct {
+	user   string
+	teams  []string
+	tenant string
+}
+
+func (s *session) GetUserID() string {
+	return s.user
+}
+
+func (s *session) GetGroups() []string {
+	return s.teams
+}
+
+func (s *session) GetAttributes() map[string]string {
+	return map[string]string{"tenantId": s.tenant}
+}
+
+func TestTargetingContexter(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {
+			Enabled: true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{
+				Name: "Microsoft.Targeting",
+				Parameters: map[string]any{"Audience": map[string]any{
+					"Users":  []any{"Alice"},
+					"Groups": []any{map[string]any{"Name": "Ring0", "RolloutPercentage": 100}},
+				}},
+			}}},
+		},
+		"Tenant": {
+			Enabled:  true,
+			Variants: []VariantDefinition{{Name: "On"}},
+			Allocation: &VariantAllocation{
+				TargetingAttribute: "tenantId",
+				User:               []UserAllocation{{Variant: "On", Users: []string{"contoso"}}},
+			},
+		},
+	})
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	for _, tc := range []struct {
+		appContext any
+		expected   bool
+	}{
+		{&session{user: "Alice"}, true},
+		{&session{user: "Bob", teams: []string{"Ring0"}}, true},
+		{&session{user: "Bob"}, false},
+		{&TargetingContext{UserID: "Alice"}, true},
+	} {
+		if enabled, err := manager.IsEnabledWithAppContext("Beta", tc.appContext); err != nil || enabled != tc.expected {
+			t.Errorf("Expected Beta enabled=%v for %+v, got %v, %v", tc.expected, tc.appContext, enabled, err)
+		}
+	}
+
+	result, err := manager.Evaluate("Tenant", &session{user: "Bob", tenant: "contoso"})
+	if err != nil || result.TargetingID != "Bob" || result.Variant == nil || result.Variant.Name != "On" {
+		t.Errorf("Expected the tenant's variant to be allocated, got %+v, %v", result, err)
+	}
+}
+
+func TestTargetingPercentileExclusion(t *testing.T) {
+	filter := &TargetingFilter{}
+	evalCtx := FeatureFilterEvaluationContext{
+		FeatureName: "Holdback",
+		Parameters: map[string]any{"Audience": map[string]any{
+			"Users":                    []any{"Alice"},
+			"DefaultRolloutPercentage": 100,
+			"Exclusion":                map[string]any{"Percentiles": []any{map[string]any{"From": 0, "To": 10}}},
+		}},
+	}
+
+	heldBack := 0
+	for i := 0; i < 1000; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		enabled, err := filter.Evaluate(evalCtx, TargetingContext{UserID: user})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		inRange, _ := isTargetedPercentile(bucketer{}, user, 0, 10, exclusionHint("Holdback")...)
+		if enabled == inRange {
+			t.Fatalf("Expected %s to be held back exactly when in the range, got enabled=%v", user, enabled)
+		}
+		if !enabled {
+			heldBack++
+		}
+	}
+	if heldBack < 70 || heldBack > 130 {
+		t.Errorf("Expected about 10%% of users to be held back, got %d of 1000", heldBack)
+	}
+
+	// The exclusion hint is documented with the regression vectors in testdata/bucketing/README.md
+	if marker := ContextMarker("Alice", exclusionHint("Holdback")...); marker != 660969468 {
+		t.Errorf("Expected the documented context marker of the exclusion hint, got %d", marker)
+	}
+	// A group named after the exclusion hint is bucketed independently of the exclusion
+	if ContextMarker("Alice", exclusionHint("Holdback")...) == ContextMarker("Alice", "Holdback", ExclusionHint) {
+		t.Error("Expected the exclusion hint to differ from the hint of a group named exclusion")
+	}
+
+	evalCtx.FeatureName = "Invalid"
+	evalCtx.Parameters = map[string]any{"Audience": map[string]any{
+		"Exclusion": map[string]any{"Percentiles": []any{map[string]any{"From": 50, "To": 20}}},
+	}}
+	if _, err := filter.Evaluate(evalCtx, TargetingContext{UserID: "Alice"}); err == nil {
+		t.Error("Expected an error for an inverted percentile range")
+	}
+}
+
+func TestTargetingFilterIgnoreCase(t *testing.T) {
+	evalCtx := FeatureFilterEvaluationContext{
+		FeatureName: "Beta",
+		Parameters: map[string]any{"Audience": map[string]any{
+			"Users":     []any{"alice@contoso.com"},
+			"Groups":    []any{map[string]any{"Name": "Ring0", "RolloutPercentage": 100}},
+			"Exclusion": map[string]any{"Users": []any{"dave@contoso.com"}},
+		}},
+	}
+
+	for _, tc := range []struct {
+		filter   *TargetingFilter
+		context  TargetingContext
+		expected bool
+	}{
+		{&TargetingFilter{}, TargetingContext{UserID: "Alice@Contoso.com"}, false},
+		{&TargetingFilter{IgnoreCase: true}, TargetingContext{UserID: "Alice@Contoso.com"}, true},
+		{&TargetingFilter{IgnoreCase: true}, TargetingContext{UserID: " alice@contoso.com"}, false},
+		{&TargetingFilter{IgnoreCase: true, TrimSpace: true}, TargetingContext{UserID: " alice@contoso.com "}, true},
+		{&TargetingFilter{IgnoreCase: true}, TargetingContext{UserID: "Bob", Groups: []string{"RING0"}}, true},
+		{&TargetingFilter{TrimSpace: true}, TargetingContext{UserID: "Bob", Groups: []string{"Ring0 "}}, true},
+		{&TargetingFilter{IgnoreCase: true}, TargetingContext{UserID: "DAVE@contoso.com", Groups: []string{"Ring0"}}, false},
+	} {
+		enabled, err := tc.filter.Evaluate(evalCtx, tc.context)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if enabled != tc.expected {
+			t.Errorf("Expected enabled=%v for %+v with IgnoreCase=%v, TrimSpace=%v, got %v",
+				tc.expected, tc.context, tc.filter.IgnoreCase, tc.filter.TrimSpace, enabled)
+		}
+	}
+}
+
+func TestTargetingFilterNormalization(t *testing.T) {
+	const (
+		precomposed = "Zo\u00eb"
+		decomposed  = "Zoe\u0308"
+	)
+	evalCtx := FeatureFilterEvaluationContext{
+		FeatureName: "Beta",
+		Parameters: map[string]any{"Audience": map[string]any{
+			"Users":  []any{precomposed, "strasse@contoso.com"},
+			"Groups": []any{map[string]any{"Name": "\u00c9quipe", "RolloutPercentage": 100}},
+		}},
+	}
+
+	for _, tc := range []struct {
+		filter   *TargetingFilter
+		context  TargetingContext
+		expected bool
+	}{
+		{&TargetingFilter{}, TargetingContext{UserID: decomposed}, false},
+		{&TargetingFilter{NormalizeUnicode: true}, TargetingContext{UserID: decomposed}, true},
+		{&TargetingFilter{NormalizeUnicode: true}, TargetingContext{UserID: "Bob", Groups: []string{"E\u0301quipe"}}, true},
+		{&TargetingFilter{NormalizeUnicode: true}, TargetingContext{UserID: "STRASSE@contoso.com"}, false},
+		{&TargetingFilter{FoldCase: true}, TargetingContext{UserID: "Stra\u00dfe@Contoso.com"}, true},
+		{&TargetingFilter{NormalizeUnicode: true, FoldCase: true}, TargetingContext{UserID: "ZOE\u0308"}, true},
+	} {
+		enabled, err := tc.filter.Evaluate(evalCtx, tc.context)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if enabled != tc.expected {
+			t.Errorf("Expected enabled=%v for %+q with NormalizeUnicode=%v, FoldCase=%v, got %v",
+				tc.expected, tc.context, tc.filter.NormalizeUnicode, tc.filter.FoldCase, enabled)
+		}
+	}
+
+	// Normalized IDs are bucketed like the normalized form, on both sides of its bucket
+	for _, tc := range []struct {
+		filter     *TargetingFilter
+		userID     string
+		normalized string
+	}{
+		{&TargetingFilter{NormalizeUnicode: true}, decomposed, precomposed},
+		{&TargetingFilter{FoldCase: true}, "ALICE", "alice"},
+	} {
+		bucket := ComputeBucket(tc.normalized, "Beta")
+		for _, rollout := range []float64{bucket, bucket + 1e-6} {
+			evalCtx := FeatureFilterEvaluationContext{
+				FeatureName: "Beta",
+				Parameters:  map[string]any{"Audience": map[string]any{"DefaultRolloutPercentage": rollout}},
+			}
+			enabled, err := tc.filter.Evaluate(evalCtx, TargetingContext{UserID: tc.userID})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if enabled != (rollout > bucket) {
+				t.Errorf("Expected %+q to be bucketed as %+q for rollout %v", tc.userID, tc.normalized, rollout)
+			}
+		}
+	}
+
+	if normalized := NormalizeTargetingID(decomposed, false); normalized != precomposed {
+		t.Errorf("Expected %+q, got %+q", precomposed, normalized)
+	}
+	if normalized := NormalizeTargetingID("ZOE\u0308", true); normalized != "zo\u00eb" {
+		t.Errorf("Expected %+q, got %+q", "zo\u00eb", normalized)
+	}
+}
+
+func TestTargetingFilterAllowAnonymous(t *testing.T) {
+	audience := func(rollout float64) FeatureFilterEvaluationContext {
+		return FeatureFilterEvaluationContext{
+			FeatureName: "Beta",
+			Parameters: map[string]any{"Audience": map[string]any{
+				"Users":                    []any{""},
+				"Groups":                   []any{map[string]any{"Name": "", "RolloutPercentage": 100}},
+				"DefaultRolloutPercentage": rollout,
+			}},
+		}
+	}
+
+	if _, err := (&TargetingFilter{}).Evaluate(audience(100), nil); err == nil {
+		t.Error("Expected an error without a targeting context")
+	}
+
+	filter := &TargetingFilter{AllowAnonymous: true}
+	for _, appContext := range []any{nil, "not a targeting context", (*TargetingContext)(nil)} {
+		for _, rollout := range []float64{0, 100} {
+			enabled, err := filter.Evaluate(audience(rollout), appContext)
+			if err != nil {
+				t.Fatalf("Unexpected error for app context %#v: %v", appContext, err)
+			}
+			if enabled != (rollout == 100) {
+				t.Errorf("Expected enabled=%v for an anonymous user and rollout %v, got %v", rollout == 100, rollout, enabled)
+			}
+		}
+	}
+
+	// Plain IsEnabled degrades to the default rollout percentage
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {Enabled: true, Conditions: &Conditions{ClientFilters: []ClientFilter{{
+			Name:       "Microsoft.Targeting",
+			Parameters: map[string]any{"Audience": map[string]any{"Users": []any{"Alice"}, "DefaultRolloutPercentage": 100}},
+		}}}},
+	}), &Options{Filters: []FeatureFilter{filter}})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	if enabled, err := manager.IsEnabled("Beta"); err != nil || !enabled {
+		t.Errorf("Expected Beta to be enabled for an anonymous user, got %v, %v", enabled, err)
+	}
+}
+
+func TestMatchWildcard(t *testing.T) {
+	for _, tc := range []struct {
+		value    string
+		pattern  string
+		expected bool
+	}{
+		{"alice@contoso.com", "*@contoso.com", true},
+		{"alice@contoso.com.evil", "*@contoso.com", false},
+		{"alice@fabrikam.com", "*@contoso.com", false},
+		{"alice@contoso.com", "alice@*", true},
+		{"alice@contoso.com", "*", true},
+		{"", "*", true},
+		{"alice@eu.contoso.com", "*@*.contoso.com", true},
+		{"alice@contoso.com", "*@*.contoso.com", false},
+		{"aba", "a*a*a", false},
+		{"abaa", "a*a*a", true},
+	} {
+		if actual := matchWildcard(tc.value, tc.pattern); actual != tc.expected {
+			t.Errorf("Expected matchWildcard(%q, %q) to be %v", tc.value, tc.pattern, tc.expected)
+		}
+	}
+}
+
+func TestTargetingFilterWildcardUsers(t *testing.T) {
+	evalCtx := FeatureFilterEvaluationContext{
+		FeatureName: "Beta",
+		Parameters: map[string]any{"Audience": map[string]any{
+			"Users":     []any{"*@contoso.com", "build*"},
+			"Exclusion": map[string]any{"Users": []any{"test-*@contoso.com"}},
+		}},
+	}
+
+	for _, tc := range []struct {
+		filter   *TargetingFilter
+		userID   string
+		expected bool
+	}{
+		{&TargetingFilter{}, "alice@contoso.com", false},
+		{&TargetingFilter{}, "build*", true},
+		{&TargetingFilter{UserPatterns: true}, "alice@contoso.com", true},
+		{&TargetingFilter{UserPatterns: true}, "alice@fabrikam.com", false},
+		{&TargetingFilter{UserPatterns: true}, "test-bot@contoso.com", false},
+		{&TargetingFilter{UserPatterns: true}, "Alice@Contoso.com", false},
+		{&TargetingFilter{UserPatterns: true, IgnoreCase: true}, "Alice@Contoso.com", true},
+		{&TargetingFilter{UserPatterns: true, IgnoreCase: true}, "TEST-bot@contoso.com", false},
+		// IgnoreCase folds patterns as strings.EqualFold does: the Kelvin sign folds to "k"
+		{&TargetingFilter{UserPatterns: true, IgnoreCase: true}, "BUILD\u212a", true},
+		{&TargetingFilter{IgnoreCase: true}, "BUILD*", true},
+		{&TargetingFilter{UserPatterns: true, TrimSpace: true}, " alice@contoso.com ", true},
+	} {
+		enabled, err := tc.filter.Evaluate(evalCtx, TargetingContext{UserID: tc.userID})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if enabled != tc.expected {
+			t.Errorf("Expected enabled=%v for %s with %+v, got %v", tc.expected, tc.userID, tc.filter, enabled)
+		}
+	}
+}
+
+func TestUserMatcherFoldsLikeEqualFold(t *testing.T) {
+	matcher := userMatcher{ignoreCase: true, patterns: true}
+	for _, tc := range []struct {
+		actual   string
+		expected string
+	}{
+		{"alice", "ALICE"},
+		{"\u212a", "k"},
+		{"straße", "STRASSE"},
+		{"ſ", "S"},
+		{"Σ", "ς"},
+	} {
+		exact := strings.EqualFold(tc.actual, tc.expected)
+		if actual := matcher.match(tc.actual, tc.expected); actual != exact {
+			t.Errorf("Expected match(%q, %q) to be %v", tc.actual, tc.expected, exact)
+		}
+		if actual := matcher.match(tc.actual, tc.expected+"*"); actual != exact {
+			t.Errorf("Expected match(%q, %q) to be %v", tc.actual, tc.expected+"*", exact)
+		}
+	}
+}
+
+func TestUserPatternsOption(t *testing.T) {
+	flag := FeatureFlag{
+		ID:      "Beta",
+		Enabled: true,
+		Conditions: &Conditions{ClientFilters: []ClientFilter{{
+			Name: "Microsoft.Targeting",
+			Parameters: map[string]any{"Audience": map[string]any{
+				"Users":     []any{"*@contoso.com"},
+				"Exclusion": map[string]any{"Users": []any{"test-*"}},
+			}},
+		}}},
+		Variants: []VariantDefinition{{Name: "Internal"}, {Name: "Bots"}},
+		Allocation: &VariantAllocation{
+			User: []UserAllocation{
+				{Variant: "Bots", Users: []string{"*-bot@*"}},
+				{Variant: "Internal", Users: []string{"*@contoso.com"}},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		options *Options
+	}{
+		{"manager", &Options{UserPatterns: true}},
+		{"registered filter", &Options{UserPatterns: true, Filters: []FeatureFilter{&TargetingFilter{IgnoreCase: true}}}},
+		{"allocation strategy", &Options{UserPatterns: true, AllocationStrategy: DefaultAllocationStrategy{}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{flag.ID: flag}), tc.options)
+			if err != nil {
+				t.Fatalf("Failed to create feature manager: %v", err)
+			}
+
+			for _, c := range []struct {
+				userID  string
+				enabled bool
+				variant string
+			}{
+				{"alice@contoso.com", true, "Internal"},
+				{"build-bot@contoso.com", true, "Bots"},
+				{"test-bot@contoso.com", false, ""},
+				{"alice@fabrikam.com", false, ""},
+			} {
+				res, err := manager.Evaluate("Beta", TargetingContext{UserID: c.userID})
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				variant := ""
+				if res.Variant != nil {
+					variant = res.Variant.Name
+				}
+				if res.Enabled != c.enabled || variant != c.variant {
+					t.Errorf("Expected enabled=%v and variant %q for %s, got %v and %q", c.enabled, c.variant, c.userID, res.Enabled, variant)
+				}
+			}
+		})
+	}
+
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{flag.ID: flag}), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	res, err := manager.Evaluate("Beta", TargetingContext{UserID: "alice@contoso.com"})
+	if err != nil || res.Enabled || res.Variant != nil {
+		t.Errorf("Expected patterns to match literally without UserPatterns, got %+v, %v", res, err)
+	}
+	res, err = manager.Evaluate("Beta", TargetingContext{UserID: "*@contoso.com"})
+	if err != nil || !res.Enabled || res.Variant == nil || res.Variant.Name != "Internal" {
+		t.Errorf("Expected a user ID containing '*' to match itself, got %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// EventFeatureEvaluation is published for every evaluation of a feature with telemetry enabled.
+	EventFeatureEvaluation = "FeatureEvaluation"
+	// EventVariantAssigned is published when a variant of a feature with telemetry enabled is
+	// allocated to a user, by user, group or percentile allocation. It marks an exposure to the
+	// variant for experiment analysis, separately from the evaluations that led to it.
+	EventVariantAssigned = "VariantAssigned"
+)
+
+// TelemetryEvent describes a feature evaluation or variant exposure.
+type TelemetryEvent struct {
+	// Name is EventFeatureEvaluation or EventVariantAssigned
+	Name string
+	// FeatureName is the name of the evaluated feature
+	FeatureName string
+	// Result is the result of the evaluation
+	Result EvaluationResult
+	// Metadata is the telemetry metadata of the feature flag
+	Metadata map[string]string
+	// Timestamp is when the evaluation happened
+	Timestamp time.Time
+}
+
+// TelemetryPublisher receives telemetry events for features with telemetry enabled.
+// Publish is called synchronously on the evaluating goroutine and must be safe for concurrent use.
+type TelemetryPublisher interface {
+	// Publish handles a telemetry event.
+	//
+	// Parameters:
+	//   - event: The event to publish
+	Publish(event TelemetryEvent)
+}
+
+// exposureKey identifies a variant exposure for deduplication
+type exposureKey struct {
+	featureName  string
+	targetingID  string
+	variant      string
+	allocationID string
+}
+
+// exposurePruneInterval is the number of recorded exposures between removals of expired entries
+const exposurePruneInterval = 1024
+
+// telemetryPublisher publishes the events of an evaluation, deduplicating exposures when configured
+type telemetryPublisher struct {
+	publisher   TelemetryPublisher
+	dedupWindow time.Duration
+	exposures   sync.Map // exposureKey -> int64 UnixNano of the last published exposure
+	recorded    atomic.Int64
+}
+
+func (p *telemetryPublisher) publish(featureName string, featureFlag *FeatureFlag, result EvaluationResult) {
+	if featureFlag == nil || featureFlag.Telemetry == nil || !featureFlag.Telemetry.Enabled {
+		return
+	}
+
+	// Publishers may keep events, so they get their own copy of the definition
+	result.Feature = cloneFeature(featureFlag)
+	event := TelemetryEvent{
+		Name:        EventFeatureEvaluation,
+		FeatureName: featureName,
+		Result:      result,
+		Metadata:    result.Feature.Telemetry.Metadata,
+		Timestamp:   time.Now(),
+	}
+	p.publisher.Publish(event)
+
+	if !isAllocated(result) || p.isDuplicateExposure(featureName, result, event.Timestamp) {
+		return
+	}
+	event.Name = EventVariantAssigned
+	p.publisher.Publish(event)
+}
+
+// isAllocated reports whether the result's variant was allocated to its user
+func isAllocated(result EvaluationResult) bool {
+	if result.Variant == nil || result.TargetingID == "" {
+		return false
+	}
+
+	switch result.VariantAssignmentReason {
+	case VariantAssignmentReasonUser, VariantAssignmentReasonGroup, VariantAssignmentReasonPercentile:
+		return true
+	default:
+		return false
+	}
+}
+
+// isDuplicateExposure reports whether the same exposure was published within the deduplication
+// window, recording it otherwise
+func (p *telemetryPublisher) isDuplicateExposure(featureName string, result EvaluationResult, now time.Time) bool {
+	if p.dedupWindow <= 0 {
+		return false
+	}
+
+	key := exposureKey{
+		featureName:  featureName,
+		targetingID:  result.TargetingID,
+		variant:      result.Variant.Name,
+		allocationID: result.AllocationID,
+	}
+	nowNano := now.UnixNano()
+	if last, ok := p.exposures.Load(key); ok && nowNano-last.(int64) < int64(p.dedupWindow) {
+		return true
+	}
+	p.exposures.Store(key, nowNano)
+
+	// Periodically forget expired exposures so the set doesn't grow with every user ever seen
+	if p.recorded.Add(1)%exposurePruneInterval == 0 {
+		p.exposures.Range(func(key, last any) bool {
+			if nowNano-last.(int64) >= int64(p.dedupWindow) {
+				p.exposures.Delete(key)
+			}
+			return true
+		})
+	}
+
+	return false
+}
+
+// variantAssignmentPercentage returns the percentage of users receiving the assigned variant for
+// the same reason: the width of the variant's percentile ranges, or the share left to the default
+// variant by all percentile ranges. It is 0 for other assignment reasons.
+func variantAssignmentPercentage(allocation *VariantAllocation, variant *Variant, reason VariantAssignmentReason) float64 {
+	if allocation == nil {
+		return 0
+	}
+
+	switch reason {
+	case VariantAssignmentReasonDefaultWhenEnabled:
+		percentage := 100.0
+		for _, percentile := range allocation.Percentile {
+			percentage -= percentile.To - percentile.From
+		}
+		return percentage
+	case VariantAssignmentReasonPercentile:
+		percentage := 0.0
+		for _, percentile := range allocation.Percentile {
+			if variant != nil && percentile.Variant == variant.Name {
+				percentage += percentile.To - percentile.From
+			}
+		}
+		return percentage
+	default:
+		return 0
+	}
+}
+
+// allocationID identifies the allocation of a feature flag for experiment analysis: the same
+// seed, default variant, percentile ranges and allocated variant values always produce the same
+// ID, and changing any of them produces a new one. It is empty for flags with nothing allocated.
+//
+// The ID is the base64url encoding of the first 15 bytes of the SHA-256 hash of:
+//
+//	seed=<seed>
+//	default_when_enabled=<variant>
+//	percentiles=<from>,<base64 variant>,<to>;...
+//	variants=<base64 name>,<JSON configuration value>;...
+func allocationID(featureFlag *FeatureFlag) string {
+	allocation := featureFlag.Allocation
+	if allocation == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	var allocated []string
+
+	b.WriteString("seed=")
+	b.WriteString(allocation.Seed)
+
+	b.WriteString("\ndefault_when_enabled=")
+	b.WriteString(allocation.DefaultWhenEnabled)
+	if allocation.DefaultWhenEnabled != "" {
+		allocated = append(allocated, allocation.DefaultWhenEnabled)
+	}
+
+	// Empty ranges don't allocate anything, so they don't change the allocation
+	percentiles := make([]PercentileAllocation, 0, len(allocation.Percentile))
+	for _, percentile := range allocation.Percentile {
+		if percentile.From != percentile.To {
+			percentiles = append(percentiles, percentile)
+		}
+	}
+	sort.SliceStable(percentiles, func(i, j int) bool {
+		return percentiles[i].From < percentiles[j].From
+	})
+
+	b.WriteString("\npercentiles=")
+	for i, percentile := range percentiles {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(strconv.FormatFloat(percentile.From, 'f', -1, 64))
+		b.WriteByte(',')
+		b.WriteString(base64.StdEncoding.EncodeToString([]byte(percentile.Variant)))
+		b.WriteByte(',')
+		b.WriteString(strconv.FormatFloat(percentile.To, 'f', -1, 64))
+		allocated = append(allocated, percentile.Variant)
+	}
+
+	if allocation.Seed == "" && len(allocated) == 0 {
+		return ""
+	}
+
+	variants := make([]VariantDefinition, 0, len(featureFlag.Variants))
+	for _, variant := range featureFlag.Variants {
+		if slices.Contains(allocated, variant.Name) {
+			variants = append(variants, variant)
+		}
+	}
+	sort.SliceStable(variants, func(i, j int) bool {
+		return variants[i].Name < variants[j].Name
+	})
+
+	b.WriteString("\nvariants=")
+	for i, variant := range variants {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(base64.StdEncoding.EncodeToString([]byte(variant.Name)))
+		b.WriteByte(',')
+		// Maps are marshaled with sorted keys, so equal values always produce the same text
+		value, err := json.Marshal(variant.ConfigurationValue)
+		if err != nil {
+			value = []byte("null")
+		}
+		b.Write(value)
+	}
+
+	hash := sha256.Sum256([]byte(b.String()))
+	return base64.RawURLEncoding.EncodeToString(hash[:15])
+}
@@ -0,0 +1,211 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultTelemetryQueueSize     = 1024
+	defaultTelemetryBatchSize     = 100
+	defaultTelemetryFlushInterval = 5 * time.Second
+)
+
+// TelemetryBatchPublisher sends batches of telemetry events, for example to an analytics backend.
+// PublishBatch is called from a single background goroutine.
+type TelemetryBatchPublisher interface {
+	// PublishBatch sends a batch of telemetry events.
+	//
+	// Parameters:
+	//   - events: The events to send, in the order they were published; the slice is not reused
+	PublishBatch(events []TelemetryEvent)
+}
+
+// TelemetryBatchPublisherFunc adapts a function to a TelemetryBatchPublisher.
+type TelemetryBatchPublisherFunc func(events []TelemetryEvent)
+
+// PublishBatch calls f(events).
+func (f TelemetryBatchPublisherFunc) PublishBatch(events []TelemetryEvent) {
+	f(events)
+}
+
+// TelemetryDropPolicy selects which events a BufferedTelemetryPublisher drops when its queue is full.
+type TelemetryDropPolicy int
+
+const (
+	// DropNewest drops the event being published, keeping the queued events
+	DropNewest TelemetryDropPolicy = iota
+	// DropOldest drops the oldest queued event to make room for the event being published
+	DropOldest
+)
+
+// BufferedTelemetryPublisherOptions configures a BufferedTelemetryPublisher.
+type BufferedTelemetryPublisherOptions struct {
+	// QueueSize bounds the number of events waiting to be sent. Defaults to 1024.
+	QueueSize int
+	// BatchSize is the number of events sent at once. Defaults to 100.
+	BatchSize int
+	// FlushInterval is the longest time an event waits for its batch to fill before the partial
+	// batch is sent. Defaults to 5 seconds.
+	FlushInterval time.Duration
+	// DropPolicy selects the events dropped when the queue is full. Defaults to DropNewest.
+	DropPolicy TelemetryDropPolicy
+}
+
+// BufferedTelemetryPublisher is a TelemetryPublisher that queues events in a bounded queue and
+// sends them in batches from a background goroutine, so that publishing never blocks the
+// evaluating goroutine and memory use stays bounded under load. Events that don't fit in the
+// queue are dropped according to the drop policy and counted.
+//
+// Call Close to send the queued events and stop the background goroutine.
+type BufferedTelemetryPublisher struct {
+	publisher     TelemetryBatchPublisher
+	queue         chan TelemetryEvent
+	batchSize     int
+	flushInterval time.Duration
+	dropPolicy    TelemetryDropPolicy
+
+	dropped   atomic.Uint64
+	closed    atomic.Bool
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewBufferedTelemetryPublisher creates a BufferedTelemetryPublisher sending batches to publisher.
+//
+// Parameters:
+//   - publisher: The publisher receiving the batches of events
+//   - options: Configuration options for the queue and batches, or nil for the defaults
+//
+// Returns:
+//   - *BufferedTelemetryPublisher: The publisher, ready to be passed as Options.TelemetryPublisher
+func NewBufferedTelemetryPublisher(publisher TelemetryBatchPublisher, options *BufferedTelemetryPublisherOptions) *BufferedTelemetryPublisher {
+	if options == nil {
+		options = &BufferedTelemetryPublisherOptions{}
+	}
+
+	queueSize := options.QueueSize
+	if queueSize <= 0 {
+		queueSize = defaultTelemetryQueueSize
+	}
+	batchSize := options.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultTelemetryBatchSize
+	}
+	flushInterval := options.FlushInterval
+	if flushInterval <= 0 {
+		flushInterval = defaultTelemetryFlushInterval
+	}
+
+	p := &BufferedTelemetryPublisher{
+		publisher:     publisher,
+		queue:         make(chan TelemetryEvent, queueSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		dropPolicy:    options.DropPolicy,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go p.run()
+
+	return p
+}
+
+// Publish queues an event without blocking. When the queue is full, or after Close, an event is
+// dropped instead.
+//
+// Parameters:
+//   - event: The event to publish
+func (p *BufferedTelemetryPublisher) Publish(event TelemetryEvent) {
+	if p.closed.Load() {
+		p.dropped.Add(1)
+		return
+	}
+
+	for {
+		select {
+		case p.queue <- event:
+			return
+		default:
+		}
+
+		if p.dropPolicy != DropOldest {
+			p.dropped.Add(1)
+			return
+		}
+
+		// Make room by dropping the oldest event, unless the background goroutine just did
+		select {
+		case <-p.queue:
+			p.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full or the publisher closed.
+func (p *BufferedTelemetryPublisher) Dropped() uint64 {
+	return p.dropped.Load()
+}
+
+// Close sends the queued events and stops the background goroutine. Events published after
+// Close, or concurrently with it, may be dropped.
+//
+// Returns:
+//   - error: Always nil
+func (p *BufferedTelemetryPublisher) Close() error {
+	p.closeOnce.Do(func() {
+		p.closed.Store(true)
+		close(p.stop)
+	})
+	<-p.done
+
+	return nil
+}
+
+func (p *BufferedTelemetryPublisher) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]TelemetryEvent, 0, p.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		p.publisher.PublishBatch(batch)
+		batch = make([]TelemetryEvent, 0, p.batchSize)
+	}
+
+	for {
+		select {
+		case event := <-p.queue:
+			batch = append(batch, event)
+			if len(batch) >= p.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-p.stop:
+			// Drain the events queued before Close
+			for {
+				select {
+				case event := <-p.queue:
+					batch = append(batch, event)
+					if len(batch) >= p.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
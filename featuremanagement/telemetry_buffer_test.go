@@ -0,0 +1,132 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]TelemetryEvent
+	block   chan struct{}
+}
+
+func (r *batchRecorder) PublishBatch(events []TelemetryEvent) {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, events)
+}
+
+func (r *batchRecorder) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var names []string
+	for _, batch := range r.batches {
+		for _, event := range batch {
+			names = append(names, event.FeatureName)
+		}
+	}
+	return names
+}
+
+func TestBufferedTelemetryPublisherBatches(t *testing.T) {
+	recorder := &batchRecorder{}
+	publisher := NewBufferedTelemetryPublisher(recorder, &BufferedTelemetryPublisherOptions{BatchSize: 2, FlushInterval: time.Hour})
+
+	for i := 0; i < 5; i++ {
+		publisher.Publish(TelemetryEvent{FeatureName: fmt.Sprint(i)})
+	}
+	if err := publisher.Close(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if names := fmt.Sprint(recorder.names()); names != "[0 1 2 3 4]" {
+		t.Errorf("Expected every event to be sent in order, got %s", names)
+	}
+	if len(recorder.batches) != 3 || len(recorder.batches[0]) != 2 {
+		t.Errorf("Expected batches of at most 2 events with the rest flushed on close, got %d batches", len(recorder.batches))
+	}
+
+	publisher.Publish(TelemetryEvent{FeatureName: "late"})
+	if publisher.Dropped() != 1 {
+		t.Errorf("Expected events published after close to be dropped, got %d dropped", publisher.Dropped())
+	}
+}
+
+func TestBufferedTelemetryPublisherFlushInterval(t *testing.T) {
+	flushed := make(chan []TelemetryEvent, 1)
+	publisher := NewBufferedTelemetryPublisher(TelemetryBatchPublisherFunc(func(events []TelemetryEvent) {
+		flushed <- events
+	}), &BufferedTelemetryPublisherOptions{FlushInterval: 10 * time.Millisecond})
+	defer publisher.Close()
+
+	publisher.Publish(TelemetryEvent{FeatureName: "Beta"})
+	select {
+	case events := <-flushed:
+		if len(events) != 1 || events[0].FeatureName != "Beta" {
+			t.Errorf("Unexpected batch: %+v", events)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a partial batch to be flushed on the interval")
+	}
+}
+
+func TestBufferedTelemetryPublisherDropPolicies(t *testing.T) {
+	for _, tc := range []struct {
+		policy   TelemetryDropPolicy
+		expected string
+	}{
+		{DropNewest, "[first 0 1]"},
+		{DropOldest, "[first 2 3]"},
+	} {
+		recorder := &batchRecorder{block: make(chan struct{})}
+		publisher := NewBufferedTelemetryPublisher(recorder, &BufferedTelemetryPublisherOptions{QueueSize: 2, BatchSize: 1, DropPolicy: tc.policy})
+
+		// The first event is taken by the background goroutine, which blocks sending it
+		publisher.Publish(TelemetryEvent{FeatureName: "first"})
+		deadline := time.Now().Add(5 * time.Second)
+		for len(publisher.queue) != 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+
+		for i := 0; i < 4; i++ {
+			publisher.Publish(TelemetryEvent{FeatureName: fmt.Sprint(i)})
+		}
+		if publisher.Dropped() != 2 {
+			t.Errorf("Expected 2 dropped events with policy %d, got %d", tc.policy, publisher.Dropped())
+		}
+
+		close(recorder.block)
+		_ = publisher.Close()
+		if names := fmt.Sprint(recorder.names()); names != tc.expected {
+			t.Errorf("Expected %s to be sent with policy %d, got %s", tc.expected, tc.policy, names)
+		}
+	}
+}
+
+func TestBufferedTelemetryPublisherWithFeatureManager(t *testing.T) {
+	flag := telemetryTestFlag()
+	recorder := &batchRecorder{}
+	publisher := NewBufferedTelemetryPublisher(recorder, nil)
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{flag.ID: flag}), &Options{TelemetryPublisher: publisher})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if _, err := manager.IsEnabled(flag.ID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_ = publisher.Close()
+
+	if names := fmt.Sprint(recorder.names()); names != "[Experiment]" {
+		t.Errorf("Expected the evaluation event to be sent on close, got %s", names)
+	}
+}
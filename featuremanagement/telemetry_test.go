@@ -0,0 +1,186 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func telemetryTestFlag() FeatureFlag {
+	return FeatureFlag{
+		ID:      "Experiment",
+		Enabled: true,
+		Variants: []VariantDefinition{
+			{Name: "Control", ConfigurationValue: map[string]any{"b": 1, "a": "x"}},
+			{Name: "Treatment", ConfigurationValue: true},
+			{Name: "Unused"},
+		},
+		Allocation: &VariantAllocation{
+			DefaultWhenEnabled: "Control",
+			Percentile: []PercentileAllocation{
+				{Variant: "Treatment", From: 40, To: 70},
+				{Variant: "Control", From: 0, To: 40},
+				{Variant: "Unused", From: 70, To: 70},
+			},
+			Seed: "experiment-1",
+		},
+		Telemetry: &Telemetry{Enabled: true},
+	}
+}
+
+func TestEvaluationResultTelemetryFields(t *testing.T) {
+	flag := telemetryTestFlag()
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{flag.ID: flag}), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	for _, userID := range []string{"Alice", "Bob", "Charlie", "Dana", "Eve"} {
+		res, err := manager.evaluate("Experiment", TargetingContext{UserID: userID})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if res.DefaultWhenEnabled != "Control" {
+			t.Errorf("Expected DefaultWhenEnabled Control, got %q", res.DefaultWhenEnabled)
+		}
+		if res.AllocationID != allocationID(&flag) || res.AllocationID == "" {
+			t.Errorf("Expected allocation ID %q, got %q", allocationID(&flag), res.AllocationID)
+		}
+
+		expected := map[string]float64{"Control": 40, "Treatment": 30}[res.Variant.Name]
+		if res.VariantAssignmentReason != VariantAssignmentReasonPercentile || res.VariantAssignmentPercentage != expected {
+			t.Errorf("Expected %s to be assigned by percentile at %v%%, got %s at %v%%",
+				res.Variant.Name, expected, res.VariantAssignmentReason, res.VariantAssignmentPercentage)
+		}
+	}
+
+	// Without a targeting context, the default variant covers what the percentiles leave
+	res, err := manager.evaluate("Experiment", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if res.VariantAssignmentReason != VariantAssignmentReasonDefaultWhenEnabled || res.VariantAssignmentPercentage != 30 {
+		t.Errorf("Expected default assignment at 30%%, got %s at %v%%", res.VariantAssignmentReason, res.VariantAssignmentPercentage)
+	}
+}
+
+func TestAllocationID(t *testing.T) {
+	flag := telemetryTestFlag()
+	id := allocationID(&flag)
+	if len(id) != 20 {
+		t.Fatalf("Expected a 20 character allocation ID, got %q", id)
+	}
+
+	// Order of percentiles, empty ranges and unallocated variants don't affect the ID
+	reordered := telemetryTestFlag()
+	reordered.Allocation.Percentile = reordered.Allocation.Percentile[:2]
+	reordered.Variants[2].ConfigurationValue = "changed"
+	if allocationID(&reordered) != id {
+		t.Error("Expected an equivalent allocation to have the same ID")
+	}
+
+	changes := map[string]func(*FeatureFlag){
+		"seed":       func(f *FeatureFlag) { f.Allocation.Seed = "experiment-2" },
+		"default":    func(f *FeatureFlag) { f.Allocation.DefaultWhenEnabled = "Treatment" },
+		"percentile": func(f *FeatureFlag) { f.Allocation.Percentile[0].To = 80 },
+		"value":      func(f *FeatureFlag) { f.Variants[1].ConfigurationValue = false },
+	}
+	for name, change := range changes {
+		changed := telemetryTestFlag()
+		change(&changed)
+		if allocationID(&changed) == id {
+			t.Errorf("Expected changing the %s to change the allocation ID", name)
+		}
+	}
+
+	if id := allocationID(&FeatureFlag{ID: "NoAllocation"}); id != "" {
+		t.Errorf("Expected no allocation ID without an allocation, got %q", id)
+	}
+	if id := allocationID(&FeatureFlag{ID: "Empty", Allocation: &VariantAllocation{DefaultWhenDisabled: "Off"}}); id != "" {
+		t.Errorf("Expected no allocation ID when nothing is allocated, got %q", id)
+	}
+}
+
+// recordingPublisher collects published telemetry events
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []TelemetryEvent
+}
+
+func (p *recordingPublisher) Publish(event TelemetryEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+func (p *recordingPublisher) count(name string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	count := 0
+	for _, event := range p.events {
+		if event.Name == name {
+			count++
+		}
+	}
+	return count
+}
+
+func TestVariantExposureEvents(t *testing.T) {
+	experiment := telemetryTestFlag()
+	experiment.Telemetry.Metadata = map[string]string{"Owner": "growth"}
+	untracked := telemetryTestFlag()
+	untracked.ID = "Untracked"
+	untracked.Telemetry = nil
+	provider := NewStaticProvider(map[string]FeatureFlag{"Experiment": experiment, "Untracked": untracked})
+
+	tests := []struct {
+		name              string
+		dedup             time.Duration
+		expectedExposures int
+	}{
+		{name: "Every exposure", dedup: 0, expectedExposures: 3},
+		{name: "Deduplicated", dedup: time.Hour, expectedExposures: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			publisher := &recordingPublisher{}
+			manager, err := NewFeatureManager(provider, &Options{
+				TelemetryPublisher:    publisher,
+				ExposureDeduplication: tc.dedup,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create feature manager: %v", err)
+			}
+
+			for i := 0; i < 3; i++ {
+				if _, err := manager.GetVariant("Experiment", TargetingContext{UserID: "Alice"}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// No allocation happens without a user, and features without telemetry publish nothing
+			if _, err := manager.GetVariant("Experiment", nil); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := manager.GetVariant("Untracked", TargetingContext{UserID: "Alice"}); err != nil {
+				t.Fatal(err)
+			}
+
+			if count := publisher.count(EventFeatureEvaluation); count != 4 {
+				t.Errorf("Expected 4 evaluation events, got %d", count)
+			}
+			if count := publisher.count(EventVariantAssigned); count != tc.expectedExposures {
+				t.Errorf("Expected %d exposure events, got %d", tc.expectedExposures, count)
+			}
+
+			event := publisher.events[len(publisher.events)-1]
+			if event.FeatureName != "Experiment" || event.Metadata["Owner"] != "growth" || event.Timestamp.IsZero() {
+				t.Errorf("Unexpected event %+v", event)
+			}
+		})
+	}
+}
@@ -4,91 +4,148 @@
 package featuremanagement
 
 import (
-	"encoding/json"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 )
 
-type TimeWindowFilter struct{}
+type TimeWindowFilter struct {
+	// StrictTimeFormat only accepts Start and End times in the formats shared by the feature
+	// management libraries of all platforms: ISO 8601, such as "2024-01-01T00:00:00Z", and RFC 1123
+	// in GMT, such as "Mon, 01 Jan 2024 00:00:00 GMT". Flags with times in other formats are
+	// rejected when they are loaded, rather than silently never matching on other platforms.
+	// Register the filter with Options.Filters to replace the default one:
+	//
+	//	manager, _ := featuremanagement.NewFeatureManager(provider, &featuremanagement.Options{
+	//		Filters: []featuremanagement.FeatureFilter{&featuremanagement.TimeWindowFilter{StrictTimeFormat: true}},
+	//	})
+	StrictTimeFormat bool
+
+	paramCache parameterCache[timeWindow]
+	logger     *logger
+}
 
 type TimeWindowFilterParameters struct {
 	Start string `json:"start,omitempty"`
 	End   string `json:"end,omitempty"`
 }
 
+// timeWindow holds the parsed bounds of a time window. A nil bound is open-ended.
+type timeWindow struct {
+	start *time.Time
+	end   *time.Time
+}
+
 func (t *TimeWindowFilter) Name() string {
 	return "Microsoft.TimeWindow"
 }
 
 func (t *TimeWindowFilter) Evaluate(evalCtx FeatureFilterEvaluationContext, appContext any) (bool, error) {
+	// Parameters are parsed once per flag definition and reused until the flag is refreshed
+	window, err := t.getTimeWindow(evalCtx.FeatureName, evalCtx.Parameters)
+	if err != nil {
+		return false, err
+	}
+
+	// An invalid window without either bound never matches
+	if window.start == nil && window.end == nil {
+		return false, nil
+	}
+
+	// Get current time
+	now := time.Now()
+
+	// Check if current time is within the window
+	// (after or equal to start time AND before end time)
+	isAfterStart := window.start == nil || !now.Before(*window.start)
+	isBeforeEnd := window.end == nil || now.Before(*window.end)
+
+	return isAfterStart && isBeforeEnd, nil
+}
+
+func (t *TimeWindowFilter) preloadParameters(featureName string, parameters map[string]any) error {
+	_, err := t.getTimeWindow(featureName, parameters)
+	return err
+}
+
+func (t *TimeWindowFilter) getTimeWindow(featureName string, parameters map[string]any) (timeWindow, error) {
+	return t.paramCache.get(featureName, parameters, t.parseTimeWindow)
+}
+
+// parseTimeWindow parses the time window of a feature, warning when it has neither bound
+func (t *TimeWindowFilter) parseTimeWindow(featureName string, parameters map[string]any) (timeWindow, error) {
+	window, err := parseTimeWindow(featureName, parameters, t.StrictTimeFormat)
+	if err == nil && window.start == nil && window.end == nil {
+		t.logger.warnf("The Microsoft.TimeWindow feature filter is not valid for feature %s. It must specify either 'Start', 'End', or both.", featureName)
+	}
+
+	return window, err
+}
+
+func parseTimeWindow(featureName string, parameters map[string]any, strict bool) (timeWindow, error) {
 	// Extract and parse parameters
-	paramsBytes, err := json.Marshal(evalCtx.Parameters)
+	params, err := decodeTimeWindowParameters(parameters)
 	if err != nil {
-		return false, fmt.Errorf("failed to marshal time window parameters: %w", err)
+		return timeWindow{}, err
 	}
 
-	var params TimeWindowFilterParameters
-	if err := json.Unmarshal(paramsBytes, &params); err != nil {
-		return false, fmt.Errorf("invalid time window parameters format: %w", err)
+	parse := parseTime
+	if strict {
+		parse = parseStrictTime
 	}
 
-	var startTime, endTime *time.Time
+	var window timeWindow
 
 	// Parse start time if provided
 	if params.Start != "" {
-		parsed, err := parseTime(params.Start)
+		parsed, err := parse(params.Start)
 		if err != nil {
-			return false, fmt.Errorf("invalid start time format for feature %s: %w", evalCtx.FeatureName, err)
+			return timeWindow{}, fmt.Errorf("invalid start time format for feature %s: %w", featureName, err)
 		}
-		startTime = &parsed
+		window.start = &parsed
 	}
 
 	// Parse end time if provided
 	if params.End != "" {
-		parsed, err := parseTime(params.End)
+		parsed, err := parse(params.End)
 		if err != nil {
-			return false, fmt.Errorf("invalid end time format for feature %s: %w", evalCtx.FeatureName, err)
+			return timeWindow{}, fmt.Errorf("invalid end time format for feature %s: %w", featureName, err)
 		}
-		endTime = &parsed
+		window.end = &parsed
 	}
 
-	// Check if at least one time parameter exists
-	if startTime == nil && endTime == nil {
-		log.Printf("The Microsoft.TimeWindow feature filter is not valid for feature %s. It must specify either 'Start', 'End', or both.", evalCtx.FeatureName)
-		return false, nil
-	}
+	return window, nil
+}
 
-	// Get current time
-	now := time.Now()
+func decodeTimeWindowParameters(parameters map[string]any) (TimeWindowFilterParameters, error) {
+	var params TimeWindowFilterParameters
+	if err := BindParameters(parameters, &params); err != nil {
+		return TimeWindowFilterParameters{}, fmt.Errorf("invalid time window parameters format: %w", err)
+	}
 
-	// Check if current time is within the window
-	// (after or equal to start time AND before end time)
-	isAfterStart := startTime == nil || !now.Before(*startTime)
-	isBeforeEnd := endTime == nil || now.Before(*endTime)
+	return params, nil
+}
 
-	return isAfterStart && isBeforeEnd, nil
+// timeFormats lists the formats accepted for time window bounds, in the order they are tried
+var timeFormats = []string{
+	time.RFC1123,
+	time.RFC3339,
+	time.RFC3339Nano,
+	time.RFC1123Z,
+	time.RFC822,
+	time.RFC822Z,
+	time.RFC850,
+	time.UnixDate,
+	time.RubyDate,
+	time.ANSIC,
+	time.Layout,
 }
 
-func parseTime(timeStr string) (time.Time, error) {
-	// List of formats to try
-	formats := []string{
-		time.RFC1123,
-		time.RFC3339,
-		time.RFC3339Nano,
-		time.RFC1123Z,
-		time.RFC822,
-		time.RFC822Z,
-		time.RFC850,
-		time.UnixDate,
-		time.RubyDate,
-		time.ANSIC,
-		time.Layout,
-	}
+var timeFormatsDescription = strings.Join(timeFormats, "\n")
 
+func parseTime(timeStr string) (time.Time, error) {
 	// Try each format in sequence
-	for _, format := range formats {
+	for _, format := range timeFormats {
 		t, err := time.Parse(format, timeStr)
 		if err == nil {
 			return t, nil // Return the first successful parse
@@ -97,5 +154,26 @@ func parseTime(timeStr string) (time.Time, error) {
 
 	// All formats failed
 	return time.Time{}, fmt.Errorf("unable to parse time %q with any known format:\n%s",
-		timeStr, strings.Join(formats, "\n"))
+		timeStr, timeFormatsDescription)
+}
+
+// strictTimeZone is the only time zone accepted in RFC 1123 times by parseStrictTime, since
+// other abbreviations are ambiguous and not understood on every platform
+const strictTimeZone = "GMT"
+
+// parseStrictTime parses a time in ISO 8601 or in RFC 1123 with the GMT time zone
+func parseStrictTime(timeStr string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, timeStr); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(time.RFC1123, timeStr); err == nil {
+		if zone, _ := t.Zone(); zone == strictTimeZone {
+			return t, nil
+		}
+		return time.Time{}, fmt.Errorf("time %q must be in the %s time zone when in RFC 1123 format", timeStr, strictTimeZone)
+	}
+
+	return time.Time{}, fmt.Errorf("time %q must be in ISO 8601 format, such as %q, or RFC 1123 format, such as %q",
+		timeStr, "2024-01-01T00:00:00Z", "Mon, 01 Jan 2024 00:00:00 GMT")
 }
@@ -119,3 +119,68 @@ func TestTimeWindowFilterIntegration(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeWindowFilterParsesOnce(t *testing.T) {
+	filter := &TimeWindowFilter{}
+	params := map[string]any{
+		"Start": "Thu, 29 Jun 2023 07:00:00 GMT",
+		"End":   "not a time",
+	}
+
+	if err := filter.preloadParameters("Invalid", params); err == nil {
+		t.Fatal("Expected preload to report the invalid end time")
+	}
+
+	// The parse result, including the error, is reused by evaluations of the same definition
+	cached, _ := filter.paramCache.entries.Load("Invalid")
+	_, err := filter.Evaluate(FeatureFilterEvaluationContext{FeatureName: "Invalid", Parameters: params}, nil)
+	if err == nil || err != cached.(*parameterCacheEntry[timeWindow]).err {
+		t.Errorf("Expected the cached parse error, got %v", err)
+	}
+
+	valid := map[string]any{"Start": "Thu, 29 Jun 2023 07:00:00 GMT"}
+	enabled, err := filter.Evaluate(FeatureFilterEvaluationContext{FeatureName: "Invalid", Parameters: valid}, nil)
+	if err != nil || !enabled {
+		t.Errorf("Expected refreshed parameters to be parsed again, got %v, %v", enabled, err)
+	}
+}
+
+func TestTimeWindowFilterStrictTimeFormat(t *testing.T) {
+	tests := []struct {
+		time   string
+		strict bool
+	}{
+		{time: "2024-01-01T00:00:00Z", strict: true},
+		{time: "2024-01-01T08:00:00.5+08:00", strict: true},
+		{time: "Mon, 01 Jan 2024 00:00:00 GMT", strict: true},
+		{time: "Mon, 01 Jan 2024 00:00:00 PST", strict: false},
+		{time: "Mon, 01 Jan 2024 00:00:00 +0000", strict: false},
+		{time: "01 Jan 24 00:00 UTC", strict: false},
+		{time: "Mon Jan  1 00:00:00 UTC 2024", strict: false},
+	}
+
+	for _, test := range tests {
+		params := map[string]any{"Start": test.time}
+		if err := (&TimeWindowFilter{}).preloadParameters("Beta", params); err != nil {
+			t.Errorf("Expected %q to be accepted by default, got %v", test.time, err)
+		}
+		err := (&TimeWindowFilter{StrictTimeFormat: true}).preloadParameters("Beta", params)
+		if (err == nil) != test.strict {
+			t.Errorf("Expected %q to be accepted in strict mode: %v, got %v", test.time, test.strict, err)
+		}
+	}
+
+	// Flags with times in other formats are rejected when they are loaded
+	manager, err := NewFeatureManager(NewStaticProvider(nil), &Options{
+		Filters: []FeatureFilter{&TimeWindowFilter{StrictTimeFormat: true}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	err = manager.CheckFilterParameters(FeatureFlag{ID: "Beta", Enabled: true, Conditions: &Conditions{
+		ClientFilters: []ClientFilter{{Name: "Microsoft.TimeWindow", Parameters: map[string]any{"End": "Mon Jan  1 00:00:00 UTC 2024"}}},
+	}})
+	if err == nil {
+		t.Error("Expected the end time to be rejected")
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// FeatureUsage describes how a feature has been used by code and configuration
+type FeatureUsage struct {
+	// FeatureName is the name of the feature
+	FeatureName string
+	// Defined reports whether the provider currently defines the feature
+	Defined bool
+	// EvaluationCount is the number of times code evaluated the feature
+	EvaluationCount uint64
+	// FirstEvaluated is the time of the first evaluation, or the zero time if never evaluated
+	FirstEvaluated time.Time
+	// LastEvaluated is the time of the most recent evaluation, or the zero time if never evaluated
+	LastEvaluated time.Time
+}
+
+// UsageReport describes which features are defined in configuration and which are evaluated by
+// code, to feed flag cleanup automation.
+type UsageReport struct {
+	// TrackingSince is the time the feature manager started recording evaluations
+	TrackingSince time.Time
+	// GeneratedAt is the time the report was created
+	GeneratedAt time.Time
+	// Features contains one entry per feature that is defined or was evaluated, sorted by name
+	Features []FeatureUsage
+	// UntrackedEvaluations is the number of evaluations of undefined features left out of
+	// Features because 1000 undefined features were already tracked
+	UntrackedEvaluations uint64
+}
+
+// Unused returns the features defined in configuration but never evaluated by code.
+func (r UsageReport) Unused() []FeatureUsage {
+	var unused []FeatureUsage
+	for _, feature := range r.Features {
+		if feature.Defined && feature.EvaluationCount == 0 {
+			unused = append(unused, feature)
+		}
+	}
+
+	return unused
+}
+
+// Undefined returns the features evaluated by code but not defined in configuration.
+func (r UsageReport) Undefined() []FeatureUsage {
+	var undefined []FeatureUsage
+	for _, feature := range r.Features {
+		if !feature.Defined {
+			undefined = append(undefined, feature)
+		}
+	}
+
+	return undefined
+}
+
+// UsageReport returns which features have been evaluated, and when, since the feature manager
+// was created, alongside the features the provider defines. Evaluations of features the provider
+// doesn't define are included, so that references in code to missing configuration can be found.
+//
+// Tracking is bounded, so that evaluating arbitrary names can't grow it without limit: at most
+// 1000 undefined features are tracked, and evaluations of further undefined features are only
+// counted in UntrackedEvaluations. The evaluations of features the provider no longer defines are
+// discarded once more than 1000 features are tracked, as in GetLifecycleReport.
+//
+// Returns:
+//   - UsageReport: The usage of every defined or evaluated feature
+func (fm *FeatureManager) UsageReport() UsageReport {
+	report := UsageReport{
+		TrackingSince:        fm.tracker.since,
+		GeneratedAt:          time.Now(),
+		UntrackedEvaluations: fm.tracker.untracked.Load(),
+	}
+
+	usages := make(map[string]*FeatureUsage)
+	usage := func(name string) *FeatureUsage {
+		if u, ok := usages[name]; ok {
+			return u
+		}
+		u := &FeatureUsage{FeatureName: name}
+		usages[name] = u
+		return u
+	}
+
+	for flag := range fm.flags() {
+		usage(flag.ID).Defined = true
+	}
+
+	for _, m := range []*sync.Map{&fm.tracker.stats, &fm.tracker.missing} {
+		m.Range(func(key, value any) bool {
+			u := usage(key.(string))
+			stats := value.(*evaluationStats)
+			u.EvaluationCount += stats.enabled.Load() + stats.disabled.Load()
+			if first := stats.firstEvaluated.Load(); first != 0 {
+				if t := time.Unix(0, first); u.FirstEvaluated.IsZero() || t.Before(u.FirstEvaluated) {
+					u.FirstEvaluated = t
+				}
+			}
+			if last := stats.lastEvaluated.Load(); last != 0 {
+				if t := time.Unix(0, last); t.After(u.LastEvaluated) {
+					u.LastEvaluated = t
+				}
+			}
+			return true
+		})
+	}
+
+	for _, u := range usages {
+		report.Features = append(report.Features, *u)
+	}
+	sort.Slice(report.Features, func(i, j int) bool {
+		return report.Features[i].FeatureName < report.Features[j].FeatureName
+	})
+
+	return report
+}
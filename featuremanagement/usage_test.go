@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestUsageReport(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta":   {Enabled: true},
+		"Gamma":  {Enabled: false},
+		"Unused": {Enabled: true},
+	})
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, _ = manager.IsEnabled("Beta")
+	}
+	_, _ = manager.IsEnabled("Gamma")
+	if _, err := manager.IsEnabled("Missing"); err == nil {
+		t.Fatal("Expected an error for an undefined feature")
+	}
+
+	report := manager.UsageReport()
+	if len(report.Features) != 4 {
+		t.Fatalf("Expected 4 features, got %+v", report.Features)
+	}
+
+	beta := report.Features[0]
+	if beta.FeatureName != "Beta" || !beta.Defined || beta.EvaluationCount != 3 {
+		t.Errorf("Unexpected usage of Beta: %+v", beta)
+	}
+	if beta.FirstEvaluated.IsZero() || beta.LastEvaluated.Before(beta.FirstEvaluated) {
+		t.Errorf("Expected evaluation times for Beta, got %v and %v", beta.FirstEvaluated, beta.LastEvaluated)
+	}
+
+	if unused := report.Unused(); len(unused) != 1 || unused[0].FeatureName != "Unused" || !unused[0].LastEvaluated.IsZero() {
+		t.Errorf("Expected Unused to be reported as unused, got %+v", unused)
+	}
+
+	undefined := report.Undefined()
+	if len(undefined) != 1 || undefined[0].FeatureName != "Missing" || undefined[0].EvaluationCount != 1 {
+		t.Errorf("Expected Missing to be reported as undefined, got %+v", undefined)
+	}
+
+	// Evaluations of undefined features don't appear in the lifecycle report
+	names := []string{}
+	for _, feature := range manager.GetLifecycleReport().Features {
+		names = append(names, feature.FeatureName)
+	}
+	if fmt.Sprint(names) != "[Beta Gamma Unused]" {
+		t.Errorf("Expected only defined features in the lifecycle report, got %v", names)
+	}
+}
+
+func TestUsageReportBoundsUndefinedFeatures(t *testing.T) {
+	manager, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{"Beta": {Enabled: true}}), nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	for i := 0; i < maxMissingFeatures+10; i++ {
+		_, _ = manager.IsEnabled(fmt.Sprintf("Missing%d", i))
+	}
+	_, _ = manager.IsEnabled("Beta")
+
+	report := manager.UsageReport()
+	if undefined := report.Undefined(); len(undefined) != maxMissingFeatures {
+		t.Errorf("Expected %d undefined features to be reported, got %d", maxMissingFeatures, len(undefined))
+	}
+	if report.UntrackedEvaluations != 10 {
+		t.Errorf("Expected 10 untracked evaluations, got %d", report.UntrackedEvaluations)
+	}
+	if len(report.Features) != maxMissingFeatures+1 {
+		t.Errorf("Expected the defined feature to be reported as well, got %d features", len(report.Features))
+	}
+}
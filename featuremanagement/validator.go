@@ -3,7 +3,251 @@
 
 package featuremanagement
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ValidateFeatureFlag checks that a feature flag definition conforms to the feature flag schema.
+// Providers can call it when loading or refreshing flags to reject invalid definitions up front.
+//
+// Parameters:
+//   - flag: The feature flag definition to validate
+//
+// Returns:
+//   - error: An error describing the first violation found, or nil if the flag is valid
+func ValidateFeatureFlag(flag FeatureFlag) error {
+	return validateFeatureFlag(flag)
+}
+
+// ValidationError reports a feature flag definition that failed validation.
+type ValidationError struct {
+	// FeatureName is the ID of the invalid feature flag
+	FeatureName string
+	// Err describes the violation
+	Err error
+}
+
+func (e ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationWarning reports a feature flag definition that is valid but likely needs attention,
+// such as a schedule that has already ended.
+type ValidationWarning struct {
+	// FeatureName is the ID of the feature flag
+	FeatureName string
+	// Path locates the problem within the feature flag definition, for example
+	// "allocation.percentile[1]", or is empty when it concerns the whole flag
+	Path string
+	// Message describes the problem
+	Message string
+}
+
+func (w ValidationWarning) String() string {
+	return w.Message
+}
+
+// FindExpiredTimeWindows returns a warning for each enabled feature flag with a
+// Microsoft.TimeWindow filter whose end is not after the given time. Such a filter never matches
+// again, which usually means the schedule is dead and the flag should be cleaned up.
+// Time windows that can't be parsed are left to validation and evaluation to report.
+//
+// Parameters:
+//   - flags: The feature flag definitions to check
+//   - now: The time against which the time windows are checked
+//
+// Returns:
+//   - []ValidationWarning: A warning for each expired time window, in flag order
+func FindExpiredTimeWindows(flags []FeatureFlag, now time.Time) []ValidationWarning {
+	var warnings []ValidationWarning
+	for _, flag := range flags {
+		warnings = append(warnings, expiredTimeWindows(flag, now)...)
+	}
+
+	return warnings
+}
+
+func expiredTimeWindows(flag FeatureFlag, now time.Time) []ValidationWarning {
+	if !flag.Enabled || flag.Conditions == nil {
+		return nil
+	}
+
+	var warnings []ValidationWarning
+	timeWindowFilterName := (&TimeWindowFilter{}).Name()
+	for i, filter := range flag.Conditions.ClientFilters {
+		if filter.Name != timeWindowFilterName {
+			continue
+		}
+
+		params, err := decodeTimeWindowParameters(filter.Parameters)
+		if err != nil || params.End == "" {
+			continue
+		}
+		end, err := parseTime(params.End)
+		if err != nil || now.Before(end) {
+			continue
+		}
+
+		warnings = append(warnings, ValidationWarning{
+			FeatureName: flag.ID,
+			Path:        fmt.Sprintf("conditions.client_filters[%d]", i),
+			Message:     fmt.Sprintf("time window of feature %s ended at %s and will never match again", flag.ID, end.Format(time.RFC3339)),
+		})
+	}
+
+	return warnings
+}
+
+// FindOverlappingPercentiles returns a warning for each percentile allocation whose range overlaps
+// the range of an earlier percentile allocation of the same feature flag. Users in the overlap are
+// always allocated the variant of the earlier range, which usually means the ranges were
+// miscalculated.
+//
+// Parameters:
+//   - flags: The feature flag definitions to check
+//
+// Returns:
+//   - []ValidationWarning: A warning for each overlapping range, in flag order
+func FindOverlappingPercentiles(flags []FeatureFlag) []ValidationWarning {
+	var warnings []ValidationWarning
+	for _, flag := range flags {
+		warnings = append(warnings, overlappingPercentiles(flag)...)
+	}
+
+	return warnings
+}
+
+func overlappingPercentiles(flag FeatureFlag) []ValidationWarning {
+	if flag.Allocation == nil {
+		return nil
+	}
+
+	var warnings []ValidationWarning
+	percentiles := flag.Allocation.Percentile
+	for i, current := range percentiles {
+		for j, earlier := range percentiles[:i] {
+			if current.From < earlier.To && earlier.From < current.To {
+				warnings = append(warnings, ValidationWarning{
+					FeatureName: flag.ID,
+					Path:        fmt.Sprintf("allocation.percentile[%d]", i),
+					Message:     fmt.Sprintf("percentile allocation at index %d of feature %s overlaps the one at index %d", i, flag.ID, j),
+				})
+				break
+			}
+		}
+	}
+
+	return warnings
+}
+
+// FindSuspiciousAllocations returns a warning for each variant allocation that is valid but likely
+// a mistake: a variant referenced by an allocation but not defined by the feature flag, which is
+// never assigned, and percentile ranges that don't cover 0 to 100 while no default_when_enabled
+// variant is set, which leaves the remaining users without a variant.
+//
+// Parameters:
+//   - flags: The feature flag definitions to check
+//
+// Returns:
+//   - []ValidationWarning: A warning for each suspicious allocation, in flag order
+func FindSuspiciousAllocations(flags []FeatureFlag) []ValidationWarning {
+	var warnings []ValidationWarning
+	for _, flag := range flags {
+		warnings = append(warnings, suspiciousAllocations(flag)...)
+	}
+
+	return warnings
+}
+
+func suspiciousAllocations(flag FeatureFlag) []ValidationWarning {
+	allocation := flag.Allocation
+	if allocation == nil {
+		return nil
+	}
+
+	var warnings []ValidationWarning
+	checkVariant := func(path, variant string) {
+		if variant != "" && getVariant(flag.Variants, variant) == nil {
+			warnings = append(warnings, ValidationWarning{
+				FeatureName: flag.ID,
+				Path:        path,
+				Message:     fmt.Sprintf("variant %s allocated by %s of feature %s is not defined", variant, path, flag.ID),
+			})
+		}
+	}
+	checkVariant("allocation.default_when_enabled", allocation.DefaultWhenEnabled)
+	checkVariant("allocation.default_when_disabled", allocation.DefaultWhenDisabled)
+	for i, user := range allocation.User {
+		checkVariant(fmt.Sprintf("allocation.user[%d]", i), user.Variant)
+	}
+	for i, group := range allocation.Group {
+		checkVariant(fmt.Sprintf("allocation.group[%d]", i), group.Variant)
+	}
+	for i, percentile := range allocation.Percentile {
+		checkVariant(fmt.Sprintf("allocation.percentile[%d]", i), percentile.Variant)
+	}
+
+	if len(allocation.Percentile) > 0 && allocation.DefaultWhenEnabled == "" {
+		if coverage := percentileCoverage(allocation.Percentile); coverage < 100 {
+			warnings = append(warnings, ValidationWarning{
+				FeatureName: flag.ID,
+				Path:        "allocation.percentile",
+				Message: fmt.Sprintf("percentile allocations of feature %s cover %g%% of users and no default_when_enabled variant is set, so the other users get no variant",
+					flag.ID, coverage),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// percentileCoverage returns the percentage of users within the union of the percentile ranges
+func percentileCoverage(percentiles []PercentileAllocation) float64 {
+	ranges := make([]PercentileAllocation, len(percentiles))
+	copy(ranges, percentiles)
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].From < ranges[j].From })
+
+	var covered, end float64
+	for _, r := range ranges {
+		from, to := max(r.From, end, 0), min(r.To, 100)
+		if to > from {
+			covered += to - from
+			end = to
+		}
+	}
+
+	return covered
+}
+
+// ValidateFeatureFlags validates a set of feature flag definitions, separating the valid flags
+// from the invalid ones. Providers can use it to serve the valid flags of a configuration while
+// reporting the rest.
+//
+// Parameters:
+//   - flags: The feature flag definitions to validate
+//
+// Returns:
+//   - []FeatureFlag: The valid feature flags, in their original order
+//   - []ValidationError: An error for each invalid feature flag, in their original order
+func ValidateFeatureFlags(flags []FeatureFlag) ([]FeatureFlag, []ValidationError) {
+	valid := make([]FeatureFlag, 0, len(flags))
+	var errs []ValidationError
+	for _, flag := range flags {
+		if err := validateFeatureFlag(flag); err != nil {
+			errs = append(errs, ValidationError{FeatureName: flag.ID, Err: err})
+			continue
+		}
+		valid = append(valid, flag)
+	}
+
+	return valid, errs
+}
 
 // validateFeatureFlag validates an individual feature flag
 func validateFeatureFlag(flag FeatureFlag) error {
@@ -107,5 +351,15 @@ func validateAllocation(id string, allocation *VariantAllocation) error {
 		}
 	}
 
+	if group := allocation.ExclusionGroup; group != nil {
+		if group.Name == "" {
+			return fmt.Errorf("invalid feature flag %s: exclusion group name is required", id)
+		}
+
+		if group.From < 0 || group.To > 100 || group.From > group.To {
+			return fmt.Errorf("invalid feature flag %s: exclusion group range must be within 0 to 100 with 'from' not larger than 'to'", id)
+		}
+	}
+
 	return nil
 }
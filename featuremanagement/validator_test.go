@@ -0,0 +1,261 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type validatingMockProvider struct {
+	mockFeatureFlagProvider
+}
+
+func (p *validatingMockProvider) ValidatesFeatureFlags() bool {
+	return true
+}
+
+func TestValidateFeatureFlag(t *testing.T) {
+	tests := []struct {
+		name  string
+		flag  FeatureFlag
+		valid bool
+	}{
+		{name: "Minimal", flag: FeatureFlag{ID: "Minimal"}, valid: true},
+		{name: "Missing ID", flag: FeatureFlag{}, valid: false},
+		{
+			name: "Invalid requirement type",
+			flag: FeatureFlag{ID: "Beta", Conditions: &Conditions{RequirementType: "Some"}},
+		},
+		{
+			name: "Invalid status override",
+			flag: FeatureFlag{ID: "Beta", Variants: []VariantDefinition{{Name: "Big", StatusOverride: "Off"}}},
+		},
+		{
+			name: "Percentile out of range",
+			flag: FeatureFlag{ID: "Beta", Allocation: &VariantAllocation{
+				Percentile: []PercentileAllocation{{Variant: "Big", From: 0, To: 101}},
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateFeatureFlag(tc.flag)
+			if tc.valid && err != nil {
+				t.Errorf("Expected valid flag, got %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestValidatingProviderSkipsEvaluationValidation(t *testing.T) {
+	invalid := FeatureFlag{ID: "Beta", Enabled: true, Conditions: &Conditions{RequirementType: "Some"}}
+
+	manager, err := NewFeatureManager(&mockFeatureFlagProvider{featureFlags: []FeatureFlag{invalid}}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	if _, err := manager.IsEnabled("Beta"); err == nil {
+		t.Error("Expected the manager to validate flags from a non-validating provider")
+	}
+
+	provider := &validatingMockProvider{mockFeatureFlagProvider{featureFlags: []FeatureFlag{invalid}}}
+	manager, err = NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	if _, err := manager.IsEnabled("Beta"); err != nil {
+		t.Errorf("Expected validation to be skipped for a validating provider, got %v", err)
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	invalid := FeatureFlag{
+		ID:      "Invalid",
+		Enabled: true,
+		Conditions: &Conditions{
+			RequirementType: "Some",
+		},
+	}
+	provider := &mockFeatureFlagProvider{
+		featureFlags: []FeatureFlag{{ID: "Valid", Enabled: true}, invalid},
+	}
+
+	var reported []ValidationError
+	manager, err := NewFeatureManager(provider, &Options{
+		OnValidationError: func(err ValidationError) {
+			reported = append(reported, err)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if len(reported) != 1 || reported[0].FeatureName != "Invalid" {
+		t.Fatalf("Expected the invalid flag to be reported at creation, got %v", reported)
+	}
+	if errs := manager.ValidationErrors(); len(errs) != 1 || errs[0].FeatureName != "Invalid" {
+		t.Errorf("Expected one validation error, got %v", errs)
+	}
+
+	if _, err := manager.IsEnabled("Invalid"); err == nil {
+		t.Error("Expected an error evaluating the invalid flag")
+	}
+	if len(reported) != 2 {
+		t.Errorf("Expected the invalid flag to be reported when evaluated, got %d reports", len(reported))
+	}
+	if names, _ := manager.GetFeatureNames(); len(names) != 2 {
+		t.Errorf("Expected invalid flags to be enumerated by default, got %v", names)
+	}
+}
+
+func TestSkipInvalidFlags(t *testing.T) {
+	provider := &mockFeatureFlagProvider{
+		featureFlags: []FeatureFlag{
+			{ID: "Valid", Enabled: true},
+			{ID: "Invalid", Enabled: true, Variants: []VariantDefinition{{Name: ""}}},
+		},
+	}
+
+	manager, err := NewFeatureManager(provider, &Options{SkipInvalidFlags: true})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	if enabled, err := manager.IsEnabled("Invalid"); err != nil || enabled {
+		t.Errorf("Expected the invalid flag to evaluate as disabled, got %v, %v", enabled, err)
+	}
+	if enabled, err := manager.IsEnabled("Valid"); err != nil || !enabled {
+		t.Errorf("Expected the valid flag to be served, got %v, %v", enabled, err)
+	}
+	if names, _ := manager.GetFeatureNames(); len(names) != 1 || names[0] != "Valid" {
+		t.Errorf("Expected only the valid flag to be enumerated, got %v", names)
+	}
+}
+
+func TestValidateFeatureFlags(t *testing.T) {
+	valid, errs := ValidateFeatureFlags([]FeatureFlag{{ID: "A"}, {ID: ""}, {ID: "B"}})
+	if len(valid) != 2 || valid[0].ID != "A" || valid[1].ID != "B" {
+		t.Errorf("Expected valid flags A and B, got %v", valid)
+	}
+	if len(errs) != 1 || errs[0].Unwrap() == nil {
+		t.Errorf("Expected one validation error, got %v", errs)
+	}
+}
+
+func TestExpiredTimeWindows(t *testing.T) {
+	timeWindow := func(end string) *Conditions {
+		return &Conditions{ClientFilters: []ClientFilter{{
+			Name:       "Microsoft.TimeWindow",
+			Parameters: map[string]any{"Start": "Mon, 01 Jan 2024 00:00:00 GMT", "End": end},
+		}}}
+	}
+	provider := &mockFeatureFlagProvider{featureFlags: []FeatureFlag{
+		{ID: "Expired", Enabled: true, Conditions: timeWindow("Tue, 02 Jan 2024 00:00:00 GMT")},
+		{ID: "Scheduled", Enabled: true, Conditions: timeWindow("Fri, 01 Jan 2100 00:00:00 GMT")},
+		{ID: "Off", Enabled: false, Conditions: timeWindow("Tue, 02 Jan 2024 00:00:00 GMT")},
+		{ID: "Unparsable", Enabled: true, Conditions: timeWindow("yesterday")},
+	}}
+
+	var reported []ValidationWarning
+	manager, err := NewFeatureManager(provider, &Options{
+		OnValidationWarning: func(warning ValidationWarning) {
+			reported = append(reported, warning)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	expected := "time window of feature Expired ended at 2024-01-02T00:00:00Z and will never match again"
+	if len(reported) != 1 || reported[0].FeatureName != "Expired" || reported[0].Message != expected {
+		t.Errorf("Expected the expired time window to be reported at load, got %+v", reported)
+	}
+	if warnings := manager.ValidationWarnings(); len(warnings) != 1 || warnings[0].FeatureName != "Expired" {
+		t.Errorf("Expected one validation warning, got %+v", warnings)
+	}
+	if warnings := FindExpiredTimeWindows(provider.featureFlags, time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC)); len(warnings) != 2 {
+		t.Errorf("Expected both enabled time windows to have expired by 2200, got %+v", warnings)
+	}
+
+	for _, feature := range manager.GetLifecycleReport().Features {
+		if hasWarnings := len(feature.Warnings) > 0; hasWarnings != (feature.FeatureName == "Expired") {
+			t.Errorf("Unexpected lifecycle warnings for %s: %v", feature.FeatureName, feature.Warnings)
+		}
+	}
+}
+
+func TestFindOverlappingPercentiles(t *testing.T) {
+	flags := []FeatureFlag{
+		{ID: "Adjacent", Allocation: &VariantAllocation{Percentile: []PercentileAllocation{
+			{Variant: "A", From: 0, To: 50},
+			{Variant: "B", From: 50, To: 100},
+		}}},
+		{ID: "Overlapping", Allocation: &VariantAllocation{Percentile: []PercentileAllocation{
+			{Variant: "A", From: 0, To: 60},
+			{Variant: "B", From: 60, To: 100},
+			{Variant: "C", From: 40, To: 70},
+		}}},
+	}
+
+	warnings := FindOverlappingPercentiles(flags)
+	if len(warnings) != 1 {
+		t.Fatalf("Expected a single warning, got %v", warnings)
+	}
+	if warnings[0].FeatureName != "Overlapping" || warnings[0].Path != "allocation.percentile[2]" ||
+		warnings[0].Message != "percentile allocation at index 2 of feature Overlapping overlaps the one at index 0" {
+		t.Errorf("Unexpected warning %+v", warnings[0])
+	}
+}
+
+func TestFindSuspiciousAllocations(t *testing.T) {
+	variants := []VariantDefinition{{Name: "A"}, {Name: "B"}}
+	flags := []FeatureFlag{
+		{ID: "Covered", Variants: variants, Allocation: &VariantAllocation{Percentile: []PercentileAllocation{
+			{Variant: "A", From: 0, To: 60},
+			{Variant: "B", From: 40, To: 100},
+		}}},
+		{ID: "Defaulted", Variants: variants, Allocation: &VariantAllocation{
+			DefaultWhenEnabled: "A",
+			Percentile:         []PercentileAllocation{{Variant: "B", From: 0, To: 10}},
+		}},
+		{ID: "Partial", Variants: variants, Allocation: &VariantAllocation{Percentile: []PercentileAllocation{
+			{Variant: "A", From: 0, To: 25},
+			{Variant: "B", From: 50, To: 75},
+		}}},
+		{ID: "Undefined", Variants: variants, Allocation: &VariantAllocation{
+			DefaultWhenDisabled: "Off",
+			User:                []UserAllocation{{Variant: "A", Users: []string{"alice"}}, {Variant: "C", Users: []string{"bob"}}},
+		}},
+	}
+
+	var actual []string
+	for _, warning := range FindSuspiciousAllocations(flags) {
+		actual = append(actual, warning.FeatureName+" "+warning.Path+": "+warning.Message)
+	}
+	expected := []string{
+		"Partial allocation.percentile: percentile allocations of feature Partial cover 50% of users and no default_when_enabled variant is set, so the other users get no variant",
+		"Undefined allocation.default_when_disabled: variant Off allocated by allocation.default_when_disabled of feature Undefined is not defined",
+		"Undefined allocation.user[1]: variant C allocated by allocation.user[1] of feature Undefined is not defined",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected warnings %q, got %q", expected, actual)
+	}
+
+	var reported []ValidationWarning
+	_, err := NewFeatureManager(NewStaticProvider(map[string]FeatureFlag{"Undefined": flags[3]}), &Options{
+		OnValidationWarning: func(warning ValidationWarning) { reported = append(reported, warning) },
+	})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	if len(reported) != 2 {
+		t.Errorf("Expected the suspicious allocations to be reported at load, got %v", reported)
+	}
+}
@@ -3,6 +3,11 @@
 
 package featuremanagement
 
+import (
+	"errors"
+	"fmt"
+)
+
 // Variant represents a feature configuration variant.
 // Variants allow different configurations or implementations of a feature
 // to be assigned to different users.
@@ -13,3 +18,73 @@ type Variant struct {
 	// ConfigurationValue holds the value for this variant
 	ConfigurationValue any
 }
+
+// ErrNoVariant is matched by the error GetVariantValue returns when no variant is assigned.
+var ErrNoVariant = errors.New("no variant assigned")
+
+// NoVariantError reports that no variant of a feature was assigned, and why.
+type NoVariantError struct {
+	// FeatureName is the name of the evaluated feature
+	FeatureName string
+	// Reason explains the outcome of the variant assignment
+	Reason VariantAssignmentReason
+}
+
+func (e *NoVariantError) Error() string {
+	return fmt.Sprintf("no variant of feature %s is assigned (reason: %s)", e.FeatureName, e.Reason)
+}
+
+// Is reports whether target is ErrNoVariant.
+func (e *NoVariantError) Is(target error) bool {
+	return target == ErrNoVariant
+}
+
+// GetVariantValue evaluates a feature and decodes the configuration value of the assigned variant
+// into T, so that typed variant configuration doesn't need type assertions:
+//
+//	type Greeting struct {
+//		Message string `json:"message"`
+//	}
+//
+//	greeting, err := featuremanagement.GetVariantValue[Greeting](manager, "Greeting", targetingContext)
+//	if errors.Is(err, featuremanagement.ErrNoVariant) {
+//		// Use the default greeting
+//	}
+//
+// Values are decoded like filter parameters with BindParameters: struct fields are matched by
+// their json tags, case-insensitively, and values are converted between compatible types.
+// A variant without a configuration value decodes to the zero value of T.
+//
+// Parameters:
+//   - fm: The feature manager evaluating the feature
+//   - featureName: The name of the feature to evaluate
+//   - appContext: An optional context object for contextual evaluation
+//
+// Returns:
+//   - T: The decoded configuration value of the assigned variant
+//   - error: A *NoVariantError matching ErrNoVariant if no variant is assigned, or an error if the
+//     feature can't be evaluated or its configuration value can't be decoded into T
+func GetVariantValue[T any](fm *FeatureManager, featureName string, appContext any) (T, error) {
+	var value T
+	res, err := fm.evaluate(featureName, appContext)
+	if err != nil {
+		return value, err
+	}
+
+	if res.Variant == nil {
+		return value, &NoVariantError{FeatureName: featureName, Reason: res.VariantAssignmentReason}
+	}
+
+	if typed, ok := res.Variant.ConfigurationValue.(T); ok {
+		return typed, nil
+	}
+	if res.Variant.ConfigurationValue == nil {
+		return value, nil
+	}
+
+	if err := decodeValue(res.Variant.ConfigurationValue, &value); err != nil {
+		return value, fmt.Errorf("failed to decode the configuration value of variant %s of feature %s: %w", res.Variant.Name, featureName, err)
+	}
+
+	return value, nil
+}
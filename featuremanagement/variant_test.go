@@ -5,6 +5,7 @@ package featuremanagement
 
 import (
 	"encoding/json"
+	"errors"
 	"testing"
 )
 
@@ -315,3 +316,56 @@ func TestGetVariant(t *testing.T) {
 		})
 	})
 }
+
+func TestGetVariantValue(t *testing.T) {
+	type greeting struct {
+		Message string `json:"message"`
+		Limit   int    `json:"limit"`
+	}
+
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Greeting": {
+			Enabled: true,
+			Variants: []VariantDefinition{
+				{Name: "Formal", ConfigurationValue: map[string]any{"Message": "Good day", "limit": "3"}},
+			},
+			Allocation: &VariantAllocation{DefaultWhenEnabled: "Formal"},
+		},
+		"Banner": {
+			Enabled:    true,
+			Variants:   []VariantDefinition{{Name: "On", ConfigurationValue: "Welcome"}},
+			Allocation: &VariantAllocation{DefaultWhenEnabled: "On"},
+		},
+		"Plain": {Enabled: true},
+	})
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	value, err := GetVariantValue[greeting](manager, "Greeting", nil)
+	if err != nil || value != (greeting{Message: "Good day", Limit: 3}) {
+		t.Errorf("Expected the decoded greeting, got %+v, %v", value, err)
+	}
+
+	if banner, err := GetVariantValue[string](manager, "Banner", nil); err != nil || banner != "Welcome" {
+		t.Errorf("Expected the banner text, got %q, %v", banner, err)
+	}
+
+	if _, err := GetVariantValue[int](manager, "Banner", nil); err == nil {
+		t.Error("Expected an error decoding text into an int")
+	}
+
+	plain, err := GetVariantValue[greeting](manager, "Plain", nil)
+	var noVariant *NoVariantError
+	if !errors.Is(err, ErrNoVariant) || !errors.As(err, &noVariant) || noVariant.Reason != VariantAssignmentReasonNone {
+		t.Errorf("Expected a no-variant error, got %v", err)
+	}
+	if plain != (greeting{}) {
+		t.Errorf("Expected the zero value, got %+v", plain)
+	}
+
+	if _, err := GetVariantValue[string](manager, "Missing", nil); err == nil || errors.Is(err, ErrNoVariant) {
+		t.Errorf("Expected an evaluation error, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// warmUpPollInterval is how often WarmUp checks whether the provider is ready
+const warmUpPollInterval = 100 * time.Millisecond
+
+// WarmUp blocks until the provider has loaded its feature flags, then validates every feature
+// flag and decodes the parameters of the built-in filters, so that a service can refuse traffic
+// until its feature state is known and the first evaluations don't pay for parsing:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := manager.WarmUp(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// Readiness is determined as by Ready. Providers implementing RefreshNotifier wake WarmUp as soon
+// as they load; other providers are checked periodically. Invalid definitions are also passed to
+// Options.OnValidationError and Options.OnError.
+//
+// Parameters:
+//   - ctx: The context bounding the wait, typically with a timeout
+//
+// Returns:
+//   - error: The context error if the provider didn't load in time, or an error listing the invalid
+//     feature flags and filter parameters unless Options.SkipInvalidFlags is set
+func (fm *FeatureManager) WarmUp(ctx context.Context) error {
+	if err := fm.waitUntilReady(ctx); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, validationErr := range fm.ValidationErrors() {
+		if fm.onValidationError != nil {
+			fm.onValidationError(validationErr)
+		}
+		errs = append(errs, fmt.Errorf("invalid feature flag %s: %w", validationErr.FeatureName, validationErr.Err))
+	}
+
+	preloadFilterParameters(fm.featureFilters, fm.flags(), func(featureName string, err error) {
+		fm.reportError(featureName, err)
+		errs = append(errs, err)
+	})
+
+	if fm.skipInvalidFlags {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+// waitUntilReady blocks until Ready reports true or the context is done
+func (fm *FeatureManager) waitUntilReady(ctx context.Context) error {
+	if fm.Ready() {
+		return nil
+	}
+
+	refreshed := make(chan struct{}, 1)
+	if notifier, ok := fm.featureProvider.(RefreshNotifier); ok {
+		unregister := notifier.OnRefresh(func() {
+			select {
+			case refreshed <- struct{}{}:
+			default:
+			}
+		})
+		defer unregister()
+	}
+
+	ticker := time.NewTicker(warmUpPollInterval)
+	defer ticker.Stop()
+	for !fm.Ready() {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("feature flags were not loaded: %w", ctx.Err())
+		case <-refreshed:
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
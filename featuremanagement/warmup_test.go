@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation.
+// Licensed under the MIT License.
+
+package featuremanagement
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// lateProvider reports it is loaded once loaded is set
+type lateProvider struct {
+	*StaticProvider
+	loaded    atomic.Bool
+	listeners RefreshListeners
+}
+
+func (p *lateProvider) ProviderStatus() ProviderStatus {
+	return ProviderStatus{Loaded: p.loaded.Load()}
+}
+
+func (p *lateProvider) OnRefresh(callback func()) func() {
+	return p.listeners.Add(callback)
+}
+
+func TestWarmUp(t *testing.T) {
+	provider := &lateProvider{StaticProvider: NewStaticProvider(map[string]FeatureFlag{"Beta": {Enabled: true}})}
+	manager, err := NewFeatureManager(provider, nil)
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := manager.WarmUp(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the warm up to time out, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		provider.loaded.Store(true)
+		provider.listeners.Notify()
+	}()
+	if err := manager.WarmUp(context.Background()); err != nil {
+		t.Errorf("Expected the warm up to succeed once loaded, got %v", err)
+	}
+}
+
+func TestWarmUpInvalidFlags(t *testing.T) {
+	provider := NewStaticProvider(map[string]FeatureFlag{
+		"Beta": {Enabled: true, Variants: []VariantDefinition{{}}},
+		"Gamma": {
+			Enabled: true,
+			Conditions: &Conditions{ClientFilters: []ClientFilter{{
+				Name:       "Microsoft.TimeWindow",
+				Parameters: map[string]any{"Start": "yesterday"},
+			}}},
+		},
+	})
+
+	manager, err := NewFeatureManager(provider, &Options{LogLevel: LogLevelSilent})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	err = manager.WarmUp(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid feature flag Beta") || !strings.Contains(err.Error(), "filter Microsoft.TimeWindow of feature Gamma") {
+		t.Errorf("Expected both invalid flags to be reported, got %v", err)
+	}
+
+	manager, err = NewFeatureManager(provider, &Options{LogLevel: LogLevelSilent, SkipInvalidFlags: true})
+	if err != nil {
+		t.Fatalf("Failed to create feature manager: %v", err)
+	}
+	if err := manager.WarmUp(context.Background()); err != nil {
+		t.Errorf("Expected invalid flags to be skipped, got %v", err)
+	}
+}